@@ -14,27 +14,99 @@ import (
 // ServerInterface represents all server handlers.
 type ServerInterface interface {
 
+	// (GET /.well-known/jwks.json)
+	GetJWKS(ctx echo.Context) error
+
+	// (GET /auth/oidc/{provider}/callback)
+	HandleOIDCCallback(ctx echo.Context, provider string, params HandleOIDCCallbackParams) error
+
+	// (GET /auth/oidc/{provider}/start)
+	StartOIDCLogin(ctx echo.Context, provider string) error
+
+	// (POST /auth/refresh)
+	RefreshAuthToken(ctx echo.Context) error
+
+	// (GET /calendar-feeds/{feedToken}/calendar.ics)
+	GetCalendarFeedCalendar(ctx echo.Context, feedToken string) error
+
+	// (POST /invitations/{invitationToken}/accept)
+	AcceptTripInvitation(ctx echo.Context, invitationToken string) error
+
 	// (POST /login)
 	LoginUser(ctx echo.Context) error
 
+	// (POST /login/2fa)
+	LoginWithTwoFactor(ctx echo.Context) error
+
 	// (POST /logout)
 	LogoutUser(ctx echo.Context) error
 
+	// (POST /logout/all)
+	LogoutAllSessions(ctx echo.Context) error
+
+	// (DELETE /me)
+	DeleteMe(ctx echo.Context) error
+
 	// (GET /me)
 	GetMe(ctx echo.Context) error
 
+	// (POST /me/2fa/confirm)
+	ConfirmTwoFactor(ctx echo.Context) error
+
+	// (POST /me/2fa/setup)
+	SetupTwoFactor(ctx echo.Context) error
+
+	// (DELETE /me/calendar-feed)
+	RevokeCalendarFeed(ctx echo.Context) error
+
+	// (GET /me/calendar-feed)
+	GetCalendarFeed(ctx echo.Context) error
+
+	// (POST /me/calendar-feed)
+	CreateCalendarFeed(ctx echo.Context) error
+
+	// (PUT /me/email)
+	ChangeEmail(ctx echo.Context) error
+
+	// (GET /me/export)
+	ExportPersonalData(ctx echo.Context) error
+
+	// (GET /me/memberships)
+	ListMyMemberships(ctx echo.Context) error
+
 	// (PUT /me/password)
 	ChangePassword(ctx echo.Context) error
 
+	// (GET /me/tokens)
+	ListPersonalAccessTokens(ctx echo.Context) error
+
+	// (POST /me/tokens)
+	CreatePersonalAccessToken(ctx echo.Context) error
+
+	// (DELETE /me/tokens/{tokenId})
+	RevokePersonalAccessToken(ctx echo.Context, tokenId TokenId) error
+
+	// (POST /password/forgot)
+	RequestPasswordReset(ctx echo.Context) error
+
+	// (POST /password/reset)
+	ResetPassword(ctx echo.Context) error
+
 	// (GET /public/trips/{shareToken})
 	GetPublicTripByShareToken(ctx echo.Context, shareToken ShareToken) error
 
 	// (PUT /public/trips/{shareToken})
 	UpdatePublicTripByShareToken(ctx echo.Context, shareToken ShareToken) error
 
+	// (GET /public/trips/{shareToken}/calendar.ics)
+	GetPublicTripCalendar(ctx echo.Context, shareToken ShareToken) error
+
 	// (GET /public/trips/{shareToken}/details)
 	GetTripDetailsForPublicTrip(ctx echo.Context, shareToken ShareToken) error
 
+	// (POST /public/trips/{shareToken}/members/{memberId}/claim)
+	ClaimMemberForPublicTrip(ctx echo.Context, shareToken ShareToken, memberId MemberId) error
+
 	// (GET /public/trips/{shareToken}/schedules)
 	GetSchedulesForPublicTrip(ctx echo.Context, shareToken ShareToken) error
 
@@ -50,6 +122,9 @@ type ServerInterface interface {
 	// (PATCH /public/trips/{shareToken}/schedules/{scheduleId})
 	UpdateScheduleForPublicTrip(ctx echo.Context, shareToken ShareToken, scheduleId ScheduleId) error
 
+	// (POST /public/trips/{shareToken}/unlock)
+	UnlockPublicTrip(ctx echo.Context, shareToken ShareToken) error
+
 	// (POST /signup)
 	CreateUser(ctx echo.Context) error
 
@@ -59,6 +134,9 @@ type ServerInterface interface {
 	// (POST /trips)
 	CreateUserTrip(ctx echo.Context) error
 
+	// (POST /trips/import)
+	ImportTrip(ctx echo.Context) error
+
 	// (DELETE /trips/{tripId})
 	DeleteUserTrip(ctx echo.Context, tripId TripId) error
 
@@ -68,15 +146,66 @@ type ServerInterface interface {
 	// (PUT /trips/{tripId})
 	UpdateUserTrip(ctx echo.Context, tripId TripId) error
 
+	// (GET /trips/{tripId}/calendar.ics)
+	GetTripCalendar(ctx echo.Context, tripId TripId) error
+
+	// (GET /trips/{tripId}/collaborators)
+	ListTripCollaborators(ctx echo.Context, tripId TripId) error
+
+	// (POST /trips/{tripId}/collaborators)
+	AddTripCollaborator(ctx echo.Context, tripId TripId) error
+
+	// (DELETE /trips/{tripId}/collaborators/{userId})
+	RemoveTripCollaborator(ctx echo.Context, tripId TripId, userId UserId) error
+
+	// (PUT /trips/{tripId}/collaborators/{userId})
+	UpdateTripCollaborator(ctx echo.Context, tripId TripId, userId UserId) error
+
 	// (GET /trips/{tripId}/details)
 	GetTripDetails(ctx echo.Context, tripId TripId) error
 
+	// (GET /trips/{tripId}/export)
+	ExportTrip(ctx echo.Context, tripId TripId) error
+
+	// (GET /trips/{tripId}/invitations)
+	ListTripInvitations(ctx echo.Context, tripId TripId) error
+
+	// (POST /trips/{tripId}/invitations)
+	CreateTripInvitation(ctx echo.Context, tripId TripId) error
+
+	// (DELETE /trips/{tripId}/invitations/{invitationId})
+	RevokeTripInvitation(ctx echo.Context, tripId TripId, invitationId InvitationId) error
+
+	// (POST /trips/{tripId}/invitations/{invitationId}/resend)
+	ResendTripInvitation(ctx echo.Context, tripId TripId, invitationId InvitationId) error
+
+	// (POST /trips/{tripId}/members)
+	AddTripMember(ctx echo.Context, tripId TripId) error
+
+	// (DELETE /trips/{tripId}/members/{memberId})
+	DeleteTripMember(ctx echo.Context, tripId TripId, memberId MemberId) error
+
+	// (PATCH /trips/{tripId}/members/{memberId})
+	UpdateTripMember(ctx echo.Context, tripId TripId, memberId MemberId) error
+
+	// (PUT /trips/{tripId}/owner)
+	TransferTripOwnership(ctx echo.Context, tripId TripId) error
+
 	// (GET /trips/{tripId}/schedules)
 	GetSchedulesForTrip(ctx echo.Context, tripId TripId) error
 
 	// (POST /trips/{tripId}/schedules)
 	AddScheduleToTrip(ctx echo.Context, tripId TripId) error
 
+	// (GET /trips/{tripId}/schedules.csv)
+	ExportSchedulesCsv(ctx echo.Context, tripId TripId, params ExportSchedulesCsvParams) error
+
+	// (POST /trips/{tripId}/schedules.csv)
+	ImportSchedulesCsv(ctx echo.Context, tripId TripId, params ImportSchedulesCsvParams) error
+
+	// (POST /trips/{tripId}/schedules/import)
+	ImportSchedulesToTrip(ctx echo.Context, tripId TripId, params ImportSchedulesToTripParams) error
+
 	// (DELETE /trips/{tripId}/schedules/{scheduleId})
 	DeleteScheduleForTrip(ctx echo.Context, tripId TripId, scheduleId ScheduleId) error
 
@@ -89,6 +218,24 @@ type ServerInterface interface {
 	// (POST /trips/{tripId}/share)
 	CreateShareLinkForTrip(ctx echo.Context, tripId TripId, params CreateShareLinkForTripParams) error
 
+	// (GET /trips/{tripId}/share-links)
+	ListTripShareLinks(ctx echo.Context, tripId TripId) error
+
+	// (POST /trips/{tripId}/share-links)
+	CreateTripShareLink(ctx echo.Context, tripId TripId) error
+
+	// (DELETE /trips/{tripId}/share-links/{shareLinkId})
+	RevokeTripShareLink(ctx echo.Context, tripId TripId, shareLinkId ShareLinkId) error
+
+	// (GET /trips/{tripId}/share/activity)
+	GetTripShareActivity(ctx echo.Context, tripId TripId, params GetTripShareActivityParams) error
+
+	// (POST /users/email/confirm/{emailChangeToken})
+	ConfirmEmailChange(ctx echo.Context, emailChangeToken string) error
+
+	// (POST /users/verify/resend)
+	ResendVerificationEmail(ctx echo.Context) error
+
 	// (POST /users/verify/{verificationToken})
 	VerifyUser(ctx echo.Context, verificationToken string) error
 }
@@ -98,6 +245,113 @@ type ServerInterfaceWrapper struct {
 	Handler ServerInterface
 }
 
+// GetJWKS converts echo context to params.
+func (w *ServerInterfaceWrapper) GetJWKS(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetJWKS(ctx)
+	return err
+}
+
+// HandleOIDCCallback converts echo context to params.
+func (w *ServerInterfaceWrapper) HandleOIDCCallback(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "provider" -------------
+	var provider string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "provider", ctx.Param("provider"), &provider, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter provider: %s", err))
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params HandleOIDCCallbackParams
+	// ------------- Optional query parameter "code" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "code", ctx.QueryParams(), &params.Code)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter code: %s", err))
+	}
+
+	// ------------- Optional query parameter "state" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "state", ctx.QueryParams(), &params.State)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter state: %s", err))
+	}
+
+	// ------------- Optional query parameter "error" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "error", ctx.QueryParams(), &params.Error)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter error: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.HandleOIDCCallback(ctx, provider, params)
+	return err
+}
+
+// StartOIDCLogin converts echo context to params.
+func (w *ServerInterfaceWrapper) StartOIDCLogin(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "provider" -------------
+	var provider string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "provider", ctx.Param("provider"), &provider, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter provider: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.StartOIDCLogin(ctx, provider)
+	return err
+}
+
+// RefreshAuthToken converts echo context to params.
+func (w *ServerInterfaceWrapper) RefreshAuthToken(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.RefreshAuthToken(ctx)
+	return err
+}
+
+// GetCalendarFeedCalendar converts echo context to params.
+func (w *ServerInterfaceWrapper) GetCalendarFeedCalendar(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "feedToken" -------------
+	var feedToken string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "feedToken", ctx.Param("feedToken"), &feedToken, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter feedToken: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetCalendarFeedCalendar(ctx, feedToken)
+	return err
+}
+
+// AcceptTripInvitation converts echo context to params.
+func (w *ServerInterfaceWrapper) AcceptTripInvitation(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "invitationToken" -------------
+	var invitationToken string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "invitationToken", ctx.Param("invitationToken"), &invitationToken, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter invitationToken: %s", err))
+	}
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.AcceptTripInvitation(ctx, invitationToken)
+	return err
+}
+
 // LoginUser converts echo context to params.
 func (w *ServerInterfaceWrapper) LoginUser(ctx echo.Context) error {
 	var err error
@@ -107,6 +361,15 @@ func (w *ServerInterfaceWrapper) LoginUser(ctx echo.Context) error {
 	return err
 }
 
+// LoginWithTwoFactor converts echo context to params.
+func (w *ServerInterfaceWrapper) LoginWithTwoFactor(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.LoginWithTwoFactor(ctx)
+	return err
+}
+
 // LogoutUser converts echo context to params.
 func (w *ServerInterfaceWrapper) LogoutUser(ctx echo.Context) error {
 	var err error
@@ -118,6 +381,28 @@ func (w *ServerInterfaceWrapper) LogoutUser(ctx echo.Context) error {
 	return err
 }
 
+// LogoutAllSessions converts echo context to params.
+func (w *ServerInterfaceWrapper) LogoutAllSessions(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.LogoutAllSessions(ctx)
+	return err
+}
+
+// DeleteMe converts echo context to params.
+func (w *ServerInterfaceWrapper) DeleteMe(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.DeleteMe(ctx)
+	return err
+}
+
 // GetMe converts echo context to params.
 func (w *ServerInterfaceWrapper) GetMe(ctx echo.Context) error {
 	var err error
@@ -129,6 +414,94 @@ func (w *ServerInterfaceWrapper) GetMe(ctx echo.Context) error {
 	return err
 }
 
+// ConfirmTwoFactor converts echo context to params.
+func (w *ServerInterfaceWrapper) ConfirmTwoFactor(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.ConfirmTwoFactor(ctx)
+	return err
+}
+
+// SetupTwoFactor converts echo context to params.
+func (w *ServerInterfaceWrapper) SetupTwoFactor(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.SetupTwoFactor(ctx)
+	return err
+}
+
+// RevokeCalendarFeed converts echo context to params.
+func (w *ServerInterfaceWrapper) RevokeCalendarFeed(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.RevokeCalendarFeed(ctx)
+	return err
+}
+
+// GetCalendarFeed converts echo context to params.
+func (w *ServerInterfaceWrapper) GetCalendarFeed(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetCalendarFeed(ctx)
+	return err
+}
+
+// CreateCalendarFeed converts echo context to params.
+func (w *ServerInterfaceWrapper) CreateCalendarFeed(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.CreateCalendarFeed(ctx)
+	return err
+}
+
+// ChangeEmail converts echo context to params.
+func (w *ServerInterfaceWrapper) ChangeEmail(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.ChangeEmail(ctx)
+	return err
+}
+
+// ExportPersonalData converts echo context to params.
+func (w *ServerInterfaceWrapper) ExportPersonalData(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.ExportPersonalData(ctx)
+	return err
+}
+
+// ListMyMemberships converts echo context to params.
+func (w *ServerInterfaceWrapper) ListMyMemberships(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.ListMyMemberships(ctx)
+	return err
+}
+
 // ChangePassword converts echo context to params.
 func (w *ServerInterfaceWrapper) ChangePassword(ctx echo.Context) error {
 	var err error
@@ -140,6 +513,64 @@ func (w *ServerInterfaceWrapper) ChangePassword(ctx echo.Context) error {
 	return err
 }
 
+// ListPersonalAccessTokens converts echo context to params.
+func (w *ServerInterfaceWrapper) ListPersonalAccessTokens(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.ListPersonalAccessTokens(ctx)
+	return err
+}
+
+// CreatePersonalAccessToken converts echo context to params.
+func (w *ServerInterfaceWrapper) CreatePersonalAccessToken(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.CreatePersonalAccessToken(ctx)
+	return err
+}
+
+// RevokePersonalAccessToken converts echo context to params.
+func (w *ServerInterfaceWrapper) RevokePersonalAccessToken(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "tokenId" -------------
+	var tokenId TokenId
+
+	err = runtime.BindStyledParameterWithOptions("simple", "tokenId", ctx.Param("tokenId"), &tokenId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter tokenId: %s", err))
+	}
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.RevokePersonalAccessToken(ctx, tokenId)
+	return err
+}
+
+// RequestPasswordReset converts echo context to params.
+func (w *ServerInterfaceWrapper) RequestPasswordReset(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.RequestPasswordReset(ctx)
+	return err
+}
+
+// ResetPassword converts echo context to params.
+func (w *ServerInterfaceWrapper) ResetPassword(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.ResetPassword(ctx)
+	return err
+}
+
 // GetPublicTripByShareToken converts echo context to params.
 func (w *ServerInterfaceWrapper) GetPublicTripByShareToken(ctx echo.Context) error {
 	var err error
@@ -172,6 +603,22 @@ func (w *ServerInterfaceWrapper) UpdatePublicTripByShareToken(ctx echo.Context)
 	return err
 }
 
+// GetPublicTripCalendar converts echo context to params.
+func (w *ServerInterfaceWrapper) GetPublicTripCalendar(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "shareToken" -------------
+	var shareToken ShareToken
+
+	err = runtime.BindStyledParameterWithOptions("simple", "shareToken", ctx.Param("shareToken"), &shareToken, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter shareToken: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetPublicTripCalendar(ctx, shareToken)
+	return err
+}
+
 // GetTripDetailsForPublicTrip converts echo context to params.
 func (w *ServerInterfaceWrapper) GetTripDetailsForPublicTrip(ctx echo.Context) error {
 	var err error
@@ -180,151 +627,554 @@ func (w *ServerInterfaceWrapper) GetTripDetailsForPublicTrip(ctx echo.Context) e
 
 	err = runtime.BindStyledParameterWithOptions("simple", "shareToken", ctx.Param("shareToken"), &shareToken, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter shareToken: %s", err))
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter shareToken: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetTripDetailsForPublicTrip(ctx, shareToken)
+	return err
+}
+
+// ClaimMemberForPublicTrip converts echo context to params.
+func (w *ServerInterfaceWrapper) ClaimMemberForPublicTrip(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "shareToken" -------------
+	var shareToken ShareToken
+
+	err = runtime.BindStyledParameterWithOptions("simple", "shareToken", ctx.Param("shareToken"), &shareToken, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter shareToken: %s", err))
+	}
+
+	// ------------- Path parameter "memberId" -------------
+	var memberId MemberId
+
+	err = runtime.BindStyledParameterWithOptions("simple", "memberId", ctx.Param("memberId"), &memberId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter memberId: %s", err))
+	}
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.ClaimMemberForPublicTrip(ctx, shareToken, memberId)
+	return err
+}
+
+// GetSchedulesForPublicTrip converts echo context to params.
+func (w *ServerInterfaceWrapper) GetSchedulesForPublicTrip(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "shareToken" -------------
+	var shareToken ShareToken
+
+	err = runtime.BindStyledParameterWithOptions("simple", "shareToken", ctx.Param("shareToken"), &shareToken, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter shareToken: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetSchedulesForPublicTrip(ctx, shareToken)
+	return err
+}
+
+// AddScheduleToPublicTrip converts echo context to params.
+func (w *ServerInterfaceWrapper) AddScheduleToPublicTrip(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "shareToken" -------------
+	var shareToken ShareToken
+
+	err = runtime.BindStyledParameterWithOptions("simple", "shareToken", ctx.Param("shareToken"), &shareToken, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter shareToken: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.AddScheduleToPublicTrip(ctx, shareToken)
+	return err
+}
+
+// DeleteScheduleForPublicTrip converts echo context to params.
+func (w *ServerInterfaceWrapper) DeleteScheduleForPublicTrip(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "shareToken" -------------
+	var shareToken ShareToken
+
+	err = runtime.BindStyledParameterWithOptions("simple", "shareToken", ctx.Param("shareToken"), &shareToken, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter shareToken: %s", err))
+	}
+
+	// ------------- Path parameter "scheduleId" -------------
+	var scheduleId ScheduleId
+
+	err = runtime.BindStyledParameterWithOptions("simple", "scheduleId", ctx.Param("scheduleId"), &scheduleId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter scheduleId: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.DeleteScheduleForPublicTrip(ctx, shareToken, scheduleId)
+	return err
+}
+
+// GetScheduleForPublicTrip converts echo context to params.
+func (w *ServerInterfaceWrapper) GetScheduleForPublicTrip(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "shareToken" -------------
+	var shareToken ShareToken
+
+	err = runtime.BindStyledParameterWithOptions("simple", "shareToken", ctx.Param("shareToken"), &shareToken, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter shareToken: %s", err))
+	}
+
+	// ------------- Path parameter "scheduleId" -------------
+	var scheduleId ScheduleId
+
+	err = runtime.BindStyledParameterWithOptions("simple", "scheduleId", ctx.Param("scheduleId"), &scheduleId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter scheduleId: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetScheduleForPublicTrip(ctx, shareToken, scheduleId)
+	return err
+}
+
+// UpdateScheduleForPublicTrip converts echo context to params.
+func (w *ServerInterfaceWrapper) UpdateScheduleForPublicTrip(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "shareToken" -------------
+	var shareToken ShareToken
+
+	err = runtime.BindStyledParameterWithOptions("simple", "shareToken", ctx.Param("shareToken"), &shareToken, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter shareToken: %s", err))
+	}
+
+	// ------------- Path parameter "scheduleId" -------------
+	var scheduleId ScheduleId
+
+	err = runtime.BindStyledParameterWithOptions("simple", "scheduleId", ctx.Param("scheduleId"), &scheduleId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter scheduleId: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.UpdateScheduleForPublicTrip(ctx, shareToken, scheduleId)
+	return err
+}
+
+// UnlockPublicTrip converts echo context to params.
+func (w *ServerInterfaceWrapper) UnlockPublicTrip(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "shareToken" -------------
+	var shareToken ShareToken
+
+	err = runtime.BindStyledParameterWithOptions("simple", "shareToken", ctx.Param("shareToken"), &shareToken, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter shareToken: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.UnlockPublicTrip(ctx, shareToken)
+	return err
+}
+
+// CreateUser converts echo context to params.
+func (w *ServerInterfaceWrapper) CreateUser(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.CreateUser(ctx)
+	return err
+}
+
+// GetUserTrips converts echo context to params.
+func (w *ServerInterfaceWrapper) GetUserTrips(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetUserTrips(ctx)
+	return err
+}
+
+// CreateUserTrip converts echo context to params.
+func (w *ServerInterfaceWrapper) CreateUserTrip(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.CreateUserTrip(ctx)
+	return err
+}
+
+// ImportTrip converts echo context to params.
+func (w *ServerInterfaceWrapper) ImportTrip(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.ImportTrip(ctx)
+	return err
+}
+
+// DeleteUserTrip converts echo context to params.
+func (w *ServerInterfaceWrapper) DeleteUserTrip(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "tripId" -------------
+	var tripId TripId
+
+	err = runtime.BindStyledParameterWithOptions("simple", "tripId", ctx.Param("tripId"), &tripId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter tripId: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.DeleteUserTrip(ctx, tripId)
+	return err
+}
+
+// GetUserTrip converts echo context to params.
+func (w *ServerInterfaceWrapper) GetUserTrip(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "tripId" -------------
+	var tripId TripId
+
+	err = runtime.BindStyledParameterWithOptions("simple", "tripId", ctx.Param("tripId"), &tripId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter tripId: %s", err))
+	}
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetUserTrip(ctx, tripId)
+	return err
+}
+
+// UpdateUserTrip converts echo context to params.
+func (w *ServerInterfaceWrapper) UpdateUserTrip(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "tripId" -------------
+	var tripId TripId
+
+	err = runtime.BindStyledParameterWithOptions("simple", "tripId", ctx.Param("tripId"), &tripId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter tripId: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.UpdateUserTrip(ctx, tripId)
+	return err
+}
+
+// GetTripCalendar converts echo context to params.
+func (w *ServerInterfaceWrapper) GetTripCalendar(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "tripId" -------------
+	var tripId TripId
+
+	err = runtime.BindStyledParameterWithOptions("simple", "tripId", ctx.Param("tripId"), &tripId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter tripId: %s", err))
+	}
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetTripCalendar(ctx, tripId)
+	return err
+}
+
+// ListTripCollaborators converts echo context to params.
+func (w *ServerInterfaceWrapper) ListTripCollaborators(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "tripId" -------------
+	var tripId TripId
+
+	err = runtime.BindStyledParameterWithOptions("simple", "tripId", ctx.Param("tripId"), &tripId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter tripId: %s", err))
+	}
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.ListTripCollaborators(ctx, tripId)
+	return err
+}
+
+// AddTripCollaborator converts echo context to params.
+func (w *ServerInterfaceWrapper) AddTripCollaborator(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "tripId" -------------
+	var tripId TripId
+
+	err = runtime.BindStyledParameterWithOptions("simple", "tripId", ctx.Param("tripId"), &tripId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter tripId: %s", err))
+	}
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.AddTripCollaborator(ctx, tripId)
+	return err
+}
+
+// RemoveTripCollaborator converts echo context to params.
+func (w *ServerInterfaceWrapper) RemoveTripCollaborator(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "tripId" -------------
+	var tripId TripId
+
+	err = runtime.BindStyledParameterWithOptions("simple", "tripId", ctx.Param("tripId"), &tripId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter tripId: %s", err))
+	}
+
+	// ------------- Path parameter "userId" -------------
+	var userId UserId
+
+	err = runtime.BindStyledParameterWithOptions("simple", "userId", ctx.Param("userId"), &userId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter userId: %s", err))
+	}
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.RemoveTripCollaborator(ctx, tripId, userId)
+	return err
+}
+
+// UpdateTripCollaborator converts echo context to params.
+func (w *ServerInterfaceWrapper) UpdateTripCollaborator(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "tripId" -------------
+	var tripId TripId
+
+	err = runtime.BindStyledParameterWithOptions("simple", "tripId", ctx.Param("tripId"), &tripId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter tripId: %s", err))
+	}
+
+	// ------------- Path parameter "userId" -------------
+	var userId UserId
+
+	err = runtime.BindStyledParameterWithOptions("simple", "userId", ctx.Param("userId"), &userId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter userId: %s", err))
+	}
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.UpdateTripCollaborator(ctx, tripId, userId)
+	return err
+}
+
+// GetTripDetails converts echo context to params.
+func (w *ServerInterfaceWrapper) GetTripDetails(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "tripId" -------------
+	var tripId TripId
+
+	err = runtime.BindStyledParameterWithOptions("simple", "tripId", ctx.Param("tripId"), &tripId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter tripId: %s", err))
+	}
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetTripDetails(ctx, tripId)
+	return err
+}
+
+// ExportTrip converts echo context to params.
+func (w *ServerInterfaceWrapper) ExportTrip(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "tripId" -------------
+	var tripId TripId
+
+	err = runtime.BindStyledParameterWithOptions("simple", "tripId", ctx.Param("tripId"), &tripId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter tripId: %s", err))
 	}
 
+	ctx.Set(BearerAuthScopes, []string{})
+
 	// Invoke the callback with all the unmarshaled arguments
-	err = w.Handler.GetTripDetailsForPublicTrip(ctx, shareToken)
+	err = w.Handler.ExportTrip(ctx, tripId)
 	return err
 }
 
-// GetSchedulesForPublicTrip converts echo context to params.
-func (w *ServerInterfaceWrapper) GetSchedulesForPublicTrip(ctx echo.Context) error {
+// ListTripInvitations converts echo context to params.
+func (w *ServerInterfaceWrapper) ListTripInvitations(ctx echo.Context) error {
 	var err error
-	// ------------- Path parameter "shareToken" -------------
-	var shareToken ShareToken
+	// ------------- Path parameter "tripId" -------------
+	var tripId TripId
 
-	err = runtime.BindStyledParameterWithOptions("simple", "shareToken", ctx.Param("shareToken"), &shareToken, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	err = runtime.BindStyledParameterWithOptions("simple", "tripId", ctx.Param("tripId"), &tripId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter shareToken: %s", err))
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter tripId: %s", err))
 	}
 
+	ctx.Set(BearerAuthScopes, []string{})
+
 	// Invoke the callback with all the unmarshaled arguments
-	err = w.Handler.GetSchedulesForPublicTrip(ctx, shareToken)
+	err = w.Handler.ListTripInvitations(ctx, tripId)
 	return err
 }
 
-// AddScheduleToPublicTrip converts echo context to params.
-func (w *ServerInterfaceWrapper) AddScheduleToPublicTrip(ctx echo.Context) error {
+// CreateTripInvitation converts echo context to params.
+func (w *ServerInterfaceWrapper) CreateTripInvitation(ctx echo.Context) error {
 	var err error
-	// ------------- Path parameter "shareToken" -------------
-	var shareToken ShareToken
+	// ------------- Path parameter "tripId" -------------
+	var tripId TripId
 
-	err = runtime.BindStyledParameterWithOptions("simple", "shareToken", ctx.Param("shareToken"), &shareToken, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	err = runtime.BindStyledParameterWithOptions("simple", "tripId", ctx.Param("tripId"), &tripId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter shareToken: %s", err))
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter tripId: %s", err))
 	}
 
+	ctx.Set(BearerAuthScopes, []string{})
+
 	// Invoke the callback with all the unmarshaled arguments
-	err = w.Handler.AddScheduleToPublicTrip(ctx, shareToken)
+	err = w.Handler.CreateTripInvitation(ctx, tripId)
 	return err
 }
 
-// DeleteScheduleForPublicTrip converts echo context to params.
-func (w *ServerInterfaceWrapper) DeleteScheduleForPublicTrip(ctx echo.Context) error {
+// RevokeTripInvitation converts echo context to params.
+func (w *ServerInterfaceWrapper) RevokeTripInvitation(ctx echo.Context) error {
 	var err error
-	// ------------- Path parameter "shareToken" -------------
-	var shareToken ShareToken
+	// ------------- Path parameter "tripId" -------------
+	var tripId TripId
 
-	err = runtime.BindStyledParameterWithOptions("simple", "shareToken", ctx.Param("shareToken"), &shareToken, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	err = runtime.BindStyledParameterWithOptions("simple", "tripId", ctx.Param("tripId"), &tripId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter shareToken: %s", err))
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter tripId: %s", err))
 	}
 
-	// ------------- Path parameter "scheduleId" -------------
-	var scheduleId ScheduleId
+	// ------------- Path parameter "invitationId" -------------
+	var invitationId InvitationId
 
-	err = runtime.BindStyledParameterWithOptions("simple", "scheduleId", ctx.Param("scheduleId"), &scheduleId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	err = runtime.BindStyledParameterWithOptions("simple", "invitationId", ctx.Param("invitationId"), &invitationId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter scheduleId: %s", err))
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter invitationId: %s", err))
 	}
 
+	ctx.Set(BearerAuthScopes, []string{})
+
 	// Invoke the callback with all the unmarshaled arguments
-	err = w.Handler.DeleteScheduleForPublicTrip(ctx, shareToken, scheduleId)
+	err = w.Handler.RevokeTripInvitation(ctx, tripId, invitationId)
 	return err
 }
 
-// GetScheduleForPublicTrip converts echo context to params.
-func (w *ServerInterfaceWrapper) GetScheduleForPublicTrip(ctx echo.Context) error {
+// ResendTripInvitation converts echo context to params.
+func (w *ServerInterfaceWrapper) ResendTripInvitation(ctx echo.Context) error {
 	var err error
-	// ------------- Path parameter "shareToken" -------------
-	var shareToken ShareToken
+	// ------------- Path parameter "tripId" -------------
+	var tripId TripId
 
-	err = runtime.BindStyledParameterWithOptions("simple", "shareToken", ctx.Param("shareToken"), &shareToken, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	err = runtime.BindStyledParameterWithOptions("simple", "tripId", ctx.Param("tripId"), &tripId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter shareToken: %s", err))
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter tripId: %s", err))
 	}
 
-	// ------------- Path parameter "scheduleId" -------------
-	var scheduleId ScheduleId
+	// ------------- Path parameter "invitationId" -------------
+	var invitationId InvitationId
 
-	err = runtime.BindStyledParameterWithOptions("simple", "scheduleId", ctx.Param("scheduleId"), &scheduleId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	err = runtime.BindStyledParameterWithOptions("simple", "invitationId", ctx.Param("invitationId"), &invitationId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter scheduleId: %s", err))
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter invitationId: %s", err))
 	}
 
+	ctx.Set(BearerAuthScopes, []string{})
+
 	// Invoke the callback with all the unmarshaled arguments
-	err = w.Handler.GetScheduleForPublicTrip(ctx, shareToken, scheduleId)
+	err = w.Handler.ResendTripInvitation(ctx, tripId, invitationId)
 	return err
 }
 
-// UpdateScheduleForPublicTrip converts echo context to params.
-func (w *ServerInterfaceWrapper) UpdateScheduleForPublicTrip(ctx echo.Context) error {
+// AddTripMember converts echo context to params.
+func (w *ServerInterfaceWrapper) AddTripMember(ctx echo.Context) error {
 	var err error
-	// ------------- Path parameter "shareToken" -------------
-	var shareToken ShareToken
+	// ------------- Path parameter "tripId" -------------
+	var tripId TripId
 
-	err = runtime.BindStyledParameterWithOptions("simple", "shareToken", ctx.Param("shareToken"), &shareToken, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	err = runtime.BindStyledParameterWithOptions("simple", "tripId", ctx.Param("tripId"), &tripId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter shareToken: %s", err))
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter tripId: %s", err))
 	}
 
-	// ------------- Path parameter "scheduleId" -------------
-	var scheduleId ScheduleId
-
-	err = runtime.BindStyledParameterWithOptions("simple", "scheduleId", ctx.Param("scheduleId"), &scheduleId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
-	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter scheduleId: %s", err))
-	}
+	ctx.Set(BearerAuthScopes, []string{})
 
 	// Invoke the callback with all the unmarshaled arguments
-	err = w.Handler.UpdateScheduleForPublicTrip(ctx, shareToken, scheduleId)
+	err = w.Handler.AddTripMember(ctx, tripId)
 	return err
 }
 
-// CreateUser converts echo context to params.
-func (w *ServerInterfaceWrapper) CreateUser(ctx echo.Context) error {
+// DeleteTripMember converts echo context to params.
+func (w *ServerInterfaceWrapper) DeleteTripMember(ctx echo.Context) error {
 	var err error
+	// ------------- Path parameter "tripId" -------------
+	var tripId TripId
 
-	// Invoke the callback with all the unmarshaled arguments
-	err = w.Handler.CreateUser(ctx)
-	return err
-}
+	err = runtime.BindStyledParameterWithOptions("simple", "tripId", ctx.Param("tripId"), &tripId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter tripId: %s", err))
+	}
 
-// GetUserTrips converts echo context to params.
-func (w *ServerInterfaceWrapper) GetUserTrips(ctx echo.Context) error {
-	var err error
+	// ------------- Path parameter "memberId" -------------
+	var memberId MemberId
+
+	err = runtime.BindStyledParameterWithOptions("simple", "memberId", ctx.Param("memberId"), &memberId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter memberId: %s", err))
+	}
 
 	ctx.Set(BearerAuthScopes, []string{})
 
 	// Invoke the callback with all the unmarshaled arguments
-	err = w.Handler.GetUserTrips(ctx)
+	err = w.Handler.DeleteTripMember(ctx, tripId, memberId)
 	return err
 }
 
-// CreateUserTrip converts echo context to params.
-func (w *ServerInterfaceWrapper) CreateUserTrip(ctx echo.Context) error {
+// UpdateTripMember converts echo context to params.
+func (w *ServerInterfaceWrapper) UpdateTripMember(ctx echo.Context) error {
 	var err error
+	// ------------- Path parameter "tripId" -------------
+	var tripId TripId
+
+	err = runtime.BindStyledParameterWithOptions("simple", "tripId", ctx.Param("tripId"), &tripId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter tripId: %s", err))
+	}
+
+	// ------------- Path parameter "memberId" -------------
+	var memberId MemberId
+
+	err = runtime.BindStyledParameterWithOptions("simple", "memberId", ctx.Param("memberId"), &memberId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter memberId: %s", err))
+	}
 
 	ctx.Set(BearerAuthScopes, []string{})
 
 	// Invoke the callback with all the unmarshaled arguments
-	err = w.Handler.CreateUserTrip(ctx)
+	err = w.Handler.UpdateTripMember(ctx, tripId, memberId)
 	return err
 }
 
-// DeleteUserTrip converts echo context to params.
-func (w *ServerInterfaceWrapper) DeleteUserTrip(ctx echo.Context) error {
+// TransferTripOwnership converts echo context to params.
+func (w *ServerInterfaceWrapper) TransferTripOwnership(ctx echo.Context) error {
 	var err error
 	// ------------- Path parameter "tripId" -------------
 	var tripId TripId
@@ -334,13 +1184,15 @@ func (w *ServerInterfaceWrapper) DeleteUserTrip(ctx echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter tripId: %s", err))
 	}
 
+	ctx.Set(BearerAuthScopes, []string{})
+
 	// Invoke the callback with all the unmarshaled arguments
-	err = w.Handler.DeleteUserTrip(ctx, tripId)
+	err = w.Handler.TransferTripOwnership(ctx, tripId)
 	return err
 }
 
-// GetUserTrip converts echo context to params.
-func (w *ServerInterfaceWrapper) GetUserTrip(ctx echo.Context) error {
+// GetSchedulesForTrip converts echo context to params.
+func (w *ServerInterfaceWrapper) GetSchedulesForTrip(ctx echo.Context) error {
 	var err error
 	// ------------- Path parameter "tripId" -------------
 	var tripId TripId
@@ -353,12 +1205,12 @@ func (w *ServerInterfaceWrapper) GetUserTrip(ctx echo.Context) error {
 	ctx.Set(BearerAuthScopes, []string{})
 
 	// Invoke the callback with all the unmarshaled arguments
-	err = w.Handler.GetUserTrip(ctx, tripId)
+	err = w.Handler.GetSchedulesForTrip(ctx, tripId)
 	return err
 }
 
-// UpdateUserTrip converts echo context to params.
-func (w *ServerInterfaceWrapper) UpdateUserTrip(ctx echo.Context) error {
+// AddScheduleToTrip converts echo context to params.
+func (w *ServerInterfaceWrapper) AddScheduleToTrip(ctx echo.Context) error {
 	var err error
 	// ------------- Path parameter "tripId" -------------
 	var tripId TripId
@@ -368,13 +1220,15 @@ func (w *ServerInterfaceWrapper) UpdateUserTrip(ctx echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter tripId: %s", err))
 	}
 
+	ctx.Set(BearerAuthScopes, []string{})
+
 	// Invoke the callback with all the unmarshaled arguments
-	err = w.Handler.UpdateUserTrip(ctx, tripId)
+	err = w.Handler.AddScheduleToTrip(ctx, tripId)
 	return err
 }
 
-// GetTripDetails converts echo context to params.
-func (w *ServerInterfaceWrapper) GetTripDetails(ctx echo.Context) error {
+// ExportSchedulesCsv converts echo context to params.
+func (w *ServerInterfaceWrapper) ExportSchedulesCsv(ctx echo.Context) error {
 	var err error
 	// ------------- Path parameter "tripId" -------------
 	var tripId TripId
@@ -386,13 +1240,22 @@ func (w *ServerInterfaceWrapper) GetTripDetails(ctx echo.Context) error {
 
 	ctx.Set(BearerAuthScopes, []string{})
 
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ExportSchedulesCsvParams
+	// ------------- Optional query parameter "tz" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "tz", ctx.QueryParams(), &params.Tz)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter tz: %s", err))
+	}
+
 	// Invoke the callback with all the unmarshaled arguments
-	err = w.Handler.GetTripDetails(ctx, tripId)
+	err = w.Handler.ExportSchedulesCsv(ctx, tripId, params)
 	return err
 }
 
-// GetSchedulesForTrip converts echo context to params.
-func (w *ServerInterfaceWrapper) GetSchedulesForTrip(ctx echo.Context) error {
+// ImportSchedulesCsv converts echo context to params.
+func (w *ServerInterfaceWrapper) ImportSchedulesCsv(ctx echo.Context) error {
 	var err error
 	// ------------- Path parameter "tripId" -------------
 	var tripId TripId
@@ -404,13 +1267,29 @@ func (w *ServerInterfaceWrapper) GetSchedulesForTrip(ctx echo.Context) error {
 
 	ctx.Set(BearerAuthScopes, []string{})
 
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ImportSchedulesCsvParams
+	// ------------- Optional query parameter "atomic" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "atomic", ctx.QueryParams(), &params.Atomic)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter atomic: %s", err))
+	}
+
+	// ------------- Optional query parameter "tz" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "tz", ctx.QueryParams(), &params.Tz)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter tz: %s", err))
+	}
+
 	// Invoke the callback with all the unmarshaled arguments
-	err = w.Handler.GetSchedulesForTrip(ctx, tripId)
+	err = w.Handler.ImportSchedulesCsv(ctx, tripId, params)
 	return err
 }
 
-// AddScheduleToTrip converts echo context to params.
-func (w *ServerInterfaceWrapper) AddScheduleToTrip(ctx echo.Context) error {
+// ImportSchedulesToTrip converts echo context to params.
+func (w *ServerInterfaceWrapper) ImportSchedulesToTrip(ctx echo.Context) error {
 	var err error
 	// ------------- Path parameter "tripId" -------------
 	var tripId TripId
@@ -422,8 +1301,24 @@ func (w *ServerInterfaceWrapper) AddScheduleToTrip(ctx echo.Context) error {
 
 	ctx.Set(BearerAuthScopes, []string{})
 
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ImportSchedulesToTripParams
+	// ------------- Optional query parameter "dryRun" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "dryRun", ctx.QueryParams(), &params.DryRun)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter dryRun: %s", err))
+	}
+
+	// ------------- Optional query parameter "tz" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "tz", ctx.QueryParams(), &params.Tz)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter tz: %s", err))
+	}
+
 	// Invoke the callback with all the unmarshaled arguments
-	err = w.Handler.AddScheduleToTrip(ctx, tripId)
+	err = w.Handler.ImportSchedulesToTrip(ctx, tripId, params)
 	return err
 }
 
@@ -532,6 +1427,127 @@ func (w *ServerInterfaceWrapper) CreateShareLinkForTrip(ctx echo.Context) error
 	return err
 }
 
+// ListTripShareLinks converts echo context to params.
+func (w *ServerInterfaceWrapper) ListTripShareLinks(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "tripId" -------------
+	var tripId TripId
+
+	err = runtime.BindStyledParameterWithOptions("simple", "tripId", ctx.Param("tripId"), &tripId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter tripId: %s", err))
+	}
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.ListTripShareLinks(ctx, tripId)
+	return err
+}
+
+// CreateTripShareLink converts echo context to params.
+func (w *ServerInterfaceWrapper) CreateTripShareLink(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "tripId" -------------
+	var tripId TripId
+
+	err = runtime.BindStyledParameterWithOptions("simple", "tripId", ctx.Param("tripId"), &tripId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter tripId: %s", err))
+	}
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.CreateTripShareLink(ctx, tripId)
+	return err
+}
+
+// RevokeTripShareLink converts echo context to params.
+func (w *ServerInterfaceWrapper) RevokeTripShareLink(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "tripId" -------------
+	var tripId TripId
+
+	err = runtime.BindStyledParameterWithOptions("simple", "tripId", ctx.Param("tripId"), &tripId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter tripId: %s", err))
+	}
+
+	// ------------- Path parameter "shareLinkId" -------------
+	var shareLinkId ShareLinkId
+
+	err = runtime.BindStyledParameterWithOptions("simple", "shareLinkId", ctx.Param("shareLinkId"), &shareLinkId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter shareLinkId: %s", err))
+	}
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.RevokeTripShareLink(ctx, tripId, shareLinkId)
+	return err
+}
+
+// GetTripShareActivity converts echo context to params.
+func (w *ServerInterfaceWrapper) GetTripShareActivity(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "tripId" -------------
+	var tripId TripId
+
+	err = runtime.BindStyledParameterWithOptions("simple", "tripId", ctx.Param("tripId"), &tripId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter tripId: %s", err))
+	}
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetTripShareActivityParams
+	// ------------- Optional query parameter "days" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "days", ctx.QueryParams(), &params.Days)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter days: %s", err))
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", ctx.QueryParams(), &params.Limit)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter limit: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetTripShareActivity(ctx, tripId, params)
+	return err
+}
+
+// ConfirmEmailChange converts echo context to params.
+func (w *ServerInterfaceWrapper) ConfirmEmailChange(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "emailChangeToken" -------------
+	var emailChangeToken string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "emailChangeToken", ctx.Param("emailChangeToken"), &emailChangeToken, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter emailChangeToken: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.ConfirmEmailChange(ctx, emailChangeToken)
+	return err
+}
+
+// ResendVerificationEmail converts echo context to params.
+func (w *ServerInterfaceWrapper) ResendVerificationEmail(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.ResendVerificationEmail(ctx)
+	return err
+}
+
 // VerifyUser converts echo context to params.
 func (w *ServerInterfaceWrapper) VerifyUser(ctx echo.Context) error {
 	var err error
@@ -576,31 +1592,80 @@ func RegisterHandlersWithBaseURL(router EchoRouter, si ServerInterface, baseURL
 		Handler: si,
 	}
 
+	router.GET(baseURL+"/.well-known/jwks.json", wrapper.GetJWKS)
+	router.GET(baseURL+"/auth/oidc/:provider/callback", wrapper.HandleOIDCCallback)
+	router.GET(baseURL+"/auth/oidc/:provider/start", wrapper.StartOIDCLogin)
+	router.POST(baseURL+"/auth/refresh", wrapper.RefreshAuthToken)
+	router.GET(baseURL+"/calendar-feeds/:feedToken/calendar.ics", wrapper.GetCalendarFeedCalendar)
+	router.POST(baseURL+"/invitations/:invitationToken/accept", wrapper.AcceptTripInvitation)
 	router.POST(baseURL+"/login", wrapper.LoginUser)
+	router.POST(baseURL+"/login/2fa", wrapper.LoginWithTwoFactor)
 	router.POST(baseURL+"/logout", wrapper.LogoutUser)
+	router.POST(baseURL+"/logout/all", wrapper.LogoutAllSessions)
+	router.DELETE(baseURL+"/me", wrapper.DeleteMe)
 	router.GET(baseURL+"/me", wrapper.GetMe)
+	router.POST(baseURL+"/me/2fa/confirm", wrapper.ConfirmTwoFactor)
+	router.POST(baseURL+"/me/2fa/setup", wrapper.SetupTwoFactor)
+	router.DELETE(baseURL+"/me/calendar-feed", wrapper.RevokeCalendarFeed)
+	router.GET(baseURL+"/me/calendar-feed", wrapper.GetCalendarFeed)
+	router.POST(baseURL+"/me/calendar-feed", wrapper.CreateCalendarFeed)
+	router.PUT(baseURL+"/me/email", wrapper.ChangeEmail)
+	router.GET(baseURL+"/me/export", wrapper.ExportPersonalData)
+	router.GET(baseURL+"/me/memberships", wrapper.ListMyMemberships)
 	router.PUT(baseURL+"/me/password", wrapper.ChangePassword)
+	router.GET(baseURL+"/me/tokens", wrapper.ListPersonalAccessTokens)
+	router.POST(baseURL+"/me/tokens", wrapper.CreatePersonalAccessToken)
+	router.DELETE(baseURL+"/me/tokens/:tokenId", wrapper.RevokePersonalAccessToken)
+	router.POST(baseURL+"/password/forgot", wrapper.RequestPasswordReset)
+	router.POST(baseURL+"/password/reset", wrapper.ResetPassword)
 	router.GET(baseURL+"/public/trips/:shareToken", wrapper.GetPublicTripByShareToken)
 	router.PUT(baseURL+"/public/trips/:shareToken", wrapper.UpdatePublicTripByShareToken)
+	router.GET(baseURL+"/public/trips/:shareToken/calendar.ics", wrapper.GetPublicTripCalendar)
 	router.GET(baseURL+"/public/trips/:shareToken/details", wrapper.GetTripDetailsForPublicTrip)
+	router.POST(baseURL+"/public/trips/:shareToken/members/:memberId/claim", wrapper.ClaimMemberForPublicTrip)
 	router.GET(baseURL+"/public/trips/:shareToken/schedules", wrapper.GetSchedulesForPublicTrip)
 	router.POST(baseURL+"/public/trips/:shareToken/schedules", wrapper.AddScheduleToPublicTrip)
 	router.DELETE(baseURL+"/public/trips/:shareToken/schedules/:scheduleId", wrapper.DeleteScheduleForPublicTrip)
 	router.GET(baseURL+"/public/trips/:shareToken/schedules/:scheduleId", wrapper.GetScheduleForPublicTrip)
 	router.PATCH(baseURL+"/public/trips/:shareToken/schedules/:scheduleId", wrapper.UpdateScheduleForPublicTrip)
+	router.POST(baseURL+"/public/trips/:shareToken/unlock", wrapper.UnlockPublicTrip)
 	router.POST(baseURL+"/signup", wrapper.CreateUser)
 	router.GET(baseURL+"/trips", wrapper.GetUserTrips)
 	router.POST(baseURL+"/trips", wrapper.CreateUserTrip)
+	router.POST(baseURL+"/trips/import", wrapper.ImportTrip)
 	router.DELETE(baseURL+"/trips/:tripId", wrapper.DeleteUserTrip)
 	router.GET(baseURL+"/trips/:tripId", wrapper.GetUserTrip)
 	router.PUT(baseURL+"/trips/:tripId", wrapper.UpdateUserTrip)
+	router.GET(baseURL+"/trips/:tripId/calendar.ics", wrapper.GetTripCalendar)
+	router.GET(baseURL+"/trips/:tripId/collaborators", wrapper.ListTripCollaborators)
+	router.POST(baseURL+"/trips/:tripId/collaborators", wrapper.AddTripCollaborator)
+	router.DELETE(baseURL+"/trips/:tripId/collaborators/:userId", wrapper.RemoveTripCollaborator)
+	router.PUT(baseURL+"/trips/:tripId/collaborators/:userId", wrapper.UpdateTripCollaborator)
 	router.GET(baseURL+"/trips/:tripId/details", wrapper.GetTripDetails)
+	router.GET(baseURL+"/trips/:tripId/export", wrapper.ExportTrip)
+	router.GET(baseURL+"/trips/:tripId/invitations", wrapper.ListTripInvitations)
+	router.POST(baseURL+"/trips/:tripId/invitations", wrapper.CreateTripInvitation)
+	router.DELETE(baseURL+"/trips/:tripId/invitations/:invitationId", wrapper.RevokeTripInvitation)
+	router.POST(baseURL+"/trips/:tripId/invitations/:invitationId/resend", wrapper.ResendTripInvitation)
+	router.POST(baseURL+"/trips/:tripId/members", wrapper.AddTripMember)
+	router.DELETE(baseURL+"/trips/:tripId/members/:memberId", wrapper.DeleteTripMember)
+	router.PATCH(baseURL+"/trips/:tripId/members/:memberId", wrapper.UpdateTripMember)
+	router.PUT(baseURL+"/trips/:tripId/owner", wrapper.TransferTripOwnership)
 	router.GET(baseURL+"/trips/:tripId/schedules", wrapper.GetSchedulesForTrip)
 	router.POST(baseURL+"/trips/:tripId/schedules", wrapper.AddScheduleToTrip)
+	router.GET(baseURL+"/trips/:tripId/schedules.csv", wrapper.ExportSchedulesCsv)
+	router.POST(baseURL+"/trips/:tripId/schedules.csv", wrapper.ImportSchedulesCsv)
+	router.POST(baseURL+"/trips/:tripId/schedules/import", wrapper.ImportSchedulesToTrip)
 	router.DELETE(baseURL+"/trips/:tripId/schedules/:scheduleId", wrapper.DeleteScheduleForTrip)
 	router.GET(baseURL+"/trips/:tripId/schedules/:scheduleId", wrapper.GetScheduleForTrip)
 	router.PATCH(baseURL+"/trips/:tripId/schedules/:scheduleId", wrapper.UpdateScheduleForTrip)
 	router.POST(baseURL+"/trips/:tripId/share", wrapper.CreateShareLinkForTrip)
+	router.GET(baseURL+"/trips/:tripId/share-links", wrapper.ListTripShareLinks)
+	router.POST(baseURL+"/trips/:tripId/share-links", wrapper.CreateTripShareLink)
+	router.DELETE(baseURL+"/trips/:tripId/share-links/:shareLinkId", wrapper.RevokeTripShareLink)
+	router.GET(baseURL+"/trips/:tripId/share/activity", wrapper.GetTripShareActivity)
+	router.POST(baseURL+"/users/email/confirm/:emailChangeToken", wrapper.ConfirmEmailChange)
+	router.POST(baseURL+"/users/verify/resend", wrapper.ResendVerificationEmail)
 	router.POST(baseURL+"/users/verify/:verificationToken", wrapper.VerifyUser)
 
 }
@@ -13,30 +13,109 @@ const (
 	BearerAuthScopes = "BearerAuth.Scopes"
 )
 
+// AccountDeletionRequest defines model for AccountDeletionRequest.
+type AccountDeletionRequest struct {
+	// Password 本人確認のための現在のパスワード
+	Password string `json:"password"`
+}
+
+// AccountDeletionResponse defines model for AccountDeletionResponse.
+type AccountDeletionResponse struct {
+	// DeletionScheduledAt アカウントが削除される日時（この日時までにログインすると取り消し）
+	DeletionScheduledAt time.Time `json:"deletionScheduledAt"`
+}
+
 // AuthResponse defines model for AuthResponse.
 type AuthResponse struct {
-	// Token Authentication token (JWT) for the new user.
+	// ExpiresIn アクセストークンの有効期間（秒）
+	ExpiresIn *int `json:"expiresIn,omitempty"`
+
+	// RefreshToken アクセストークン再発行用のリフレッシュトークン（使用ごとにローテーション）
+	RefreshToken *string `json:"refreshToken,omitempty"`
+
+	// Token Authentication token (JWT, RS256 or EdDSA signed) for the new user.
 	Token *string `json:"token,omitempty"`
 	User  *User   `json:"user,omitempty"`
 }
 
+// CalendarFeed defines model for CalendarFeed.
+type CalendarFeed struct {
+	// CreatedAt 購読URLを発行した日時
+	CreatedAt time.Time `json:"createdAt"`
+
+	// LastUsedAt カレンダーアプリから最後に取得された日時
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty"`
+}
+
+// CalendarFeedCreateResponse defines model for CalendarFeedCreateResponse.
+type CalendarFeedCreateResponse struct {
+	CalendarFeed CalendarFeed `json:"calendarFeed"`
+
+	// Token 購読URLのトークン（再表示不可）
+	Token string `json:"token"`
+
+	// Url カレンダーアプリに登録する購読URL
+	Url string `json:"url"`
+}
+
+// EmailChangeRequest defines model for EmailChangeRequest.
+type EmailChangeRequest struct {
+	// CurrentPassword 現在のパスワード
+	CurrentPassword string `json:"currentPassword"`
+
+	// NewEmail 新しいメールアドレス
+	NewEmail openapi_types.Email `json:"newEmail"`
+}
+
 // Error defines model for Error.
 type Error struct {
 	Message *string `json:"message,omitempty"`
 }
 
+// JSONWebKey 公開鍵。RSA鍵はn・e、Ed25519鍵はcrv・xを持つ
+type JSONWebKey struct {
+	// Alg 署名アルゴリズム（RS256 または EdDSA）
+	Alg string  `json:"alg"`
+	Crv *string `json:"crv,omitempty"`
+
+	// E RSAの公開指数（base64url）
+	E *string `json:"e,omitempty"`
+
+	// Kid 鍵ID（JWTヘッダーのkid）
+	Kid string `json:"kid"`
+
+	// Kty 鍵の種類（RSA または OKP）
+	Kty string `json:"kty"`
+
+	// N RSAのモジュラス（base64url）
+	N   *string `json:"n,omitempty"`
+	Use string  `json:"use"`
+
+	// X Ed25519の公開鍵（base64url）
+	X *string `json:"x,omitempty"`
+}
+
+// JSONWebKeySet defines model for JSONWebKeySet.
+type JSONWebKeySet struct {
+	Keys []JSONWebKey `json:"keys"`
+}
+
 // LoginRequest defines model for LoginRequest.
 type LoginRequest struct {
 	Email openapi_types.Email `json:"email"`
 
-	// Password メールで受け取った初期パスワード
+	// Password パスワード
 	Password string `json:"password"`
 }
 
 // Member defines model for Member.
 type Member struct {
+	// Id 旅行の更新時に指定すると既存のメンバーとして名前を変更（省略すると追加、含めなかったメンバーは削除）
 	Id   *openapi_types.UUID `json:"id,omitempty"`
 	Name *string             `json:"name,omitempty"`
+
+	// UserId メンバーに紐付いた登録ユーザーのID（招待の承諾で紐付く）
+	UserId *openapi_types.UUID `json:"userId,omitempty"`
 }
 
 // Message defines model for Message.
@@ -66,10 +145,78 @@ type PasswordChangeRequest struct {
 	// CurrentPassword 現在のパスワード
 	CurrentPassword string `json:"currentPassword"`
 
-	// NewPassword 新しいパスワード
+	// NewPassword 新しいパスワード（UTF-8で72バイトまで）
 	NewPassword string `json:"newPassword"`
 }
 
+// PasswordForgotRequest defines model for PasswordForgotRequest.
+type PasswordForgotRequest struct {
+	Email openapi_types.Email `json:"email"`
+}
+
+// PasswordResetRequest defines model for PasswordResetRequest.
+type PasswordResetRequest struct {
+	// NewPassword 新しいパスワード（UTF-8で72バイトまで）
+	NewPassword string `json:"newPassword"`
+
+	// Token メールで受け取ったパスワード再設定トークン
+	Token string `json:"token"`
+}
+
+// PersonalAccessToken defines model for PersonalAccessToken.
+type PersonalAccessToken struct {
+	CreatedAt time.Time `json:"createdAt"`
+
+	// ExpiresAt 有効期限（未指定の場合は無期限）
+	ExpiresAt *time.Time         `json:"expiresAt,omitempty"`
+	Id        openapi_types.UUID `json:"id"`
+
+	// LastUsedAt 最後に使用された日時
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty"`
+	Name       string     `json:"name"`
+	Scopes     []string   `json:"scopes"`
+}
+
+// PersonalAccessTokenCreateRequest defines model for PersonalAccessTokenCreateRequest.
+type PersonalAccessTokenCreateRequest struct {
+	// ExpiresAt 有効期限（省略時は無期限）
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+
+	// Name 用途を識別するための名前
+	Name string `json:"name"`
+
+	// Scopes 付与するスコープ（trips:read, trips:write）
+	Scopes []string `json:"scopes"`
+}
+
+// PersonalAccessTokenCreateResponse defines model for PersonalAccessTokenCreateResponse.
+type PersonalAccessTokenCreateResponse struct {
+	PersonalAccessToken PersonalAccessToken `json:"personalAccessToken"`
+
+	// Token Authorizationヘッダーに指定するトークン（再表示不可）
+	Token string `json:"token"`
+}
+
+// PersonalDataExport defines model for PersonalDataExport.
+type PersonalDataExport struct {
+	ExportedAt time.Time                `json:"exportedAt"`
+	Trips      []PersonalDataExportTrip `json:"trips"`
+	User       User                     `json:"user"`
+}
+
+// PersonalDataExportTrip defines model for PersonalDataExportTrip.
+type PersonalDataExportTrip struct {
+	Schedules  []Schedule           `json:"schedules"`
+	ShareLinks *[]ShareLinkMetadata `json:"shareLinks,omitempty"`
+	Trip       Trip                 `json:"trip"`
+}
+
+// RefreshTokenRequest defines model for RefreshTokenRequest.
+type RefreshTokenRequest struct {
+	// RefreshToken ログインまたは前回のリフレッシュで取得したリフレッシュトークン
+	RefreshToken string `json:"refreshToken"`
+}
+
 // Schedule defines model for Schedule.
 type Schedule struct {
 	CreatedAt     *time.Time          `json:"createdAt,omitempty"`
@@ -81,9 +228,173 @@ type Schedule struct {
 	UpdatedAt     *time.Time          `json:"updatedAt,omitempty"`
 }
 
+// ScheduleCsvImportReport defines model for ScheduleCsvImportReport.
+type ScheduleCsvImportReport struct {
+	Atomic bool `json:"atomic"`
+
+	// Created 作成したスケジュールの数
+	Created int `json:"created"`
+
+	// Failed エラーになった行の数
+	Failed int `json:"failed"`
+
+	// Rows ファイル内の順に並んだ行ごとの結果（空行は含まない）
+	Rows []ScheduleCsvImportRow `json:"rows"`
+
+	// Skipped atomicで他の行がエラーになったため作成しなかった正しい行の数
+	Skipped int `json:"skipped"`
+}
+
+// ScheduleCsvImportRow defines model for ScheduleCsvImportRow.
+type ScheduleCsvImportRow struct {
+	// Error statusがerrorの場合、取り込めなかった理由
+	Error *string `json:"error,omitempty"`
+
+	// Line ファイル内の行番号（ヘッダーが1行目）
+	Line     int       `json:"line"`
+	Schedule *Schedule `json:"schedule,omitempty"`
+
+	// Status created、skipped、errorのいずれか
+	Status string  `json:"status"`
+	Title  *string `json:"title,omitempty"`
+}
+
+// ScheduleImportReport defines model for ScheduleImportReport.
+type ScheduleImportReport struct {
+	// Created 作成した（ドライランでは作成される）スケジュールの数
+	Created int `json:"created"`
+
+	// DryRun trueの場合、スケジュールは保存されていない
+	DryRun bool `json:"dryRun"`
+
+	// Duplicates 重複としてスキップした予定の数
+	Duplicates int `json:"duplicates"`
+
+	// Failed 取り込めなかった予定の数
+	Failed int `json:"failed"`
+
+	// Results ファイル内の順に並んだ予定ごとの結果
+	Results []ScheduleImportResult `json:"results"`
+}
+
+// ScheduleImportResult defines model for ScheduleImportResult.
+type ScheduleImportResult struct {
+	// Error statusがerrorの場合、取り込めなかった理由
+	Error *string `json:"error,omitempty"`
+
+	// Index ファイル内でのVEVENTの位置（0始まり）
+	Index    int       `json:"index"`
+	Schedule *Schedule `json:"schedule,omitempty"`
+
+	// Status created、would_create（ドライラン）、duplicate、errorのいずれか
+	Status  string  `json:"status"`
+	Summary *string `json:"summary,omitempty"`
+	Uid     *string `json:"uid,omitempty"`
+}
+
+// ShareActivity defines model for ShareActivity.
+type ShareActivity struct {
+	// Daily 古い日から順に、アクセスのない日も含む
+	Daily []ShareActivityDay `json:"daily"`
+
+	// RecentEdits 共有リンクで行われた編集（新しい順）
+	RecentEdits []ShareLinkEdit `json:"recentEdits"`
+}
+
+// ShareActivityDay defines model for ShareActivityDay.
+type ShareActivityDay struct {
+	Date openapi_types.Date `json:"date"`
+
+	// Requests 共有リンクでのリクエスト数
+	Requests int `json:"requests"`
+
+	// Visitors 異なるIPアドレスの数
+	Visitors int `json:"visitors"`
+}
+
+// ShareGrantResponse defines model for ShareGrantResponse.
+type ShareGrantResponse struct {
+	ExpiresAt time.Time `json:"expiresAt"`
+
+	// GrantToken 共有リンクのAPIに X-Share-Grant ヘッダーで送るアクセス許可トークン
+	GrantToken string `json:"grantToken"`
+}
+
+// ShareLink defines model for ShareLink.
+type ShareLink struct {
+	CreatedAt  time.Time          `json:"createdAt"`
+	ExpiresAt  *time.Time         `json:"expiresAt,omitempty"`
+	Id         openapi_types.UUID `json:"id"`
+	LastUsedAt *time.Time         `json:"lastUsedAt,omitempty"`
+	MaxUses    *int               `json:"maxUses,omitempty"`
+	Name       string             `json:"name"`
+
+	// PasswordProtected パスフレーズが設定されているか
+	PasswordProtected bool `json:"passwordProtected"`
+
+	// Scope 共有リンクで許可する操作の範囲（view / comment / edit_schedules / edit_trip）
+	Scope string `json:"scope"`
+
+	// UseCount 共有リンクが使用された回数（GET /public/trips/{shareToken} とページ表示の回数）
+	UseCount int `json:"useCount"`
+}
+
+// ShareLinkCreateRequest defines model for ShareLinkCreateRequest.
+type ShareLinkCreateRequest struct {
+	// ExpiresAt 有効期限（省略時は無期限）
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+
+	// MaxUses 利用回数（訪問回数）の上限（省略時は無制限）。上限に達すると新しい訪問はできず、訪問トークン（X-Share-Visit）のないリクエストも拒否する。上限までに始めた訪問は訪問トークンの有効期間（30分）中は続けられる
+	MaxUses *int `json:"maxUses,omitempty"`
+
+	// Name 共有リンクの名前（共有相手の区別に使用）
+	Name string `json:"name"`
+
+	// Passphrase パスフレーズ（省略時はなし）。設定した場合、アクセスには POST /public/trips/{shareToken}/unlock が必要
+	Passphrase *string `json:"passphrase,omitempty"`
+
+	// Scope 共有リンクで許可する操作の範囲（view / comment / edit_schedules / edit_trip）
+	Scope string `json:"scope"`
+}
+
+// ShareLinkEdit defines model for ShareLinkEdit.
+type ShareLinkEdit struct {
+	AccessedAt time.Time `json:"accessedAt"`
+	Method     string    `json:"method"`
+	Route      string    `json:"route"`
+
+	// ShareLinkId 失効した共有リンクの場合は含まない
+	ShareLinkId   *openapi_types.UUID `json:"shareLinkId,omitempty"`
+	ShareLinkName string              `json:"shareLinkName"`
+	Status        int                 `json:"status"`
+
+	// UserAgentFamily ブラウザの種類（Chrome, Safari, Firefox, Edge, Opera, curl, Bot, Other, Unknown）
+	UserAgentFamily string `json:"userAgentFamily"`
+}
+
+// ShareLinkMetadata defines model for ShareLinkMetadata.
+type ShareLinkMetadata struct {
+	CreatedAt time.Time  `json:"createdAt"`
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+	MaxUses   *int       `json:"maxUses,omitempty"`
+	Name      string     `json:"name"`
+	Scope     string     `json:"scope"`
+	UpdatedAt time.Time  `json:"updatedAt"`
+}
+
 // ShareLinkResponse defines model for ShareLinkResponse.
 type ShareLinkResponse struct {
-	CreatedAt *time.Time `json:"createdAt,omitempty"`
+	CreatedAt *time.Time          `json:"createdAt,omitempty"`
+	ExpiresAt *time.Time          `json:"expiresAt,omitempty"`
+	Id        *openapi_types.UUID `json:"id,omitempty"`
+	MaxUses   *int                `json:"maxUses,omitempty"`
+	Name      *string             `json:"name,omitempty"`
+
+	// PasswordProtected パスフレーズが設定されているか
+	PasswordProtected *bool `json:"passwordProtected,omitempty"`
+
+	// Scope 共有リンクで許可する操作の範囲
+	Scope *string `json:"scope,omitempty"`
 
 	// ShareToken 生成された共有用トークン
 	ShareToken *string `json:"shareToken,omitempty"`
@@ -93,23 +404,181 @@ type ShareLinkResponse struct {
 	UpdatedAt *time.Time `json:"updatedAt,omitempty"`
 }
 
+// ShareLinkUnlockRequest defines model for ShareLinkUnlockRequest.
+type ShareLinkUnlockRequest struct {
+	Passphrase string `json:"passphrase"`
+}
+
 // Trip defines model for Trip.
 type Trip struct {
 	CreatedAt *time.Time          `json:"createdAt,omitempty"`
 	EndDate   *openapi_types.Date `json:"endDate,omitempty"`
 	Id        *openapi_types.UUID `json:"id,omitempty"`
 	Members   *[]Member           `json:"members,omitempty"`
+
+	// Role ログイン中のユーザーの権限（owner / editor / viewer）
+	Role      *string             `json:"role,omitempty"`
 	StartDate *openapi_types.Date `json:"startDate,omitempty"`
 	Title     *string             `json:"title,omitempty"`
 	UpdatedAt *time.Time          `json:"updatedAt,omitempty"`
 }
 
+// TripBackup defines model for TripBackup.
+type TripBackup struct {
+	ExportedAt time.Time            `json:"exportedAt"`
+	Format     string               `json:"format"`
+	Members    []TripBackupMember   `json:"members"`
+	Schedules  []TripBackupSchedule `json:"schedules"`
+	Trip       TripBackupTrip       `json:"trip"`
+
+	// Version 形式のバージョン。取り込みでは古いバージョンも受け付けます
+	Version int `json:"version"`
+}
+
+// TripBackupMember defines model for TripBackupMember.
+type TripBackupMember struct {
+	Name string `json:"name"`
+
+	// Ref 文書内でメンバーを識別する値
+	Ref string `json:"ref"`
+}
+
+// TripBackupSchedule defines model for TripBackupSchedule.
+type TripBackupSchedule struct {
+	EndDateTime   time.Time `json:"endDateTime"`
+	Memo          *string   `json:"memo,omitempty"`
+	StartDateTime time.Time `json:"startDateTime"`
+	Title         string    `json:"title"`
+}
+
+// TripBackupTrip defines model for TripBackupTrip.
+type TripBackupTrip struct {
+	EndDate openapi_types.Date `json:"endDate"`
+
+	// OwnerMemberRef 所有者に紐付いていたメンバーのref
+	OwnerMemberRef *string            `json:"ownerMemberRef"`
+	StartDate      openapi_types.Date `json:"startDate"`
+	Title          string             `json:"title"`
+}
+
+// TripCollaborator defines model for TripCollaborator.
+type TripCollaborator struct {
+	CreatedAt time.Time           `json:"createdAt"`
+	Email     openapi_types.Email `json:"email"`
+	Name      string              `json:"name"`
+
+	// Role 権限（owner / editor / viewer）
+	Role   string             `json:"role"`
+	UserId openapi_types.UUID `json:"userId"`
+}
+
+// TripCollaboratorCreateRequest defines model for TripCollaboratorCreateRequest.
+type TripCollaboratorCreateRequest struct {
+	// Email 追加する登録済みユーザーのメールアドレス
+	Email openapi_types.Email `json:"email"`
+
+	// Role 付与する権限（editor / viewer）
+	Role string `json:"role"`
+}
+
+// TripCollaboratorUpdateRequest defines model for TripCollaboratorUpdateRequest.
+type TripCollaboratorUpdateRequest struct {
+	// Role 変更後の権限（editor / viewer）
+	Role string `json:"role"`
+}
+
 // TripDetailView defines model for TripDetailView.
 type TripDetailView struct {
 	Schedules *[]Schedule `json:"schedules,omitempty"`
 	Trip      *Trip       `json:"trip,omitempty"`
 }
 
+// TripInvitation defines model for TripInvitation.
+type TripInvitation struct {
+	CreatedAt time.Time           `json:"createdAt"`
+	Email     openapi_types.Email `json:"email"`
+	ExpiresAt time.Time           `json:"expiresAt"`
+	Id        openapi_types.UUID  `json:"id"`
+
+	// MemberName 承諾したユーザーを紐付けるメンバーの名前
+	MemberName *string `json:"memberName,omitempty"`
+
+	// Role 承諾後の権限（editor / viewer）
+	Role string `json:"role"`
+
+	// SentAt 最後に招待メールを送信した日時
+	SentAt time.Time `json:"sentAt"`
+}
+
+// TripInvitationCreateRequest defines model for TripInvitationCreateRequest.
+type TripInvitationCreateRequest struct {
+	Email openapi_types.Email `json:"email"`
+
+	// MemberName 承諾したユーザーを紐付けるメンバーの名前（旅行のメンバーに同じ名前が必要）
+	MemberName *string `json:"memberName,omitempty"`
+
+	// Role 承諾後の権限（editor / viewer）
+	Role string `json:"role"`
+}
+
+// TripMemberRequest defines model for TripMemberRequest.
+type TripMemberRequest struct {
+	Name string `json:"name"`
+}
+
+// TripMembership defines model for TripMembership.
+type TripMembership struct {
+	// MemberId ユーザーが紐付いているメンバーのID
+	MemberId   openapi_types.UUID `json:"memberId"`
+	MemberName string             `json:"memberName"`
+	Trip       Trip               `json:"trip"`
+}
+
+// TripOwnershipTransferRequest defines model for TripOwnershipTransferRequest.
+type TripOwnershipTransferRequest struct {
+	// UserId 新しいownerのユーザーID（共同編集者である必要がある）
+	UserId openapi_types.UUID `json:"userId"`
+}
+
+// TwoFactorChallengeResponse defines model for TwoFactorChallengeResponse.
+type TwoFactorChallengeResponse struct {
+	// ChallengeToken /login/2fa に送信するチャレンジトークン
+	ChallengeToken string `json:"challengeToken"`
+
+	// ExpiresIn チャレンジトークンの有効期間（秒）
+	ExpiresIn int `json:"expiresIn"`
+}
+
+// TwoFactorConfirmRequest defines model for TwoFactorConfirmRequest.
+type TwoFactorConfirmRequest struct {
+	// Code 認証アプリの6桁のコード
+	Code string `json:"code"`
+}
+
+// TwoFactorConfirmResponse defines model for TwoFactorConfirmResponse.
+type TwoFactorConfirmResponse struct {
+	// RecoveryCodes 使い捨てのリカバリーコード（再表示不可）
+	RecoveryCodes []string `json:"recoveryCodes"`
+}
+
+// TwoFactorLoginRequest defines model for TwoFactorLoginRequest.
+type TwoFactorLoginRequest struct {
+	// ChallengeToken ログイン時に発行されたチャレンジトークン
+	ChallengeToken string `json:"challengeToken"`
+
+	// Code 認証アプリの6桁のコード、またはリカバリーコード
+	Code string `json:"code"`
+}
+
+// TwoFactorSetupResponse defines model for TwoFactorSetupResponse.
+type TwoFactorSetupResponse struct {
+	// OtpauthUri 認証アプリに読み込ませる otpauth:// URI
+	OtpauthUri string `json:"otpauthUri"`
+
+	// Secret Base32形式の共有鍵（URIを読み込めない場合の手入力用）
+	Secret string `json:"secret"`
+}
+
 // UpdateSchedule defines model for UpdateSchedule.
 type UpdateSchedule struct {
 	EndDateTime   *time.Time `json:"endDateTime,omitempty"`
@@ -133,36 +602,152 @@ type User struct {
 	UpdatedAt *time.Time `json:"updatedAt,omitempty"`
 }
 
+// VerificationResendRequest defines model for VerificationResendRequest.
+type VerificationResendRequest struct {
+	Email openapi_types.Email `json:"email"`
+}
+
+// VerifyUserRequest defines model for VerifyUserRequest.
+type VerifyUserRequest struct {
+	// Password ユーザーが設定するパスワード（パスワードポリシーを満たし、UTF-8で72バイトまで）
+	Password *string `json:"password,omitempty"`
+}
+
+// InvitationId defines model for InvitationId.
+type InvitationId = openapi_types.UUID
+
+// MemberId defines model for MemberId.
+type MemberId = openapi_types.UUID
+
 // ScheduleId defines model for ScheduleId.
 type ScheduleId = openapi_types.UUID
 
+// ShareLinkId defines model for ShareLinkId.
+type ShareLinkId = openapi_types.UUID
+
+// TokenId defines model for TokenId.
+type TokenId = openapi_types.UUID
+
 // TripId defines model for TripId.
 type TripId = openapi_types.UUID
 
+// UserId defines model for UserId.
+type UserId = openapi_types.UUID
+
 // ShareToken defines model for shareToken.
 type ShareToken = string
 
 // BadRequest defines model for BadRequest.
 type BadRequest = Error
 
+// Forbidden defines model for Forbidden.
+type Forbidden = Error
+
 // NotFound defines model for NotFound.
 type NotFound = Error
 
+// TooManyRequests defines model for TooManyRequests.
+type TooManyRequests = Error
+
 // Unauthorized defines model for Unauthorized.
 type Unauthorized = Error
 
+// HandleOIDCCallbackParams defines parameters for HandleOIDCCallback.
+type HandleOIDCCallbackParams struct {
+	// Code 認可コード
+	Code *string `form:"code,omitempty" json:"code,omitempty"`
+
+	// State ログイン開始時に発行したstate
+	State *string `form:"state,omitempty" json:"state,omitempty"`
+
+	// Error プロバイダーが認可を拒否した場合のエラーコード
+	Error *string `form:"error,omitempty" json:"error,omitempty"`
+}
+
+// ImportTripMultipartBody defines parameters for ImportTrip.
+type ImportTripMultipartBody struct {
+	File openapi_types.File `json:"file"`
+}
+
+// ExportSchedulesCsvParams defines parameters for ExportSchedulesCsv.
+type ExportSchedulesCsvParams struct {
+	// Tz 日時を書き出すタイムゾーン（IANA名）
+	Tz *string `form:"tz,omitempty" json:"tz,omitempty"`
+}
+
+// ImportSchedulesCsvParams defines parameters for ImportSchedulesCsv.
+type ImportSchedulesCsvParams struct {
+	// Atomic trueの場合、全ての行が正しいときだけスケジュールを作成します
+	Atomic *bool `form:"atomic,omitempty" json:"atomic,omitempty"`
+
+	// Tz time_zone列が空の行の日時を解釈するタイムゾーン（IANA名）
+	Tz *string `form:"tz,omitempty" json:"tz,omitempty"`
+}
+
+// ImportSchedulesCsvMultipartBody defines parameters for ImportSchedulesCsv.
+type ImportSchedulesCsvMultipartBody struct {
+	File openapi_types.File `json:"file"`
+}
+
+// ImportSchedulesToTripParams defines parameters for ImportSchedulesToTrip.
+type ImportSchedulesToTripParams struct {
+	// DryRun trueの場合、スケジュールを保存せずに作成される内容を返します
+	DryRun *bool `form:"dryRun,omitempty" json:"dryRun,omitempty"`
+
+	// Tz タイムゾーンのない日時と終日の予定を解釈するタイムゾーン（IANA名）
+	Tz *string `form:"tz,omitempty" json:"tz,omitempty"`
+}
+
+// ImportSchedulesToTripMultipartBody defines parameters for ImportSchedulesToTrip.
+type ImportSchedulesToTripMultipartBody struct {
+	File openapi_types.File `json:"file"`
+}
+
 // CreateShareLinkForTripParams defines parameters for CreateShareLinkForTrip.
 type CreateShareLinkForTripParams struct {
 	// Regenerate trueの場合、既存トークンを再生成します
 	Regenerate *bool `form:"regenerate,omitempty" json:"regenerate,omitempty"`
 }
 
+// GetTripShareActivityParams defines parameters for GetTripShareActivity.
+type GetTripShareActivityParams struct {
+	// Days 集計する日数（今日を含む）
+	Days *int `form:"days,omitempty" json:"days,omitempty"`
+
+	// Limit 返す編集の件数
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
+}
+
+// RefreshAuthTokenJSONRequestBody defines body for RefreshAuthToken for application/json ContentType.
+type RefreshAuthTokenJSONRequestBody = RefreshTokenRequest
+
 // LoginUserJSONRequestBody defines body for LoginUser for application/json ContentType.
 type LoginUserJSONRequestBody = LoginRequest
 
+// LoginWithTwoFactorJSONRequestBody defines body for LoginWithTwoFactor for application/json ContentType.
+type LoginWithTwoFactorJSONRequestBody = TwoFactorLoginRequest
+
+// DeleteMeJSONRequestBody defines body for DeleteMe for application/json ContentType.
+type DeleteMeJSONRequestBody = AccountDeletionRequest
+
+// ConfirmTwoFactorJSONRequestBody defines body for ConfirmTwoFactor for application/json ContentType.
+type ConfirmTwoFactorJSONRequestBody = TwoFactorConfirmRequest
+
+// ChangeEmailJSONRequestBody defines body for ChangeEmail for application/json ContentType.
+type ChangeEmailJSONRequestBody = EmailChangeRequest
+
 // ChangePasswordJSONRequestBody defines body for ChangePassword for application/json ContentType.
 type ChangePasswordJSONRequestBody = PasswordChangeRequest
 
+// CreatePersonalAccessTokenJSONRequestBody defines body for CreatePersonalAccessToken for application/json ContentType.
+type CreatePersonalAccessTokenJSONRequestBody = PersonalAccessTokenCreateRequest
+
+// RequestPasswordResetJSONRequestBody defines body for RequestPasswordReset for application/json ContentType.
+type RequestPasswordResetJSONRequestBody = PasswordForgotRequest
+
+// ResetPasswordJSONRequestBody defines body for ResetPassword for application/json ContentType.
+type ResetPasswordJSONRequestBody = PasswordResetRequest
+
 // UpdatePublicTripByShareTokenJSONRequestBody defines body for UpdatePublicTripByShareToken for application/json ContentType.
 type UpdatePublicTripByShareTokenJSONRequestBody = UpdateTripRequest
 
@@ -172,17 +757,59 @@ type AddScheduleToPublicTripJSONRequestBody = NewSchedule
 // UpdateScheduleForPublicTripJSONRequestBody defines body for UpdateScheduleForPublicTrip for application/json ContentType.
 type UpdateScheduleForPublicTripJSONRequestBody = UpdateSchedule
 
+// UnlockPublicTripJSONRequestBody defines body for UnlockPublicTrip for application/json ContentType.
+type UnlockPublicTripJSONRequestBody = ShareLinkUnlockRequest
+
 // CreateUserJSONRequestBody defines body for CreateUser for application/json ContentType.
 type CreateUserJSONRequestBody = NewUser
 
 // CreateUserTripJSONRequestBody defines body for CreateUserTrip for application/json ContentType.
 type CreateUserTripJSONRequestBody = NewTripRequest
 
+// ImportTripJSONRequestBody defines body for ImportTrip for application/json ContentType.
+type ImportTripJSONRequestBody = TripBackup
+
+// ImportTripMultipartRequestBody defines body for ImportTrip for multipart/form-data ContentType.
+type ImportTripMultipartRequestBody = ImportTripMultipartBody
+
 // UpdateUserTripJSONRequestBody defines body for UpdateUserTrip for application/json ContentType.
 type UpdateUserTripJSONRequestBody = UpdateTripRequest
 
+// AddTripCollaboratorJSONRequestBody defines body for AddTripCollaborator for application/json ContentType.
+type AddTripCollaboratorJSONRequestBody = TripCollaboratorCreateRequest
+
+// UpdateTripCollaboratorJSONRequestBody defines body for UpdateTripCollaborator for application/json ContentType.
+type UpdateTripCollaboratorJSONRequestBody = TripCollaboratorUpdateRequest
+
+// CreateTripInvitationJSONRequestBody defines body for CreateTripInvitation for application/json ContentType.
+type CreateTripInvitationJSONRequestBody = TripInvitationCreateRequest
+
+// AddTripMemberJSONRequestBody defines body for AddTripMember for application/json ContentType.
+type AddTripMemberJSONRequestBody = TripMemberRequest
+
+// UpdateTripMemberJSONRequestBody defines body for UpdateTripMember for application/json ContentType.
+type UpdateTripMemberJSONRequestBody = TripMemberRequest
+
+// TransferTripOwnershipJSONRequestBody defines body for TransferTripOwnership for application/json ContentType.
+type TransferTripOwnershipJSONRequestBody = TripOwnershipTransferRequest
+
 // AddScheduleToTripJSONRequestBody defines body for AddScheduleToTrip for application/json ContentType.
 type AddScheduleToTripJSONRequestBody = NewSchedule
 
+// ImportSchedulesCsvMultipartRequestBody defines body for ImportSchedulesCsv for multipart/form-data ContentType.
+type ImportSchedulesCsvMultipartRequestBody = ImportSchedulesCsvMultipartBody
+
+// ImportSchedulesToTripMultipartRequestBody defines body for ImportSchedulesToTrip for multipart/form-data ContentType.
+type ImportSchedulesToTripMultipartRequestBody = ImportSchedulesToTripMultipartBody
+
 // UpdateScheduleForTripJSONRequestBody defines body for UpdateScheduleForTrip for application/json ContentType.
 type UpdateScheduleForTripJSONRequestBody = UpdateSchedule
+
+// CreateTripShareLinkJSONRequestBody defines body for CreateTripShareLink for application/json ContentType.
+type CreateTripShareLinkJSONRequestBody = ShareLinkCreateRequest
+
+// ResendVerificationEmailJSONRequestBody defines body for ResendVerificationEmail for application/json ContentType.
+type ResendVerificationEmailJSONRequestBody = VerificationResendRequest
+
+// VerifyUserJSONRequestBody defines body for VerifyUser for application/json ContentType.
+type VerifyUserJSONRequestBody = VerifyUserRequest
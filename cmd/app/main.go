@@ -1,12 +1,19 @@
 package main
 
 import (
+	"context"
+	"crypto/rand"
 	"log"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"trip_app/api"
+	"trip_app/internal/domain"
 	"trip_app/internal/handler"
 	"trip_app/internal/infrastructure/email"
+	"trip_app/internal/infrastructure/oidc"
 	"trip_app/internal/middleware"
 	"trip_app/internal/repository"
 	"trip_app/internal/security"
@@ -23,28 +30,97 @@ func main() {
 
 	// connect to the database
 	dsn := os.Getenv("DATABASE_URL")
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	// TranslateError maps unique violations to gorm.ErrDuplicatedKey
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{TranslateError: true})
 	if err != nil {
 		log.Fatalf("failed to connect database: %v", err)
 	}
 
-	// get jwt secret
-	jwtSecret := os.Getenv("JWT_SECRET")
-	if jwtSecret == "" {
-		log.Fatal("JWT_SECRET is not set")
+	// load the jwt signing keys; the newest private key signs unless JWT_SIGNING_KEY_ID pins one
+	jwtKeyDir := os.Getenv("JWT_KEY_DIR")
+	if jwtKeyDir == "" {
+		log.Fatal("JWT_KEY_DIR is not set")
+	}
+	jwtKeys, err := security.LoadKeySet(jwtKeyDir, os.Getenv("JWT_SIGNING_KEY_ID"))
+	if err != nil {
+		log.Fatalf("failed to load jwt signing keys: %v", err)
+	}
+	log.Printf("signing access tokens with key %q", jwtKeys.SigningKeyID())
+
+	// load password policy and signup flow settings
+	passwordPolicy := usecase.DefaultPasswordPolicy()
+	if v := os.Getenv("PASSWORD_MIN_LENGTH"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			log.Fatalf("invalid PASSWORD_MIN_LENGTH: %q", v)
+		}
+		passwordPolicy.MinLength = n
+	}
+	passwordPolicy.RequireUpper = getEnvBool("PASSWORD_REQUIRE_UPPER", passwordPolicy.RequireUpper)
+	passwordPolicy.RequireLower = getEnvBool("PASSWORD_REQUIRE_LOWER", passwordPolicy.RequireLower)
+	passwordPolicy.RequireDigit = getEnvBool("PASSWORD_REQUIRE_DIGIT", passwordPolicy.RequireDigit)
+	passwordPolicy.RequireSymbol = getEnvBool("PASSWORD_REQUIRE_SYMBOL", passwordPolicy.RequireSymbol)
+	verificationPolicy := usecase.DefaultVerificationPolicy()
+	verificationPolicy.TokenTTL = getEnvDuration("VERIFICATION_TOKEN_TTL", verificationPolicy.TokenTTL)
+	verificationPolicy.ResendCooldown = getEnvDuration("VERIFICATION_RESEND_COOLDOWN", verificationPolicy.ResendCooldown)
+	if v := os.Getenv("VERIFICATION_RESEND_DAILY_LIMIT"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			log.Fatalf("invalid VERIFICATION_RESEND_DAILY_LIMIT: %q", v)
+		}
+		verificationPolicy.MaxResendsPerDay = n
+	}
+	if verificationPolicy.TokenTTL <= 0 {
+		log.Fatal("VERIFICATION_TOKEN_TTL must be positive")
+	}
+	userUsecaseConfig := usecase.UserUsecaseConfig{
+		LegacyInitialPassword: getEnvBool("LEGACY_INITIAL_PASSWORD", false),
+		LoginThrottle:         usecase.DefaultLoginThrottlePolicy(),
+		Verification:          verificationPolicy,
+	}
+	accountUsecaseConfig := usecase.AccountUsecaseConfig{
+		DeletionGracePeriod: getEnvDuration("ACCOUNT_DELETION_GRACE_PERIOD", usecase.DefaultAccountDeletionGracePeriod),
+	}
+	// client IPs in the share link access log are hashed with this key; without one the
+	// hashes change on every restart, so visitors are counted again after a restart
+	shareActivityConfig := usecase.ShareActivityConfig{
+		IPHashKey: []byte(os.Getenv("SHARE_ACCESS_LOG_IP_KEY")),
+		QueueSize: usecase.DefaultShareActivityQueueSize,
+	}
+	if len(shareActivityConfig.IPHashKey) == 0 {
+		log.Print("SHARE_ACCESS_LOG_IP_KEY is not set, using a random key for hashing client IPs")
+		shareActivityConfig.IPHashKey = make([]byte, 32)
+		if _, err := rand.Read(shareActivityConfig.IPHashKey); err != nil {
+			log.Fatalf("failed to generate the ip hash key: %v", err)
+		}
 	}
 
 	// initialize repositories
 	userRepo := repository.NewUserRepository(db)
+	sessionRepo := repository.NewSessionRepository(db)
+	refreshTokenRepo := repository.NewRefreshTokenRepository(db)
+	personalAccessTokenRepo := repository.NewPersonalAccessTokenRepository(db)
+	recoveryCodeRepo := repository.NewRecoveryCodeRepository(db)
+	loginAttemptRepo := repository.NewLoginAttemptRepository(db)
+	userIdentityRepo := repository.NewUserIdentityRepository(db)
+	oidcAuthRequestRepo := repository.NewOIDCAuthRequestRepository(db)
 	tripRepo := repository.NewTripRepository(db)
+	tripCollaboratorRepo := repository.NewTripCollaboratorRepository(db)
+	tripInvitationRepo := repository.NewTripInvitationRepository(db)
+	memberRepo := repository.NewMemberRepository(db)
 	scheduleRepo := repository.NewScheduleRepository(db)
 	shareTokenRepo := repository.NewShareTokenRepository(db)
 	publicTripRepo := repository.NewPublicTripRepository(db)
+	shareLinkAccessRepo := repository.NewShareLinkAccessRepository(db)
+	calendarFeedRepo := repository.NewCalendarFeedRepository(db)
+	scheduleImportRepo := repository.NewScheduleImportRepository(db)
 
 	// initialize services
 	passwordGenerator := security.NewPasswordGenerator()
 	tokenGenerator := security.NewTokenGenerator()
-	authTokenGenerator := security.NewAuthTokenGenerator(jwtSecret)
+	authTokenGenerator := security.NewAuthTokenGenerator(jwtKeys)
+	totpGenerator := security.NewTOTPGenerator("Trip App")
+	oidcProviders := loadOIDCProviders()
 	emailSender, err := email.NewEmailSender(
 		os.Getenv("SMTP_HOST"),
 		os.Getenv("SMTP_PORT"),
@@ -58,70 +134,175 @@ func main() {
 
 	// initialize validators
 	userHandlerValidator := handler.NewUserHandlerValidator()
-	userUsecaseValidator := usecase.NewUserUsecaseValidator()
+	userUsecaseValidator := usecase.NewUserUsecaseValidator(passwordPolicy)
 	scheduleHandlerValidator := handler.NewScheduleHandlerValidator()
 	scheduleUsecaseValidator := usecase.NewScheduleUsecaseValidator()
 
 	// initialize usecases
-	userUsecase := usecase.NewUserUsecase(userRepo, userUsecaseValidator, passwordGenerator, tokenGenerator, authTokenGenerator, emailSender)
-	tripUsecase := usecase.NewTripUsecase(tripRepo, tokenGenerator)
+	userUsecase := usecase.NewUserUsecase(userRepo, sessionRepo, refreshTokenRepo, recoveryCodeRepo, loginAttemptRepo, userIdentityRepo, oidcAuthRequestRepo, userUsecaseValidator, passwordGenerator, tokenGenerator, authTokenGenerator, totpGenerator, emailSender, oidcProviders, userUsecaseConfig)
+	accountUsecase := usecase.NewAccountUsecase(userRepo, tripRepo, shareTokenRepo, sessionRepo, personalAccessTokenRepo, calendarFeedRepo, passwordGenerator, accountUsecaseConfig)
+	personalAccessTokenUsecase := usecase.NewPersonalAccessTokenUsecase(personalAccessTokenRepo, tokenGenerator)
+	tripUsecase := usecase.NewTripUsecase(tripRepo, tripCollaboratorRepo, tokenGenerator)
+	tripCollaboratorUsecase := usecase.NewTripCollaboratorUsecase(tripCollaboratorRepo, tripRepo, userRepo)
+	tripInvitationUsecase := usecase.NewTripInvitationUsecase(tripInvitationRepo, tripRepo, tripCollaboratorRepo, userRepo, tokenGenerator, emailSender)
+	tripMemberUsecase := usecase.NewTripMemberUsecase(memberRepo, tripRepo)
+	tripBackupUsecase := usecase.NewTripBackupUsecase(tripRepo, scheduleUsecaseValidator)
 	scheduleUsecase := usecase.NewScheduleUsecase(scheduleRepo, scheduleUsecaseValidator)
-	shareTokenUsecase := usecase.NewShareTokenUsecase(shareTokenRepo, tokenGenerator)
-	publicTripUsecase := usecase.NewPublicTripUsecase(publicTripRepo, tokenGenerator)
+	scheduleImportUsecase := usecase.NewScheduleImportUsecase(scheduleUsecase, scheduleImportRepo, scheduleUsecaseValidator)
+	scheduleCSVUsecase := usecase.NewScheduleCSVUsecase(scheduleRepo, scheduleUsecaseValidator)
+	shareTokenUsecase := usecase.NewShareTokenUsecase(shareTokenRepo, tokenGenerator, passwordGenerator)
+	publicTripUsecase := usecase.NewPublicTripUsecase(publicTripRepo, shareTokenRepo, loginAttemptRepo, tokenGenerator, passwordGenerator, authTokenGenerator, userUsecaseConfig.LoginThrottle)
+	shareActivityUsecase := usecase.NewShareActivityUsecase(shareLinkAccessRepo, shareActivityConfig)
+	calendarFeedUsecase := usecase.NewCalendarFeedUsecase(calendarFeedRepo, tripRepo, tokenGenerator)
 
 	// initialize the composite handler
-	h := handler.NewHandler(userUsecase, tripUsecase, scheduleUsecase, shareTokenUsecase, publicTripUsecase, userHandlerValidator, scheduleHandlerValidator)
+	h := handler.NewHandler(userUsecase, accountUsecase, personalAccessTokenUsecase, tripUsecase, tripCollaboratorUsecase, tripInvitationUsecase, tripMemberUsecase, tripBackupUsecase, scheduleUsecase, scheduleImportUsecase, scheduleCSVUsecase, shareTokenUsecase, shareActivityUsecase, publicTripUsecase, calendarFeedUsecase, jwtKeys, userHandlerValidator, scheduleHandlerValidator)
+	// the html pages are served outside of the openapi server interface
+	publicPageHandler := handler.NewPublicPageHandler(publicTripUsecase, shareActivityUsecase)
 
 	// initialize middlewares
-	tripOwnershipMiddleware := middleware.TripOwnershipMiddleware(tripUsecase)
-	authMiddleware := middleware.AuthMiddleware(jwtSecret)
-	shareTokenOwnershipMiddleware := middleware.ShareTokenOwnershipMiddleware(publicTripUsecase)
+	tripPermissionMiddleware := middleware.TripPermissionMiddleware(tripUsecase)
+	tripOwnerOnlyMiddleware := middleware.TripRoleMiddleware(domain.TripRoleOwner)
+	authMiddleware := middleware.AuthMiddleware(jwtKeys, userUsecase, personalAccessTokenUsecase)
+	sessionOnlyMiddleware := middleware.SessionOnlyMiddleware()
+	tripsScopeMiddleware := middleware.ScopeMiddleware(domain.ScopeTripsRead, domain.ScopeTripsWrite)
+	shareViewMiddleware := middleware.ShareTokenOwnershipMiddleware(publicTripUsecase, shareActivityUsecase, domain.ShareScopeView)
+	shareVisitMiddleware := middleware.ShareTokenVisitMiddleware(publicTripUsecase, shareActivityUsecase)
+	shareEditSchedulesMiddleware := middleware.ShareTokenOwnershipMiddleware(publicTripUsecase, shareActivityUsecase, domain.ShareScopeEditSchedules)
+	shareEditTripMiddleware := middleware.ShareTokenOwnershipMiddleware(publicTripUsecase, shareActivityUsecase, domain.ShareScopeEditTrip)
+
+	// write the share link access log in the background
+	go shareActivityUsecase.Run(context.Background())
+
+	// purge accounts whose deletion grace period has ended
+	go func() {
+		ticker := time.NewTicker(time.Hour)
+		defer ticker.Stop()
+		for ; ; <-ticker.C {
+			if _, err := accountUsecase.PurgeScheduledDeletions(context.Background(), time.Now()); err != nil {
+				log.Printf("failed to purge scheduled account deletions: %v", err)
+			}
+		}
+	}()
 
 	// start Echo server
 	e := echo.New()
+	// login throttling is keyed by client IP, so do not trust X-Forwarded-For unless
+	// the app is deployed behind a proxy that sets it (TRUST_PROXY_HEADERS=true)
+	if getEnvBool("TRUST_PROXY_HEADERS", false) {
+		e.IPExtractor = echo.ExtractIPFromXFFHeader()
+	} else {
+		e.IPExtractor = echo.ExtractIPDirect()
+	}
 
 	// Create a wrapper for manual route registration
 	wrapper := &api.ServerInterfaceWrapper{Handler: h}
 
 	// Public routes (no authentication)
 	e.POST("/login", wrapper.LoginUser)
+	e.POST("/login/2fa", wrapper.LoginWithTwoFactor)
+	e.POST("/auth/refresh", wrapper.RefreshAuthToken)
+	e.GET("/.well-known/jwks.json", wrapper.GetJWKS)
+	e.GET("/auth/oidc/:provider/start", wrapper.StartOIDCLogin)
+	e.GET("/auth/oidc/:provider/callback", wrapper.HandleOIDCCallback)
 	e.POST("/signup", wrapper.CreateUser)
+	e.POST("/users/verify/resend", wrapper.ResendVerificationEmail)
 	e.POST("/users/verify/:verificationToken", wrapper.VerifyUser)
+	e.POST("/users/email/confirm/:emailChangeToken", wrapper.ConfirmEmailChange)
+	e.POST("/password/forgot", wrapper.RequestPasswordReset)
+	e.POST("/password/reset", wrapper.ResetPassword)
 
-	// Public trip routes (with share token validation)
+	// Public trip routes check the share link and its scope per route: view (GET), edit_schedules (schedule changes) or edit_trip (trip update)
+	// only the entry of a visit, GET of the trip, counts as a use of the link
 	publicTripGroup := e.Group("/public/trips/:shareToken")
-	publicTripGroup.Use(shareTokenOwnershipMiddleware)
-	publicTripGroup.GET("", wrapper.GetPublicTripByShareToken)
-	publicTripGroup.PUT("", wrapper.UpdatePublicTripByShareToken)
-	publicTripGroup.GET("/details", wrapper.GetTripDetailsForPublicTrip)
-	publicTripGroup.GET("/schedules", wrapper.GetSchedulesForPublicTrip)
-	publicTripGroup.POST("/schedules", wrapper.AddScheduleToPublicTrip)
-	publicTripGroup.GET("/schedules/:scheduleId", wrapper.GetScheduleForPublicTrip)
-	publicTripGroup.PATCH("/schedules/:scheduleId", wrapper.UpdateScheduleForPublicTrip)
-	publicTripGroup.DELETE("/schedules/:scheduleId", wrapper.DeleteScheduleForPublicTrip)
-
-	// Auth-required routes
+	publicTripGroup.POST("/unlock", wrapper.UnlockPublicTrip)
+	publicTripGroup.GET("", wrapper.GetPublicTripByShareToken, shareVisitMiddleware)
+	publicTripGroup.PUT("", wrapper.UpdatePublicTripByShareToken, shareEditTripMiddleware)
+	publicTripGroup.GET("/details", wrapper.GetTripDetailsForPublicTrip, shareViewMiddleware)
+	publicTripGroup.GET("/schedules", wrapper.GetSchedulesForPublicTrip, shareViewMiddleware)
+	publicTripGroup.POST("/schedules", wrapper.AddScheduleToPublicTrip, shareEditSchedulesMiddleware)
+	publicTripGroup.GET("/schedules/:scheduleId", wrapper.GetScheduleForPublicTrip, shareViewMiddleware)
+	publicTripGroup.PATCH("/schedules/:scheduleId", wrapper.UpdateScheduleForPublicTrip, shareEditSchedulesMiddleware)
+	publicTripGroup.DELETE("/schedules/:scheduleId", wrapper.DeleteScheduleForPublicTrip, shareEditSchedulesMiddleware)
+	publicTripGroup.GET("/calendar.ics", wrapper.GetPublicTripCalendar, shareViewMiddleware)
+
+	// html page of the share link for browsers; it only needs the view scope and
+	// keeps the grant of a passphrase in a cookie instead of the X-Share-Grant header
+	e.GET("/p/:shareToken", publicPageHandler.GetPublicTripPage)
+	e.POST("/p/:shareToken/unlock", publicPageHandler.UnlockPublicTripPage)
+	e.GET("/static/*", echo.WrapHandler(handler.PublicPageStatic()))
+
+	// calendar subscription of all trips of a user; calendar clients can not log in, the secret url is the credential
+	e.GET("/calendar-feeds/:feedToken/calendar.ics", wrapper.GetCalendarFeedCalendar)
+
+	// Auth-required routes (JWT or personal access token)
 	authRequired := e.Group("")
 	authRequired.Use(authMiddleware)
-	authRequired.POST("/logout", wrapper.LogoutUser)
-	authRequired.GET("/me", wrapper.GetMe)
-	authRequired.PUT("/me/password", wrapper.ChangePassword)
-	authRequired.GET("/trips", wrapper.GetUserTrips)
-	authRequired.POST("/trips", wrapper.CreateUserTrip)
-
-	// Trip ownership-required routes
-	tripOwnerGroup := authRequired.Group("/trips/:tripId")
-	tripOwnerGroup.Use(tripOwnershipMiddleware)
-	tripOwnerGroup.GET("", wrapper.GetUserTrip)
-	tripOwnerGroup.PUT("", wrapper.UpdateUserTrip)
-	tripOwnerGroup.DELETE("", wrapper.DeleteUserTrip)
-	tripOwnerGroup.GET("/details", wrapper.GetTripDetails)
-	tripOwnerGroup.GET("/schedules", wrapper.GetSchedulesForTrip)
-	tripOwnerGroup.POST("/schedules", wrapper.AddScheduleToTrip)
-	tripOwnerGroup.GET("/schedules/:scheduleId", wrapper.GetScheduleForTrip)
-	tripOwnerGroup.PATCH("/schedules/:scheduleId", wrapper.UpdateScheduleForTrip)
-	tripOwnerGroup.DELETE("/schedules/:scheduleId", wrapper.DeleteScheduleForTrip)
-	tripOwnerGroup.POST("/share", wrapper.CreateShareLinkForTrip)
+
+	// Account routes are only available to login sessions, not to personal access tokens
+	sessionOnlyGroup := authRequired.Group("")
+	sessionOnlyGroup.Use(sessionOnlyMiddleware)
+	sessionOnlyGroup.POST("/logout", wrapper.LogoutUser)
+	sessionOnlyGroup.POST("/logout/all", wrapper.LogoutAllSessions)
+	sessionOnlyGroup.GET("/me", wrapper.GetMe)
+	sessionOnlyGroup.DELETE("/me", wrapper.DeleteMe)
+	sessionOnlyGroup.GET("/me/export", wrapper.ExportPersonalData)
+	sessionOnlyGroup.GET("/me/memberships", wrapper.ListMyMemberships)
+	sessionOnlyGroup.POST("/me/2fa/setup", wrapper.SetupTwoFactor)
+	sessionOnlyGroup.POST("/me/2fa/confirm", wrapper.ConfirmTwoFactor)
+	sessionOnlyGroup.PUT("/me/password", wrapper.ChangePassword)
+	sessionOnlyGroup.PUT("/me/email", wrapper.ChangeEmail)
+	sessionOnlyGroup.GET("/me/tokens", wrapper.ListPersonalAccessTokens)
+	sessionOnlyGroup.POST("/me/tokens", wrapper.CreatePersonalAccessToken)
+	sessionOnlyGroup.DELETE("/me/tokens/:tokenId", wrapper.RevokePersonalAccessToken)
+	sessionOnlyGroup.GET("/me/calendar-feed", wrapper.GetCalendarFeed)
+	sessionOnlyGroup.POST("/me/calendar-feed", wrapper.CreateCalendarFeed)
+	sessionOnlyGroup.DELETE("/me/calendar-feed", wrapper.RevokeCalendarFeed)
+	sessionOnlyGroup.POST("/invitations/:invitationToken/accept", wrapper.AcceptTripInvitation)
+	// claiming a member changes who appears on the trip, so it needs a link that can edit the trip
+	sessionOnlyGroup.POST("/public/trips/:shareToken/members/:memberId/claim", wrapper.ClaimMemberForPublicTrip, shareEditTripMiddleware)
+
+	// Trip routes accept personal access tokens with trips:read (GET) or trips:write (others)
+	tripsGroup := authRequired.Group("/trips")
+	tripsGroup.Use(tripsScopeMiddleware)
+	tripsGroup.GET("", wrapper.GetUserTrips)
+	tripsGroup.POST("", wrapper.CreateUserTrip)
+	tripsGroup.POST("/import", wrapper.ImportTrip)
+
+	// Trip routes require viewer (GET) or editor (others); owner-only routes add tripOwnerOnlyMiddleware
+	tripGroup := tripsGroup.Group("/:tripId")
+	tripGroup.Use(tripPermissionMiddleware)
+	tripGroup.GET("", wrapper.GetUserTrip)
+	tripGroup.PUT("", wrapper.UpdateUserTrip)
+	tripGroup.DELETE("", wrapper.DeleteUserTrip, tripOwnerOnlyMiddleware)
+	tripGroup.GET("/details", wrapper.GetTripDetails)
+	tripGroup.GET("/calendar.ics", wrapper.GetTripCalendar)
+	tripGroup.GET("/export", wrapper.ExportTrip)
+	tripGroup.POST("/members", wrapper.AddTripMember)
+	tripGroup.PATCH("/members/:memberId", wrapper.UpdateTripMember)
+	tripGroup.DELETE("/members/:memberId", wrapper.DeleteTripMember)
+	tripGroup.GET("/schedules", wrapper.GetSchedulesForTrip)
+	tripGroup.POST("/schedules", wrapper.AddScheduleToTrip)
+	tripGroup.GET("/schedules.csv", wrapper.ExportSchedulesCsv)
+	tripGroup.POST("/schedules.csv", wrapper.ImportSchedulesCsv)
+	tripGroup.POST("/schedules/import", wrapper.ImportSchedulesToTrip)
+	tripGroup.GET("/schedules/:scheduleId", wrapper.GetScheduleForTrip)
+	tripGroup.PATCH("/schedules/:scheduleId", wrapper.UpdateScheduleForTrip)
+	tripGroup.DELETE("/schedules/:scheduleId", wrapper.DeleteScheduleForTrip)
+	tripGroup.POST("/share", wrapper.CreateShareLinkForTrip, tripOwnerOnlyMiddleware)
+	tripGroup.GET("/share-links", wrapper.ListTripShareLinks, tripOwnerOnlyMiddleware)
+	tripGroup.POST("/share-links", wrapper.CreateTripShareLink, tripOwnerOnlyMiddleware)
+	tripGroup.DELETE("/share-links/:shareLinkId", wrapper.RevokeTripShareLink, tripOwnerOnlyMiddleware)
+	tripGroup.GET("/share/activity", wrapper.GetTripShareActivity, tripOwnerOnlyMiddleware)
+	tripGroup.GET("/collaborators", wrapper.ListTripCollaborators)
+	tripGroup.POST("/collaborators", wrapper.AddTripCollaborator, tripOwnerOnlyMiddleware)
+	tripGroup.PUT("/collaborators/:userId", wrapper.UpdateTripCollaborator, tripOwnerOnlyMiddleware)
+	tripGroup.DELETE("/collaborators/:userId", wrapper.RemoveTripCollaborator, tripOwnerOnlyMiddleware)
+	tripGroup.PUT("/owner", wrapper.TransferTripOwnership, tripOwnerOnlyMiddleware)
+	tripGroup.GET("/invitations", wrapper.ListTripInvitations, tripOwnerOnlyMiddleware)
+	tripGroup.POST("/invitations", wrapper.CreateTripInvitation, tripOwnerOnlyMiddleware)
+	tripGroup.DELETE("/invitations/:invitationId", wrapper.RevokeTripInvitation, tripOwnerOnlyMiddleware)
+	tripGroup.POST("/invitations/:invitationId/resend", wrapper.ResendTripInvitation, tripOwnerOnlyMiddleware)
 
 	// Start server
 	log.Println("Server starting on port 8080...")
@@ -129,3 +310,56 @@ func main() {
 		log.Fatalf("failed to start server: %v", err)
 	}
 }
+
+// getEnvBool reads a boolean environment variable, falling back to def when it is unset
+func getEnvBool(key string, def bool) bool {
+	v := os.Getenv(key)
+	if v == "" {
+		return def
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		log.Fatalf("invalid %s: %q", key, v)
+	}
+	return b
+}
+
+// getEnvDuration reads a non-negative duration (e.g. "30m") from the environment, falling back to def when it is unset
+func getEnvDuration(key string, def time.Duration) time.Duration {
+	v := os.Getenv(key)
+	if v == "" {
+		return def
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d < 0 {
+		log.Fatalf("invalid %s: %q", key, v)
+	}
+	return d
+}
+
+// loadOIDCProviders reads the providers listed in OIDC_PROVIDERS (e.g. "google,corp").
+// Each provider NAME is configured with OIDC_NAME_ISSUER, OIDC_NAME_CLIENT_ID, OIDC_NAME_CLIENT_SECRET,
+// OIDC_NAME_REDIRECT_URL and optionally OIDC_NAME_SCOPES (space-separated).
+func loadOIDCProviders() map[string]oidc.Provider {
+	providers := make(map[string]oidc.Provider)
+	for _, name := range strings.Split(os.Getenv("OIDC_PROVIDERS"), ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		prefix := "OIDC_" + strings.ToUpper(strings.ReplaceAll(name, "-", "_")) + "_"
+		cfg := oidc.Config{
+			Name:         name,
+			Issuer:       os.Getenv(prefix + "ISSUER"),
+			ClientID:     os.Getenv(prefix + "CLIENT_ID"),
+			ClientSecret: os.Getenv(prefix + "CLIENT_SECRET"),
+			RedirectURL:  os.Getenv(prefix + "REDIRECT_URL"),
+			Scopes:       strings.Fields(os.Getenv(prefix + "SCOPES")),
+		}
+		if cfg.Issuer == "" || cfg.ClientID == "" || cfg.RedirectURL == "" {
+			log.Fatalf("%sISSUER, %sCLIENT_ID and %sREDIRECT_URL must be set", prefix, prefix, prefix)
+		}
+		providers[name] = oidc.NewProvider(cfg, nil)
+	}
+	return providers
+}
@@ -0,0 +1,16 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CalendarFeed はカレンダーアプリから購読する、ユーザーが参照できる全旅行の予定をまとめたiCalendarフィード（トークンのハッシュのみ保存）
+// ユーザーごとに1つで、再発行すると以前のURLは使えなくなる
+type CalendarFeed struct {
+	UserID     uuid.UUID  `gorm:"column:user_id;type:uuid;primaryKey"`
+	TokenHash  string     `gorm:"column:token_hash;size:255;not null;uniqueIndex"`
+	LastUsedAt *time.Time `gorm:"column:last_used_at;type:timestamptz"`
+	CreatedAt  time.Time  `gorm:"column:created_at;type:timestamptz;not null;autoCreateTime:false"`
+}
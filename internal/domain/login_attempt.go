@@ -0,0 +1,14 @@
+package domain
+
+import (
+	"time"
+)
+
+// LoginAttempt はログイン失敗回数のカウンター
+// Keyはメールアドレス単位（"email:..."）またはクライアントIP単位（"ip:..."）
+type LoginAttempt struct {
+	Key          string     `gorm:"column:key;size:320;primaryKey"`
+	FailureCount int        `gorm:"column:failure_count;not null;default:0"`
+	LastFailedAt time.Time  `gorm:"column:last_failed_at;type:timestamptz;not null"`
+	LockedUntil  *time.Time `gorm:"column:locked_until;type:timestamptz"`
+}
@@ -6,4 +6,8 @@ type Member struct {
 	ID     uuid.UUID `gorm:"column:id;type:uuid;default:uuid_generate_v7();primaryKey"`
 	TripID uuid.UUID `gorm:"column:trip_id;type:uuid;not null;index"`
 	Name   string    `gorm:"column:name;size:255;not null"`
+	// UserID はメンバーに紐付いた登録ユーザー（招待の承諾などで設定、未紐付けはnil）
+	UserID *uuid.UUID `gorm:"column:user_id;type:uuid;index"`
+
+	User *User `gorm:"foreignKey:UserID;constraint:OnDelete:SET NULL"`
 }
@@ -0,0 +1,14 @@
+package domain
+
+import "time"
+
+// OIDCAuthRequest はOpenID Connectログインの開始からコールバックまでの一時的な状態
+// stateはハッシュのみ保存し、PKCEのcode_verifierとnonceはサーバー側にのみ保持する
+type OIDCAuthRequest struct {
+	StateHash    string    `gorm:"column:state_hash;size:255;primaryKey"`
+	Provider     string    `gorm:"column:provider;size:64;not null"`
+	Nonce        string    `gorm:"column:nonce;size:255;not null"`
+	CodeVerifier string    `gorm:"column:code_verifier;size:255;not null"`
+	ExpiresAt    time.Time `gorm:"column:expires_at;type:timestamptz;not null;index"`
+	CreatedAt    time.Time `gorm:"column:created_at;type:timestamptz;not null;autoCreateTime:false"`
+}
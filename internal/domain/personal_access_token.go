@@ -0,0 +1,29 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// パーソナルアクセストークンのスコープ
+const (
+	ScopeTripsRead  = "trips:read"
+	ScopeTripsWrite = "trips:write"
+)
+
+// PersonalAccessTokenScopes は発行可能なスコープの一覧
+var PersonalAccessTokenScopes = []string{ScopeTripsRead, ScopeTripsWrite}
+
+// PersonalAccessToken はスクリプトや外部連携用のパーソナルアクセストークン（ハッシュのみ保存）
+// Scopesはスペース区切り（例: "trips:read trips:write"）
+type PersonalAccessToken struct {
+	ID         uuid.UUID  `gorm:"column:id;type:uuid;primaryKey"`
+	UserID     uuid.UUID  `gorm:"column:user_id;type:uuid;not null;index"`
+	Name       string     `gorm:"column:name;size:255;not null"`
+	TokenHash  string     `gorm:"column:token_hash;size:255;not null;uniqueIndex"`
+	Scopes     string     `gorm:"column:scopes;size:255;not null"`
+	ExpiresAt  *time.Time `gorm:"column:expires_at;type:timestamptz"`
+	LastUsedAt *time.Time `gorm:"column:last_used_at;type:timestamptz"`
+	CreatedAt  time.Time  `gorm:"column:created_at;type:timestamptz;not null;autoCreateTime:false"`
+}
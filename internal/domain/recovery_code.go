@@ -0,0 +1,16 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RecoveryCode は2段階認証の端末を紛失した場合に使用する使い捨てのリカバリーコード（ハッシュのみ保存）
+type RecoveryCode struct {
+	ID        uuid.UUID  `gorm:"column:id;type:uuid;primaryKey"`
+	UserID    uuid.UUID  `gorm:"column:user_id;type:uuid;not null;index"`
+	CodeHash  string     `gorm:"column:code_hash;size:255;not null"`
+	UsedAt    *time.Time `gorm:"column:used_at;type:timestamptz"`
+	CreatedAt time.Time  `gorm:"column:created_at;type:timestamptz;not null;autoCreateTime:false"`
+}
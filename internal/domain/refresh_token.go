@@ -0,0 +1,19 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RefreshToken はアクセストークン再発行用の不透明トークン
+// 同一セッション(SessionID)から派生したトークンが1つのファミリーを構成する
+type RefreshToken struct {
+	ID        uuid.UUID  `gorm:"column:id;type:uuid;primaryKey"`
+	SessionID uuid.UUID  `gorm:"column:session_id;type:uuid;not null;index"`
+	UserID    uuid.UUID  `gorm:"column:user_id;type:uuid;not null;index"`
+	TokenHash string     `gorm:"column:token_hash;size:255;not null;uniqueIndex"`
+	ExpiresAt time.Time  `gorm:"column:expires_at;type:timestamptz;not null"`
+	RotatedAt *time.Time `gorm:"column:rotated_at;type:timestamptz"`
+	CreatedAt time.Time  `gorm:"column:created_at;type:timestamptz;not null;autoCreateTime:false"`
+}
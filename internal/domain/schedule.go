@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -16,3 +17,24 @@ type Schedule struct {
 	CreatedAt     time.Time `gorm:"column:created_at;type:timestamptz;not null;autoCreateTime:false"`
 	UpdatedAt     time.Time `gorm:"column:updated_at;type:timestamptz;not null;autoUpdateTime:false"`
 }
+
+// calendarUIDSuffix はエクスポートするVEVENTのUIDの接尾辞
+const calendarUIDSuffix = "@trip-app"
+
+// CalendarUID はiCalendarでエクスポートするときのUID。スケジュールIDから作るため、編集しても変わらない
+func (s *Schedule) CalendarUID() string {
+	return s.ID.String() + calendarUIDSuffix
+}
+
+// ScheduleIDFromCalendarUID はCalendarUIDで作ったUIDからスケジュールIDを取り出す
+func ScheduleIDFromCalendarUID(uid string) (uuid.UUID, bool) {
+	id, ok := strings.CutSuffix(uid, calendarUIDSuffix)
+	if !ok {
+		return uuid.Nil, false
+	}
+	scheduleID, err := uuid.Parse(id)
+	if err != nil {
+		return uuid.Nil, false
+	}
+	return scheduleID, true
+}
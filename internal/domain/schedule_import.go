@@ -0,0 +1,19 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ScheduleImport はiCalendarからインポートしたスケジュールと元のイベントのUIDの対応
+// 同じ旅行に同じUIDのイベントを再度インポートしないために使う。スケジュールを削除すると一緒に削除される
+type ScheduleImport struct {
+	TripID     uuid.UUID `gorm:"column:trip_id;type:uuid;primaryKey"`
+	UID        string    `gorm:"column:uid;size:255;primaryKey"`
+	ScheduleID uuid.UUID `gorm:"column:schedule_id;type:uuid;not null;index"`
+	ImportedAt time.Time `gorm:"column:imported_at;type:timestamptz;not null"`
+
+	Trip     Trip     `gorm:"foreignKey:TripID;constraint:OnDelete:CASCADE"`
+	Schedule Schedule `gorm:"foreignKey:ScheduleID;constraint:OnDelete:CASCADE"`
+}
@@ -0,0 +1,16 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Session はログインごとに発行されるセッション（IDはJWTのjtiと一致）
+type Session struct {
+	ID        uuid.UUID  `gorm:"column:id;type:uuid;primaryKey"`
+	UserID    uuid.UUID  `gorm:"column:user_id;type:uuid;not null;index"`
+	ExpiresAt time.Time  `gorm:"column:expires_at;type:timestamptz;not null"`
+	RevokedAt *time.Time `gorm:"column:revoked_at;type:timestamptz"`
+	CreatedAt time.Time  `gorm:"column:created_at;type:timestamptz;not null;autoCreateTime:false"`
+}
@@ -0,0 +1,34 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ShareLinkAccess は共有リンク経由のリクエストのアクセスログ
+// IPアドレスはハッシュのみ保存し、共有リンクを失効させた後もログは旅行に残る
+type ShareLinkAccess struct {
+	ID              uuid.UUID  `gorm:"column:id;type:uuid;default:uuid_generate_v7();primaryKey"`
+	TripID          uuid.UUID  `gorm:"column:trip_id;type:uuid;not null;index:idx_share_link_access_trip_id_accessed_at,priority:1"`
+	ShareTokenID    *uuid.UUID `gorm:"column:share_token_id;type:uuid"`
+	ShareLinkName   string     `gorm:"column:share_link_name;size:100;not null"`
+	Method          string     `gorm:"column:method;size:10;not null"`
+	Route           string     `gorm:"column:route;size:255;not null"`
+	Status          int        `gorm:"column:status;not null"`
+	IPHash          string     `gorm:"column:ip_hash;size:64;not null"`
+	UserAgentFamily string     `gorm:"column:user_agent_family;size:32;not null"`
+	AccessedAt      time.Time  `gorm:"column:accessed_at;type:timestamptz;not null;index:idx_share_link_access_trip_id_accessed_at,priority:2"`
+
+	Trip       Trip        `gorm:"foreignKey:TripID;constraint:OnDelete:CASCADE"`
+	ShareToken *ShareToken `gorm:"foreignKey:ShareTokenID;constraint:OnDelete:SET NULL"`
+}
+
+// ShareAccessDailyCount は共有リンク経由のアクセスの日ごとの集計
+type ShareAccessDailyCount struct {
+	Date time.Time `gorm:"column:date"`
+	// Requests はリクエスト数
+	Requests int `gorm:"column:requests"`
+	// Visitors はIPアドレスのハッシュで数えた訪問者数
+	Visitors int `gorm:"column:visitors"`
+}
@@ -6,9 +6,79 @@ import (
 	"github.com/google/uuid"
 )
 
+// ShareScope は共有リンクで許可する操作の範囲
+type ShareScope string
+
+// 共有リンクのスコープ（edit_trip ⊃ edit_schedules ⊃ comment ⊃ view）
+const (
+	// ShareScopeView は旅行情報とスケジュールを参照できる
+	ShareScopeView ShareScope = "view"
+	// ShareScopeComment はコメント用のスコープ（コメント機能の追加までは参照のみ）
+	ShareScopeComment ShareScope = "comment"
+	// ShareScopeEditSchedules はスケジュールを追加・編集・削除できる
+	ShareScopeEditSchedules ShareScope = "edit_schedules"
+	// ShareScopeEditTrip は旅行情報（タイトル・日程・メンバー）も編集できる
+	ShareScopeEditTrip ShareScope = "edit_trip"
+)
+
+// shareScopeRanks はスコープの強さ（大きいほど多くの操作ができる）
+var shareScopeRanks = map[ShareScope]int{
+	ShareScopeView:          1,
+	ShareScopeComment:       2,
+	ShareScopeEditSchedules: 3,
+	ShareScopeEditTrip:      4,
+}
+
+// Valid は定義済みのスコープかどうかを返す
+func (s ShareScope) Valid() bool {
+	_, ok := shareScopeRanks[s]
+	return ok
+}
+
+// Includes はsのスコープでrequiredのスコープが必要な操作ができるかどうかを返す
+func (s ShareScope) Includes(required ShareScope) bool {
+	return s.Valid() && shareScopeRanks[s] >= shareScopeRanks[required]
+}
+
+// DefaultShareLinkName は POST /trips/{tripId}/share で発行する共有リンクの名前（表示用で、デフォルトのリンクはIsDefaultで区別する）
+const DefaultShareLinkName = "共有リンク"
+
+// ShareToken は旅行の共有リンク
+// 1つの旅行に名前付きで複数発行でき、それぞれにスコープ・有効期限・利用回数の上限・パスフレーズを設定できる
 type ShareToken struct {
-	TripID    uuid.UUID `gorm:"column:trip_id;type:uuid;not null;primaryKey"`
-	TokenHash string    `gorm:"column:token_hash;size:255;not null;uniqueIndex"`
-	CreatedAt time.Time `gorm:"column:created_at;type:timestamptz;not null;autoCreateTime:false"`
-	UpdatedAt time.Time `gorm:"column:updated_at;type:timestamptz;not null;autoUpdateTime:false"`
+	ID         uuid.UUID  `gorm:"column:id;type:uuid;default:uuid_generate_v7();primaryKey"`
+	TripID     uuid.UUID  `gorm:"column:trip_id;type:uuid;not null;index"`
+	Name       string     `gorm:"column:name;size:100;not null"`
+	Scope      ShareScope `gorm:"column:scope;size:16;not null"`
+	TokenHash  string     `gorm:"column:token_hash;size:255;not null;uniqueIndex"`
+	ExpiresAt  *time.Time `gorm:"column:expires_at;type:timestamptz"`
+	MaxUses    *int       `gorm:"column:max_uses"`
+	UseCount   int        `gorm:"column:use_count;not null;default:0"`
+	LastUsedAt *time.Time `gorm:"column:last_used_at;type:timestamptz"`
+	// IsDefault は POST /trips/{tripId}/share で発行したデフォルトの共有リンクかどうか（旅行ごとに1つまで）
+	IsDefault bool `gorm:"column:is_default;not null;default:false;index:idx_share_token_default,unique,where:is_default"`
+	// PassphraseHash はパスフレーズのbcryptハッシュ（パスフレーズなしの場合はnil）
+	PassphraseHash *string   `gorm:"column:passphrase_hash;size:255"`
+	CreatedAt      time.Time `gorm:"column:created_at;type:timestamptz;not null;autoCreateTime:false"`
+	UpdatedAt      time.Time `gorm:"column:updated_at;type:timestamptz;not null;autoUpdateTime:false"`
+}
+
+// IsUsable は共有リンクが有効期限内かつ利用回数の上限に達していないかを返す
+func (t *ShareToken) IsUsable(now time.Time) bool {
+	return !t.IsExpired(now) && t.HasUsesLeft()
+}
+
+// IsExpired は共有リンクの有効期限が過ぎているかを返す
+func (t *ShareToken) IsExpired(now time.Time) bool {
+	return t.ExpiresAt != nil && !now.Before(*t.ExpiresAt)
+}
+
+// HasUsesLeft は共有リンクの利用回数が上限に達していないかを返す
+func (t *ShareToken) HasUsesLeft() bool {
+	return t.MaxUses == nil || t.UseCount < *t.MaxUses
+}
+
+// IsPasswordProtected はアクセスにパスフレーズの確認が必要かどうかを返す
+func (t *ShareToken) IsPasswordProtected() bool {
+	return t.PassphraseHash != nil
 }
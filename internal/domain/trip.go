@@ -15,7 +15,31 @@ type Trip struct {
 	CreatedAt time.Time `gorm:"column:created_at;type:timestamptz;not null;autoCreateTime:false"`
 	UpdatedAt time.Time `gorm:"column:updated_at;type:timestamptz;not null;autoUpdateTime:false"`
 
-	Members    []Member   `gorm:"foreignKey:trip_id;constraint:OnDelete:CASCADE"`
-	Schedules  []Schedule `gorm:"foreignKey:trip_id;constraint:OnDelete:CASCADE"`
-	ShareToken ShareToken `gorm:"foreignKey:trip_id;constraint:OnDelete:CASCADE"`
+	Members       []Member           `gorm:"foreignKey:trip_id;constraint:OnDelete:CASCADE"`
+	Schedules     []Schedule         `gorm:"foreignKey:trip_id;constraint:OnDelete:CASCADE"`
+	ShareTokens   []ShareToken       `gorm:"foreignKey:trip_id;constraint:OnDelete:CASCADE"`
+	Collaborators []TripCollaborator `gorm:"foreignKey:trip_id;constraint:OnDelete:CASCADE"`
+}
+
+// RoleOf はユーザーの旅行に対する権限を返す（Collaboratorsを読み込んでいる場合のみ共同編集者を判定できる）
+func (t *Trip) RoleOf(userID uuid.UUID) (TripRole, bool) {
+	if t.UserID == userID {
+		return TripRoleOwner, true
+	}
+	for _, c := range t.Collaborators {
+		if c.UserID == userID {
+			return c.Role, true
+		}
+	}
+	return "", false
+}
+
+// MemberOf はユーザーに紐付いたメンバーを返す（Membersを読み込んでいる場合のみ判定できる）
+func (t *Trip) MemberOf(userID uuid.UUID) (*Member, bool) {
+	for i := range t.Members {
+		if m := &t.Members[i]; m.UserID != nil && *m.UserID == userID {
+			return m, true
+		}
+	}
+	return nil, false
 }
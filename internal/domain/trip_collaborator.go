@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TripRole は旅行に対するユーザーの権限
+type TripRole string
+
+// 旅行の権限（owner ⊃ editor ⊃ viewer）
+const (
+	// TripRoleOwner は削除・共有リンク発行・共同編集者の管理・所有権の譲渡ができる（旅行ごとに1人）
+	TripRoleOwner TripRole = "owner"
+	// TripRoleEditor は旅行情報とスケジュールを編集できる
+	TripRoleEditor TripRole = "editor"
+	// TripRoleViewer は旅行情報とスケジュールを参照できる
+	TripRoleViewer TripRole = "viewer"
+)
+
+// tripRoleRanks は権限の強さ（大きいほど多くの操作ができる）
+var tripRoleRanks = map[TripRole]int{
+	TripRoleViewer: 1,
+	TripRoleEditor: 2,
+	TripRoleOwner:  3,
+}
+
+// Valid は定義済みの権限かどうかを返す
+func (r TripRole) Valid() bool {
+	_, ok := tripRoleRanks[r]
+	return ok
+}
+
+// Includes はrの権限でrequiredの権限が必要な操作ができるかどうかを返す
+func (r TripRole) Includes(required TripRole) bool {
+	return r.Valid() && tripRoleRanks[r] >= tripRoleRanks[required]
+}
+
+// TripCollaborator は旅行にアクセスできる登録ユーザーとその権限
+// ownerの行はTrip.UserIDと常に一致する
+type TripCollaborator struct {
+	TripID    uuid.UUID `gorm:"column:trip_id;type:uuid;primaryKey"`
+	UserID    uuid.UUID `gorm:"column:user_id;type:uuid;primaryKey;index"`
+	Role      TripRole  `gorm:"column:role;size:16;not null"`
+	CreatedAt time.Time `gorm:"column:created_at;type:timestamptz;not null;autoCreateTime:false"`
+	UpdatedAt time.Time `gorm:"column:updated_at;type:timestamptz;not null;autoUpdateTime:false"`
+
+	User User `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+}
@@ -0,0 +1,32 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TripInvitation はメールアドレス宛の旅行への招待（トークンはハッシュのみ保存）
+// 承諾されるまでは保留中として扱い、承諾すると招待されたユーザーがRoleの共同編集者になる
+type TripInvitation struct {
+	ID     uuid.UUID `gorm:"column:id;type:uuid;default:uuid_generate_v7();primaryKey"`
+	TripID uuid.UUID `gorm:"column:trip_id;type:uuid;not null;index"`
+	Email  string    `gorm:"column:email;size:255;not null"`
+	Role   TripRole  `gorm:"column:role;size:16;not null"`
+	// MemberName は承諾したユーザーを紐付けるメンバーの名前（招待時にownerが選択、未選択はnil）
+	MemberName *string    `gorm:"column:member_name;size:255"`
+	TokenHash  string     `gorm:"column:token_hash;size:255;not null;uniqueIndex"`
+	InvitedBy  uuid.UUID  `gorm:"column:invited_by;type:uuid;not null"`
+	ExpiresAt  time.Time  `gorm:"column:expires_at;type:timestamptz;not null"`
+	SentAt     time.Time  `gorm:"column:sent_at;type:timestamptz;not null"`
+	AcceptedAt *time.Time `gorm:"column:accepted_at;type:timestamptz"`
+	AcceptedBy *uuid.UUID `gorm:"column:accepted_by;type:uuid"`
+	CreatedAt  time.Time  `gorm:"column:created_at;type:timestamptz;not null;autoCreateTime:false"`
+
+	Trip Trip `gorm:"foreignKey:TripID;constraint:OnDelete:CASCADE"`
+}
+
+// IsPending は招待が未承諾かどうかを返す
+func (i *TripInvitation) IsPending() bool {
+	return i.AcceptedAt == nil
+}
@@ -9,14 +9,31 @@ import (
 )
 
 type User struct {
-	ID                         uuid.UUID `gorm:"column:id;type:uuid;default:uuid_generate_v7();primaryKey"`
-	Name                       string    `gorm:"column:name;size:255;not null"`
-	Email                      string    `gorm:"column:email;size:255;uniqueIndex;not null"`
-	PasswordHash               string    `gorm:"column:password_hash;size:255;not null"`
-	IsActive                   bool      `gorm:"column:is_active;not null;default:false"`
-	VerificationTokenHash      *string   `gorm:"column:verification_token_hash;size:255;uniqueIndex"`
-	VerificationTokenExpiresAt *time.Time `gorm:"column:verification_token_expires_at"`
-	CreatedAt                  time.Time `gorm:"column:created_at;type:timestamptz;not null;autoCreateTime:false"`
-	UpdatedAt                  time.Time `gorm:"column:updated_at;type:timestamptz;not null;autoUpdateTime:false"`
-	Trips                      []Trip    `gorm:"foreignKey:user_id;constraint:OnDelete:CASCADE"`
+	ID                                uuid.UUID             `gorm:"column:id;type:uuid;default:uuid_generate_v7();primaryKey"`
+	Name                              string                `gorm:"column:name;size:255;not null"`
+	Email                             string                `gorm:"column:email;size:255;uniqueIndex;not null"`
+	PasswordHash                      string                `gorm:"column:password_hash;size:255;not null"`
+	IsActive                          bool                  `gorm:"column:is_active;not null;default:false"`
+	VerificationTokenHash             *string               `gorm:"column:verification_token_hash;size:255;uniqueIndex"`
+	VerificationTokenExpiresAt        *time.Time            `gorm:"column:verification_token_expires_at"`
+	VerificationSentAt                *time.Time            `gorm:"column:verification_sent_at"`
+	VerificationResendCount           int                   `gorm:"column:verification_resend_count;not null;default:0"`
+	VerificationResendWindowStartedAt *time.Time            `gorm:"column:verification_resend_window_started_at"`
+	PasswordResetTokenHash            *string               `gorm:"column:password_reset_token_hash;size:255;uniqueIndex"`
+	PasswordResetTokenExpiresAt       *time.Time            `gorm:"column:password_reset_token_expires_at"`
+	PendingEmail                      *string               `gorm:"column:pending_email;size:255"`
+	EmailChangeTokenHash              *string               `gorm:"column:email_change_token_hash;size:255;uniqueIndex"`
+	EmailChangeTokenExpiresAt         *time.Time            `gorm:"column:email_change_token_expires_at"`
+	TwoFactorSecret                   *string               `gorm:"column:two_factor_secret;size:255"`
+	TwoFactorEnabled                  bool                  `gorm:"column:two_factor_enabled;not null;default:false"`
+	TwoFactorLastUsedStep             int64                 `gorm:"column:two_factor_last_used_step;not null;default:0"`
+	DeletionScheduledAt               *time.Time            `gorm:"column:deletion_scheduled_at;index"`
+	CreatedAt                         time.Time             `gorm:"column:created_at;type:timestamptz;not null;autoCreateTime:false"`
+	UpdatedAt                         time.Time             `gorm:"column:updated_at;type:timestamptz;not null;autoUpdateTime:false"`
+	Trips                             []Trip                `gorm:"foreignKey:user_id;constraint:OnDelete:CASCADE"`
+	Sessions                          []Session             `gorm:"foreignKey:user_id;constraint:OnDelete:CASCADE"`
+	RecoveryCodes                     []RecoveryCode        `gorm:"foreignKey:user_id;constraint:OnDelete:CASCADE"`
+	PersonalAccessTokens              []PersonalAccessToken `gorm:"foreignKey:user_id;constraint:OnDelete:CASCADE"`
+	Identities                        []UserIdentity        `gorm:"foreignKey:user_id;constraint:OnDelete:CASCADE"`
+	CalendarFeed                      *CalendarFeed         `gorm:"foreignKey:user_id;constraint:OnDelete:CASCADE"`
 }
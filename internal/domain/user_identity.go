@@ -0,0 +1,19 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UserIdentity は外部IDプロバイダー（OpenID Connect）のアカウントとユーザーの紐付け
+// プロバイダー内で一意なsub（Subject）でユーザーを特定する
+type UserIdentity struct {
+	ID          uuid.UUID `gorm:"column:id;type:uuid;primaryKey"`
+	UserID      uuid.UUID `gorm:"column:user_id;type:uuid;not null;index"`
+	Provider    string    `gorm:"column:provider;size:64;not null;uniqueIndex:idx_user_identity_provider_subject"`
+	Subject     string    `gorm:"column:subject;size:255;not null;uniqueIndex:idx_user_identity_provider_subject"`
+	Email       string    `gorm:"column:email;size:255;not null"`
+	CreatedAt   time.Time `gorm:"column:created_at;type:timestamptz;not null;autoCreateTime:false"`
+	LastLoginAt time.Time `gorm:"column:last_login_at;type:timestamptz;not null"`
+}
@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"trip_app/api"
+	"trip_app/internal/usecase"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type accountHandler struct {
+	au usecase.AccountUsecase
+	uv UserHandlerValidator
+}
+
+func NewAccountHandler(au usecase.AccountUsecase, uv UserHandlerValidator) *accountHandler {
+	return &accountHandler{au, uv}
+}
+
+func toAPIPersonalDataExport(export *usecase.PersonalDataExport) api.PersonalDataExport {
+	trips := make([]api.PersonalDataExportTrip, len(export.Trips))
+	for i := range export.Trips {
+		trip := &export.Trips[i]
+		schedules := []api.Schedule{}
+		if s := toAPISchedules(trip.Schedules); s != nil {
+			schedules = *s
+		}
+		trips[i] = api.PersonalDataExportTrip{
+			Trip:      *toAPITrip(trip),
+			Schedules: schedules,
+		}
+		// only the metadata of the share links is exported; the tokens themselves are never stored in plain text
+		if len(trip.ShareTokens) > 0 {
+			shareLinks := make([]api.ShareLinkMetadata, len(trip.ShareTokens))
+			for j, shareToken := range trip.ShareTokens {
+				shareLinks[j] = api.ShareLinkMetadata{
+					Name:      shareToken.Name,
+					Scope:     string(shareToken.Scope),
+					ExpiresAt: shareToken.ExpiresAt,
+					MaxUses:   shareToken.MaxUses,
+					CreatedAt: shareToken.CreatedAt,
+					UpdatedAt: shareToken.UpdatedAt,
+				}
+			}
+			trips[i].ShareLinks = &shareLinks
+		}
+	}
+
+	return api.PersonalDataExport{
+		ExportedAt: export.ExportedAt,
+		User:       toAPIUser(export.User),
+		Trips:      trips,
+	}
+}
+
+func (h *accountHandler) ExportPersonalData(ctx echo.Context) error {
+	userID, ok := ctx.Get("user_id").(uuid.UUID)
+	if !ok {
+		return ctx.JSON(http.StatusUnauthorized, map[string]string{"message": "Unauthorized"})
+	}
+
+	export, err := h.au.ExportPersonalData(ctx.Request().Context(), userID)
+	if err != nil {
+		if errors.Is(err, usecase.ErrUserNotFound) {
+			return ctx.JSON(http.StatusNotFound, map[string]string{"message": err.Error()})
+		}
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{"message": "Internal server error"})
+	}
+
+	// serve the export as a downloadable file
+	filename := fmt.Sprintf("trip-app-export-%s.json", export.ExportedAt.Format("20060102"))
+	ctx.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", filename))
+
+	return ctx.JSON(http.StatusOK, toAPIPersonalDataExport(export))
+}
+
+func (h *accountHandler) DeleteMe(ctx echo.Context) error {
+	var req api.AccountDeletionRequest
+
+	if err := ctx.Bind(&req); err != nil {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid request body"})
+	}
+
+	if err := h.uv.ValidateDeleteAccount(req.Password); err != nil {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"message": err.Error()})
+	}
+
+	userID, ok := ctx.Get("user_id").(uuid.UUID)
+	if !ok {
+		return ctx.JSON(http.StatusUnauthorized, map[string]string{"message": "Unauthorized"})
+	}
+
+	scheduledAt, err := h.au.RequestDeletion(ctx.Request().Context(), userID, req.Password)
+	if err != nil {
+		if errors.Is(err, usecase.ErrUserNotFound) {
+			return ctx.JSON(http.StatusNotFound, map[string]string{"message": err.Error()})
+		}
+		if errors.Is(err, usecase.ErrIncorrectCurrentPassword) {
+			return ctx.JSON(http.StatusUnauthorized, map[string]string{"message": err.Error()})
+		}
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{"message": "Internal server error"})
+	}
+
+	// no grace period: the account is already gone
+	if scheduledAt == nil {
+		return ctx.NoContent(http.StatusNoContent)
+	}
+
+	return ctx.JSON(http.StatusAccepted, api.AccountDeletionResponse{DeletionScheduledAt: *scheduledAt})
+}
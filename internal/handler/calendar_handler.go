@@ -0,0 +1,177 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/url"
+	"sort"
+
+	"trip_app/api"
+	"trip_app/internal/domain"
+	"trip_app/internal/infrastructure/ical"
+	"trip_app/internal/usecase"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// calendarFeedName is the calendar name of the feed that aggregates all trips of a user
+const calendarFeedName = "Trip App"
+
+type calendarHandler struct {
+	tu  usecase.TripUsecase
+	ptu usecase.PublicTripUsecase
+	cfu usecase.CalendarFeedUsecase
+}
+
+func NewCalendarHandler(tu usecase.TripUsecase, ptu usecase.PublicTripUsecase, cfu usecase.CalendarFeedUsecase) *calendarHandler {
+	return &calendarHandler{tu, ptu, cfu}
+}
+
+func toAPICalendarFeed(feed *domain.CalendarFeed) api.CalendarFeed {
+	return api.CalendarFeed{
+		LastUsedAt: feed.LastUsedAt,
+		CreatedAt:  feed.CreatedAt,
+	}
+}
+
+// toCalendarEvent makes a VEVENT of the schedule. The uid stays the same across edits,
+// so calendar clients update the event instead of adding a new one.
+func toCalendarEvent(schedule *domain.Schedule, summary string) ical.Event {
+	return ical.Event{
+		UID:         schedule.CalendarUID(),
+		Summary:     summary,
+		Description: schedule.Memo,
+		Start:       schedule.StartDateTime,
+		End:         schedule.EndDateTime,
+		Stamp:       schedule.UpdatedAt,
+	}
+}
+
+func toTripCalendar(trip *domain.Trip) *ical.Calendar {
+	calendar := &ical.Calendar{Name: trip.Title}
+	for i := range trip.Schedules {
+		calendar.Events = append(calendar.Events, toCalendarEvent(&trip.Schedules[i], trip.Schedules[i].Title))
+	}
+	sortCalendarEvents(calendar.Events)
+	return calendar
+}
+
+// toFeedCalendar puts the schedules of all trips in one calendar, prefixing each event with its trip
+func toFeedCalendar(trips []domain.Trip) *ical.Calendar {
+	calendar := &ical.Calendar{Name: calendarFeedName}
+	for _, trip := range trips {
+		for i := range trip.Schedules {
+			schedule := &trip.Schedules[i]
+			calendar.Events = append(calendar.Events, toCalendarEvent(schedule, "["+trip.Title+"] "+schedule.Title))
+		}
+	}
+	sortCalendarEvents(calendar.Events)
+	return calendar
+}
+
+func sortCalendarEvents(events []ical.Event) {
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Start.Before(events[j].Start)
+	})
+}
+
+func writeCalendar(ctx echo.Context, calendar *ical.Calendar) error {
+	var buf bytes.Buffer
+	if err := calendar.Encode(&buf); err != nil {
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{"message": "Internal server error"})
+	}
+	// the calendar holds private plans, calendar clients poll for changes anyway
+	ctx.Response().Header().Set("Cache-Control", "no-store")
+	return ctx.Blob(http.StatusOK, ical.ContentType, buf.Bytes())
+}
+
+func (h *calendarHandler) GetTripCalendar(ctx echo.Context, tripId api.TripId) error {
+	trip, err := h.tu.GetTripDetailsByID(ctx.Request().Context(), tripId)
+	if err != nil {
+		if errors.Is(err, usecase.ErrTripNotFound) {
+			return ctx.JSON(http.StatusNotFound, map[string]string{"message": "Trip not found"})
+		}
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{"message": "Internal server error"})
+	}
+
+	return writeCalendar(ctx, toTripCalendar(trip))
+}
+
+func (h *calendarHandler) GetPublicTripCalendar(ctx echo.Context, shareToken api.ShareToken) error {
+	trip, err := h.ptu.GetTripDetailsByShareToken(ctx.Request().Context(), shareToken)
+	if err != nil {
+		if errors.Is(err, usecase.ErrTripNotFound) {
+			return ctx.JSON(http.StatusNotFound, map[string]string{"message": "Trip not found"})
+		}
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{"message": "Internal server error"})
+	}
+
+	return writeCalendar(ctx, toTripCalendar(trip))
+}
+
+func (h *calendarHandler) GetCalendarFeedCalendar(ctx echo.Context, feedToken string) error {
+	trips, err := h.cfu.GetFeedTrips(ctx.Request().Context(), feedToken)
+	if err != nil {
+		if errors.Is(err, usecase.ErrCalendarFeedNotFound) {
+			return ctx.JSON(http.StatusNotFound, map[string]string{"message": err.Error()})
+		}
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{"message": "Internal server error"})
+	}
+
+	return writeCalendar(ctx, toFeedCalendar(trips))
+}
+
+func (h *calendarHandler) GetCalendarFeed(ctx echo.Context) error {
+	userID, ok := ctx.Get("user_id").(uuid.UUID)
+	if !ok {
+		return ctx.JSON(http.StatusUnauthorized, map[string]string{"message": "Unauthorized"})
+	}
+
+	feed, err := h.cfu.Get(ctx.Request().Context(), userID)
+	if err != nil {
+		if errors.Is(err, usecase.ErrCalendarFeedNotFound) {
+			return ctx.JSON(http.StatusNotFound, map[string]string{"message": err.Error()})
+		}
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{"message": "Internal server error"})
+	}
+
+	return ctx.JSON(http.StatusOK, toAPICalendarFeed(feed))
+}
+
+func (h *calendarHandler) CreateCalendarFeed(ctx echo.Context) error {
+	userID, ok := ctx.Get("user_id").(uuid.UUID)
+	if !ok {
+		return ctx.JSON(http.StatusUnauthorized, map[string]string{"message": "Unauthorized"})
+	}
+
+	feed, rawToken, err := h.cfu.Create(ctx.Request().Context(), userID)
+	if err != nil {
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{"message": "Internal server error"})
+	}
+
+	res := api.CalendarFeedCreateResponse{
+		Token:        rawToken,
+		Url:          ctx.Scheme() + "://" + ctx.Request().Host + "/calendar-feeds/" + url.PathEscape(rawToken) + "/calendar.ics",
+		CalendarFeed: toAPICalendarFeed(feed),
+	}
+
+	return ctx.JSON(http.StatusCreated, res)
+}
+
+func (h *calendarHandler) RevokeCalendarFeed(ctx echo.Context) error {
+	userID, ok := ctx.Get("user_id").(uuid.UUID)
+	if !ok {
+		return ctx.JSON(http.StatusUnauthorized, map[string]string{"message": "Unauthorized"})
+	}
+
+	if err := h.cfu.Revoke(ctx.Request().Context(), userID); err != nil {
+		if errors.Is(err, usecase.ErrCalendarFeedNotFound) {
+			return ctx.JSON(http.StatusNotFound, map[string]string{"message": err.Error()})
+		}
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{"message": "Internal server error"})
+	}
+
+	return ctx.NoContent(http.StatusNoContent)
+}
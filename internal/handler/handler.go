@@ -2,34 +2,66 @@ package handler
 
 import (
 	"trip_app/api"
+	"trip_app/internal/security"
 	"trip_app/internal/usecase"
 )
 
 // Handler holds all handlers
 type Handler struct {
 	*userHandler
+	*accountHandler
+	*personalAccessTokenHandler
+	*jwksHandler
 	*tripHandler
+	*tripCollaboratorHandler
+	*tripInvitationHandler
+	*tripMemberHandler
+	*tripBackupHandler
 	*scheduleHandler
+	*scheduleImportHandler
+	*scheduleCSVHandler
 	*shareTokenHandler
 	*publicTripHandler
 	*publicScheduleHandler
+	*calendarHandler
 }
 
 func NewHandler(
 	userUsecase usecase.UserUsecase,
+	accountUsecase usecase.AccountUsecase,
+	personalAccessTokenUsecase usecase.PersonalAccessTokenUsecase,
 	tripUsecase usecase.TripUsecase,
+	tripCollaboratorUsecase usecase.TripCollaboratorUsecase,
+	tripInvitationUsecase usecase.TripInvitationUsecase,
+	tripMemberUsecase usecase.TripMemberUsecase,
+	tripBackupUsecase usecase.TripBackupUsecase,
 	scheduleUsecase usecase.ScheduleUsecase,
+	scheduleImportUsecase usecase.ScheduleImportUsecase,
+	scheduleCSVUsecase usecase.ScheduleCSVUsecase,
 	shareTokenUsecase usecase.ShareTokenUsecase,
+	shareActivityUsecase usecase.ShareActivityUsecase,
 	publicTripUsecase usecase.PublicTripUsecase,
+	calendarFeedUsecase usecase.CalendarFeedUsecase,
+	keys security.KeySet,
 	userHandlerValidator UserHandlerValidator,
 	scheduleHandlerValidator ScheduleHandlerValidator,
 ) api.ServerInterface {
 	return &Handler{
 		userHandler:          NewUserHandler(userUsecase, userHandlerValidator),
+		accountHandler:       NewAccountHandler(accountUsecase, userHandlerValidator),
+		personalAccessTokenHandler: NewPersonalAccessTokenHandler(personalAccessTokenUsecase, userHandlerValidator),
+		jwksHandler:          NewJWKSHandler(keys),
 		tripHandler:          NewTripHandler(tripUsecase),
+		tripCollaboratorHandler: NewTripCollaboratorHandler(tripCollaboratorUsecase),
+		tripInvitationHandler: NewTripInvitationHandler(tripInvitationUsecase),
+		tripMemberHandler:     NewTripMemberHandler(tripMemberUsecase),
+		tripBackupHandler:     NewTripBackupHandler(tripBackupUsecase),
 		scheduleHandler:      NewScheduleHandler(scheduleUsecase, scheduleHandlerValidator),
-		shareTokenHandler:    NewShareTokenHandler(shareTokenUsecase),
+		scheduleImportHandler: NewScheduleImportHandler(scheduleImportUsecase),
+		scheduleCSVHandler:   NewScheduleCSVHandler(scheduleUsecase, scheduleCSVUsecase),
+		shareTokenHandler:    NewShareTokenHandler(shareTokenUsecase, shareActivityUsecase),
 		publicTripHandler:    NewPublicTripHandler(publicTripUsecase),
 		publicScheduleHandler: NewPublicScheduleHandler(scheduleUsecase, scheduleHandlerValidator),
+		calendarHandler:      NewCalendarHandler(tripUsecase, publicTripUsecase, calendarFeedUsecase),
 	}
 }
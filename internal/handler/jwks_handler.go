@@ -0,0 +1,48 @@
+package handler
+
+import (
+	"net/http"
+
+	"trip_app/api"
+	"trip_app/internal/security"
+
+	"github.com/labstack/echo/v4"
+)
+
+type jwksHandler struct {
+	keys security.KeySet
+}
+
+func NewJWKSHandler(keys security.KeySet) *jwksHandler {
+	return &jwksHandler{keys}
+}
+
+func (h *jwksHandler) GetJWKS(ctx echo.Context) error {
+	publicKeys := h.keys.PublicJWKs()
+	res := api.JSONWebKeySet{Keys: make([]api.JSONWebKey, len(publicKeys))}
+	for i, key := range publicKeys {
+		res.Keys[i] = api.JSONWebKey{
+			Kty: key.Kty,
+			Kid: key.Kid,
+			Use: key.Use,
+			Alg: key.Alg,
+			N:   optionalString(key.N),
+			E:   optionalString(key.E),
+			Crv: optionalString(key.Crv),
+			X:   optionalString(key.X),
+		}
+	}
+
+	// verifiers may cache the key set; a new key should be published at least this long before it signs
+	ctx.Response().Header().Set("Cache-Control", "public, max-age=300")
+
+	return ctx.JSON(http.StatusOK, res)
+}
+
+// optionalString maps an empty string to an omitted JSON field
+func optionalString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
@@ -0,0 +1,101 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"trip_app/api"
+	"trip_app/internal/domain"
+	"trip_app/internal/usecase"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type personalAccessTokenHandler struct {
+	pu usecase.PersonalAccessTokenUsecase
+	uv UserHandlerValidator
+}
+
+func NewPersonalAccessTokenHandler(pu usecase.PersonalAccessTokenUsecase, uv UserHandlerValidator) *personalAccessTokenHandler {
+	return &personalAccessTokenHandler{pu, uv}
+}
+
+func toAPIPersonalAccessToken(token *domain.PersonalAccessToken) api.PersonalAccessToken {
+	return api.PersonalAccessToken{
+		Id:         token.ID,
+		Name:       token.Name,
+		Scopes:     strings.Fields(token.Scopes),
+		ExpiresAt:  token.ExpiresAt,
+		LastUsedAt: token.LastUsedAt,
+		CreatedAt:  token.CreatedAt,
+	}
+}
+
+func (h *personalAccessTokenHandler) CreatePersonalAccessToken(ctx echo.Context) error {
+	var req api.PersonalAccessTokenCreateRequest
+
+	if err := ctx.Bind(&req); err != nil {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid request body"})
+	}
+
+	if err := h.uv.ValidateCreatePersonalAccessToken(req.Name, req.Scopes); err != nil {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"message": err.Error()})
+	}
+
+	userID, ok := ctx.Get("user_id").(uuid.UUID)
+	if !ok {
+		return ctx.JSON(http.StatusUnauthorized, map[string]string{"message": "Unauthorized"})
+	}
+
+	token, rawToken, err := h.pu.Create(ctx.Request().Context(), userID, req.Name, req.Scopes, req.ExpiresAt)
+	if err != nil {
+		if errors.Is(err, usecase.ErrValidation) {
+			return ctx.JSON(http.StatusBadRequest, map[string]string{"message": err.Error()})
+		}
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{"message": "Internal server error"})
+	}
+
+	res := api.PersonalAccessTokenCreateResponse{
+		Token:               rawToken,
+		PersonalAccessToken: toAPIPersonalAccessToken(token),
+	}
+
+	return ctx.JSON(http.StatusCreated, res)
+}
+
+func (h *personalAccessTokenHandler) ListPersonalAccessTokens(ctx echo.Context) error {
+	userID, ok := ctx.Get("user_id").(uuid.UUID)
+	if !ok {
+		return ctx.JSON(http.StatusUnauthorized, map[string]string{"message": "Unauthorized"})
+	}
+
+	tokens, err := h.pu.List(ctx.Request().Context(), userID)
+	if err != nil {
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{"message": "Internal server error"})
+	}
+
+	res := make([]api.PersonalAccessToken, len(tokens))
+	for i := range tokens {
+		res[i] = toAPIPersonalAccessToken(&tokens[i])
+	}
+
+	return ctx.JSON(http.StatusOK, res)
+}
+
+func (h *personalAccessTokenHandler) RevokePersonalAccessToken(ctx echo.Context, tokenId api.TokenId) error {
+	userID, ok := ctx.Get("user_id").(uuid.UUID)
+	if !ok {
+		return ctx.JSON(http.StatusUnauthorized, map[string]string{"message": "Unauthorized"})
+	}
+
+	if err := h.pu.Revoke(ctx.Request().Context(), userID, tokenId); err != nil {
+		if errors.Is(err, usecase.ErrPersonalAccessTokenNotFound) {
+			return ctx.JSON(http.StatusNotFound, map[string]string{"message": err.Error()})
+		}
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{"message": "Internal server error"})
+	}
+
+	return ctx.NoContent(http.StatusNoContent)
+}
@@ -0,0 +1,307 @@
+package handler
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"log"
+	"math"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"time"
+	// the container images do not ship the zone database, so embed it for the tz parameter
+	_ "time/tzdata"
+
+	"trip_app/internal/domain"
+	"trip_app/internal/usecase"
+
+	"github.com/labstack/echo/v4"
+)
+
+//go:embed templates/*.html
+var pageTemplates embed.FS
+
+//go:embed static
+var pageStatic embed.FS
+
+// ShareGrantCookie carries the grant of a share link with a passphrase for the html page, which can not send headers.
+// It is scoped to the page of that link, so the json api never receives it.
+const ShareGrantCookie = "share_grant"
+
+// defaultPageTimeZone is used when the page is opened without the tz parameter
+const defaultPageTimeZone = "UTC"
+
+// maxPageEmptyDays caps the trip length for which the days without schedules are listed,
+// so a trip spanning decades does not render one section per day
+const maxPageEmptyDays = 366
+
+var pageWeekdays = [...]string{"日", "月", "火", "水", "木", "金", "土"}
+
+type publicPageHandler struct {
+	ptu  usecase.PublicTripUsecase
+	sau  usecase.ShareActivityUsecase
+	tmpl *template.Template
+}
+
+func NewPublicPageHandler(ptu usecase.PublicTripUsecase, sau usecase.ShareActivityUsecase) *publicPageHandler {
+	tmpl := template.Must(template.ParseFS(pageTemplates, "templates/*.html"))
+	return &publicPageHandler{ptu, sau, tmpl}
+}
+
+// PublicPageStatic serves the stylesheet of the public pages under /static/
+func PublicPageStatic() http.Handler {
+	static, err := fs.Sub(pageStatic, "static")
+	if err != nil {
+		panic(err)
+	}
+	return http.StripPrefix("/static/", http.FileServer(http.FS(static)))
+}
+
+// pageMeta is the head of every page, including the Open Graph tags for link previews
+type pageMeta struct {
+	Title       string
+	Description string
+	URL         string
+}
+
+type pageSchedule struct {
+	Time  string
+	Title string
+	Memo  string
+}
+
+type pageDay struct {
+	Date      string
+	Schedules []pageSchedule
+}
+
+type tripPage struct {
+	Meta     pageMeta
+	Title    string
+	Period   string
+	TimeZone string
+	Members  []string
+	Days     []pageDay
+}
+
+type unlockPage struct {
+	Meta     pageMeta
+	Action   string
+	TimeZone string
+	Error    string
+}
+
+type errorPage struct {
+	Meta    pageMeta
+	Message string
+}
+
+func (h *publicPageHandler) GetPublicTripPage(ctx echo.Context) error {
+	shareToken := ctx.Param("shareToken")
+	tz := ctx.QueryParam("tz")
+	if tz == "" {
+		tz = defaultPageTimeZone
+	}
+	// "Local" would show the server's zone, which the caller can not know
+	loc, err := time.LoadLocation(tz)
+	if err != nil || tz == "Local" {
+		return h.renderError(ctx, http.StatusBadRequest, "タイムゾーンが正しくありません（例: Asia/Tokyo）")
+	}
+
+	var grant string
+	if cookie, err := ctx.Cookie(ShareGrantCookie); err == nil {
+		grant = cookie.Value
+	}
+	_, shareLink, err := h.ptu.GetTripByShareToken(ctx.Request().Context(), shareToken, grant, "", domain.ShareScopeView, true)
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrShareLinkLocked):
+			return h.renderUnlock(ctx, http.StatusUnauthorized, shareToken, tz, "")
+		case errors.Is(err, usecase.ErrTripNotFound):
+			return h.renderError(ctx, http.StatusNotFound, "旅行が見つかりません")
+		case errors.Is(err, usecase.ErrShareLinkExpired):
+			return h.renderError(ctx, http.StatusGone, "この共有リンクは有効期限が切れています")
+		case errors.Is(err, usecase.ErrShareScopeInsufficient):
+			return h.renderError(ctx, http.StatusForbidden, "この共有リンクでは旅行を表示できません")
+		default:
+			return h.renderError(ctx, http.StatusInternalServerError, "エラーが発生しました")
+		}
+	}
+
+	status := http.StatusOK
+	defer func() {
+		h.sau.RecordAccess(shareLink, ctx.Request().Method, ctx.Path(), status, ctx.RealIP(), ctx.Request().UserAgent())
+	}()
+
+	trip, err := h.ptu.GetTripDetailsByShareToken(ctx.Request().Context(), shareToken)
+	if err != nil {
+		if errors.Is(err, usecase.ErrTripNotFound) {
+			status = http.StatusNotFound
+			return h.renderError(ctx, status, "旅行が見つかりません")
+		}
+		status = http.StatusInternalServerError
+		return h.renderError(ctx, status, "エラーが発生しました")
+	}
+
+	page := toTripPage(trip, loc)
+	page.Meta.URL = pageURL(ctx)
+	return h.render(ctx, status, "trip.html", page)
+}
+
+func (h *publicPageHandler) UnlockPublicTripPage(ctx echo.Context) error {
+	shareToken := ctx.Param("shareToken")
+	tz := ctx.FormValue("tz")
+	passphrase := ctx.FormValue("passphrase")
+
+	grant, expiresAt, err := h.ptu.UnlockShareLink(ctx.Request().Context(), shareToken, passphrase)
+	if err != nil {
+		var throttled *usecase.ShareUnlockThrottledError
+		switch {
+		case errors.As(err, &throttled):
+			retryAfter := int(math.Ceil(throttled.RetryAfter.Seconds()))
+			ctx.Response().Header().Set("Retry-After", strconv.Itoa(retryAfter))
+			return h.renderUnlock(ctx, http.StatusTooManyRequests, shareToken, tz, fmt.Sprintf("試行回数が多すぎます。%d秒後に再度お試しください", retryAfter))
+		case errors.Is(err, usecase.ErrInvalidSharePassphrase):
+			return h.renderUnlock(ctx, http.StatusUnauthorized, shareToken, tz, "パスフレーズが正しくありません")
+		case errors.Is(err, usecase.ErrShareLinkNotProtected):
+			// nothing to unlock, show the trip
+			return ctx.Redirect(http.StatusSeeOther, tripPagePath(shareToken, tz))
+		case errors.Is(err, usecase.ErrTripNotFound):
+			return h.renderError(ctx, http.StatusNotFound, "旅行が見つかりません")
+		case errors.Is(err, usecase.ErrShareLinkExpired):
+			return h.renderError(ctx, http.StatusGone, "この共有リンクは有効期限が切れています")
+		default:
+			return h.renderError(ctx, http.StatusInternalServerError, "エラーが発生しました")
+		}
+	}
+
+	ctx.SetCookie(&http.Cookie{
+		Name:     ShareGrantCookie,
+		Value:    grant,
+		Path:     "/p/" + url.PathEscape(shareToken),
+		Expires:  expiresAt,
+		MaxAge:   int(time.Until(expiresAt).Seconds()),
+		Secure:   ctx.Scheme() == "https",
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+	return ctx.Redirect(http.StatusSeeOther, tripPagePath(shareToken, tz))
+}
+
+// toTripPage groups the schedules by their start day in loc. Every day of the trip is listed,
+// plus the days of schedules that fall outside of it. A trip longer than maxPageEmptyDays
+// lists only the days with schedules.
+func toTripPage(trip *domain.Trip, loc *time.Location) tripPage {
+	schedules := make([]domain.Schedule, len(trip.Schedules))
+	copy(schedules, trip.Schedules)
+	sort.SliceStable(schedules, func(i, j int) bool {
+		return schedules[i].StartDateTime.Before(schedules[j].StartDateTime)
+	})
+
+	byDay := make(map[string][]pageSchedule)
+	var dates []time.Time
+	addDate := func(d time.Time) string {
+		key := d.Format(time.DateOnly)
+		if _, ok := byDay[key]; !ok {
+			byDay[key] = nil
+			dates = append(dates, d)
+		}
+		return key
+	}
+	if trip.EndDate.Sub(trip.StartDate) < maxPageEmptyDays*24*time.Hour {
+		for d := trip.StartDate; !d.After(trip.EndDate); d = d.AddDate(0, 0, 1) {
+			addDate(time.Date(d.Year(), d.Month(), d.Day(), 0, 0, 0, 0, time.UTC))
+		}
+	}
+	for _, s := range schedules {
+		start := s.StartDateTime.In(loc)
+		end := s.EndDateTime.In(loc)
+		key := addDate(time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.UTC))
+		endFormat := "15:04"
+		if end.Format(time.DateOnly) != start.Format(time.DateOnly) {
+			endFormat = "1/2 15:04"
+		}
+		byDay[key] = append(byDay[key], pageSchedule{
+			Time:  start.Format("15:04") + "〜" + end.Format(endFormat),
+			Title: s.Title,
+			Memo:  s.Memo,
+		})
+	}
+	sort.Slice(dates, func(i, j int) bool { return dates[i].Before(dates[j]) })
+
+	days := make([]pageDay, len(dates))
+	for i, d := range dates {
+		days[i] = pageDay{
+			Date:      formatPageDate(d),
+			Schedules: byDay[d.Format(time.DateOnly)],
+		}
+	}
+	members := make([]string, len(trip.Members))
+	for i, m := range trip.Members {
+		members[i] = m.Name
+	}
+
+	period := formatPageDate(trip.StartDate) + " 〜 " + formatPageDate(trip.EndDate)
+	return tripPage{
+		Meta: pageMeta{
+			Title:       trip.Title,
+			Description: fmt.Sprintf("%s・メンバー%d人・予定%d件", period, len(members), len(schedules)),
+		},
+		Title:    trip.Title,
+		Period:   period,
+		TimeZone: loc.String(),
+		Members:  members,
+		Days:     days,
+	}
+}
+
+func formatPageDate(d time.Time) string {
+	return fmt.Sprintf("%d/%d/%d（%s）", d.Year(), d.Month(), d.Day(), pageWeekdays[d.Weekday()])
+}
+
+func tripPagePath(shareToken, tz string) string {
+	path := "/p/" + url.PathEscape(shareToken)
+	if tz != "" {
+		path += "?tz=" + url.QueryEscape(tz)
+	}
+	return path
+}
+
+// pageURL is the absolute url of the page for og:url
+func pageURL(ctx echo.Context) string {
+	return ctx.Scheme() + "://" + ctx.Request().Host + ctx.Request().URL.RequestURI()
+}
+
+func (h *publicPageHandler) renderUnlock(ctx echo.Context, status int, shareToken, tz, message string) error {
+	return h.render(ctx, status, "unlock.html", unlockPage{
+		Meta:     pageMeta{Title: "パスフレーズが必要です", Description: "この旅行はパスフレーズで保護されています"},
+		Action:   "/p/" + url.PathEscape(shareToken) + "/unlock",
+		TimeZone: tz,
+		Error:    message,
+	})
+}
+
+// renderError shows a generic page; it says nothing about the trip, so it is safe before the link is checked
+func (h *publicPageHandler) renderError(ctx echo.Context, status int, message string) error {
+	return h.render(ctx, status, "error.html", errorPage{
+		Meta:    pageMeta{Title: "Trip App", Description: message},
+		Message: message,
+	})
+}
+
+func (h *publicPageHandler) render(ctx echo.Context, status int, name string, data interface{}) error {
+	// render into a buffer so a template error does not leave a half-written page
+	var buf bytes.Buffer
+	if err := h.tmpl.ExecuteTemplate(&buf, name, data); err != nil {
+		log.Printf("failed to render %s: %v", name, err)
+		return ctx.String(http.StatusInternalServerError, "Internal server error")
+	}
+	// the page can contain a passphrase form and private plans, never cache it
+	ctx.Response().Header().Set("Cache-Control", "no-store")
+	return ctx.HTMLBlob(status, buf.Bytes())
+}
@@ -92,7 +92,9 @@ func (h *publicScheduleHandler) GetSchedulesForPublicTrip(ctx echo.Context, shar
 
 // (GET /public/trips/{shareToken}/schedules/{scheduleId})
 func (h *publicScheduleHandler) GetScheduleForPublicTrip(ctx echo.Context, shareToken api.ShareToken, scheduleId api.ScheduleId) error {
-	schedule, err := h.su.GetScheduleByID(ctx.Request().Context(), scheduleId)
+	trip := ctx.Get("trip").(*domain.Trip)
+
+	schedule, err := h.su.GetScheduleByID(ctx.Request().Context(), trip.ID, scheduleId)
 	if err != nil {
 		if errors.Is(err, usecase.ErrScheduleNotFound) {
 			return ctx.JSON(http.StatusNotFound, map[string]string{"message": "Schedule not found"})
@@ -115,6 +117,8 @@ func (h *publicScheduleHandler) GetScheduleForPublicTrip(ctx echo.Context, share
 
 // (PATCH /public/trips/{shareToken}/schedules/{scheduleId})
 func (h *publicScheduleHandler) UpdateScheduleForPublicTrip(ctx echo.Context, shareToken api.ShareToken, scheduleId api.ScheduleId) error {
+	trip := ctx.Get("trip").(*domain.Trip)
+
 	var req api.UpdateSchedule
 	if err := ctx.Bind(&req); err != nil {
 		return ctx.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid request body"})
@@ -122,6 +126,7 @@ func (h *publicScheduleHandler) UpdateScheduleForPublicTrip(ctx echo.Context, sh
 
 	updatedSchedule, err := h.su.UpdateSchedule(
 		ctx.Request().Context(),
+		trip.ID,
 		scheduleId,
 		usecase.UpdateScheduleParams{
 			Title:         req.Title,
@@ -155,7 +160,9 @@ func (h *publicScheduleHandler) UpdateScheduleForPublicTrip(ctx echo.Context, sh
 
 // (DELETE /public/trips/{shareToken}/schedules/{scheduleId})
 func (h *publicScheduleHandler) DeleteScheduleForPublicTrip(ctx echo.Context, shareToken api.ShareToken, scheduleId api.ScheduleId) error {
-	if err := h.su.DeleteSchedule(ctx.Request().Context(), scheduleId); err != nil {
+	trip := ctx.Get("trip").(*domain.Trip)
+
+	if err := h.su.DeleteSchedule(ctx.Request().Context(), trip.ID, scheduleId); err != nil {
 		if errors.Is(err, usecase.ErrScheduleNotFound) {
 			return ctx.JSON(http.StatusNotFound, map[string]string{"message": "Schedule not found"})
 		}
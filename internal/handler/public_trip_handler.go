@@ -82,27 +82,21 @@ func (h *publicTripHandler) UpdatePublicTripByShareToken(ctx echo.Context, share
 		return ctx.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid request body"})
 	}
 
-	var members []domain.Member
-	if req.Members != nil {
-		for _, m := range *req.Members {
-			if m.Name != nil {
-				members = append(members, domain.Member{Name: *m.Name})
-			}
-		}
-	}
-
 	updatedTrip, err := h.ptu.UpdateTripByShareToken(
 		ctx.Request().Context(),
 		shareToken,
 		req.Title,
 		req.StartDate.Time,
 		req.EndDate.Time,
-		members,
+		toDomainMembers(req.Members),
 	)
 	if err != nil {
 		if errors.Is(err, usecase.ErrTripNotFound) {
 			return ctx.JSON(http.StatusNotFound, map[string]string{"message": "Trip not found"})
 		}
+		if errors.Is(err, usecase.ErrValidation) || errors.Is(err, usecase.ErrMemberNotFound) {
+			return ctx.JSON(http.StatusBadRequest, map[string]string{"message": err.Error()})
+		}
 		return ctx.JSON(http.StatusInternalServerError, map[string]string{"message": "Internal server error"})
 	}
 
@@ -125,3 +119,34 @@ func (h *publicTripHandler) GetTripDetailsForPublicTrip(ctx echo.Context, shareT
 
 	return ctx.JSON(http.StatusOK, res)
 }
+
+func (h *publicTripHandler) UnlockPublicTrip(ctx echo.Context, shareToken api.ShareToken) error {
+	var req api.ShareLinkUnlockRequest
+	if err := ctx.Bind(&req); err != nil {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid request body"})
+	}
+
+	grant, expiresAt, err := h.ptu.UnlockShareLink(ctx.Request().Context(), shareToken, req.Passphrase)
+	if err != nil {
+		var throttled *usecase.ShareUnlockThrottledError
+		switch {
+		case errors.As(err, &throttled):
+			return tooManyRequests(ctx, throttled.RetryAfter, throttled.Error())
+		case errors.Is(err, usecase.ErrTripNotFound):
+			return ctx.JSON(http.StatusNotFound, map[string]string{"message": "Trip not found"})
+		case errors.Is(err, usecase.ErrShareLinkExpired):
+			return ctx.JSON(http.StatusGone, map[string]string{"message": "Share link has expired"})
+		case errors.Is(err, usecase.ErrShareLinkNotProtected):
+			return ctx.JSON(http.StatusBadRequest, map[string]string{"message": err.Error()})
+		case errors.Is(err, usecase.ErrInvalidSharePassphrase):
+			return ctx.JSON(http.StatusUnauthorized, map[string]string{"message": err.Error()})
+		default:
+			return ctx.JSON(http.StatusInternalServerError, map[string]string{"message": "Internal server error"})
+		}
+	}
+
+	return ctx.JSON(http.StatusOK, api.ShareGrantResponse{
+		GrantToken: grant,
+		ExpiresAt:  expiresAt,
+	})
+}
@@ -0,0 +1,126 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+
+	"trip_app/api"
+	"trip_app/internal/infrastructure/schedulecsv"
+	"trip_app/internal/usecase"
+
+	"github.com/labstack/echo/v4"
+)
+
+type scheduleCSVHandler struct {
+	su  usecase.ScheduleUsecase
+	scu usecase.ScheduleCSVUsecase
+}
+
+func NewScheduleCSVHandler(su usecase.ScheduleUsecase, scu usecase.ScheduleCSVUsecase) *scheduleCSVHandler {
+	return &scheduleCSVHandler{su, scu}
+}
+
+func toAPIScheduleCsvImportReport(atomic bool, results []usecase.ScheduleCSVResult) api.ScheduleCsvImportReport {
+	report := api.ScheduleCsvImportReport{
+		Atomic: atomic,
+		Rows:   make([]api.ScheduleCsvImportRow, len(results)),
+	}
+	for i, result := range results {
+		row := api.ScheduleCsvImportRow{
+			Line:   result.Line,
+			Status: string(result.Status),
+		}
+		if result.Title != "" {
+			row.Title = &result.Title
+		}
+		if result.Schedule != nil {
+			row.Schedule = toAPIImportedSchedule(result.Schedule)
+		}
+		if result.Err != nil {
+			message := result.Err.Error()
+			row.Error = &message
+		}
+
+		switch result.Status {
+		case usecase.ScheduleCSVCreated:
+			report.Created++
+		case usecase.ScheduleCSVFailed:
+			report.Failed++
+		case usecase.ScheduleCSVSkipped:
+			report.Skipped++
+		}
+		report.Rows[i] = row
+	}
+	return report
+}
+
+// (GET /trips/{tripId}/schedules.csv)
+func (h *scheduleCSVHandler) ExportSchedulesCsv(ctx echo.Context, tripId api.TripId, params api.ExportSchedulesCsvParams) error {
+	loc, ok := timeZoneParam(params.Tz)
+	if !ok {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid time zone"})
+	}
+
+	schedules, err := h.su.GetSchedulesByTripID(ctx.Request().Context(), tripId)
+	if err != nil {
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{"message": "Internal server error"})
+	}
+	sort.SliceStable(schedules, func(i, j int) bool {
+		return schedules[i].StartDateTime.Before(schedules[j].StartDateTime)
+	})
+
+	records := make([]schedulecsv.Record, len(schedules))
+	for i, s := range schedules {
+		records[i] = schedulecsv.Record{
+			Title: s.Title,
+			Start: s.StartDateTime,
+			End:   s.EndDateTime,
+			Memo:  s.Memo,
+		}
+	}
+	var buf bytes.Buffer
+	if err := schedulecsv.Write(&buf, records, loc); err != nil {
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{"message": "Internal server error"})
+	}
+
+	ctx.Response().Header().Set(echo.HeaderContentDisposition, `attachment; filename="schedules.csv"`)
+	ctx.Response().Header().Set("Cache-Control", "no-store")
+	return ctx.Blob(http.StatusOK, schedulecsv.ContentType, buf.Bytes())
+}
+
+// (POST /trips/{tripId}/schedules.csv)
+func (h *scheduleCSVHandler) ImportSchedulesCsv(ctx echo.Context, tripId api.TripId, params api.ImportSchedulesCsvParams) error {
+	loc, ok := timeZoneParam(params.Tz)
+	if !ok {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid time zone"})
+	}
+	atomic := params.Atomic != nil && *params.Atomic
+
+	req := ctx.Request()
+	req.Body = http.MaxBytesReader(ctx.Response(), req.Body, maxScheduleImportSize)
+	file, err := importFile(ctx)
+	if err != nil {
+		if isTooLarge(err) {
+			return importTooLarge(ctx)
+		}
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"message": fmt.Sprintf("A CSV file is required in the %q field", scheduleImportFormField)})
+	}
+	defer file.Close()
+
+	results, err := h.scu.Import(req.Context(), tripId, file, loc, atomic)
+	if err != nil {
+		switch {
+		case isTooLarge(err):
+			return importTooLarge(ctx)
+		case errors.Is(err, usecase.ErrValidation):
+			return ctx.JSON(http.StatusBadRequest, map[string]string{"message": err.Error()})
+		default:
+			return ctx.JSON(http.StatusInternalServerError, map[string]string{"message": "Internal server error"})
+		}
+	}
+
+	return ctx.JSON(http.StatusOK, toAPIScheduleCsvImportReport(atomic, results))
+}
@@ -88,7 +88,7 @@ func (h *scheduleHandler) GetSchedulesForTrip(ctx echo.Context, tripId api.TripI
 
 // (GET /trips/{tripId}/schedules/{scheduleId})
 func (h *scheduleHandler) GetScheduleForTrip(ctx echo.Context, tripId api.TripId, scheduleId api.ScheduleId) error {
-	schedule, err := h.su.GetScheduleByID(ctx.Request().Context(), scheduleId)
+	schedule, err := h.su.GetScheduleByID(ctx.Request().Context(), tripId, scheduleId)
 	if err != nil {
 		if errors.Is(err, usecase.ErrScheduleNotFound) {
 			return ctx.JSON(http.StatusNotFound, map[string]string{"message": "Schedule not found"})
@@ -118,6 +118,7 @@ func (h *scheduleHandler) UpdateScheduleForTrip(ctx echo.Context, tripId api.Tri
 
 	updatedSchedule, err := h.su.UpdateSchedule(
 		ctx.Request().Context(),
+		tripId,
 		scheduleId,
 		usecase.UpdateScheduleParams{
 			Title:         req.Title,
@@ -151,7 +152,7 @@ func (h *scheduleHandler) UpdateScheduleForTrip(ctx echo.Context, tripId api.Tri
 
 // (DELETE /trips/{tripId}/schedules/{scheduleId})
 func (h *scheduleHandler) DeleteScheduleForTrip(ctx echo.Context, tripId api.TripId, scheduleId api.ScheduleId) error {
-	if err := h.su.DeleteSchedule(ctx.Request().Context(), scheduleId); err != nil {
+	if err := h.su.DeleteSchedule(ctx.Request().Context(), tripId, scheduleId); err != nil {
 		if errors.Is(err, usecase.ErrScheduleNotFound) {
 			return ctx.JSON(http.StatusNotFound, map[string]string{"message": "Schedule not found"})
 		}
@@ -0,0 +1,151 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"trip_app/api"
+	"trip_app/internal/domain"
+	"trip_app/internal/usecase"
+
+	"github.com/labstack/echo/v4"
+)
+
+// maxScheduleImportSize is the largest request the import accepts, including the multipart envelope
+const maxScheduleImportSize = 1 << 20
+
+// scheduleImportFormField is the multipart field that carries the .ics file
+const scheduleImportFormField = "file"
+
+type scheduleImportHandler struct {
+	siu usecase.ScheduleImportUsecase
+}
+
+func NewScheduleImportHandler(siu usecase.ScheduleImportUsecase) *scheduleImportHandler {
+	return &scheduleImportHandler{siu}
+}
+
+func toAPIScheduleImportReport(dryRun bool, results []usecase.ScheduleImportResult) api.ScheduleImportReport {
+	report := api.ScheduleImportReport{
+		DryRun:  dryRun,
+		Results: make([]api.ScheduleImportResult, len(results)),
+	}
+	for i, result := range results {
+		res := api.ScheduleImportResult{
+			Index:  i,
+			Status: string(result.Status),
+		}
+		if result.UID != "" {
+			res.Uid = &result.UID
+		}
+		if result.Summary != "" {
+			res.Summary = &result.Summary
+		}
+		if result.Schedule != nil {
+			res.Schedule = toAPIImportedSchedule(result.Schedule)
+		}
+		if result.Err != nil {
+			message := result.Err.Error()
+			res.Error = &message
+		}
+
+		switch result.Status {
+		case usecase.ScheduleImportCreated, usecase.ScheduleImportWouldCreate:
+			report.Created++
+		case usecase.ScheduleImportDuplicate:
+			report.Duplicates++
+		case usecase.ScheduleImportFailed:
+			report.Failed++
+		}
+		report.Results[i] = res
+	}
+	return report
+}
+
+// toAPIImportedSchedule leaves out the id and timestamps of a dry run, which has not saved the schedule
+func toAPIImportedSchedule(schedule *domain.Schedule) *api.Schedule {
+	res := &api.Schedule{
+		Title:         &schedule.Title,
+		StartDateTime: &schedule.StartDateTime,
+		EndDateTime:   &schedule.EndDateTime,
+		Memo:          &schedule.Memo,
+	}
+	if !schedule.CreatedAt.IsZero() {
+		res.Id = &schedule.ID
+		res.CreatedAt = &schedule.CreatedAt
+		res.UpdatedAt = &schedule.UpdatedAt
+	}
+	return res
+}
+
+// (POST /trips/{tripId}/schedules/import)
+func (h *scheduleImportHandler) ImportSchedulesToTrip(ctx echo.Context, tripId api.TripId, params api.ImportSchedulesToTripParams) error {
+	loc, ok := timeZoneParam(params.Tz)
+	if !ok {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid time zone"})
+	}
+	dryRun := params.DryRun != nil && *params.DryRun
+
+	req := ctx.Request()
+	req.Body = http.MaxBytesReader(ctx.Response(), req.Body, maxScheduleImportSize)
+	file, err := importFile(ctx)
+	if err != nil {
+		if isTooLarge(err) {
+			return importTooLarge(ctx)
+		}
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"message": fmt.Sprintf("An iCalendar file is required in the %q field", scheduleImportFormField)})
+	}
+	defer file.Close()
+
+	results, err := h.siu.Import(req.Context(), tripId, file, loc, dryRun)
+	if err != nil {
+		switch {
+		case isTooLarge(err):
+			return importTooLarge(ctx)
+		case errors.Is(err, usecase.ErrValidation):
+			return ctx.JSON(http.StatusBadRequest, map[string]string{"message": err.Error()})
+		default:
+			return ctx.JSON(http.StatusInternalServerError, map[string]string{"message": "Internal server error"})
+		}
+	}
+
+	return ctx.JSON(http.StatusOK, toAPIScheduleImportReport(dryRun, results))
+}
+
+// timeZoneParam loads the zone of an optional tz query parameter, UTC by default.
+// "Local" is refused: it would be the server's zone, which the caller can not know.
+func timeZoneParam(tz *string) (*time.Location, bool) {
+	if tz == nil || *tz == "" {
+		return time.UTC, true
+	}
+	loc, err := time.LoadLocation(*tz)
+	if err != nil || *tz == "Local" {
+		return nil, false
+	}
+	return loc, true
+}
+
+// importFile returns the uploaded file: the file field of a multipart form, or else the request body itself
+func importFile(ctx echo.Context) (io.ReadCloser, error) {
+	if strings.HasPrefix(ctx.Request().Header.Get(echo.HeaderContentType), echo.MIMEMultipartForm) {
+		header, err := ctx.FormFile(scheduleImportFormField)
+		if err != nil {
+			return nil, err
+		}
+		return header.Open()
+	}
+	return ctx.Request().Body, nil
+}
+
+func isTooLarge(err error) bool {
+	var maxBytesErr *http.MaxBytesError
+	return errors.As(err, &maxBytesErr)
+}
+
+func importTooLarge(ctx echo.Context) error {
+	return ctx.JSON(http.StatusRequestEntityTooLarge, map[string]string{"message": fmt.Sprintf("The file must be at most %d bytes", maxScheduleImportSize)})
+}
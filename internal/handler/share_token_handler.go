@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 
 	"trip_app/api"
@@ -8,14 +9,35 @@ import (
 	"trip_app/internal/usecase"
 
 	"github.com/labstack/echo/v4"
+	openapi_types "github.com/oapi-codegen/runtime/types"
 )
 
 type shareTokenHandler struct {
-	u usecase.ShareTokenUsecase
+	u   usecase.ShareTokenUsecase
+	sau usecase.ShareActivityUsecase
 }
 
-func NewShareTokenHandler(u usecase.ShareTokenUsecase) *shareTokenHandler {
-	return &shareTokenHandler{u}
+func NewShareTokenHandler(u usecase.ShareTokenUsecase, sau usecase.ShareActivityUsecase) *shareTokenHandler {
+	return &shareTokenHandler{u, sau}
+}
+
+func toAPIShareLinkResponse(shareToken *domain.ShareToken, token string) api.ShareLinkResponse {
+	shareUrl := "/public/trips/" + token
+	scope := string(shareToken.Scope)
+	passwordProtected := shareToken.IsPasswordProtected()
+
+	return api.ShareLinkResponse{
+		Id:                &shareToken.ID,
+		Name:              &shareToken.Name,
+		Scope:             &scope,
+		ExpiresAt:         shareToken.ExpiresAt,
+		MaxUses:           shareToken.MaxUses,
+		ShareToken:        &token,
+		PasswordProtected: &passwordProtected,
+		ShareUrl:          &shareUrl,
+		CreatedAt:         &shareToken.CreatedAt,
+		UpdatedAt:         &shareToken.UpdatedAt,
+	}
 }
 
 func (h *shareTokenHandler) CreateShareLinkForTrip(ctx echo.Context, tripId api.TripId, params api.CreateShareLinkForTripParams) error {
@@ -31,17 +53,106 @@ func (h *shareTokenHandler) CreateShareLinkForTrip(ctx echo.Context, tripId api.
 
 	shareToken, token, err := h.u.CreateShareToken(ctx.Request().Context(), trip.ID, regenerate)
 	if err != nil {
+		if errors.Is(err, usecase.ErrShareLinkAlreadyExists) {
+			return ctx.JSON(http.StatusConflict, map[string]string{"message": "Share token already exists"})
+		}
 		return err
 	}
 
-	shareUrl := "/public/trips/" + token
+	return ctx.JSON(http.StatusCreated, toAPIShareLinkResponse(shareToken, token))
+}
 
-	res := api.ShareLinkResponse{
-		ShareToken: &token,
-		ShareUrl:   &shareUrl,
-		CreatedAt:  &shareToken.CreatedAt,
-		UpdatedAt:  &shareToken.UpdatedAt,
+func (h *shareTokenHandler) ListTripShareLinks(ctx echo.Context, tripId api.TripId) error {
+	shareTokens, err := h.u.ListShareLinks(ctx.Request().Context(), tripId)
+	if err != nil {
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{"message": "Internal server error"})
+	}
+
+	res := make([]api.ShareLink, len(shareTokens))
+	for i, shareToken := range shareTokens {
+		res[i] = api.ShareLink{
+			Id:                shareToken.ID,
+			Name:              shareToken.Name,
+			Scope:             string(shareToken.Scope),
+			ExpiresAt:         shareToken.ExpiresAt,
+			MaxUses:           shareToken.MaxUses,
+			UseCount:          shareToken.UseCount,
+			PasswordProtected: shareToken.IsPasswordProtected(),
+			LastUsedAt:        shareToken.LastUsedAt,
+			CreatedAt:         shareToken.CreatedAt,
+		}
+	}
+
+	return ctx.JSON(http.StatusOK, res)
+}
+
+func (h *shareTokenHandler) CreateTripShareLink(ctx echo.Context, tripId api.TripId) error {
+	var req api.ShareLinkCreateRequest
+	if err := ctx.Bind(&req); err != nil {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid request body"})
+	}
+
+	shareToken, token, err := h.u.CreateShareLink(ctx.Request().Context(), tripId, req.Name, domain.ShareScope(req.Scope), req.ExpiresAt, req.MaxUses, req.Passphrase)
+	if err != nil {
+		if errors.Is(err, usecase.ErrValidation) {
+			return ctx.JSON(http.StatusBadRequest, map[string]string{"message": err.Error()})
+		}
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{"message": "Internal server error"})
+	}
+
+	return ctx.JSON(http.StatusCreated, toAPIShareLinkResponse(shareToken, token))
+}
+
+func (h *shareTokenHandler) RevokeTripShareLink(ctx echo.Context, tripId api.TripId, shareLinkId api.ShareLinkId) error {
+	if err := h.u.RevokeShareLink(ctx.Request().Context(), tripId, shareLinkId); err != nil {
+		if errors.Is(err, usecase.ErrShareLinkNotFound) {
+			return ctx.JSON(http.StatusNotFound, map[string]string{"message": err.Error()})
+		}
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{"message": "Internal server error"})
+	}
+
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+func (h *shareTokenHandler) GetTripShareActivity(ctx echo.Context, tripId api.TripId, params api.GetTripShareActivityParams) error {
+	var days, limit int
+	if params.Days != nil {
+		days = *params.Days
+	}
+	if params.Limit != nil {
+		limit = *params.Limit
+	}
+
+	activity, err := h.sau.GetActivity(ctx.Request().Context(), tripId, days, limit)
+	if err != nil {
+		if errors.Is(err, usecase.ErrValidation) {
+			return ctx.JSON(http.StatusBadRequest, map[string]string{"message": err.Error()})
+		}
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{"message": "Internal server error"})
+	}
+
+	res := api.ShareActivity{
+		Daily:       make([]api.ShareActivityDay, len(activity.Daily)),
+		RecentEdits: make([]api.ShareLinkEdit, len(activity.RecentEdits)),
+	}
+	for i, d := range activity.Daily {
+		res.Daily[i] = api.ShareActivityDay{
+			Date:     openapi_types.Date{Time: d.Date},
+			Requests: d.Requests,
+			Visitors: d.Visitors,
+		}
+	}
+	for i, e := range activity.RecentEdits {
+		res.RecentEdits[i] = api.ShareLinkEdit{
+			ShareLinkId:     e.ShareTokenID,
+			ShareLinkName:   e.ShareLinkName,
+			Method:          e.Method,
+			Route:           e.Route,
+			Status:          e.Status,
+			UserAgentFamily: e.UserAgentFamily,
+			AccessedAt:      e.AccessedAt,
+		}
 	}
 
-	return ctx.JSON(http.StatusCreated, res)
+	return ctx.JSON(http.StatusOK, res)
 }
@@ -0,0 +1,84 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"trip_app/api"
+	"trip_app/internal/domain"
+	"trip_app/internal/infrastructure/tripbackup"
+	"trip_app/internal/usecase"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// maxTripBackupSize is the largest request the trip import accepts
+const maxTripBackupSize = 5 << 20
+
+type tripBackupHandler struct {
+	tbu usecase.TripBackupUsecase
+}
+
+func NewTripBackupHandler(tbu usecase.TripBackupUsecase) *tripBackupHandler {
+	return &tripBackupHandler{tbu}
+}
+
+// (GET /trips/{tripId}/export)
+func (h *tripBackupHandler) ExportTrip(ctx echo.Context, tripId api.TripId) error {
+	doc, err := h.tbu.Export(ctx.Request().Context(), tripId)
+	if err != nil {
+		if errors.Is(err, usecase.ErrTripNotFound) {
+			return ctx.JSON(http.StatusNotFound, map[string]string{"message": err.Error()})
+		}
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{"message": "Internal server error"})
+	}
+
+	var buf bytes.Buffer
+	if err := tripbackup.Encode(&buf, doc); err != nil {
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{"message": "Internal server error"})
+	}
+
+	ctx.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="trip-%s.json"`, tripId))
+	ctx.Response().Header().Set("Cache-Control", "no-store")
+	return ctx.Blob(http.StatusOK, echo.MIMEApplicationJSON, buf.Bytes())
+}
+
+// (POST /trips/import)
+func (h *tripBackupHandler) ImportTrip(ctx echo.Context) error {
+	userID, ok := ctx.Get("user_id").(uuid.UUID)
+	if !ok {
+		return ctx.JSON(http.StatusUnauthorized, map[string]string{"message": "Unauthorized"})
+	}
+
+	req := ctx.Request()
+	req.Body = http.MaxBytesReader(ctx.Response(), req.Body, maxTripBackupSize)
+	file, err := importFile(ctx)
+	if err != nil {
+		if isTooLarge(err) {
+			return backupTooLarge(ctx)
+		}
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"message": fmt.Sprintf("A trip backup is required in the %q field", scheduleImportFormField)})
+	}
+	defer file.Close()
+
+	trip, err := h.tbu.Import(req.Context(), userID, file)
+	if err != nil {
+		switch {
+		case isTooLarge(err):
+			return backupTooLarge(ctx)
+		case errors.Is(err, usecase.ErrValidation):
+			return ctx.JSON(http.StatusBadRequest, map[string]string{"message": err.Error()})
+		default:
+			return ctx.JSON(http.StatusInternalServerError, map[string]string{"message": "Internal server error"})
+		}
+	}
+
+	return ctx.JSON(http.StatusCreated, toAPITripWithRole(trip, domain.TripRoleOwner))
+}
+
+func backupTooLarge(ctx echo.Context) error {
+	return ctx.JSON(http.StatusRequestEntityTooLarge, map[string]string{"message": fmt.Sprintf("The backup must be at most %d bytes", maxTripBackupSize)})
+}
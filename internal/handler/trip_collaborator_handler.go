@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"trip_app/api"
+	"trip_app/internal/domain"
+	"trip_app/internal/usecase"
+
+	"github.com/labstack/echo/v4"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+type tripCollaboratorHandler struct {
+	tcu usecase.TripCollaboratorUsecase
+}
+
+func NewTripCollaboratorHandler(tcu usecase.TripCollaboratorUsecase) *tripCollaboratorHandler {
+	return &tripCollaboratorHandler{tcu}
+}
+
+func toAPITripCollaborator(collaborator *domain.TripCollaborator) api.TripCollaborator {
+	return api.TripCollaborator{
+		UserId:    collaborator.UserID,
+		Name:      collaborator.User.Name,
+		Email:     openapi_types.Email(collaborator.User.Email),
+		Role:      string(collaborator.Role),
+		CreatedAt: collaborator.CreatedAt,
+	}
+}
+
+// collaboratorError maps the errors of the collaborator usecase to a response
+func collaboratorError(ctx echo.Context, err error) error {
+	switch {
+	case errors.Is(err, usecase.ErrValidation):
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"message": err.Error()})
+	case errors.Is(err, usecase.ErrUserNotFound), errors.Is(err, usecase.ErrCollaboratorNotFound), errors.Is(err, usecase.ErrTripNotFound):
+		return ctx.JSON(http.StatusNotFound, map[string]string{"message": err.Error()})
+	case errors.Is(err, usecase.ErrCollaboratorAlreadyExists), errors.Is(err, usecase.ErrOwnerRoleImmutable):
+		return ctx.JSON(http.StatusConflict, map[string]string{"message": err.Error()})
+	default:
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{"message": "Internal server error"})
+	}
+}
+
+func (h *tripCollaboratorHandler) ListTripCollaborators(ctx echo.Context, tripId api.TripId) error {
+	collaborators, err := h.tcu.List(ctx.Request().Context(), tripId)
+	if err != nil {
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{"message": "Internal server error"})
+	}
+
+	res := make([]api.TripCollaborator, len(collaborators))
+	for i := range collaborators {
+		res[i] = toAPITripCollaborator(&collaborators[i])
+	}
+
+	return ctx.JSON(http.StatusOK, res)
+}
+
+func (h *tripCollaboratorHandler) AddTripCollaborator(ctx echo.Context, tripId api.TripId) error {
+	var req api.TripCollaboratorCreateRequest
+	if err := ctx.Bind(&req); err != nil {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid request body"})
+	}
+
+	collaborator, err := h.tcu.Add(ctx.Request().Context(), tripId, string(req.Email), domain.TripRole(req.Role))
+	if err != nil {
+		return collaboratorError(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusCreated, toAPITripCollaborator(collaborator))
+}
+
+func (h *tripCollaboratorHandler) UpdateTripCollaborator(ctx echo.Context, tripId api.TripId, userId api.UserId) error {
+	var req api.TripCollaboratorUpdateRequest
+	if err := ctx.Bind(&req); err != nil {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid request body"})
+	}
+
+	collaborator, err := h.tcu.UpdateRole(ctx.Request().Context(), tripId, userId, domain.TripRole(req.Role))
+	if err != nil {
+		return collaboratorError(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, toAPITripCollaborator(collaborator))
+}
+
+func (h *tripCollaboratorHandler) RemoveTripCollaborator(ctx echo.Context, tripId api.TripId, userId api.UserId) error {
+	if err := h.tcu.Remove(ctx.Request().Context(), tripId, userId); err != nil {
+		return collaboratorError(ctx, err)
+	}
+
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+func (h *tripCollaboratorHandler) TransferTripOwnership(ctx echo.Context, tripId api.TripId) error {
+	var req api.TripOwnershipTransferRequest
+	if err := ctx.Bind(&req); err != nil {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid request body"})
+	}
+
+	if err := h.tcu.TransferOwnership(ctx.Request().Context(), tripId, req.UserId); err != nil {
+		return collaboratorError(ctx, err)
+	}
+
+	return ctx.NoContent(http.StatusNoContent)
+}
@@ -27,15 +27,40 @@ func toAPIMembers(members []domain.Member) *[]api.Member {
 		return nil
 	}
 	apiMembers := make([]api.Member, len(members))
-	for i, m := range members {
-		apiMembers[i] = api.Member{
-			Id:   &m.ID,
-			Name: &m.Name,
-		}
+	for i := range members {
+		apiMembers[i] = toAPIMember(&members[i])
 	}
 	return &apiMembers
 }
 
+// toDomainMembers converts the members of an update request. Members with an id keep it, so they are renamed
+// instead of recreated. nil (members omitted) is kept so the members stay unchanged.
+func toDomainMembers(apiMembers *[]api.Member) []domain.Member {
+	if apiMembers == nil {
+		return nil
+	}
+	members := []domain.Member{}
+	for _, m := range *apiMembers {
+		if m.Name == nil {
+			continue
+		}
+		member := domain.Member{Name: *m.Name}
+		if m.Id != nil {
+			member.ID = *m.Id
+		}
+		members = append(members, member)
+	}
+	return members
+}
+
+func toAPIMember(member *domain.Member) api.Member {
+	return api.Member{
+		Id:     &member.ID,
+		Name:   &member.Name,
+		UserId: member.UserID,
+	}
+}
+
 func toAPITrip(trip *domain.Trip) *api.Trip {
 	if trip == nil {
 		return nil
@@ -51,10 +76,21 @@ func toAPITrip(trip *domain.Trip) *api.Trip {
 	}
 }
 
-func toAPITrips(trips []domain.Trip) []api.Trip {
+// toAPITripWithRole converts the trip and adds the role of the logged-in user
+func toAPITripWithRole(trip *domain.Trip, role domain.TripRole) *api.Trip {
+	apiTrip := toAPITrip(trip)
+	if apiTrip != nil {
+		r := string(role)
+		apiTrip.Role = &r
+	}
+	return apiTrip
+}
+
+func toAPITrips(trips []domain.Trip, userID uuid.UUID) []api.Trip {
 	apiTrips := make([]api.Trip, len(trips))
 	for i, t := range trips {
-		apiTrips[i] = *toAPITrip(&t)
+		role, _ := t.RoleOf(userID)
+		apiTrips[i] = *toAPITripWithRole(&t, role)
 	}
 	return apiTrips
 }
@@ -112,7 +148,7 @@ func (h *tripHandler) CreateUserTrip(ctx echo.Context) error {
 		return ctx.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to create trip"})
 	}
 
-	return ctx.JSON(http.StatusCreated, toAPITrip(createdTrip))
+	return ctx.JSON(http.StatusCreated, toAPITripWithRole(createdTrip, domain.TripRoleOwner))
 }
 
 func (h *tripHandler) GetUserTrips(ctx echo.Context) error {
@@ -126,12 +162,13 @@ func (h *tripHandler) GetUserTrips(ctx echo.Context) error {
 		return ctx.JSON(http.StatusInternalServerError, map[string]string{"message": "Internal server error"})
 	}
 
-	return ctx.JSON(http.StatusOK, toAPITrips(trips))
+	return ctx.JSON(http.StatusOK, toAPITrips(trips, userID))
 }
 
 func (h *tripHandler) GetUserTrip(ctx echo.Context, tripId api.TripId) error {
 	trip := ctx.Get("trip").(*domain.Trip)
-	return ctx.JSON(http.StatusOK, toAPITrip(trip))
+	role, _ := ctx.Get("trip_role").(domain.TripRole)
+	return ctx.JSON(http.StatusOK, toAPITripWithRole(trip, role))
 }
 
 func (h *tripHandler) UpdateUserTrip(ctx echo.Context, tripId api.TripId) error {
@@ -140,31 +177,26 @@ func (h *tripHandler) UpdateUserTrip(ctx echo.Context, tripId api.TripId) error
 		return ctx.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid request body"})
 	}
 
-	var members []domain.Member
-	if req.Members != nil {
-		for _, m := range *req.Members {
-			if m.Name != nil {
-				members = append(members, domain.Member{Name: *m.Name})
-			}
-		}
-	}
-
 	updatedTrip, err := h.tu.UpdateTrip(
 		ctx.Request().Context(),
 		tripId,
 		req.Title,
 		req.StartDate.Time,
 		req.EndDate.Time,
-		members,
+		toDomainMembers(req.Members),
 	)
 	if err != nil {
 		if errors.Is(err, usecase.ErrTripNotFound) {
 			return ctx.JSON(http.StatusNotFound, map[string]string{"message": "Trip not found"})
 		}
+		if errors.Is(err, usecase.ErrValidation) || errors.Is(err, usecase.ErrMemberNotFound) {
+			return ctx.JSON(http.StatusBadRequest, map[string]string{"message": err.Error()})
+		}
 		return ctx.JSON(http.StatusInternalServerError, map[string]string{"message": "Internal server error"})
 	}
 
-	return ctx.JSON(http.StatusOK, toAPITrip(updatedTrip))
+	role, _ := ctx.Get("trip_role").(domain.TripRole)
+	return ctx.JSON(http.StatusOK, toAPITripWithRole(updatedTrip, role))
 }
 
 func (h *tripHandler) GetTripDetails(ctx echo.Context, tripId api.TripId) error {
@@ -176,8 +208,9 @@ func (h *tripHandler) GetTripDetails(ctx echo.Context, tripId api.TripId) error
 		return ctx.JSON(http.StatusInternalServerError, map[string]string{"message": "Internal server error"})
 	}
 
+	role, _ := ctx.Get("trip_role").(domain.TripRole)
 	res := api.TripDetailView{
-		Trip:      toAPITrip(tripWithSchedules),
+		Trip:      toAPITripWithRole(tripWithSchedules, role),
 		Schedules: toAPISchedules(tripWithSchedules.Schedules),
 	}
 
@@ -0,0 +1,117 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"trip_app/api"
+	"trip_app/internal/domain"
+	"trip_app/internal/usecase"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+type tripInvitationHandler struct {
+	tiu usecase.TripInvitationUsecase
+}
+
+func NewTripInvitationHandler(tiu usecase.TripInvitationUsecase) *tripInvitationHandler {
+	return &tripInvitationHandler{tiu}
+}
+
+func toAPITripInvitation(invitation *domain.TripInvitation) api.TripInvitation {
+	return api.TripInvitation{
+		Id:         invitation.ID,
+		Email:      openapi_types.Email(invitation.Email),
+		Role:       string(invitation.Role),
+		MemberName: invitation.MemberName,
+		ExpiresAt:  invitation.ExpiresAt,
+		SentAt:     invitation.SentAt,
+		CreatedAt:  invitation.CreatedAt,
+	}
+}
+
+// invitationError maps the errors of the invitation usecase to a response
+func invitationError(ctx echo.Context, err error) error {
+	var throttled *usecase.InvitationResendThrottledError
+	switch {
+	case errors.As(err, &throttled):
+		return tooManyRequests(ctx, throttled.RetryAfter, throttled.Error())
+	case errors.Is(err, usecase.ErrValidation), errors.Is(err, usecase.ErrInvalidInvitationToken):
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"message": err.Error()})
+	case errors.Is(err, usecase.ErrInvitationEmailMismatch):
+		return ctx.JSON(http.StatusForbidden, map[string]string{"message": err.Error()})
+	case errors.Is(err, usecase.ErrInvitationNotFound), errors.Is(err, usecase.ErrTripNotFound):
+		return ctx.JSON(http.StatusNotFound, map[string]string{"message": err.Error()})
+	case errors.Is(err, usecase.ErrCollaboratorAlreadyExists), errors.Is(err, usecase.ErrInvitationAlreadyExists):
+		return ctx.JSON(http.StatusConflict, map[string]string{"message": err.Error()})
+	default:
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{"message": "Internal server error"})
+	}
+}
+
+func (h *tripInvitationHandler) ListTripInvitations(ctx echo.Context, tripId api.TripId) error {
+	invitations, err := h.tiu.ListPending(ctx.Request().Context(), tripId)
+	if err != nil {
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{"message": "Internal server error"})
+	}
+
+	res := make([]api.TripInvitation, len(invitations))
+	for i := range invitations {
+		res[i] = toAPITripInvitation(&invitations[i])
+	}
+
+	return ctx.JSON(http.StatusOK, res)
+}
+
+func (h *tripInvitationHandler) CreateTripInvitation(ctx echo.Context, tripId api.TripId) error {
+	var req api.TripInvitationCreateRequest
+	if err := ctx.Bind(&req); err != nil {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid request body"})
+	}
+
+	userID, ok := ctx.Get("user_id").(uuid.UUID)
+	if !ok {
+		return ctx.JSON(http.StatusUnauthorized, map[string]string{"message": "Unauthorized"})
+	}
+
+	invitation, err := h.tiu.Invite(ctx.Request().Context(), tripId, userID, string(req.Email), domain.TripRole(req.Role), req.MemberName)
+	if err != nil {
+		return invitationError(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusCreated, toAPITripInvitation(invitation))
+}
+
+func (h *tripInvitationHandler) RevokeTripInvitation(ctx echo.Context, tripId api.TripId, invitationId api.InvitationId) error {
+	if err := h.tiu.Revoke(ctx.Request().Context(), tripId, invitationId); err != nil {
+		return invitationError(ctx, err)
+	}
+
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+func (h *tripInvitationHandler) ResendTripInvitation(ctx echo.Context, tripId api.TripId, invitationId api.InvitationId) error {
+	invitation, err := h.tiu.Resend(ctx.Request().Context(), tripId, invitationId)
+	if err != nil {
+		return invitationError(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, toAPITripInvitation(invitation))
+}
+
+func (h *tripInvitationHandler) AcceptTripInvitation(ctx echo.Context, invitationToken string) error {
+	userID, ok := ctx.Get("user_id").(uuid.UUID)
+	if !ok {
+		return ctx.JSON(http.StatusUnauthorized, map[string]string{"message": "Unauthorized"})
+	}
+
+	trip, role, err := h.tiu.Accept(ctx.Request().Context(), userID, invitationToken)
+	if err != nil {
+		return invitationError(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, toAPITripWithRole(trip, role))
+}
@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"trip_app/api"
+	"trip_app/internal/domain"
+	"trip_app/internal/usecase"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type tripMemberHandler struct {
+	tmu usecase.TripMemberUsecase
+}
+
+func NewTripMemberHandler(tmu usecase.TripMemberUsecase) *tripMemberHandler {
+	return &tripMemberHandler{tmu}
+}
+
+// memberError maps the errors of the member usecase to a response
+func memberError(ctx echo.Context, err error) error {
+	switch {
+	case errors.Is(err, usecase.ErrValidation):
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"message": err.Error()})
+	case errors.Is(err, usecase.ErrMemberNotFound):
+		return ctx.JSON(http.StatusNotFound, map[string]string{"message": err.Error()})
+	case errors.Is(err, usecase.ErrMemberAlreadyClaimed), errors.Is(err, usecase.ErrAlreadyTripMember):
+		return ctx.JSON(http.StatusConflict, map[string]string{"message": err.Error()})
+	default:
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{"message": "Internal server error"})
+	}
+}
+
+func (h *tripMemberHandler) AddTripMember(ctx echo.Context, tripId api.TripId) error {
+	var req api.TripMemberRequest
+	if err := ctx.Bind(&req); err != nil {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid request body"})
+	}
+
+	member, err := h.tmu.Add(ctx.Request().Context(), tripId, req.Name)
+	if err != nil {
+		return memberError(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusCreated, toAPIMember(member))
+}
+
+func (h *tripMemberHandler) UpdateTripMember(ctx echo.Context, tripId api.TripId, memberId api.MemberId) error {
+	var req api.TripMemberRequest
+	if err := ctx.Bind(&req); err != nil {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid request body"})
+	}
+
+	member, err := h.tmu.Rename(ctx.Request().Context(), tripId, memberId, req.Name)
+	if err != nil {
+		return memberError(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, toAPIMember(member))
+}
+
+func (h *tripMemberHandler) DeleteTripMember(ctx echo.Context, tripId api.TripId, memberId api.MemberId) error {
+	if err := h.tmu.Remove(ctx.Request().Context(), tripId, memberId); err != nil {
+		return memberError(ctx, err)
+	}
+
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+func (h *tripMemberHandler) ListMyMemberships(ctx echo.Context) error {
+	userID, ok := ctx.Get("user_id").(uuid.UUID)
+	if !ok {
+		return ctx.JSON(http.StatusUnauthorized, map[string]string{"message": "Unauthorized"})
+	}
+
+	trips, err := h.tmu.ListMemberships(ctx.Request().Context(), userID)
+	if err != nil {
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{"message": "Internal server error"})
+	}
+
+	res := make([]api.TripMembership, 0, len(trips))
+	for i := range trips {
+		member, ok := trips[i].MemberOf(userID)
+		if !ok {
+			continue
+		}
+		// the role is only set while the user still has access to the trip
+		trip := toAPITrip(&trips[i])
+		if role, ok := trips[i].RoleOf(userID); ok {
+			trip = toAPITripWithRole(&trips[i], role)
+		}
+		res = append(res, api.TripMembership{
+			MemberId:   member.ID,
+			MemberName: member.Name,
+			Trip:       *trip,
+		})
+	}
+
+	return ctx.JSON(http.StatusOK, res)
+}
+
+func (h *tripMemberHandler) ClaimMemberForPublicTrip(ctx echo.Context, shareToken api.ShareToken, memberId api.MemberId) error {
+	userID, ok := ctx.Get("user_id").(uuid.UUID)
+	if !ok {
+		return ctx.JSON(http.StatusUnauthorized, map[string]string{"message": "Unauthorized"})
+	}
+	trip := ctx.Get("trip").(*domain.Trip)
+
+	member, err := h.tmu.Claim(ctx.Request().Context(), trip.ID, memberId, userID)
+	if err != nil {
+		return memberError(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, toAPIMember(member))
+}
@@ -2,9 +2,13 @@ package handler
 
 import (
 	"errors"
+	"math"
 	"net/http"
+	"strconv"
+	"time"
 
 	"trip_app/api"
+	"trip_app/internal/domain"
 	"trip_app/internal/usecase"
 
 	"github.com/google/uuid"
@@ -58,9 +62,21 @@ func (h *userHandler) CreateUser(ctx echo.Context) error {
 }
 
 func (h *userHandler) VerifyUser(ctx echo.Context, verificationToken string) error {
-	message, err := h.uu.VerifyEmail(ctx.Request().Context(), verificationToken)
+	// the body is optional while the legacy initial password flow is enabled
+	var req api.VerifyUserRequest
+	if err := ctx.Bind(&req); err != nil {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid request body"})
+	}
+
+	var password string
+	if req.Password != nil {
+		password = *req.Password
+	}
+
+	message, err := h.uu.VerifyEmail(ctx.Request().Context(), verificationToken, password)
 	if err != nil {
-		if errors.Is(err, usecase.ErrInvalidVerificationToken) || errors.Is(err, usecase.ErrVerificationTokenExpired) {
+		if errors.Is(err, usecase.ErrInvalidVerificationToken) || errors.Is(err, usecase.ErrVerificationTokenExpired) ||
+			errors.Is(err, usecase.ErrPasswordRequired) || errors.Is(err, usecase.ErrValidation) {
 			return ctx.JSON(http.StatusBadRequest, map[string]string{"message": err.Error()})
 		}
 		return ctx.JSON(http.StatusInternalServerError, map[string]string{"message": "Internal server error"})
@@ -69,6 +85,26 @@ func (h *userHandler) VerifyUser(ctx echo.Context, verificationToken string) err
 	return ctx.JSON(http.StatusOK, map[string]string{"message": message})
 }
 
+func (h *userHandler) ResendVerificationEmail(ctx echo.Context) error {
+	var req api.VerificationResendRequest
+
+	if err := ctx.Bind(&req); err != nil {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid request body"})
+	}
+
+	if err := h.uv.ValidateResendVerification(string(req.Email)); err != nil {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"message": err.Error()})
+	}
+
+	// a throttled resend sends nothing, but answers like the others: a 429 would tell an unverified account exists
+	if err := h.uu.ResendVerificationEmail(ctx.Request().Context(), string(req.Email)); err != nil && !errors.Is(err, usecase.ErrTooManyVerificationEmails) {
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{"message": "Internal server error"})
+	}
+
+	// same response whether or not the email belongs to an unverified account
+	return ctx.JSON(http.StatusAccepted, map[string]string{"message": "If the email is awaiting verification, a new verification email has been sent."})
+}
+
 func (h *userHandler) LoginUser(ctx echo.Context) error {
 	var req api.LoginRequest
 
@@ -82,8 +118,20 @@ func (h *userHandler) LoginUser(ctx echo.Context) error {
 	}
 
 	// send request data to usecase from handler
-	user, token, err := h.uu.Login(ctx.Request().Context(), string(req.Email), req.Password)
+	user, tokens, err := h.uu.Login(ctx.Request().Context(), string(req.Email), req.Password, ctx.RealIP())
 	if err != nil {
+		var throttled *usecase.LoginThrottledError
+		if errors.As(err, &throttled) {
+			return tooManyRequests(ctx, throttled.RetryAfter, throttled.Error())
+		}
+		// password was correct but a second factor is required
+		var challenge *usecase.TwoFactorChallengeError
+		if errors.As(err, &challenge) {
+			return ctx.JSON(http.StatusAccepted, api.TwoFactorChallengeResponse{
+				ChallengeToken: challenge.ChallengeToken,
+				ExpiresIn:      int(time.Until(challenge.ExpiresAt).Seconds()),
+			})
+		}
 		if errors.Is(err, usecase.ErrValidation) {
 			return ctx.JSON(http.StatusBadRequest, map[string]string{"message": err.Error()})
 		}
@@ -93,9 +141,120 @@ func (h *userHandler) LoginUser(ctx echo.Context) error {
 		return ctx.JSON(http.StatusInternalServerError, map[string]string{"message": "Internal server error"})
 	}
 
-	// prepare response
+	return ctx.JSON(http.StatusOK, toAPIAuthResponse(user, tokens))
+}
+
+func (h *userHandler) LoginWithTwoFactor(ctx echo.Context) error {
+	var req api.TwoFactorLoginRequest
+
+	if err := ctx.Bind(&req); err != nil {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid request body"})
+	}
+
+	if err := h.uv.ValidateTwoFactorLogin(req.ChallengeToken, req.Code); err != nil {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"message": err.Error()})
+	}
+
+	user, tokens, err := h.uu.LoginWithTwoFactor(ctx.Request().Context(), req.ChallengeToken, req.Code, ctx.RealIP())
+	if err != nil {
+		var throttled *usecase.LoginThrottledError
+		if errors.As(err, &throttled) {
+			return tooManyRequests(ctx, throttled.RetryAfter, throttled.Error())
+		}
+		if errors.Is(err, usecase.ErrInvalidTwoFactorChallenge) || errors.Is(err, usecase.ErrInvalidTwoFactorCode) || errors.Is(err, usecase.ErrUserNotActive) {
+			return ctx.JSON(http.StatusUnauthorized, map[string]string{"message": err.Error()})
+		}
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{"message": "Internal server error"})
+	}
+
+	return ctx.JSON(http.StatusOK, toAPIAuthResponse(user, tokens))
+}
+
+func (h *userHandler) RefreshAuthToken(ctx echo.Context) error {
+	var req api.RefreshTokenRequest
+
+	if err := ctx.Bind(&req); err != nil {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid request body"})
+	}
+
+	if err := h.uv.ValidateRefreshToken(req.RefreshToken); err != nil {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"message": err.Error()})
+	}
+
+	// rotate the refresh token and issue a new token pair
+	user, tokens, err := h.uu.RefreshTokens(ctx.Request().Context(), req.RefreshToken)
+	if err != nil {
+		if errors.Is(err, usecase.ErrInvalidRefreshToken) || errors.Is(err, usecase.ErrRefreshTokenReused) || errors.Is(err, usecase.ErrUserNotActive) {
+			return ctx.JSON(http.StatusUnauthorized, map[string]string{"message": err.Error()})
+		}
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{"message": "Internal server error"})
+	}
+
+	return ctx.JSON(http.StatusOK, toAPIAuthResponse(user, tokens))
+}
+
+func (h *userHandler) StartOIDCLogin(ctx echo.Context, provider string) error {
+	authURL, err := h.uu.StartOIDCLogin(ctx.Request().Context(), provider)
+	if err != nil {
+		if errors.Is(err, usecase.ErrOIDCProviderNotFound) {
+			return ctx.JSON(http.StatusNotFound, map[string]string{"message": err.Error()})
+		}
+		if errors.Is(err, usecase.ErrOIDCProviderUnavailable) {
+			return ctx.JSON(http.StatusBadGateway, map[string]string{"message": usecase.ErrOIDCProviderUnavailable.Error()})
+		}
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{"message": "Internal server error"})
+	}
+
+	return ctx.Redirect(http.StatusFound, authURL)
+}
+
+func (h *userHandler) HandleOIDCCallback(ctx echo.Context, provider string, params api.HandleOIDCCallbackParams) error {
+	// the user cancelled or the provider refused the authorization
+	if params.Error != nil {
+		return ctx.JSON(http.StatusUnauthorized, map[string]string{"message": usecase.ErrOIDCAuthenticationFailed.Error()})
+	}
+	if params.Code == nil || *params.Code == "" || params.State == nil || *params.State == "" {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"message": "code and state are required"})
+	}
+
+	user, tokens, err := h.uu.CompleteOIDCLogin(ctx.Request().Context(), provider, *params.State, *params.Code)
+	if err != nil {
+		var challenge *usecase.TwoFactorChallengeError
+		if errors.As(err, &challenge) {
+			return ctx.JSON(http.StatusAccepted, api.TwoFactorChallengeResponse{
+				ChallengeToken: challenge.ChallengeToken,
+				ExpiresIn:      int(time.Until(challenge.ExpiresAt).Seconds()),
+			})
+		}
+		if errors.Is(err, usecase.ErrOIDCProviderNotFound) {
+			return ctx.JSON(http.StatusNotFound, map[string]string{"message": err.Error()})
+		}
+		if errors.Is(err, usecase.ErrInvalidOIDCState) {
+			return ctx.JSON(http.StatusBadRequest, map[string]string{"message": err.Error()})
+		}
+		if errors.Is(err, usecase.ErrOIDCAuthenticationFailed) || errors.Is(err, usecase.ErrUserNotActive) {
+			return ctx.JSON(http.StatusUnauthorized, map[string]string{"message": err.Error()})
+		}
+		if errors.Is(err, usecase.ErrOIDCEmailNotVerified) {
+			return ctx.JSON(http.StatusForbidden, map[string]string{"message": err.Error()})
+		}
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{"message": "Internal server error"})
+	}
+
+	return ctx.JSON(http.StatusOK, toAPIAuthResponse(user, tokens))
+}
+
+// tooManyRequests responds 429 with Retry-After in whole seconds (rounded up)
+func tooManyRequests(ctx echo.Context, retryAfter time.Duration, message string) error {
+	seconds := int(math.Ceil(retryAfter.Seconds()))
+	ctx.Response().Header().Set("Retry-After", strconv.Itoa(seconds))
+	return ctx.JSON(http.StatusTooManyRequests, map[string]string{"message": message})
+}
+
+// toAPIUser converts the user into the API model without any credentials
+func toAPIUser(user *domain.User) api.User {
 	emailDTO := openapi_types.Email(user.Email)
-	userResponse := api.User{
+	return api.User{
 		Id:        &user.ID,
 		Name:      &user.Name,
 		Email:     &emailDTO,
@@ -103,19 +262,55 @@ func (h *userHandler) LoginUser(ctx echo.Context) error {
 		CreatedAt: &user.CreatedAt,
 		UpdatedAt: &user.UpdatedAt,
 	}
+}
 
-	res := api.AuthResponse{
-		User:  &userResponse,
-		Token: &token,
-	}
+// toAPIAuthResponse converts the user and issued tokens into the API response
+func toAPIAuthResponse(user *domain.User, tokens *usecase.AuthTokens) api.AuthResponse {
+	userResponse := toAPIUser(user)
 
-	return ctx.JSON(http.StatusOK, res)
+	expiresIn := int(time.Until(tokens.AccessTokenExpiresAt).Seconds())
+
+	return api.AuthResponse{
+		User:         &userResponse,
+		Token:        &tokens.AccessToken,
+		RefreshToken: &tokens.RefreshToken,
+		ExpiresIn:    &expiresIn,
+	}
 }
 
 func (h *userHandler) LogoutUser(ctx echo.Context) error {
-	// currently, client-side just deletes the token, so nothing to do server-side
-	// in the future, we might want to implement token blacklisting or expiration by redis
-	return nil
+	userID, ok := ctx.Get("user_id").(uuid.UUID)
+	if !ok {
+		return ctx.JSON(http.StatusUnauthorized, map[string]string{"message": "Unauthorized"})
+	}
+	sessionID, ok := ctx.Get("session_id").(uuid.UUID)
+	if !ok {
+		return ctx.JSON(http.StatusUnauthorized, map[string]string{"message": "Unauthorized"})
+	}
+
+	// revoke the session bound to the current token
+	if err := h.uu.Logout(ctx.Request().Context(), userID, sessionID); err != nil {
+		if errors.Is(err, usecase.ErrSessionRevoked) {
+			return ctx.JSON(http.StatusUnauthorized, map[string]string{"message": err.Error()})
+		}
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{"message": "Internal server error"})
+	}
+
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+func (h *userHandler) LogoutAllSessions(ctx echo.Context) error {
+	userID, ok := ctx.Get("user_id").(uuid.UUID)
+	if !ok {
+		return ctx.JSON(http.StatusUnauthorized, map[string]string{"message": "Unauthorized"})
+	}
+
+	// revoke every session of the user (log out of all devices)
+	if err := h.uu.LogoutAllSessions(ctx.Request().Context(), userID); err != nil {
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{"message": "Internal server error"})
+	}
+
+	return ctx.NoContent(http.StatusNoContent)
 }
 
 func (h *userHandler) GetMe(ctx echo.Context) error {
@@ -133,15 +328,7 @@ func (h *userHandler) GetMe(ctx echo.Context) error {
 	}
 
 	// prepare response
-	emailDTO := openapi_types.Email(user.Email)
-	res := api.User{
-		Id:        &user.ID,
-		Name:      &user.Name,
-		Email:     &emailDTO,
-		IsActive:  &user.IsActive,
-		CreatedAt: &user.CreatedAt,
-		UpdatedAt: &user.UpdatedAt,
-	}
+	res := toAPIUser(user)
 
 	return ctx.JSON(http.StatusOK, res)
 }
@@ -178,3 +365,151 @@ func (h *userHandler) ChangePassword(ctx echo.Context) error {
 
 	return ctx.JSON(http.StatusNoContent, map[string]string{"message": "Password changed successfully"})
 }
+
+func (h *userHandler) RequestPasswordReset(ctx echo.Context) error {
+	var req api.PasswordForgotRequest
+
+	if err := ctx.Bind(&req); err != nil {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid request body"})
+	}
+
+	if err := h.uv.ValidateForgotPassword(string(req.Email)); err != nil {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"message": err.Error()})
+	}
+
+	if err := h.uu.RequestPasswordReset(ctx.Request().Context(), string(req.Email)); err != nil {
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{"message": "Internal server error"})
+	}
+
+	// same response whether or not the email is registered
+	return ctx.JSON(http.StatusAccepted, map[string]string{"message": "If the email is registered, a password reset email has been sent."})
+}
+
+func (h *userHandler) ResetPassword(ctx echo.Context) error {
+	var req api.PasswordResetRequest
+
+	if err := ctx.Bind(&req); err != nil {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid request body"})
+	}
+
+	if err := h.uv.ValidateResetPassword(req.Token, req.NewPassword); err != nil {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"message": err.Error()})
+	}
+
+	err := h.uu.ResetPassword(ctx.Request().Context(), req.Token, req.NewPassword)
+	if err != nil {
+		if errors.Is(err, usecase.ErrValidation) || errors.Is(err, usecase.ErrInvalidPasswordResetToken) {
+			return ctx.JSON(http.StatusBadRequest, map[string]string{"message": err.Error()})
+		}
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{"message": "Internal server error"})
+	}
+
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+func (h *userHandler) ChangeEmail(ctx echo.Context) error {
+	var req api.EmailChangeRequest
+
+	if err := ctx.Bind(&req); err != nil {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid request body"})
+	}
+
+	if err := h.uv.ValidateChangeEmail(req.CurrentPassword, string(req.NewEmail)); err != nil {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"message": err.Error()})
+	}
+
+	userID, ok := ctx.Get("user_id").(uuid.UUID)
+	if !ok {
+		return ctx.JSON(http.StatusUnauthorized, map[string]string{"message": "Unauthorized"})
+	}
+
+	err := h.uu.RequestEmailChange(ctx.Request().Context(), userID, req.CurrentPassword, string(req.NewEmail))
+	if err != nil {
+		if errors.Is(err, usecase.ErrSameEmail) {
+			return ctx.JSON(http.StatusBadRequest, map[string]string{"message": err.Error()})
+		}
+		if errors.Is(err, usecase.ErrUserNotFound) {
+			return ctx.JSON(http.StatusNotFound, map[string]string{"message": err.Error()})
+		}
+		if errors.Is(err, usecase.ErrIncorrectCurrentPassword) {
+			return ctx.JSON(http.StatusUnauthorized, map[string]string{"message": err.Error()})
+		}
+		if errors.Is(err, usecase.ErrEmailConflict) {
+			return ctx.JSON(http.StatusConflict, map[string]string{"message": err.Error()})
+		}
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{"message": "Internal server error"})
+	}
+
+	return ctx.JSON(http.StatusAccepted, map[string]string{"message": "A confirmation email has been sent to the new email address."})
+}
+
+func (h *userHandler) ConfirmEmailChange(ctx echo.Context, emailChangeToken string) error {
+	_, err := h.uu.ConfirmEmailChange(ctx.Request().Context(), emailChangeToken)
+	if err != nil {
+		if errors.Is(err, usecase.ErrInvalidEmailChangeToken) {
+			return ctx.JSON(http.StatusBadRequest, map[string]string{"message": err.Error()})
+		}
+		if errors.Is(err, usecase.ErrEmailConflict) {
+			return ctx.JSON(http.StatusConflict, map[string]string{"message": err.Error()})
+		}
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{"message": "Internal server error"})
+	}
+
+	return ctx.JSON(http.StatusOK, map[string]string{"message": "Email address has been changed."})
+}
+
+func (h *userHandler) SetupTwoFactor(ctx echo.Context) error {
+	userID, ok := ctx.Get("user_id").(uuid.UUID)
+	if !ok {
+		return ctx.JSON(http.StatusUnauthorized, map[string]string{"message": "Unauthorized"})
+	}
+
+	setup, err := h.uu.SetupTwoFactor(ctx.Request().Context(), userID)
+	if err != nil {
+		if errors.Is(err, usecase.ErrUserNotFound) {
+			return ctx.JSON(http.StatusNotFound, map[string]string{"message": err.Error()})
+		}
+		if errors.Is(err, usecase.ErrTwoFactorAlreadyEnabled) {
+			return ctx.JSON(http.StatusConflict, map[string]string{"message": err.Error()})
+		}
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{"message": "Internal server error"})
+	}
+
+	return ctx.JSON(http.StatusOK, api.TwoFactorSetupResponse{
+		Secret:     setup.Secret,
+		OtpauthUri: setup.ProvisioningURI,
+	})
+}
+
+func (h *userHandler) ConfirmTwoFactor(ctx echo.Context) error {
+	var req api.TwoFactorConfirmRequest
+
+	if err := ctx.Bind(&req); err != nil {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid request body"})
+	}
+
+	if err := h.uv.ValidateTwoFactorCode(req.Code); err != nil {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"message": err.Error()})
+	}
+
+	userID, ok := ctx.Get("user_id").(uuid.UUID)
+	if !ok {
+		return ctx.JSON(http.StatusUnauthorized, map[string]string{"message": "Unauthorized"})
+	}
+
+	recoveryCodes, err := h.uu.ConfirmTwoFactor(ctx.Request().Context(), userID, req.Code)
+	if err != nil {
+		if errors.Is(err, usecase.ErrUserNotFound) {
+			return ctx.JSON(http.StatusNotFound, map[string]string{"message": err.Error()})
+		}
+		if errors.Is(err, usecase.ErrTwoFactorAlreadyEnabled) {
+			return ctx.JSON(http.StatusConflict, map[string]string{"message": err.Error()})
+		}
+		if errors.Is(err, usecase.ErrTwoFactorNotSetUp) || errors.Is(err, usecase.ErrInvalidTwoFactorCode) {
+			return ctx.JSON(http.StatusBadRequest, map[string]string{"message": err.Error()})
+		}
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{"message": "Internal server error"})
+	}
+
+	return ctx.JSON(http.StatusOK, api.TwoFactorConfirmResponse{RecoveryCodes: recoveryCodes})
+}
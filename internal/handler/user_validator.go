@@ -8,6 +8,15 @@ type UserHandlerValidator interface {
 	ValidateSignUp(name, email string) error
 	ValidateLogin(email, password string) error
 	ValidateChangePassword(currentPassword, newPassword string) error
+	ValidateRefreshToken(refreshToken string) error
+	ValidateForgotPassword(email string) error
+	ValidateResendVerification(email string) error
+	ValidateResetPassword(token, newPassword string) error
+	ValidateChangeEmail(currentPassword, newEmail string) error
+	ValidateTwoFactorLogin(challengeToken, code string) error
+	ValidateTwoFactorCode(code string) error
+	ValidateDeleteAccount(password string) error
+	ValidateCreatePersonalAccessToken(name string, scopes []string) error
 }
 
 type userHandlerValidator struct {
@@ -44,3 +53,79 @@ func (uv *userHandlerValidator) ValidateChangePassword(currentPassword, newPassw
 	req := changePasswordRequest{CurrentPassword: currentPassword, NewPassword: newPassword}
 	return uv.validate.Struct(req)
 }
+
+func (uv *userHandlerValidator) ValidateRefreshToken(refreshToken string) error {
+	type refreshTokenRequest struct {
+		RefreshToken string `validate:"required"`
+	}
+	req := refreshTokenRequest{RefreshToken: refreshToken}
+	return uv.validate.Struct(req)
+}
+
+func (uv *userHandlerValidator) ValidateForgotPassword(email string) error {
+	type forgotPasswordRequest struct {
+		Email string `validate:"required,email"`
+	}
+	req := forgotPasswordRequest{Email: email}
+	return uv.validate.Struct(req)
+}
+
+func (uv *userHandlerValidator) ValidateResendVerification(email string) error {
+	type resendVerificationRequest struct {
+		Email string `validate:"required,email"`
+	}
+	req := resendVerificationRequest{Email: email}
+	return uv.validate.Struct(req)
+}
+
+func (uv *userHandlerValidator) ValidateResetPassword(token, newPassword string) error {
+	type resetPasswordRequest struct {
+		Token       string `validate:"required"`
+		NewPassword string `validate:"required,min=8"`
+	}
+	req := resetPasswordRequest{Token: token, NewPassword: newPassword}
+	return uv.validate.Struct(req)
+}
+
+func (uv *userHandlerValidator) ValidateChangeEmail(currentPassword, newEmail string) error {
+	type changeEmailRequest struct {
+		CurrentPassword string `validate:"required"`
+		NewEmail        string `validate:"required,email"`
+	}
+	req := changeEmailRequest{CurrentPassword: currentPassword, NewEmail: newEmail}
+	return uv.validate.Struct(req)
+}
+
+func (uv *userHandlerValidator) ValidateTwoFactorLogin(challengeToken, code string) error {
+	type twoFactorLoginRequest struct {
+		ChallengeToken string `validate:"required"`
+		Code           string `validate:"required"`
+	}
+	req := twoFactorLoginRequest{ChallengeToken: challengeToken, Code: code}
+	return uv.validate.Struct(req)
+}
+
+func (uv *userHandlerValidator) ValidateTwoFactorCode(code string) error {
+	type twoFactorCodeRequest struct {
+		Code string `validate:"required,len=6,numeric"`
+	}
+	req := twoFactorCodeRequest{Code: code}
+	return uv.validate.Struct(req)
+}
+
+func (uv *userHandlerValidator) ValidateDeleteAccount(password string) error {
+	type deleteAccountRequest struct {
+		Password string `validate:"required"`
+	}
+	req := deleteAccountRequest{Password: password}
+	return uv.validate.Struct(req)
+}
+
+func (uv *userHandlerValidator) ValidateCreatePersonalAccessToken(name string, scopes []string) error {
+	type createPersonalAccessTokenRequest struct {
+		Name   string   `validate:"required,max=255"`
+		Scopes []string `validate:"required,min=1,dive,required"`
+	}
+	req := createPersonalAccessTokenRequest{Name: name, Scopes: scopes}
+	return uv.validate.Struct(req)
+}
@@ -10,6 +10,10 @@ import (
 
 type Sender interface {
 	SendVerificationEmail(ctx context.Context, recipientEmail, rawToken, rawPassword string) error
+	SendPasswordResetEmail(ctx context.Context, recipientEmail, rawToken string) error
+	SendEmailChangeConfirmationEmail(ctx context.Context, recipientEmail, rawToken string) error
+	SendEmailChangeNoticeEmail(ctx context.Context, recipientEmail, newEmail string) error
+	SendTripInvitationEmail(ctx context.Context, recipientEmail, inviterName, tripTitle, rawToken string) error
 }
 
 type emailSender struct {
@@ -44,20 +48,30 @@ func (e *emailSender) SendVerificationEmail(ctx context.Context, recipientEmail,
 	m.SetHeader("To", recipientEmail)
 	m.SetHeader("Subject", "【Trip App】アカウント有効化のご案内")
 
+	// 初回パスワードの案内は旧フロー（初回パスワードをメールで送付）の場合のみ記載
+	passwordSection := `
+	<p>メールアドレスの認証時に、ご自身で決めたパスワードを設定してください。</p>
+	`
+	if rawPassword != "" {
+		passwordSection = fmt.Sprintf(`
+	<p>メールアドレスの認証完了後、以下の初回パスワードを使用してログインしてください。</p>
+	<hr>
+	<p><b>初回パスワード:</b> %s</p>
+	<hr>
+	<p>※このパスワードは初回ログイン後に変更してください。</p>
+	`, rawPassword)
+	}
+
 	body := fmt.Sprintf(`
 	<p>Trip Appへのご登録ありがとうございます。</p>
 	<p>ご登録のメールアドレスをご確認いただき、お間違いなければ、下のリンクをクリックしてメールアドレスの認証を完了してください。</p>
 	<hr>
 	<p><b>認証トークン:</b> %s</p>
 	<hr>
-	<p>メールアドレスの認証完了後、以下の初回パスワードを使用してログインしてください。</p>
-	<hr>
-	<p><b>初回パスワード:</b> %s</p>
-	<hr>
-	<p>※このパスワードは初回ログイン後に変更してください。</p>
+	%s
 	<p>※認証トークンの有効期限は30分です。有効期限を過ぎた場合は、再度サインアップをお願いいたします。</p>
 	<p>このメールにお心当たりがない場合は、お手数ですが本メールを破棄してください。</p>
-	`, rawToken, rawPassword)
+	`, rawToken, passwordSection)
 	m.SetBody("text/html", body)
 
 	d := gomail.NewDialer(e.smtpHost, e.smtpPort, e.smtpUser, e.smtpPassword)
@@ -69,3 +83,109 @@ func (e *emailSender) SendVerificationEmail(ctx context.Context, recipientEmail,
 	fmt.Printf("✅ Verification email sent to %s\n", recipientEmail)
 	return nil
 }
+
+func (e *emailSender) SendPasswordResetEmail(ctx context.Context, recipientEmail, rawToken string) error {
+	m := gomail.NewMessage()
+	m.SetHeader("From", e.fromEmail)
+	m.SetHeader("To", recipientEmail)
+	m.SetHeader("Subject", "【Trip App】パスワード再設定のご案内")
+
+	body := fmt.Sprintf(`
+	<p>Trip Appのパスワード再設定のリクエストを受け付けました。</p>
+	<p>以下の再設定トークンを使用して、新しいパスワードを設定してください。</p>
+	<hr>
+	<p><b>再設定トークン:</b> %s</p>
+	<hr>
+	<p>※再設定トークンの有効期限は30分です。</p>
+	<p>※パスワードを再設定すると、すべての端末からログアウトされます。</p>
+	<p>このメールにお心当たりがない場合は、お手数ですが本メールを破棄してください。パスワードは変更されません。</p>
+	`, rawToken)
+	m.SetBody("text/html", body)
+
+	d := gomail.NewDialer(e.smtpHost, e.smtpPort, e.smtpUser, e.smtpPassword)
+
+	if err := d.DialAndSend(m); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+
+	fmt.Printf("✅ Password reset email sent to %s\n", recipientEmail)
+	return nil
+}
+
+func (e *emailSender) SendEmailChangeConfirmationEmail(ctx context.Context, recipientEmail, rawToken string) error {
+	m := gomail.NewMessage()
+	m.SetHeader("From", e.fromEmail)
+	m.SetHeader("To", recipientEmail)
+	m.SetHeader("Subject", "【Trip App】メールアドレス変更の確認")
+
+	body := fmt.Sprintf(`
+	<p>Trip Appのメールアドレスを、このメールアドレスに変更するリクエストを受け付けました。</p>
+	<p>以下の確認トークンを使用して、メールアドレスの変更を完了してください。</p>
+	<hr>
+	<p><b>確認トークン:</b> %s</p>
+	<hr>
+	<p>※確認トークンの有効期限は30分です。</p>
+	<p>※変更が完了するまでは、これまでのメールアドレスでログインしてください。</p>
+	<p>このメールにお心当たりがない場合は、お手数ですが本メールを破棄してください。</p>
+	`, rawToken)
+	m.SetBody("text/html", body)
+
+	d := gomail.NewDialer(e.smtpHost, e.smtpPort, e.smtpUser, e.smtpPassword)
+
+	if err := d.DialAndSend(m); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+
+	fmt.Printf("✅ Email change confirmation sent to %s\n", recipientEmail)
+	return nil
+}
+
+func (e *emailSender) SendEmailChangeNoticeEmail(ctx context.Context, recipientEmail, newEmail string) error {
+	m := gomail.NewMessage()
+	m.SetHeader("From", e.fromEmail)
+	m.SetHeader("To", recipientEmail)
+	m.SetHeader("Subject", "【Trip App】メールアドレス変更のお知らせ")
+
+	body := fmt.Sprintf(`
+	<p>Trip Appのメールアドレスを %s に変更するリクエストを受け付けました。</p>
+	<p>変更後のメールアドレスで確認が完了すると、このメールアドレスではログインできなくなります。</p>
+	<p>このリクエストにお心当たりがない場合は、至急パスワードを変更してください。</p>
+	`, newEmail)
+	m.SetBody("text/html", body)
+
+	d := gomail.NewDialer(e.smtpHost, e.smtpPort, e.smtpUser, e.smtpPassword)
+
+	if err := d.DialAndSend(m); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+
+	fmt.Printf("✅ Email change notice sent to %s\n", recipientEmail)
+	return nil
+}
+
+func (e *emailSender) SendTripInvitationEmail(ctx context.Context, recipientEmail, inviterName, tripTitle, rawToken string) error {
+	m := gomail.NewMessage()
+	m.SetHeader("From", e.fromEmail)
+	m.SetHeader("To", recipientEmail)
+	m.SetHeader("Subject", "【Trip App】旅行への招待")
+
+	body := fmt.Sprintf(`
+	<p>%s さんから、Trip Appの旅行「%s」に招待されました。</p>
+	<p>Trip Appにログイン（アカウントをお持ちでない場合はこのメールアドレスでサインアップ）したうえで、以下の招待トークンを使用して招待を承諾してください。</p>
+	<hr>
+	<p><b>招待トークン:</b> %s</p>
+	<hr>
+	<p>※招待トークンの有効期限は7日間です。</p>
+	<p>このメールにお心当たりがない場合は、お手数ですが本メールを破棄してください。</p>
+	`, inviterName, tripTitle, rawToken)
+	m.SetBody("text/html", body)
+
+	d := gomail.NewDialer(e.smtpHost, e.smtpPort, e.smtpUser, e.smtpPassword)
+
+	if err := d.DialAndSend(m); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+
+	fmt.Printf("✅ Trip invitation sent to %s\n", recipientEmail)
+	return nil
+}
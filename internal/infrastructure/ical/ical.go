@@ -0,0 +1,106 @@
+package ical
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ContentType はiCalendarのレスポンスに使うContent-Type
+const ContentType = "text/calendar; charset=utf-8"
+
+// prodID はこのアプリが生成したカレンダーであることを示すPRODID
+const prodID = "-//Trip App//Trip Calendar//JA"
+
+// maxLineOctets は1行の最大オクテット数（改行を除く、RFC 5545 3.1）
+const maxLineOctets = 75
+
+// utcFormat はUTCの日時（FORM #2）の形式
+const utcFormat = "20060102T150405Z"
+
+// Calendar はVCALENDAR（RFC 5545）
+type Calendar struct {
+	// Name はカレンダーアプリに表示される名前（X-WR-CALNAME）
+	Name   string
+	Events []Event
+}
+
+// Event はVEVENT。日時はUTCで出力する
+type Event struct {
+	// UID は更新・再取得しても変わらない識別子
+	UID         string
+	Summary     string
+	Description string
+	Start       time.Time
+	End         time.Time
+	// Stamp はDTSTAMP（イベントの最終更新日時）
+	Stamp time.Time
+}
+
+// Encode はカレンダーをCRLF区切り・75オクテットで折り返したiCalendar形式で書き出す
+func (c *Calendar) Encode(w io.Writer) error {
+	var buf bytes.Buffer
+	writeLine(&buf, "BEGIN:VCALENDAR")
+	writeLine(&buf, "VERSION:2.0")
+	writeLine(&buf, "PRODID:"+prodID)
+	writeLine(&buf, "CALSCALE:GREGORIAN")
+	writeLine(&buf, "METHOD:PUBLISH")
+	if c.Name != "" {
+		writeLine(&buf, "X-WR-CALNAME:"+escapeText(c.Name))
+	}
+	for _, e := range c.Events {
+		writeLine(&buf, "BEGIN:VEVENT")
+		writeLine(&buf, "UID:"+escapeText(e.UID))
+		writeLine(&buf, "DTSTAMP:"+e.Stamp.UTC().Format(utcFormat))
+		writeLine(&buf, "DTSTART:"+e.Start.UTC().Format(utcFormat))
+		writeLine(&buf, "DTEND:"+e.End.UTC().Format(utcFormat))
+		writeLine(&buf, "SUMMARY:"+escapeText(e.Summary))
+		if e.Description != "" {
+			writeLine(&buf, "DESCRIPTION:"+escapeText(e.Description))
+		}
+		writeLine(&buf, "END:VEVENT")
+	}
+	writeLine(&buf, "END:VCALENDAR")
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// textEscaper はTEXT型の値のエスケープ（RFC 5545 3.3.11）。改行はCRLF・CR・LFのいずれも\nにする
+var textEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	";", `\;`,
+	",", `\,`,
+	"\r\n", `\n`,
+	"\r", `\n`,
+	"\n", `\n`,
+)
+
+func escapeText(s string) string {
+	return textEscaper.Replace(s)
+}
+
+// writeLine はcontent lineを75オクテットごとに折り返して書き出す（RFC 5545 3.1）
+// 継続行は空白1文字で始め、UTF-8の文字の途中では折り返さない
+func writeLine(buf *bytes.Buffer, line string) {
+	limit := maxLineOctets
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		// 不正なUTF-8でもオクテット数の上限は守る
+		if cut == 0 {
+			cut = limit
+		}
+		buf.WriteString(line[:cut])
+		buf.WriteString("\r\n ")
+		line = line[cut:]
+		// 継続行の先頭の空白も1オクテットに数える
+		limit = maxLineOctets - 1
+	}
+	buf.WriteString(line)
+	buf.WriteString("\r\n")
+}
@@ -0,0 +1,133 @@
+package ical
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// -update でtestdata/*.icsのゴールデンファイルを書き直す
+var update = flag.Bool("update", false, "update golden files")
+
+func TestCalendarEncode(t *testing.T) {
+	jst := time.FixedZone("JST", 9*60*60)
+	stamp := time.Date(2025, 7, 1, 12, 34, 56, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		calendar Calendar
+	}{
+		{
+			name:     "empty",
+			calendar: Calendar{Name: "空の旅行"},
+		},
+		{
+			name: "basic",
+			calendar: Calendar{
+				Name: "沖縄旅行",
+				Events: []Event{
+					{
+						UID:         "0197c4a0-0000-7000-8000-000000000001@trip-app",
+						Summary:     "美ら海水族館",
+						Description: "開館は8:30",
+						// UTC以外のタイムゾーンもUTCで出力されるべき
+						Start: time.Date(2025, 8, 1, 10, 0, 0, 0, jst),
+						End:   time.Date(2025, 8, 1, 12, 0, 0, 0, jst),
+						Stamp: stamp,
+					},
+					{
+						UID:     "0197c4a0-0000-7000-8000-000000000002@trip-app",
+						Summary: "移動",
+						Start:   time.Date(2025, 8, 1, 5, 0, 0, 0, time.UTC),
+						End:     time.Date(2025, 8, 1, 6, 0, 0, 0, time.UTC),
+						Stamp:   stamp,
+					},
+				},
+			},
+		},
+		{
+			name: "escaping",
+			calendar: Calendar{
+				Name: `旅行; A, B \ C`,
+				Events: []Event{
+					{
+						UID:         "0197c4a0-0000-7000-8000-000000000003@trip-app",
+						Summary:     `集合; 駅前, 北口 \ 改札`,
+						Description: "持ち物:\n- 水着\r\n- タオル\r- 日焼け止め",
+						Start:       time.Date(2025, 8, 2, 0, 0, 0, 0, time.UTC),
+						End:         time.Date(2025, 8, 2, 1, 0, 0, 0, time.UTC),
+						Stamp:       stamp,
+					},
+				},
+			},
+		},
+		{
+			name: "folding",
+			calendar: Calendar{
+				Name: "折り返し",
+				Events: []Event{
+					{
+						UID:         "0197c4a0-0000-7000-8000-000000000004@trip-app",
+						Summary:     strings.Repeat("Long summary text ", 10),
+						Description: strings.Repeat("日本語の長いメモ、", 20),
+						Start:       time.Date(2025, 8, 3, 0, 0, 0, 0, time.UTC),
+						End:         time.Date(2025, 8, 3, 1, 0, 0, 0, time.UTC),
+						Stamp:       stamp,
+					},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			require.NoError(t, tt.calendar.Encode(&buf))
+
+			golden := filepath.Join("testdata", tt.name+".ics")
+			if *update {
+				require.NoError(t, os.WriteFile(golden, buf.Bytes(), 0o644))
+			}
+			want, err := os.ReadFile(golden)
+			require.NoError(t, err)
+			assert.Equal(t, string(want), buf.String())
+
+			assertContentLines(t, buf.String())
+		})
+	}
+}
+
+// assertContentLines はゴールデンファイルとは別に、RFC 5545の行の規則を満たすことを確認する
+func assertContentLines(t *testing.T, out string) {
+	t.Helper()
+	require.True(t, strings.HasSuffix(out, "\r\n"), "must end with CRLF")
+	for _, line := range strings.Split(strings.TrimSuffix(out, "\r\n"), "\r\n") {
+		assert.LessOrEqual(t, len(line), maxLineOctets, "line longer than 75 octets: %q", line)
+		assert.True(t, utf8.ValidString(line), "line splits a UTF-8 sequence: %q", line)
+		assert.NotContains(t, line, "\n")
+		assert.NotContains(t, line, "\r")
+	}
+}
+
+func TestWriteLineUnfoldsToOriginal(t *testing.T) {
+	// 折り返した行を展開（CRLF+空白を削除）すると元の行に戻るべき
+	for _, line := range []string{
+		"SUMMARY:" + strings.Repeat("a", 200),
+		"DESCRIPTION:" + strings.Repeat("あ", 100),
+		"DESCRIPTION:" + strings.Repeat("a😀", 50),
+		"SUMMARY:" + strings.Repeat("\xff", 100),
+	} {
+		var buf bytes.Buffer
+		writeLine(&buf, line)
+		unfolded := strings.ReplaceAll(strings.TrimSuffix(buf.String(), "\r\n"), "\r\n ", "")
+		assert.Equal(t, line, unfolded)
+	}
+}
@@ -0,0 +1,316 @@
+package ical
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidCalendar はファイル全体がiCalendarとして読めない場合のエラー
+var ErrInvalidCalendar = errors.New("invalid iCalendar data")
+
+// localFormat はタイムゾーンなし（FORM #1）またはTZID付き（FORM #3）の日時の形式
+const localFormat = "20060102T150405"
+
+// dateFormat はDATE型の値の形式
+const dateFormat = "20060102"
+
+// ParsedEvent はインポートで読み取ったVEVENT
+type ParsedEvent struct {
+	UID         string
+	Summary     string
+	Description string
+	Start       time.Time
+	End         time.Time
+	// AllDay は終日の予定（DTSTARTがDATE型）かどうか
+	AllDay bool
+	// Err はこのイベントを読み取れなかった理由。他のイベントの読み取りは続ける
+	Err error
+}
+
+// contentLine は展開済みのcontent line（RFC 5545 3.1）。名前とパラメーター名は大文字にそろえる
+type contentLine struct {
+	name   string
+	params map[string]string
+	value  string
+}
+
+// Parse はiCalendarのデータからVEVENTを順に読み取る
+// タイムゾーンのない日時（floating）と終日の予定の日付はlocの時刻として扱う
+func Parse(r io.Reader, loc *time.Location) ([]ParsedEvent, error) {
+	lines, err := unfold(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []ParsedEvent
+	var event []contentLine
+	found := false
+	// 読み取り中のコンポーネント（VCALENDAR、VEVENT、VTIMEZONEなど）
+	var stack []string
+	for _, raw := range lines {
+		if raw == "" {
+			continue
+		}
+		line, lineErr := parseContentLine(raw)
+		if lineErr != nil {
+			if len(stack) > 0 && stack[len(stack)-1] == "VEVENT" {
+				// 壊れた行はイベントの読み取り時にエラーとして報告する
+				event = append(event, contentLine{name: "X-INVALID", value: raw})
+			}
+			continue
+		}
+
+		switch line.name {
+		case "BEGIN":
+			component := strings.ToUpper(line.value)
+			if len(stack) == 0 && component != "VCALENDAR" {
+				return nil, fmt.Errorf("%w: expected BEGIN:VCALENDAR", ErrInvalidCalendar)
+			}
+			if component == "VEVENT" {
+				event = nil
+			}
+			found = true
+			stack = append(stack, component)
+		case "END":
+			component := strings.ToUpper(line.value)
+			if len(stack) == 0 || stack[len(stack)-1] != component {
+				return nil, fmt.Errorf("%w: unexpected END:%s", ErrInvalidCalendar, line.value)
+			}
+			stack = stack[:len(stack)-1]
+			// VEVENTの中のVALARMなどは入れ子のため、VEVENT自体の終わりだけを数える
+			if component == "VEVENT" {
+				events = append(events, parseEvent(event, loc))
+			}
+		default:
+			if len(stack) == 0 {
+				return nil, fmt.Errorf("%w: expected BEGIN:VCALENDAR", ErrInvalidCalendar)
+			}
+			if stack[len(stack)-1] == "VEVENT" {
+				event = append(event, line)
+			}
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("%w: expected BEGIN:VCALENDAR", ErrInvalidCalendar)
+	}
+	if len(stack) > 0 {
+		return nil, fmt.Errorf("%w: missing END:%s", ErrInvalidCalendar, stack[len(stack)-1])
+	}
+
+	return events, nil
+}
+
+// unfold は折り返された行を1行に戻す。CRLFに加えて、LFのみの改行も受け付ける
+func unfold(r io.Reader) ([]string, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	var lines []string
+	for scanner.Scan() {
+		line := strings.TrimSuffix(scanner.Text(), "\r")
+		if len(lines) > 0 && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidCalendar, err)
+	}
+	return lines, nil
+}
+
+// parseContentLine は "NAME;PARAM=value:値" を分解する。パラメーターの値は引用符で囲まれている場合がある
+func parseContentLine(raw string) (contentLine, error) {
+	line := contentLine{params: map[string]string{}}
+
+	inQuotes := false
+	colon := -1
+	for i, c := range raw {
+		if c == '"' {
+			inQuotes = !inQuotes
+		} else if c == ':' && !inQuotes {
+			colon = i
+			break
+		}
+	}
+	if colon <= 0 {
+		return line, fmt.Errorf("missing ':' in %q", raw)
+	}
+	line.value = raw[colon+1:]
+
+	parts := splitOutsideQuotes(raw[:colon], ';')
+	line.name = strings.ToUpper(parts[0])
+	for _, p := range parts[1:] {
+		name, value, ok := strings.Cut(p, "=")
+		if !ok {
+			return line, fmt.Errorf("invalid parameter %q", p)
+		}
+		line.params[strings.ToUpper(name)] = strings.Trim(value, `"`)
+	}
+	return line, nil
+}
+
+func splitOutsideQuotes(s string, sep rune) []string {
+	var parts []string
+	inQuotes := false
+	start := 0
+	for i, c := range s {
+		switch {
+		case c == '"':
+			inQuotes = !inQuotes
+		case c == sep && !inQuotes:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:])
+}
+
+func parseEvent(lines []contentLine, loc *time.Location) ParsedEvent {
+	var event ParsedEvent
+	var start, end, duration *contentLine
+	for i := range lines {
+		line := &lines[i]
+		switch line.name {
+		case "X-INVALID":
+			event.Err = fmt.Errorf("invalid line %q", line.value)
+		case "UID":
+			event.UID = unescapeText(line.value)
+		case "SUMMARY":
+			event.Summary = unescapeText(line.value)
+		case "DESCRIPTION":
+			event.Description = unescapeText(line.value)
+		case "DTSTART":
+			start = line
+		case "DTEND":
+			end = line
+		case "DURATION":
+			duration = line
+		}
+	}
+	if event.Err != nil {
+		return event
+	}
+	if start == nil {
+		event.Err = errors.New("DTSTART is missing")
+		return event
+	}
+
+	var err error
+	event.Start, event.AllDay, err = parseDateTime(start, loc)
+	if err != nil {
+		event.Err = fmt.Errorf("DTSTART: %w", err)
+		return event
+	}
+
+	switch {
+	case end != nil:
+		event.End, _, err = parseDateTime(end, loc)
+		if err != nil {
+			event.Err = fmt.Errorf("DTEND: %w", err)
+			return event
+		}
+	case duration != nil:
+		d, err := parseDuration(duration.value)
+		if err != nil {
+			event.Err = fmt.Errorf("DURATION: %w", err)
+			return event
+		}
+		event.End = event.Start.Add(d)
+	case event.AllDay:
+		// 終了のない終日の予定はその日1日（RFC 5545 3.6.1）
+		event.End = event.Start.AddDate(0, 0, 1)
+	default:
+		event.End = event.Start
+	}
+
+	return event
+}
+
+// parseDateTime はDATE型・UTC・TZID付き・floatingの日時を読み取る。DATE型の場合はallDayがtrue
+func parseDateTime(line *contentLine, loc *time.Location) (t time.Time, allDay bool, err error) {
+	value := line.value
+	if strings.EqualFold(line.params["VALUE"], "DATE") || len(value) == len(dateFormat) {
+		t, err = time.ParseInLocation(dateFormat, value, loc)
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("invalid date %q", value)
+		}
+		return t, true, nil
+	}
+
+	if strings.HasSuffix(value, "Z") {
+		t, err = time.Parse(utcFormat, value)
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("invalid date-time %q", value)
+		}
+		return t, false, nil
+	}
+
+	if tzid := line.params["TZID"]; tzid != "" {
+		// VTIMEZONEの定義は読まず、IANAのタイムゾーン名として解決する
+		loc, err = time.LoadLocation(strings.TrimPrefix(tzid, "/"))
+		if err != nil || tzid == "Local" {
+			return time.Time{}, false, fmt.Errorf("unknown time zone %q", tzid)
+		}
+	}
+	t, err = time.ParseInLocation(localFormat, value, loc)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("invalid date-time %q", value)
+	}
+	return t, false, nil
+}
+
+// parseDuration はDURATION（RFC 5545 3.3.6、例: PT1H30M、P1D、P2W）を読み取る。負の期間は受け付けない
+func parseDuration(value string) (time.Duration, error) {
+	s := strings.TrimPrefix(value, "+")
+	if !strings.HasPrefix(s, "P") || len(s) < 3 {
+		return 0, fmt.Errorf("invalid duration %q", value)
+	}
+	s = s[1:]
+
+	units := map[byte]time.Duration{'W': 7 * 24 * time.Hour, 'D': 24 * time.Hour}
+	var total time.Duration
+	num := ""
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c >= '0' && c <= '9':
+			num += string(c)
+		case c == 'T' && num == "":
+			units = map[byte]time.Duration{'H': time.Hour, 'M': time.Minute, 'S': time.Second}
+		default:
+			unit, ok := units[c]
+			if !ok || num == "" {
+				return 0, fmt.Errorf("invalid duration %q", value)
+			}
+			n, err := strconv.Atoi(num)
+			if err != nil {
+				return 0, fmt.Errorf("invalid duration %q", value)
+			}
+			total += time.Duration(n) * unit
+			num = ""
+		}
+	}
+	if num != "" {
+		return 0, fmt.Errorf("invalid duration %q", value)
+	}
+	return total, nil
+}
+
+// textUnescaper はescapeTextの逆変換。\Nも改行として扱う
+var textUnescaper = strings.NewReplacer(
+	`\\`, `\`,
+	`\;`, ";",
+	`\,`, ",",
+	`\n`, "\n",
+	`\N`, "\n",
+)
+
+func unescapeText(s string) string {
+	return textUnescaper.Replace(s)
+}
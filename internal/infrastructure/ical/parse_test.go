@@ -0,0 +1,120 @@
+package ical
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	f, err := os.Open("testdata/import.ics")
+	require.NoError(t, err)
+	defer f.Close()
+
+	// floatingの日時と終日の予定はlocの時刻になるべき
+	jst, err := time.LoadLocation("Asia/Tokyo")
+	require.NoError(t, err)
+	hst, err := time.LoadLocation("Pacific/Honolulu")
+	require.NoError(t, err)
+	events, err := Parse(f, hst)
+	require.NoError(t, err)
+	require.Len(t, events, 6)
+
+	// TZID付きの日時（引用符付きのパラメーターを含む）、VALARMは無視
+	flight := events[0]
+	require.NoError(t, flight.Err)
+	assert.Equal(t, "flight-NH123-20250801@example.com", flight.UID)
+	assert.Equal(t, "NH123 羽田 → 那覇", flight.Summary)
+	assert.Equal(t, "予約番号: ABC123\n座席: 12A, 12B", flight.Description)
+	assert.True(t, flight.Start.Equal(time.Date(2025, 8, 1, 8, 30, 0, 0, jst)))
+	assert.True(t, flight.End.Equal(time.Date(2025, 8, 1, 11, 5, 0, 0, jst)))
+	assert.False(t, flight.AllDay)
+
+	// 終日の予定（DTENDは含まない日）、折り返された行
+	hotel := events[1]
+	require.NoError(t, hotel.Err)
+	assert.True(t, hotel.AllDay)
+	assert.True(t, hotel.Start.Equal(time.Date(2025, 8, 1, 0, 0, 0, 0, hst)))
+	assert.True(t, hotel.End.Equal(time.Date(2025, 8, 3, 0, 0, 0, 0, hst)))
+	assert.Equal(t, "チェックイン 15:00 以降。長い説明はカレンダーアプリによって75オクテットで折り返されることがある", hotel.Description)
+
+	// UTCの日時とDURATION
+	tour := events[2]
+	require.NoError(t, tour.Err)
+	assert.True(t, tour.Start.Equal(time.Date(2025, 8, 2, 1, 0, 0, 0, time.UTC)))
+	assert.Equal(t, 150*time.Minute, tour.End.Sub(tour.Start))
+
+	// floatingの日時
+	dinner := events[3]
+	require.NoError(t, dinner.Err)
+	assert.True(t, dinner.Start.Equal(time.Date(2025, 8, 2, 19, 0, 0, 0, hst)))
+
+	// 読み取れないイベントはイベントごとのエラーになるべき
+	assert.ErrorContains(t, events[4].Err, `unknown time zone "Mars/Olympus"`)
+	assert.Equal(t, "broken@example.com", events[4].UID)
+	assert.ErrorContains(t, events[5].Err, "DTSTART is missing")
+}
+
+func TestParseInvalidCalendar(t *testing.T) {
+	for name, data := range map[string]string{
+		"empty":           "",
+		"not a calendar":  "hello\r\n",
+		"no vcalendar":    "BEGIN:VEVENT\r\nEND:VEVENT\r\n",
+		"unclosed":        "BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\n",
+		"mismatched end":  "BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nEND:VCALENDAR\r\n",
+		"property before": "SUMMARY:x\r\nBEGIN:VCALENDAR\r\nEND:VCALENDAR\r\n",
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := Parse(strings.NewReader(data), time.UTC)
+			assert.ErrorIs(t, err, ErrInvalidCalendar)
+		})
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	for value, want := range map[string]time.Duration{
+		"PT1H30M":  90 * time.Minute,
+		"P1D":      24 * time.Hour,
+		"P2W":      14 * 24 * time.Hour,
+		"P1DT2H":   26 * time.Hour,
+		"+PT15M5S": 15*time.Minute + 5*time.Second,
+	} {
+		got, err := parseDuration(value)
+		require.NoError(t, err, value)
+		assert.Equal(t, want, got, value)
+	}
+	for _, value := range []string{"", "P", "PT", "-PT1H", "PT1X", "P1H", "PT1", "1H"} {
+		_, err := parseDuration(value)
+		assert.Error(t, err, value)
+	}
+}
+
+func TestEncodeParseRoundTrip(t *testing.T) {
+	// 出力したカレンダーを読み込むと、エスケープや折り返しを含めて元に戻るべき
+	want := Event{
+		UID:         "0197c4a0-0000-7000-8000-000000000001@trip-app",
+		Summary:     `集合; 駅前, 北口 \ 改札`,
+		Description: "持ち物:\n- " + strings.Repeat("日焼け止め、", 30),
+		Start:       time.Date(2025, 8, 1, 1, 0, 0, 0, time.UTC),
+		End:         time.Date(2025, 8, 1, 3, 0, 0, 0, time.UTC),
+		Stamp:       time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC),
+	}
+	var buf bytes.Buffer
+	require.NoError(t, (&Calendar{Name: "旅行", Events: []Event{want}}).Encode(&buf))
+
+	events, err := Parse(&buf, time.UTC)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	got := events[0]
+	require.NoError(t, got.Err)
+	assert.Equal(t, want.UID, got.UID)
+	assert.Equal(t, want.Summary, got.Summary)
+	assert.Equal(t, want.Description, got.Description)
+	assert.True(t, want.Start.Equal(got.Start))
+	assert.True(t, want.End.Equal(got.End))
+}
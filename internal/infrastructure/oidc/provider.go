@@ -0,0 +1,310 @@
+package oidc
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// ErrIDTokenInvalid はIDトークンの署名・発行者・audience・期限・nonceのいずれかが不正な場合のエラー
+var ErrIDTokenInvalid = errors.New("invalid id token")
+
+// defaultScopes はスコープ未指定時に要求するスコープ
+var defaultScopes = []string{"openid", "email", "profile"}
+
+// idTokenAlgorithms はIDトークンの署名として受け付けるアルゴリズム（HS系は受け付けない）
+var idTokenAlgorithms = []string{"RS256", "RS384", "RS512", "PS256", "ES256", "ES384", "EdDSA"}
+
+// jwksRefreshInterval は未知のkidによるJWKSの再取得の最短間隔
+// 偽のIDトークンを送り続けてもプロバイダーへのリクエストが増えないようにする
+const jwksRefreshInterval = time.Minute
+
+// Config はOpenID Connectプロバイダーの設定
+type Config struct {
+	// Name はURLの{provider}に使う識別子（例: "google"）
+	Name string
+	// Issuer は"{Issuer}/.well-known/openid-configuration"でディスカバリーを行うURL
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	// RedirectURL はプロバイダーに登録したコールバックURL
+	RedirectURL string
+	// Scopes は要求するスコープ（省略時は openid email profile）
+	Scopes []string
+}
+
+// Claims はIDトークンから取り出した外部アカウントの情報
+type Claims struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+}
+
+// Provider は認可コードフロー（PKCE）でログインするOpenID Connectプロバイダー
+type Provider interface {
+	Name() string
+	// AuthCodeURL は認可エンドポイントへのURLを返す（code_challenge_methodはS256）
+	AuthCodeURL(ctx context.Context, state, nonce, codeChallenge string) (string, error)
+	// Exchange は認可コードをトークンに交換し、検証済みのIDトークンのクレームを返す
+	Exchange(ctx context.Context, code, codeVerifier, nonce string) (*Claims, error)
+}
+
+// CodeChallenge はPKCEのcode_verifierからS256のcode_challengeを計算
+func CodeChallenge(codeVerifier string) string {
+	sum := sha256.Sum256([]byte(codeVerifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// discoveryDocument はディスカバリーで取得するメタデータのうち使用する項目
+type discoveryDocument struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+type provider struct {
+	cfg        Config
+	httpClient *http.Client
+
+	mu        sync.Mutex
+	discovery *discoveryDocument
+	keys      map[string]interface{}
+	// keysFetchedAt は最後にJWKSの取得を始めた時刻（失敗した取得も含む）
+	keysFetchedAt time.Time
+}
+
+// NewProvider はプロバイダーを作成（ディスカバリーは初回利用時に行う）
+func NewProvider(cfg Config, httpClient *http.Client) Provider {
+	if len(cfg.Scopes) == 0 {
+		cfg.Scopes = defaultScopes
+	}
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 10 * time.Second}
+	}
+	cfg.Issuer = strings.TrimSuffix(cfg.Issuer, "/")
+	return &provider{cfg: cfg, httpClient: httpClient}
+}
+
+func (p *provider) Name() string {
+	return p.cfg.Name
+}
+
+func (p *provider) AuthCodeURL(ctx context.Context, state, nonce, codeChallenge string) (string, error) {
+	doc, err := p.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	authURL, err := url.Parse(doc.AuthorizationEndpoint)
+	if err != nil {
+		return "", fmt.Errorf("invalid authorization endpoint: %w", err)
+	}
+	query := authURL.Query()
+	query.Set("response_type", "code")
+	query.Set("client_id", p.cfg.ClientID)
+	query.Set("redirect_uri", p.cfg.RedirectURL)
+	query.Set("scope", strings.Join(p.cfg.Scopes, " "))
+	query.Set("state", state)
+	query.Set("nonce", nonce)
+	query.Set("code_challenge", codeChallenge)
+	query.Set("code_challenge_method", "S256")
+	authURL.RawQuery = query.Encode()
+
+	return authURL.String(), nil
+}
+
+func (p *provider) Exchange(ctx context.Context, code, codeVerifier, nonce string) (*Claims, error) {
+	doc, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", p.cfg.RedirectURL)
+	form.Set("code_verifier", codeVerifier)
+	form.Set("client_id", p.cfg.ClientID)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, doc.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	// クライアント認証はclient_secret_basic（RFC 6749 2.3.1）
+	if p.cfg.ClientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(p.cfg.ClientID), url.QueryEscape(p.cfg.ClientSecret))
+	}
+
+	var tokenResponse struct {
+		IDToken string `json:"id_token"`
+	}
+	if err := p.doJSON(req, &tokenResponse); err != nil {
+		return nil, fmt.Errorf("token request failed: %w", err)
+	}
+	if tokenResponse.IDToken == "" {
+		return nil, fmt.Errorf("%w: token response has no id_token", ErrIDTokenInvalid)
+	}
+
+	return p.verifyIDToken(ctx, doc, tokenResponse.IDToken, nonce)
+}
+
+// idTokenClaims はIDトークンのクレーム
+type idTokenClaims struct {
+	Email           string       `json:"email"`
+	EmailVerified   flexibleBool `json:"email_verified"`
+	Name            string       `json:"name"`
+	Nonce           string       `json:"nonce"`
+	AuthorizedParty string       `json:"azp"`
+	jwt.RegisteredClaims
+}
+
+func (p *provider) verifyIDToken(ctx context.Context, doc *discoveryDocument, rawIDToken, nonce string) (*Claims, error) {
+	claims := &idTokenClaims{}
+	_, err := jwt.ParseWithClaims(rawIDToken, claims,
+		func(token *jwt.Token) (interface{}, error) {
+			kid, _ := token.Header["kid"].(string)
+			return p.verificationKey(ctx, doc, kid)
+		},
+		jwt.WithValidMethods(idTokenAlgorithms),
+		jwt.WithIssuer(doc.Issuer),
+		jwt.WithAudience(p.cfg.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(time.Minute),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrIDTokenInvalid, err)
+	}
+
+	// 複数のクライアント向けに発行されたトークンは、azpが自身である場合のみ受け付ける
+	if len(claims.Audience) > 1 && claims.AuthorizedParty != p.cfg.ClientID {
+		return nil, fmt.Errorf("%w: unexpected authorized party", ErrIDTokenInvalid)
+	}
+	if claims.Nonce != nonce {
+		return nil, fmt.Errorf("%w: nonce mismatch", ErrIDTokenInvalid)
+	}
+	if claims.Subject == "" {
+		return nil, fmt.Errorf("%w: missing subject", ErrIDTokenInvalid)
+	}
+
+	return &Claims{
+		Subject:       claims.Subject,
+		Email:         claims.Email,
+		EmailVerified: bool(claims.EmailVerified),
+		Name:          claims.Name,
+	}, nil
+}
+
+// discover はディスカバリードキュメントを取得してキャッシュする
+func (p *provider) discover(ctx context.Context) (*discoveryDocument, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.discovery != nil {
+		return p.discovery, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.cfg.Issuer+"/.well-known/openid-configuration", nil)
+	if err != nil {
+		return nil, err
+	}
+	var doc discoveryDocument
+	if err := p.doJSON(req, &doc); err != nil {
+		return nil, fmt.Errorf("oidc discovery for %s failed: %w", p.cfg.Name, err)
+	}
+	// メタデータのissuerは設定した発行者と一致しなければならない（OpenID Connect Discovery 4.3）
+	if strings.TrimSuffix(doc.Issuer, "/") != p.cfg.Issuer {
+		return nil, fmt.Errorf("oidc discovery for %s returned issuer %q", p.cfg.Name, doc.Issuer)
+	}
+	if doc.AuthorizationEndpoint == "" || doc.TokenEndpoint == "" || doc.JWKSURI == "" {
+		return nil, fmt.Errorf("oidc discovery for %s is missing endpoints", p.cfg.Name)
+	}
+
+	p.discovery = &doc
+	return p.discovery, nil
+}
+
+// verificationKey はkidに対応する公開鍵を返す。未知のkidの場合はプロバイダーの鍵ローテーションに備えてJWKSを再取得する
+// 再取得はjwksRefreshIntervalに1回までとし、取得中も他のログインを止めないようロックの外で行う
+func (p *provider) verificationKey(ctx context.Context, doc *discoveryDocument, kid string) (interface{}, error) {
+	p.mu.Lock()
+	if key, ok := p.keys[kid]; ok {
+		p.mu.Unlock()
+		return key, nil
+	}
+	now := time.Now()
+	if !p.keysFetchedAt.IsZero() && now.Sub(p.keysFetchedAt) < jwksRefreshInterval {
+		p.mu.Unlock()
+		return nil, fmt.Errorf("unknown key id %q", kid)
+	}
+	// 取得を予約し、同時に届いた他のリクエストが重ねて取得しないようにする
+	p.keysFetchedAt = now
+	p.mu.Unlock()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, doc.JWKSURI, nil)
+	if err != nil {
+		return nil, err
+	}
+	var jwks jsonWebKeySet
+	if err := p.doJSON(req, &jwks); err != nil {
+		return nil, fmt.Errorf("fetching jwks failed: %w", err)
+	}
+	keys := jwks.publicKeys()
+
+	p.mu.Lock()
+	p.keys = keys
+	p.mu.Unlock()
+
+	key, ok := keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown key id %q", kid)
+	}
+	return key, nil
+}
+
+// doJSON はリクエストを送信し、2xxのJSONレスポンスをoutにデコードする
+func (p *provider) doJSON(req *http.Request, out interface{}) error {
+	res, err := p.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(res.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return fmt.Errorf("%s %s: status %d: %s", req.Method, req.URL.Redacted(), res.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return json.Unmarshal(body, out)
+}
+
+// flexibleBool は真偽値または"true"/"false"の文字列を受け付ける（email_verifiedを文字列で返すプロバイダーがあるため）
+type flexibleBool bool
+
+func (b *flexibleBool) UnmarshalJSON(data []byte) error {
+	switch strings.Trim(string(data), `"`) {
+	case "true":
+		*b = true
+	case "false", "null", "":
+		*b = false
+	default:
+		return fmt.Errorf("invalid boolean %s", data)
+	}
+	return nil
+}
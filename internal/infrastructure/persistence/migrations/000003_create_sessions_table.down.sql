@@ -0,0 +1,3 @@
+-- 000003_create_sessions_table.down.sql
+
+DROP TABLE IF EXISTS "Session";
@@ -0,0 +1,11 @@
+-- 000003_create_sessions_table.up.sql
+
+CREATE TABLE "Session" (
+    "id" UUID PRIMARY KEY,
+    "user_id" UUID NOT NULL REFERENCES "User"("id") ON DELETE CASCADE,
+    "expires_at" TIMESTAMPTZ NOT NULL,
+    "revoked_at" TIMESTAMPTZ,
+    "created_at" TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX "idx_session_user_id" ON "Session"("user_id");
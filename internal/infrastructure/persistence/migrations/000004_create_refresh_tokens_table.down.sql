@@ -0,0 +1,3 @@
+-- 000004_create_refresh_tokens_table.down.sql
+
+DROP TABLE IF EXISTS "RefreshToken";
@@ -0,0 +1,13 @@
+-- 000004_create_refresh_tokens_table.up.sql
+
+CREATE TABLE "RefreshToken" (
+    "id" UUID PRIMARY KEY,
+    "session_id" UUID NOT NULL REFERENCES "Session"("id") ON DELETE CASCADE,
+    "user_id" UUID NOT NULL REFERENCES "User"("id") ON DELETE CASCADE,
+    "token_hash" VARCHAR(255) UNIQUE NOT NULL,
+    "expires_at" TIMESTAMPTZ NOT NULL,
+    "rotated_at" TIMESTAMPTZ,
+    "created_at" TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX "idx_refresh_token_session_id" ON "RefreshToken"("session_id");
@@ -0,0 +1,5 @@
+-- 000005_add_password_reset_token_to_users.down.sql
+
+ALTER TABLE "User"
+    DROP COLUMN IF EXISTS "password_reset_token_expires_at",
+    DROP COLUMN IF EXISTS "password_reset_token_hash";
@@ -0,0 +1,5 @@
+-- 000005_add_password_reset_token_to_users.up.sql
+
+ALTER TABLE "User"
+    ADD COLUMN "password_reset_token_hash" VARCHAR(255) UNIQUE,
+    ADD COLUMN "password_reset_token_expires_at" TIMESTAMPTZ;
@@ -0,0 +1,6 @@
+-- 000006_add_two_factor_to_users.down.sql
+
+ALTER TABLE "User"
+    DROP COLUMN IF EXISTS "two_factor_last_used_step",
+    DROP COLUMN IF EXISTS "two_factor_enabled",
+    DROP COLUMN IF EXISTS "two_factor_secret";
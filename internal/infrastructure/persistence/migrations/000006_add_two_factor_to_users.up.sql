@@ -0,0 +1,6 @@
+-- 000006_add_two_factor_to_users.up.sql
+
+ALTER TABLE "User"
+    ADD COLUMN "two_factor_secret" VARCHAR(255),
+    ADD COLUMN "two_factor_enabled" BOOLEAN NOT NULL DEFAULT false,
+    ADD COLUMN "two_factor_last_used_step" BIGINT NOT NULL DEFAULT 0;
@@ -0,0 +1,3 @@
+-- 000007_create_recovery_codes_table.down.sql
+
+DROP TABLE IF EXISTS "RecoveryCode";
@@ -0,0 +1,11 @@
+-- 000007_create_recovery_codes_table.up.sql
+
+CREATE TABLE "RecoveryCode" (
+    "id" UUID PRIMARY KEY,
+    "user_id" UUID NOT NULL REFERENCES "User"("id") ON DELETE CASCADE,
+    "code_hash" VARCHAR(255) NOT NULL,
+    "used_at" TIMESTAMPTZ,
+    "created_at" TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX "idx_recovery_code_user_id" ON "RecoveryCode"("user_id");
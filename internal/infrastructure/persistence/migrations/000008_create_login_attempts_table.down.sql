@@ -0,0 +1,3 @@
+-- 000008_create_login_attempts_table.down.sql
+
+DROP TABLE IF EXISTS "LoginAttempt";
@@ -0,0 +1,8 @@
+-- 000008_create_login_attempts_table.up.sql
+
+CREATE TABLE "LoginAttempt" (
+    "key" VARCHAR(320) PRIMARY KEY,
+    "failure_count" INTEGER NOT NULL DEFAULT 0,
+    "last_failed_at" TIMESTAMPTZ NOT NULL,
+    "locked_until" TIMESTAMPTZ
+);
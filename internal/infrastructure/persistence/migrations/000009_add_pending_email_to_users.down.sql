@@ -0,0 +1,6 @@
+-- 000009_add_pending_email_to_users.down.sql
+
+ALTER TABLE "User"
+    DROP COLUMN IF EXISTS "email_change_token_expires_at",
+    DROP COLUMN IF EXISTS "email_change_token_hash",
+    DROP COLUMN IF EXISTS "pending_email";
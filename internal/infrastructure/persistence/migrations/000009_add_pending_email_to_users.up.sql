@@ -0,0 +1,6 @@
+-- 000009_add_pending_email_to_users.up.sql
+
+ALTER TABLE "User"
+    ADD COLUMN "pending_email" VARCHAR(255),
+    ADD COLUMN "email_change_token_hash" VARCHAR(255) UNIQUE,
+    ADD COLUMN "email_change_token_expires_at" TIMESTAMPTZ;
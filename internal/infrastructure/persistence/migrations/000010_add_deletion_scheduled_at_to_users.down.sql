@@ -0,0 +1,6 @@
+-- 000010_add_deletion_scheduled_at_to_users.down.sql
+
+DROP INDEX IF EXISTS "idx_user_deletion_scheduled_at";
+
+ALTER TABLE "User"
+    DROP COLUMN IF EXISTS "deletion_scheduled_at";
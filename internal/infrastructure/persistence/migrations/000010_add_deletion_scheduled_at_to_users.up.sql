@@ -0,0 +1,6 @@
+-- 000010_add_deletion_scheduled_at_to_users.up.sql
+
+ALTER TABLE "User"
+    ADD COLUMN "deletion_scheduled_at" TIMESTAMPTZ;
+
+CREATE INDEX "idx_user_deletion_scheduled_at" ON "User"("deletion_scheduled_at") WHERE "deletion_scheduled_at" IS NOT NULL;
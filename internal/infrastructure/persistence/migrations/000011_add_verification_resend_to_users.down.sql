@@ -0,0 +1,6 @@
+-- 000011_add_verification_resend_to_users.down.sql
+
+ALTER TABLE "User"
+    DROP COLUMN IF EXISTS "verification_resend_window_started_at",
+    DROP COLUMN IF EXISTS "verification_resend_count",
+    DROP COLUMN IF EXISTS "verification_sent_at";
@@ -0,0 +1,6 @@
+-- 000011_add_verification_resend_to_users.up.sql
+
+ALTER TABLE "User"
+    ADD COLUMN "verification_sent_at" TIMESTAMPTZ,
+    ADD COLUMN "verification_resend_count" INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN "verification_resend_window_started_at" TIMESTAMPTZ;
@@ -0,0 +1,3 @@
+-- 000012_create_personal_access_tokens_table.down.sql
+
+DROP TABLE IF EXISTS "PersonalAccessToken";
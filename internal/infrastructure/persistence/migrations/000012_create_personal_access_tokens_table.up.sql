@@ -0,0 +1,14 @@
+-- 000012_create_personal_access_tokens_table.up.sql
+
+CREATE TABLE "PersonalAccessToken" (
+    "id" UUID PRIMARY KEY,
+    "user_id" UUID NOT NULL REFERENCES "User"("id") ON DELETE CASCADE,
+    "name" VARCHAR(255) NOT NULL,
+    "token_hash" VARCHAR(255) UNIQUE NOT NULL,
+    "scopes" VARCHAR(255) NOT NULL,
+    "expires_at" TIMESTAMPTZ,
+    "last_used_at" TIMESTAMPTZ,
+    "created_at" TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX "idx_personal_access_token_user_id" ON "PersonalAccessToken"("user_id");
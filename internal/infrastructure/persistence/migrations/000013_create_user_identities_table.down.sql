@@ -0,0 +1,4 @@
+-- 000013_create_user_identities_table.down.sql
+
+DROP TABLE IF EXISTS "OIDCAuthRequest";
+DROP TABLE IF EXISTS "UserIdentity";
@@ -0,0 +1,25 @@
+-- 000013_create_user_identities_table.up.sql
+
+CREATE TABLE "UserIdentity" (
+    "id" UUID PRIMARY KEY,
+    "user_id" UUID NOT NULL REFERENCES "User"("id") ON DELETE CASCADE,
+    "provider" VARCHAR(64) NOT NULL,
+    "subject" VARCHAR(255) NOT NULL,
+    "email" VARCHAR(255) NOT NULL,
+    "created_at" TIMESTAMPTZ NOT NULL DEFAULT now(),
+    "last_login_at" TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX "idx_user_identity_provider_subject" ON "UserIdentity"("provider", "subject");
+CREATE INDEX "idx_user_identity_user_id" ON "UserIdentity"("user_id");
+
+CREATE TABLE "OIDCAuthRequest" (
+    "state_hash" VARCHAR(255) PRIMARY KEY,
+    "provider" VARCHAR(64) NOT NULL,
+    "nonce" VARCHAR(255) NOT NULL,
+    "code_verifier" VARCHAR(255) NOT NULL,
+    "expires_at" TIMESTAMPTZ NOT NULL,
+    "created_at" TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX "idx_oidc_auth_request_expires_at" ON "OIDCAuthRequest"("expires_at");
@@ -0,0 +1,3 @@
+-- 000014_create_trip_collaborators_table.down.sql
+
+DROP TABLE IF EXISTS "TripCollaborator";
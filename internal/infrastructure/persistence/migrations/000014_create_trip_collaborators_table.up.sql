@@ -0,0 +1,25 @@
+-- 000014_create_trip_collaborators_table.up.sql
+
+CREATE TABLE "TripCollaborator" (
+    "trip_id" UUID NOT NULL REFERENCES "Trip"("id") ON DELETE CASCADE,
+    "user_id" UUID NOT NULL REFERENCES "User"("id") ON DELETE CASCADE,
+    "role" VARCHAR(16) NOT NULL,
+    "created_at" TIMESTAMPTZ NOT NULL DEFAULT now(),
+    "updated_at" TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY ("trip_id", "user_id"),
+    CONSTRAINT "trip_collaborator_role" CHECK ("role" IN ('owner', 'editor', 'viewer'))
+);
+
+CREATE INDEX "idx_trip_collaborator_user_id" ON "TripCollaborator"("user_id");
+
+-- 旅行ごとにownerは1人のみ
+CREATE UNIQUE INDEX "idx_trip_collaborator_owner" ON "TripCollaborator"("trip_id") WHERE "role" = 'owner';
+
+CREATE TRIGGER update_tripcollaborator_updated_at
+BEFORE UPDATE ON "TripCollaborator"
+FOR EACH ROW
+EXECUTE FUNCTION update_updated_at_column();
+
+-- 既存の旅行の作成者をownerとして登録
+INSERT INTO "TripCollaborator" ("trip_id", "user_id", "role", "created_at", "updated_at")
+SELECT "id", "user_id", 'owner', "created_at", now() FROM "Trip";
@@ -0,0 +1,7 @@
+-- 000015_create_trip_invitations_table.down.sql
+
+DROP INDEX IF EXISTS "idx_member_user_id";
+
+ALTER TABLE "Member" DROP COLUMN IF EXISTS "user_id";
+
+DROP TABLE IF EXISTS "TripInvitation";
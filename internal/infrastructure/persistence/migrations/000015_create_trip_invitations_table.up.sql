@@ -0,0 +1,27 @@
+-- 000015_create_trip_invitations_table.up.sql
+
+CREATE TABLE "TripInvitation" (
+    "id" UUID PRIMARY KEY DEFAULT uuid_generate_v7(),
+    "trip_id" UUID NOT NULL REFERENCES "Trip"("id") ON DELETE CASCADE,
+    "email" VARCHAR(255) NOT NULL,
+    "role" VARCHAR(16) NOT NULL,
+    "member_name" VARCHAR(255),
+    "token_hash" VARCHAR(255) UNIQUE NOT NULL,
+    "invited_by" UUID NOT NULL REFERENCES "User"("id") ON DELETE CASCADE,
+    "expires_at" TIMESTAMPTZ NOT NULL,
+    "sent_at" TIMESTAMPTZ NOT NULL,
+    "accepted_at" TIMESTAMPTZ,
+    "accepted_by" UUID REFERENCES "User"("id") ON DELETE SET NULL,
+    "created_at" TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT "trip_invitation_role" CHECK ("role" IN ('editor', 'viewer'))
+);
+
+CREATE INDEX "idx_trip_invitation_trip_id" ON "TripInvitation"("trip_id");
+
+-- 同じメールアドレスへの保留中の招待は旅行ごとに1件のみ
+CREATE UNIQUE INDEX "idx_trip_invitation_pending_email" ON "TripInvitation"("trip_id", lower("email")) WHERE "accepted_at" IS NULL;
+
+-- 招待を承諾したユーザーをメンバーに紐付ける
+ALTER TABLE "Member" ADD COLUMN "user_id" UUID REFERENCES "User"("id") ON DELETE SET NULL;
+
+CREATE INDEX "idx_member_user_id" ON "Member"("user_id");
@@ -0,0 +1,3 @@
+-- 000016_add_member_user_unique_index.down.sql
+
+DROP INDEX IF EXISTS "idx_member_trip_id_user_id";
@@ -0,0 +1,4 @@
+-- 000016_add_member_user_unique_index.up.sql
+
+-- ユーザーが紐付くメンバーは旅行ごとに1人のみ
+CREATE UNIQUE INDEX "idx_member_trip_id_user_id" ON "Member"("trip_id", "user_id") WHERE "user_id" IS NOT NULL;
@@ -0,0 +1,30 @@
+-- 000017_create_share_links.down.sql
+
+-- 旅行ごとに最も古い共有リンクだけを残す
+DELETE FROM "ShareToken" s
+USING "ShareToken" older
+WHERE s."trip_id" = older."trip_id" AND (older."created_at", older."id") < (s."created_at", s."id");
+
+DROP INDEX IF EXISTS "idx_share_token_default";
+
+ALTER TABLE "ShareToken" DROP COLUMN IF EXISTS "is_default";
+
+ALTER TABLE "ShareToken" DROP COLUMN IF EXISTS "last_used_at";
+
+ALTER TABLE "ShareToken" DROP COLUMN IF EXISTS "use_count";
+
+ALTER TABLE "ShareToken" DROP COLUMN IF EXISTS "max_uses";
+
+ALTER TABLE "ShareToken" DROP COLUMN IF EXISTS "expires_at";
+
+ALTER TABLE "ShareToken" DROP COLUMN IF EXISTS "scope";
+
+ALTER TABLE "ShareToken" DROP COLUMN IF EXISTS "name";
+
+DROP INDEX IF EXISTS "idx_share_token_trip_id";
+
+ALTER TABLE "ShareToken" DROP CONSTRAINT "ShareToken_pkey";
+
+ALTER TABLE "ShareToken" DROP COLUMN "id";
+
+ALTER TABLE "ShareToken" ADD PRIMARY KEY ("trip_id");
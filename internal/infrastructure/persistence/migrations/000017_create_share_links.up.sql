@@ -0,0 +1,36 @@
+-- 000017_create_share_links.up.sql
+
+-- 共有リンクを旅行ごとに複数発行できるようにする
+ALTER TABLE "ShareToken" DROP CONSTRAINT "ShareToken_pkey";
+
+ALTER TABLE "ShareToken" ADD COLUMN "id" UUID NOT NULL DEFAULT uuid_generate_v7();
+
+ALTER TABLE "ShareToken" ADD PRIMARY KEY ("id");
+
+CREATE INDEX "idx_share_token_trip_id" ON "ShareToken"("trip_id");
+
+-- 既存の共有リンクはこれまでどおり旅行情報まで編集できる
+ALTER TABLE "ShareToken" ADD COLUMN "name" VARCHAR(100) NOT NULL DEFAULT '共有リンク';
+
+ALTER TABLE "ShareToken" ADD COLUMN "scope" VARCHAR(16) NOT NULL DEFAULT 'edit_trip';
+
+ALTER TABLE "ShareToken" ALTER COLUMN "name" DROP DEFAULT;
+
+ALTER TABLE "ShareToken" ALTER COLUMN "scope" DROP DEFAULT;
+
+ALTER TABLE "ShareToken" ADD CONSTRAINT "share_token_scope" CHECK ("scope" IN ('view', 'comment', 'edit_schedules', 'edit_trip'));
+
+ALTER TABLE "ShareToken" ADD COLUMN "expires_at" TIMESTAMPTZ;
+
+ALTER TABLE "ShareToken" ADD COLUMN "max_uses" INTEGER CHECK ("max_uses" > 0);
+
+ALTER TABLE "ShareToken" ADD COLUMN "use_count" INTEGER NOT NULL DEFAULT 0;
+
+ALTER TABLE "ShareToken" ADD COLUMN "last_used_at" TIMESTAMPTZ;
+
+-- POST /trips/{tripId}/share で発行するデフォルトの共有リンクは名前ではなくこの列で区別する（旅行ごとに1つまで）
+ALTER TABLE "ShareToken" ADD COLUMN "is_default" BOOLEAN NOT NULL DEFAULT FALSE;
+
+UPDATE "ShareToken" SET "is_default" = TRUE;
+
+CREATE UNIQUE INDEX "idx_share_token_default" ON "ShareToken"("trip_id") WHERE "is_default";
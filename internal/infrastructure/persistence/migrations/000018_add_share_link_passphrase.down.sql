@@ -0,0 +1,3 @@
+-- 000018_add_share_link_passphrase.down.sql
+
+ALTER TABLE "ShareToken" DROP COLUMN IF EXISTS "passphrase_hash";
@@ -0,0 +1,4 @@
+-- 000018_add_share_link_passphrase.up.sql
+
+-- パスフレーズ付き共有リンク（bcryptハッシュ、NULLはパスフレーズなし）
+ALTER TABLE "ShareToken" ADD COLUMN "passphrase_hash" VARCHAR(255);
@@ -0,0 +1,3 @@
+-- 000019_create_share_link_accesses_table.down.sql
+
+DROP TABLE IF EXISTS "ShareLinkAccess";
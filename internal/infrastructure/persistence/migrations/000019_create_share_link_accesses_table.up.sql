@@ -0,0 +1,17 @@
+-- 000019_create_share_link_accesses_table.up.sql
+
+CREATE TABLE "ShareLinkAccess" (
+    "id" UUID PRIMARY KEY DEFAULT uuid_generate_v7(),
+    "trip_id" UUID NOT NULL REFERENCES "Trip"("id") ON DELETE CASCADE,
+    "share_token_id" UUID REFERENCES "ShareToken"("id") ON DELETE SET NULL,
+    "share_link_name" VARCHAR(100) NOT NULL,
+    "method" VARCHAR(10) NOT NULL,
+    "route" VARCHAR(255) NOT NULL,
+    "status" INTEGER NOT NULL,
+    "ip_hash" VARCHAR(64) NOT NULL,
+    "user_agent_family" VARCHAR(32) NOT NULL,
+    "accessed_at" TIMESTAMPTZ NOT NULL
+);
+
+-- 旅行ごとの日別集計と最近の編集の取得に使用
+CREATE INDEX "idx_share_link_access_trip_id_accessed_at" ON "ShareLinkAccess"("trip_id", "accessed_at");
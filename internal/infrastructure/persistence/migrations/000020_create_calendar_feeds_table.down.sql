@@ -0,0 +1,3 @@
+-- 000020_create_calendar_feeds_table.down.sql
+
+DROP TABLE IF EXISTS "CalendarFeed";
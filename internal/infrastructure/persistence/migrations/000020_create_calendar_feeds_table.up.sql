@@ -0,0 +1,9 @@
+-- 000020_create_calendar_feeds_table.up.sql
+
+-- ユーザーごとに1つのカレンダー購読URL（再発行時はトークンを置き換える）
+CREATE TABLE "CalendarFeed" (
+    "user_id" UUID PRIMARY KEY REFERENCES "User"("id") ON DELETE CASCADE,
+    "token_hash" VARCHAR(255) UNIQUE NOT NULL,
+    "last_used_at" TIMESTAMPTZ,
+    "created_at" TIMESTAMPTZ NOT NULL DEFAULT now()
+);
@@ -0,0 +1,3 @@
+-- 000021_create_schedule_imports_table.down.sql
+
+DROP TABLE IF EXISTS "ScheduleImport";
@@ -0,0 +1,12 @@
+-- 000021_create_schedule_imports_table.up.sql
+
+-- iCalendarからインポートしたイベントのUID（同じ旅行への重複インポートの検出に使用）
+CREATE TABLE "ScheduleImport" (
+    "trip_id" UUID NOT NULL REFERENCES "Trip"("id") ON DELETE CASCADE,
+    "uid" VARCHAR(255) NOT NULL,
+    "schedule_id" UUID NOT NULL REFERENCES "Schedule"("id") ON DELETE CASCADE,
+    "imported_at" TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY ("trip_id", "uid")
+);
+
+CREATE INDEX "idx_schedule_import_schedule_id" ON "ScheduleImport"("schedule_id");
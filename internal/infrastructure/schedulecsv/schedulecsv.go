@@ -0,0 +1,261 @@
+package schedulecsv
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// ErrInvalidCSV はファイル全体がCSVとして読めない、またはヘッダーが正しくない場合のエラー
+var ErrInvalidCSV = errors.New("invalid CSV data")
+
+// ContentType はCSVのレスポンスのContent-Type
+const ContentType = "text/csv; charset=utf-8"
+
+// 列名。インポートでは大文字・小文字と列の順序を問わず、memoとtime_zoneは省略できる
+const (
+	ColumnTitle    = "title"
+	ColumnStart    = "start"
+	ColumnEnd      = "end"
+	ColumnMemo     = "memo"
+	ColumnTimeZone = "time_zone"
+)
+
+// Header はエクスポートするCSVの列の順序
+var Header = []string{ColumnTitle, ColumnStart, ColumnEnd, ColumnMemo, ColumnTimeZone}
+
+// requiredColumns はインポートするCSVのヘッダーに必須の列
+var requiredColumns = []string{ColumnTitle, ColumnStart, ColumnEnd}
+
+// bom はExcelがUTF-8として開くためのバイトオーダーマーク
+const bom = '\uFEFF'
+
+// dateTimeFormat はエクスポートする日時の形式（秒が0でなければdateTimeSecondsFormat）
+const (
+	dateTimeFormat        = "2006-01-02 15:04"
+	dateTimeSecondsFormat = "2006-01-02 15:04:05"
+)
+
+// dateTimeLayouts はインポートで受け付けるタイムゾーンのない日時の形式
+// 表計算ソフトで編集すると区切りや桁数が変わるため、月・日・時の1桁と「/」区切りも受け付ける
+var dateTimeLayouts = []string{
+	"2006-1-2 15:04",
+	"2006-1-2 15:04:05",
+	"2006-1-2T15:04",
+	"2006-1-2T15:04:05",
+	"2006/1/2 15:04",
+	"2006/1/2 15:04:05",
+}
+
+// formulaPrefixes で始まるセルは表計算ソフトで数式として実行されるため、エクスポートでは「'」を付ける
+const formulaPrefixes = "=+-@\t\r"
+
+// Record はCSVの1行に書き出すスケジュール
+type Record struct {
+	Title string
+	Start time.Time
+	End   time.Time
+	Memo  string
+}
+
+// Row はインポートで読み取った1行
+type Row struct {
+	// Line はファイル内の行番号（ヘッダーが1行目）
+	Line int
+	Record
+	// Err はこの行を読み取れなかった理由。他の行の読み取りは続ける
+	Err error
+}
+
+// Write はヘッダーとレコードをBOM付きのUTF-8・CRLF改行で書き出す
+// 日時はlocの時刻で書き、time_zone列にlocの名前を書く
+func Write(w io.Writer, records []Record, loc *time.Location) error {
+	if _, err := io.WriteString(w, string(bom)); err != nil {
+		return err
+	}
+	cw := csv.NewWriter(w)
+	cw.UseCRLF = true
+	if err := cw.Write(Header); err != nil {
+		return err
+	}
+	for _, record := range records {
+		if err := cw.Write([]string{
+			escapeFormula(record.Title),
+			formatDateTime(record.Start.In(loc)),
+			formatDateTime(record.End.In(loc)),
+			escapeFormula(record.Memo),
+			loc.String(),
+		}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// Read はヘッダー行に続く行を順に読み取る。先頭のBOMと空行は読み飛ばす
+// time_zone列が空の行の日時はlocの時刻として扱う。オフセット付き（RFC 3339）の日時はそのオフセットを使う
+func Read(r io.Reader, loc *time.Location) ([]Row, error) {
+	br := bufio.NewReader(r)
+	if first, _, err := br.ReadRune(); err == nil && first != bom {
+		if err := br.UnreadRune(); err != nil {
+			return nil, err
+		}
+	} else if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	cr := csv.NewReader(br)
+	// 末尾の空の列を削除した表計算ソフトの出力も受け付ける
+	cr.FieldsPerRecord = -1
+	header, err := cr.Read()
+	if err != nil {
+		if err == io.EOF {
+			return nil, fmt.Errorf("%w: the file is empty", ErrInvalidCSV)
+		}
+		return nil, readError(err)
+	}
+	columns, err := parseHeader(header)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []Row
+	for {
+		fields, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			// 引用符の対応が崩れると以降の行の区切りも信用できないため、ファイル全体をエラーにする
+			return nil, readError(err)
+		}
+		line, _ := cr.FieldPos(0)
+		if isBlank(fields) {
+			continue
+		}
+		rows = append(rows, readRow(fields, columns, line, loc))
+	}
+	return rows, nil
+}
+
+// readError はCSVの構文エラーをErrInvalidCSVにする。読み込み自体のエラー（サイズ超過など）はそのまま返す
+func readError(err error) error {
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		return fmt.Errorf("%w: %w", ErrInvalidCSV, err)
+	}
+	return err
+}
+
+// parseHeader は列名から列の位置を求める。知らない列は無視する
+func parseHeader(header []string) (map[string]int, error) {
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		if _, ok := columns[name]; ok {
+			return nil, fmt.Errorf("%w: column %q appears twice in the header", ErrInvalidCSV, name)
+		}
+		columns[name] = i
+	}
+	for _, name := range requiredColumns {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("%w: the header must have the columns %s", ErrInvalidCSV, strings.Join(requiredColumns, ","))
+		}
+	}
+	return columns, nil
+}
+
+func readRow(fields []string, columns map[string]int, line int, loc *time.Location) Row {
+	get := func(name string) string {
+		i, ok := columns[name]
+		if !ok || i >= len(fields) {
+			return ""
+		}
+		return fields[i]
+	}
+
+	row := Row{
+		Line: line,
+		Record: Record{
+			Title: unescapeFormula(strings.TrimSpace(get(ColumnTitle))),
+			Memo:  unescapeFormula(get(ColumnMemo)),
+		},
+	}
+
+	if tz := strings.TrimSpace(get(ColumnTimeZone)); tz != "" {
+		// "Local"はサーバーのタイムゾーンになるため受け付けない
+		rowLoc, err := time.LoadLocation(tz)
+		if err != nil || tz == "Local" {
+			row.Err = fmt.Errorf("%s: unknown time zone %q", ColumnTimeZone, tz)
+			return row
+		}
+		loc = rowLoc
+	}
+
+	var err error
+	if row.Start, err = parseDateTime(get(ColumnStart), loc); err != nil {
+		row.Err = fmt.Errorf("%s: %w", ColumnStart, err)
+		return row
+	}
+	if row.End, err = parseDateTime(get(ColumnEnd), loc); err != nil {
+		row.Err = fmt.Errorf("%s: %w", ColumnEnd, err)
+		return row
+	}
+	return row
+}
+
+func parseDateTime(value string, loc *time.Location) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, errors.New("is required")
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	for _, layout := range dateTimeLayouts {
+		if t, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%q is not a date and time like 2025-08-01 09:00", value)
+}
+
+func formatDateTime(t time.Time) string {
+	if t.Second() != 0 {
+		return t.Format(dateTimeSecondsFormat)
+	}
+	return t.Format(dateTimeFormat)
+}
+
+// escapeFormula は数式として実行されるセルの先頭に「'」を付ける（CSVインジェクション対策）
+func escapeFormula(value string) string {
+	if value != "" && strings.ContainsRune(formulaPrefixes, rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+// unescapeFormula はescapeFormulaで付けた「'」を取り除く
+func unescapeFormula(value string) string {
+	if len(value) >= 2 && value[0] == '\'' && strings.ContainsRune(formulaPrefixes, rune(value[1])) {
+		return value[1:]
+	}
+	return value
+}
+
+func isBlank(fields []string) bool {
+	for _, field := range fields {
+		if strings.TrimSpace(field) != "" {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,114 @@
+package schedulecsv
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRead(t *testing.T) {
+	f, err := os.Open("testdata/import.csv")
+	require.NoError(t, err)
+	defer f.Close()
+
+	// time_zone列が空の行はlocの時刻になるべき
+	jst, err := time.LoadLocation("Asia/Tokyo")
+	require.NoError(t, err)
+	hst, err := time.LoadLocation("Pacific/Honolulu")
+	require.NoError(t, err)
+	rows, err := Read(f, hst)
+	require.NoError(t, err)
+	require.Len(t, rows, 7)
+
+	// BOM付き・大文字の列名・知らない列、time_zone列、複数行のメモ
+	flight := rows[0]
+	require.NoError(t, flight.Err)
+	assert.Equal(t, 2, flight.Line)
+	assert.Equal(t, "NH123 羽田 → 那覇", flight.Title)
+	assert.Equal(t, "座席: 12A, 12B\n預け荷物あり", flight.Memo)
+	assert.True(t, flight.Start.Equal(time.Date(2025, 8, 1, 8, 30, 0, 0, jst)))
+	assert.True(t, flight.End.Equal(time.Date(2025, 8, 1, 11, 5, 0, 0, jst)))
+
+	// 表計算ソフトが書き換えた日時の形式、空行は読み飛ばす
+	hotel := rows[1]
+	require.NoError(t, hotel.Err)
+	assert.Equal(t, 4, hotel.Line)
+	assert.True(t, hotel.Start.Equal(time.Date(2025, 8, 1, 15, 0, 0, 0, hst)))
+	assert.True(t, hotel.End.Equal(time.Date(2025, 8, 2, 11, 0, 0, 0, hst)))
+
+	// オフセット付きの日時
+	tour := rows[2]
+	require.NoError(t, tour.Err)
+	assert.Equal(t, 6, tour.Line)
+	assert.True(t, tour.Start.Equal(time.Date(2025, 8, 2, 1, 0, 0, 0, time.UTC)))
+
+	// 数式対策の「'」は取り除くべき
+	formula := rows[3]
+	require.NoError(t, formula.Err)
+	assert.Equal(t, "=1+1", formula.Title)
+	assert.Equal(t, "-辛口", formula.Memo)
+
+	// 読み取れない行は行ごとのエラーになるべき
+	assert.Equal(t, 8, rows[4].Line)
+	assert.ErrorContains(t, rows[4].Err, `start: "2025-08-03" is not a date and time`)
+	assert.ErrorContains(t, rows[5].Err, `time_zone: unknown time zone "Mars/Olympus"`)
+	assert.Equal(t, "火星", rows[5].Title)
+	assert.ErrorContains(t, rows[6].Err, "end: is required")
+}
+
+func TestReadInvalidCSV(t *testing.T) {
+	for name, data := range map[string]string{
+		"empty":            "",
+		"bom only":         "\uFEFF",
+		"missing column":   "title,start\r\nx,2025-08-01 09:00\r\n",
+		"duplicate column": "title,start,end,Title\r\n",
+		"bare quote":       "title,start,end\r\na\"b,2025-08-01 09:00,2025-08-01 10:00\r\n",
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := Read(strings.NewReader(data), time.UTC)
+			assert.ErrorIs(t, err, ErrInvalidCSV)
+		})
+	}
+}
+
+func TestReadMissingColumnListsRequiredColumns(t *testing.T) {
+	_, err := Read(strings.NewReader("title,start,memo\r\n"), time.UTC)
+	assert.ErrorIs(t, err, ErrInvalidCSV)
+	assert.ErrorContains(t, err, "the header must have the columns title,start,end")
+	assert.NotContains(t, err.Error(), ColumnTimeZone)
+}
+
+func TestWriteReadRoundTrip(t *testing.T) {
+	jst, err := time.LoadLocation("Asia/Tokyo")
+	require.NoError(t, err)
+	records := []Record{
+		{Title: "美ら海水族館", Start: time.Date(2025, 8, 1, 1, 0, 0, 0, time.UTC), End: time.Date(2025, 8, 1, 3, 0, 0, 0, time.UTC), Memo: "チケット, 予約済み"},
+		{Title: "=cmd|' /C calc'!A0", Start: time.Date(2025, 8, 1, 9, 0, 30, 0, jst), End: time.Date(2025, 8, 1, 10, 0, 0, 0, jst), Memo: "\"引用符\"\n改行"},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, records, jst))
+	data := buf.String()
+	// ExcelがUTF-8として開けるBOM付き・CRLF改行で、日時はlocの時刻になるべき
+	assert.True(t, strings.HasPrefix(data, "\uFEFFtitle,start,end,memo,time_zone\r\n"))
+	assert.Contains(t, data, "美ら海水族館,2025-08-01 10:00,2025-08-01 12:00,\"チケット, 予約済み\",Asia/Tokyo\r\n")
+	assert.Contains(t, data, "2025-08-01 09:00:30")
+	// 数式として実行されないように「'」が付くべき
+	assert.Contains(t, data, "\r\n'=cmd|")
+
+	rows, err := Read(&buf, time.UTC)
+	require.NoError(t, err)
+	require.Len(t, rows, len(records))
+	for i, row := range rows {
+		require.NoError(t, row.Err)
+		assert.Equal(t, records[i].Title, row.Title)
+		assert.Equal(t, records[i].Memo, row.Memo)
+		assert.True(t, records[i].Start.Equal(row.Start))
+		assert.True(t, records[i].End.Equal(row.End))
+	}
+}
@@ -0,0 +1,199 @@
+package tripbackup
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Format はドキュメントの種類を示すformatフィールドの値
+const Format = "trip-app/trip"
+
+// CurrentVersion は書き出すドキュメントのバージョン
+// 形式を変えるときはこの値を上げ、前のバージョンのドキュメントを変換するMigrationをmigrationsに追加する
+const CurrentVersion = 1
+
+// ErrInvalidDocument はドキュメントが旅行のバックアップとして読めない場合のエラー
+var ErrInvalidDocument = errors.New("invalid trip document")
+
+// ErrUnsupportedVersion はドキュメントのバージョンを読めない（このサーバーより新しい、または変換できない）場合のエラー
+var ErrUnsupportedVersion = errors.New("unsupported trip document version")
+
+// Document は旅行・メンバー・スケジュールのバックアップ（バージョンCurrentVersionの形式）
+// IDは環境ごとに異なるため含めず、メンバーはドキュメント内だけで使うrefで参照する
+type Document struct {
+	Format     string     `json:"format"`
+	Version    int        `json:"version"`
+	ExportedAt time.Time  `json:"exportedAt"`
+	Trip       Trip       `json:"trip"`
+	Members    []Member   `json:"members"`
+	Schedules  []Schedule `json:"schedules"`
+}
+
+type Trip struct {
+	Title string `json:"title"`
+	// StartDateとEndDateは"2006-01-02"形式の日付
+	StartDate string `json:"startDate"`
+	EndDate   string `json:"endDate"`
+	// OwnerMemberRef は旅行の所有者に紐付いたメンバーのref。インポートではインポートしたユーザーに紐付ける
+	OwnerMemberRef *string `json:"ownerMemberRef,omitempty"`
+}
+
+type Member struct {
+	// Ref はドキュメント内でメンバーを参照するための値。インポートでは新しいIDを振り直す
+	Ref  string `json:"ref"`
+	Name string `json:"name"`
+}
+
+type Schedule struct {
+	Title         string    `json:"title"`
+	StartDateTime time.Time `json:"startDateTime"`
+	EndDateTime   time.Time `json:"endDateTime"`
+	Memo          string    `json:"memo,omitempty"`
+}
+
+// Migration はバージョンnのドキュメントをバージョンn+1の形式に書き換える
+// 書き換えはJSONをデコードしたままの値に対して行い、versionフィールドは呼び出し側で更新する
+type Migration func(doc map[string]any) error
+
+// migrations は変換元のバージョンごとのMigration。古いバージョンは順に変換してから読み取る
+var migrations = map[int]Migration{}
+
+// NewDocument は書き出す日時と現在の形式・バージョンを設定したドキュメントを作る
+func NewDocument(exportedAt time.Time) *Document {
+	return &Document{
+		Format:     Format,
+		Version:    CurrentVersion,
+		ExportedAt: exportedAt,
+		Members:    []Member{},
+		Schedules:  []Schedule{},
+	}
+}
+
+// Encode はドキュメントを人が読めるように整形したJSONで書き出す
+func Encode(w io.Writer, doc *Document) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	return enc.Encode(doc)
+}
+
+// Decode はドキュメントを読み取る。古いバージョンはmigrationsで現在の形式に変換する
+// 読み込み自体のエラー（サイズ超過など）はそのまま返す
+func Decode(r io.Reader) (*Document, error) {
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+	var raw map[string]any
+	if err := dec.Decode(&raw); err != nil {
+		return nil, decodeError(err)
+	}
+	if raw == nil {
+		return nil, fmt.Errorf("%w: the document must be a JSON object", ErrInvalidDocument)
+	}
+	// ドキュメントの後には空白以外を受け付けない（2つ目の値や壊れたデータの連結を防ぐ）
+	if _, err := dec.Token(); !errors.Is(err, io.EOF) {
+		var syntaxErr *json.SyntaxError
+		if err != nil && !errors.As(err, &syntaxErr) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w: unexpected data after the document", ErrInvalidDocument)
+	}
+	if format, _ := raw["format"].(string); format != Format {
+		return nil, fmt.Errorf("%w: format must be %q", ErrInvalidDocument, Format)
+	}
+	version, err := documentVersion(raw)
+	if err != nil {
+		return nil, err
+	}
+	if err := upgrade(raw, version, CurrentVersion, migrations); err != nil {
+		return nil, err
+	}
+
+	// 変換後の値を現在の形式として厳密に読み直す
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil, err
+	}
+	strict := json.NewDecoder(bytes.NewReader(data))
+	strict.DisallowUnknownFields()
+	var doc Document
+	if err := strict.Decode(&doc); err != nil {
+		// dataはメモリ上にあるため、ここでのエラーは全て内容の誤り
+		return nil, fmt.Errorf("%w: %w", ErrInvalidDocument, err)
+	}
+	if err := validateReferences(&doc); err != nil {
+		return nil, err
+	}
+	return &doc, nil
+}
+
+// decodeError はJSONとして読めないエラーをErrInvalidDocumentにする。読み込み自体のエラーはそのまま返す
+func decodeError(err error) error {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+		return fmt.Errorf("%w: the document is empty or truncated", ErrInvalidDocument)
+	case errors.As(err, &syntaxErr), errors.As(err, &typeErr):
+		return fmt.Errorf("%w: %w", ErrInvalidDocument, err)
+	default:
+		return err
+	}
+}
+
+func documentVersion(raw map[string]any) (int, error) {
+	number, ok := raw["version"].(json.Number)
+	if !ok {
+		return 0, fmt.Errorf("%w: version is missing", ErrInvalidDocument)
+	}
+	version, err := number.Int64()
+	if err != nil || version < 1 {
+		return 0, fmt.Errorf("%w: version must be a positive integer", ErrInvalidDocument)
+	}
+	if version > CurrentVersion {
+		return 0, fmt.Errorf("%w: version %d is newer than this server supports (%d)", ErrUnsupportedVersion, version, CurrentVersion)
+	}
+	return int(version), nil
+}
+
+// upgrade はバージョンversionのドキュメントをtargetまで1つずつ変換する
+func upgrade(raw map[string]any, version, target int, migrations map[int]Migration) error {
+	for ; version < target; version++ {
+		migrate, ok := migrations[version]
+		if !ok {
+			return fmt.Errorf("%w: version %d can not be read anymore", ErrUnsupportedVersion, version)
+		}
+		if err := migrate(raw); err != nil {
+			return fmt.Errorf("%w: converting version %d: %w", ErrInvalidDocument, version, err)
+		}
+		raw["version"] = json.Number(fmt.Sprint(version + 1))
+	}
+	return nil
+}
+
+// validateReferences はメンバーのrefが一意で、参照先のメンバーが存在し、日付が読めることを確認する
+func validateReferences(doc *Document) error {
+	refs := make(map[string]bool, len(doc.Members))
+	for i, member := range doc.Members {
+		if member.Ref == "" {
+			return fmt.Errorf("%w: members[%d].ref is required", ErrInvalidDocument, i)
+		}
+		if refs[member.Ref] {
+			return fmt.Errorf("%w: members[%d].ref %q is used twice", ErrInvalidDocument, i, member.Ref)
+		}
+		refs[member.Ref] = true
+	}
+	if ref := doc.Trip.OwnerMemberRef; ref != nil && !refs[*ref] {
+		return fmt.Errorf("%w: trip.ownerMemberRef %q does not refer to a member", ErrInvalidDocument, *ref)
+	}
+	if _, err := time.Parse(time.DateOnly, doc.Trip.StartDate); err != nil {
+		return fmt.Errorf("%w: trip.startDate must be a date like 2025-08-01", ErrInvalidDocument)
+	}
+	if _, err := time.Parse(time.DateOnly, doc.Trip.EndDate); err != nil {
+		return fmt.Errorf("%w: trip.endDate must be a date like 2025-08-01", ErrInvalidDocument)
+	}
+	return nil
+}
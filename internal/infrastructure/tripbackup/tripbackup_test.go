@@ -0,0 +1,131 @@
+package tripbackup
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncodeDecodeRoundTrip(t *testing.T) {
+	owner := "m1"
+	doc := NewDocument(time.Date(2025, 7, 1, 12, 0, 0, 0, time.UTC))
+	doc.Trip = Trip{Title: "沖縄旅行 <夏>", StartDate: "2025-08-01", EndDate: "2025-08-03", OwnerMemberRef: &owner}
+	doc.Members = []Member{{Ref: "m1", Name: "太郎"}, {Ref: "m2", Name: "花子"}}
+	doc.Schedules = []Schedule{{
+		Title:         "美ら海水族館",
+		StartDateTime: time.Date(2025, 8, 1, 1, 0, 0, 0, time.UTC),
+		EndDateTime:   time.Date(2025, 8, 1, 3, 0, 0, 0, time.UTC),
+		Memo:          "チケット予約済み",
+	}}
+
+	var buf bytes.Buffer
+	require.NoError(t, Encode(&buf, doc))
+	// HTMLのエスケープをせず、人が読めるように整形されるべき
+	assert.Contains(t, buf.String(), "\"title\": \"沖縄旅行 <夏>\"")
+	assert.Contains(t, buf.String(), "\"version\": 1,\n")
+
+	decoded, err := Decode(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, doc, decoded)
+}
+
+func TestDecodeInvalidDocument(t *testing.T) {
+	valid := func(edit func(doc map[string]any)) string {
+		doc := map[string]any{
+			"format":     Format,
+			"version":    1,
+			"exportedAt": "2025-07-01T12:00:00Z",
+			"trip":       map[string]any{"title": "沖縄旅行", "startDate": "2025-08-01", "endDate": "2025-08-03"},
+			"members":    []any{map[string]any{"ref": "m1", "name": "太郎"}},
+			"schedules":  []any{},
+		}
+		edit(doc)
+		data, err := json.Marshal(doc)
+		require.NoError(t, err)
+		return string(data)
+	}
+	_, err := Decode(strings.NewReader(valid(func(map[string]any) {})))
+	require.NoError(t, err)
+	// 末尾の改行は受け付けるべき
+	_, err = Decode(strings.NewReader(valid(func(map[string]any) {}) + "\n"))
+	require.NoError(t, err)
+
+	for name, data := range map[string]string{
+		"empty":           "",
+		"not json":        "hello",
+		"array":           "[]",
+		"null":            "null",
+		"truncated":       `{"format": "trip-app/trip", "version": 1`,
+		"second document": valid(func(map[string]any) {}) + valid(func(map[string]any) {}),
+		"trailing data":   valid(func(map[string]any) {}) + "garbage",
+		"trailing brace":  valid(func(map[string]any) {}) + "}",
+		"wrong format":    valid(func(doc map[string]any) { doc["format"] = "other" }),
+		"missing version": valid(func(doc map[string]any) { delete(doc, "version") }),
+		"zero version":    valid(func(doc map[string]any) { doc["version"] = 0 }),
+		"string version":  valid(func(doc map[string]any) { doc["version"] = "1" }),
+		"unknown field":   valid(func(doc map[string]any) { doc["extra"] = true }),
+		"bad date":        valid(func(doc map[string]any) { doc["trip"].(map[string]any)["startDate"] = "8/1" }),
+		"bad date time": valid(func(doc map[string]any) {
+			doc["schedules"] = []any{map[string]any{"title": "x", "startDateTime": "tomorrow", "endDateTime": "2025-08-01T00:00:00Z"}}
+		}),
+		"duplicate ref": valid(func(doc map[string]any) {
+			doc["members"] = []any{map[string]any{"ref": "m1", "name": "太郎"}, map[string]any{"ref": "m1", "name": "花子"}}
+		}),
+		"unknown owner ref": valid(func(doc map[string]any) { doc["trip"].(map[string]any)["ownerMemberRef"] = "m9" }),
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := Decode(strings.NewReader(data))
+			assert.ErrorIs(t, err, ErrInvalidDocument)
+		})
+	}
+
+	// このサーバーより新しいバージョンは読めないべき
+	_, err = Decode(strings.NewReader(valid(func(doc map[string]any) { doc["version"] = CurrentVersion + 1 })))
+	assert.ErrorIs(t, err, ErrUnsupportedVersion)
+}
+
+func TestUpgrade(t *testing.T) {
+	// バージョン1ではメンバーが名前の配列、バージョン2ではtitleがnameだった想定
+	fake := map[int]Migration{
+		1: func(doc map[string]any) error {
+			names, _ := doc["members"].([]any)
+			members := make([]any, len(names))
+			for i, name := range names {
+				members[i] = map[string]any{"ref": name, "name": name}
+			}
+			doc["members"] = members
+			return nil
+		},
+		2: func(doc map[string]any) error {
+			trip, ok := doc["trip"].(map[string]any)
+			if !ok {
+				return errors.New("trip is missing")
+			}
+			trip["title"] = trip["name"]
+			delete(trip, "name")
+			return nil
+		},
+	}
+
+	raw := map[string]any{
+		"version": json.Number("1"),
+		"trip":    map[string]any{"name": "沖縄旅行"},
+		"members": []any{"太郎"},
+	}
+	require.NoError(t, upgrade(raw, 1, 3, fake))
+	assert.Equal(t, json.Number("3"), raw["version"])
+	assert.Equal(t, map[string]any{"title": "沖縄旅行"}, raw["trip"])
+	assert.Equal(t, []any{map[string]any{"ref": "太郎", "name": "太郎"}}, raw["members"])
+
+	// 変換に失敗したドキュメントと、変換のないバージョンは読めないべき
+	err := upgrade(map[string]any{"members": []any{}}, 1, 3, fake)
+	assert.ErrorIs(t, err, ErrInvalidDocument)
+	err = upgrade(map[string]any{}, 0, 3, fake)
+	assert.ErrorIs(t, err, ErrUnsupportedVersion)
+}
@@ -1,7 +1,12 @@
 package middleware
 
 import (
+	"errors"
+	"net/http"
+	"strings"
+
 	"trip_app/internal/security"
+	"trip_app/internal/usecase"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
@@ -9,17 +14,19 @@ import (
 	"github.com/labstack/echo/v4"
 )
 
-// JWTトークンを検証し、ユーザーIDをコンテキストに設定するEchoミドルウェアを生成
-func AuthMiddleware(secret string) echo.MiddlewareFunc {
-	return echojwt.WithConfig(echojwt.Config{
-		// JWT トークンの署名に使用するキー
-		SigningKey: []byte(secret),
+// JWTトークンまたはパーソナルアクセストークンを検証し、ユーザーIDをコンテキストに設定するEchoミドルウェアを生成
+// JWTの場合は署名・有効期限の検証に加え、セッションが失効していないかをサーバー側で確認し、セッションIDも設定する
+// パーソナルアクセストークンの場合はスコープ（"token_scopes"）を設定し、ScopeMiddlewareで権限を確認する
+func AuthMiddleware(keys security.KeySet, userUsecase usecase.UserUsecase, patUsecase usecase.PersonalAccessTokenUsecase) echo.MiddlewareFunc {
+	jwtMiddleware := echojwt.WithConfig(echojwt.Config{
+		// JWTヘッダーのkidから検証鍵を選択（ローテーション済みの旧鍵もトークンの期限まで検証に使用）
+		KeyFunc: keys.Keyfunc,
 		// Claims の型を指定
 		NewClaimsFunc: func(c echo.Context) jwt.Claims {
 			return new(security.JwtCustomClaims)
 		},
 		// SuccessHandlerはトークンが有効な場合に呼び出される関数
-		// トークンからユーザーIDを抽出してコンテキストに保存
+		// トークンからユーザーIDとセッションID(jti)を抽出してコンテキストに保存
 		SuccessHandler: func(c echo.Context) {
 			if user, ok := c.Get("user").(*jwt.Token); ok {
 				if claims, ok := user.Claims.(*security.JwtCustomClaims); ok {
@@ -28,8 +35,58 @@ func AuthMiddleware(secret string) echo.MiddlewareFunc {
 					if err == nil {
 						c.Set("user_id", parsedUserID)
 					}
+					parsedSessionID, err := uuid.Parse(claims.ID)
+					if err == nil {
+						c.Set("session_id", parsedSessionID)
+					}
 				}
 			}
 		},
 	})
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		jwtHandler := jwtMiddleware(func(c echo.Context) error {
+			userID, ok := c.Get("user_id").(uuid.UUID)
+			if !ok {
+				return c.JSON(http.StatusUnauthorized, map[string]string{"message": "Unauthorized"})
+			}
+			sessionID, ok := c.Get("session_id").(uuid.UUID)
+			if !ok {
+				return c.JSON(http.StatusUnauthorized, map[string]string{"message": "Unauthorized"})
+			}
+
+			// 失効済み（ログアウト済み）のセッションを拒否
+			if err := userUsecase.ValidateSession(c.Request().Context(), userID, sessionID); err != nil {
+				if errors.Is(err, usecase.ErrSessionRevoked) {
+					return c.JSON(http.StatusUnauthorized, map[string]string{"message": err.Error()})
+				}
+				return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Internal server error"})
+			}
+
+			// handlerへ処理を渡す
+			return next(c)
+		})
+
+		return func(c echo.Context) error {
+			// パーソナルアクセストークンはプレフィックスで判別し、JWTとしては検証しない
+			rawToken, ok := strings.CutPrefix(c.Request().Header.Get(echo.HeaderAuthorization), "Bearer ")
+			if !ok || !strings.HasPrefix(rawToken, usecase.PersonalAccessTokenPrefix) {
+				return jwtHandler(c)
+			}
+
+			token, err := patUsecase.Authenticate(c.Request().Context(), rawToken)
+			if err != nil {
+				if errors.Is(err, usecase.ErrInvalidPersonalAccessToken) {
+					return c.JSON(http.StatusUnauthorized, map[string]string{"message": err.Error()})
+				}
+				return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Internal server error"})
+			}
+
+			c.Set("user_id", token.UserID)
+			c.Set("token_scopes", strings.Fields(token.Scopes))
+
+			// handlerへ処理を渡す
+			return next(c)
+		}
+	}
 }
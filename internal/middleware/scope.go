@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"net/http"
+	"slices"
+
+	"github.com/labstack/echo/v4"
+)
+
+// ScopeMiddleware はパーソナルアクセストークンのスコープを検証するEchoミドルウェアを生成
+// 参照系（GET・HEAD）はreadScope、それ以外はwriteScopeが必要
+// JWT（ログインセッション）でのアクセスはスコープの制限を受けない
+func ScopeMiddleware(readScope, writeScope string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			// AuthMiddlewareはパーソナルアクセストークンの場合のみスコープを設定する
+			scopes, ok := c.Get("token_scopes").([]string)
+			if !ok {
+				return next(c)
+			}
+
+			required := writeScope
+			if method := c.Request().Method; method == http.MethodGet || method == http.MethodHead {
+				required = readScope
+			}
+			if !slices.Contains(scopes, required) {
+				return c.JSON(http.StatusForbidden, map[string]string{"message": "Personal access token lacks the required scope: " + required})
+			}
+
+			// handlerへ処理を渡す
+			return next(c)
+		}
+	}
+}
+
+// SessionOnlyMiddleware はパーソナルアクセストークンでのアクセスを拒否するEchoミドルウェアを生成
+// アカウント管理（パスワード変更、トークン発行など）はログインセッションからのみ許可する
+func SessionOnlyMiddleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if _, ok := c.Get("token_scopes").([]string); ok {
+				return c.JSON(http.StatusForbidden, map[string]string{"message": "This endpoint is not available to personal access tokens"})
+			}
+
+			// handlerへ処理を渡す
+			return next(c)
+		}
+	}
+}
@@ -3,12 +3,35 @@ package middleware
 import (
 	"errors"
 	"net/http"
+	"trip_app/internal/domain"
 	"trip_app/internal/usecase"
 
 	"github.com/labstack/echo/v4"
 )
 
-func ShareTokenOwnershipMiddleware(publicTripUsecase usecase.PublicTripUsecase) echo.MiddlewareFunc {
+// ShareGrantHeader はパスフレーズ付き共有リンクのアクセス許可トークンを送るヘッダー
+const ShareGrantHeader = "X-Share-Grant"
+
+// ShareVisitHeader は訪問の入口で発行する訪問トークンを返し、同じ訪問の他のリクエストで送るヘッダー
+const ShareVisitHeader = "X-Share-Visit"
+
+// ShareTokenOwnershipMiddleware は共有リンクを検証するEchoミドルウェアを生成
+// ルートごとに必要なスコープを指定し、有効期限切れ・利用回数の上限に達したリンクは拒否する
+// パスフレーズ付きの共有リンクはそのリンク用のアクセス許可トークン（ShareGrantHeader）がない場合401を返す
+// 検証を通ったリクエストはハンドラーの処理後にアクセスログへ記録する（書き込みは非同期）
+// 利用回数には数えない（訪問の入口のルートはShareTokenVisitMiddlewareを使う）
+// 訪問トークン（ShareVisitHeader）があれば、その訪問で利用回数の上限に達していても拒否しない
+func ShareTokenOwnershipMiddleware(publicTripUsecase usecase.PublicTripUsecase, shareActivityUsecase usecase.ShareActivityUsecase, required domain.ShareScope) echo.MiddlewareFunc {
+	return shareTokenMiddleware(publicTripUsecase, shareActivityUsecase, required, false)
+}
+
+// ShareTokenVisitMiddleware は訪問の入口（GET /public/trips/{shareToken}）用のミドルウェアを生成
+// viewスコープで検証し、リクエストを共有リンクの利用回数に1回数え、訪問トークンをShareVisitHeaderで返す
+func ShareTokenVisitMiddleware(publicTripUsecase usecase.PublicTripUsecase, shareActivityUsecase usecase.ShareActivityUsecase) echo.MiddlewareFunc {
+	return shareTokenMiddleware(publicTripUsecase, shareActivityUsecase, domain.ShareScopeView, true)
+}
+
+func shareTokenMiddleware(publicTripUsecase usecase.PublicTripUsecase, shareActivityUsecase usecase.ShareActivityUsecase, required domain.ShareScope, countUse bool) echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
 			// URLからshareTokenを取得
@@ -18,19 +41,56 @@ func ShareTokenOwnershipMiddleware(publicTripUsecase usecase.PublicTripUsecase)
 			}
 
 			// Usecaseを使ってshareTokenから旅行情報を取得
-			trip, err := publicTripUsecase.GetTripByShareToken(c.Request().Context(), shareToken)
+			// パスフレーズ付きの共有リンクは /unlock で取得したアクセス許可トークンが必要
+			grant := c.Request().Header.Get(ShareGrantHeader)
+			// 入口で利用回数に数えた訪問の詳細やスケジュールの取得は、訪問トークンで上限に達していても許可する
+			visit := c.Request().Header.Get(ShareVisitHeader)
+
+			trip, shareLink, err := publicTripUsecase.GetTripByShareToken(c.Request().Context(), shareToken, grant, visit, required, countUse)
 			if err != nil {
 				if errors.Is(err, usecase.ErrTripNotFound) {
 					return c.JSON(http.StatusNotFound, map[string]string{"message": "Trip not found"})
 				}
+				if errors.Is(err, usecase.ErrShareLinkExpired) {
+					return c.JSON(http.StatusGone, map[string]string{"message": "Share link has expired"})
+				}
+				if errors.Is(err, usecase.ErrShareLinkLocked) {
+					return c.JSON(http.StatusUnauthorized, map[string]string{"message": "Share link requires a passphrase"})
+				}
+				if errors.Is(err, usecase.ErrShareScopeInsufficient) {
+					return c.JSON(http.StatusForbidden, map[string]string{"message": "Share link does not allow this operation"})
+				}
 				return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Internal server error"})
 			}
 
-			// 取得した旅行情報をctxに保存
+			if countUse {
+				visitToken, _, err := publicTripUsecase.StartShareVisit(shareLink)
+				if err != nil {
+					return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Internal server error"})
+				}
+				c.Response().Header().Set(ShareVisitHeader, visitToken)
+			}
+
+			// 取得した旅行情報と共有リンクをctxに保存
 			c.Set("trip", trip)
+			c.Set("share_link", shareLink)
 
 			// handlerへ処理を渡す
-			return next(c)
+			err = next(c)
+
+			// アクセスログを記録（ルートはパターンを使い、URLの共有トークンは残さない）
+			status := c.Response().Status
+			if err != nil {
+				var he *echo.HTTPError
+				if errors.As(err, &he) {
+					status = he.Code
+				} else {
+					status = http.StatusInternalServerError
+				}
+			}
+			shareActivityUsecase.RecordAccess(shareLink, c.Request().Method, c.Path(), status, c.RealIP(), c.Request().UserAgent())
+
+			return err
 		}
 	}
 }
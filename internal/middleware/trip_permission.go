@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"trip_app/internal/domain"
+	"trip_app/internal/usecase"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// TripPermissionMiddleware は旅行へのアクセス権限を検証するEchoミドルウェアを生成
+// 参照系（GET・HEAD）はviewer以上、それ以外はeditor以上の権限が必要
+func TripPermissionMiddleware(tripUsecase usecase.TripUsecase) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			// 認証済みユーザーIDを取得
+			userID, ok := c.Get("user_id").(uuid.UUID)
+			if !ok {
+				return c.JSON(http.StatusUnauthorized, map[string]string{"message": "Unauthorized"})
+			}
+
+			// URLからtripIdを取得
+			tripIDStr := c.Param("tripId")
+			tripID, err := uuid.Parse(tripIDStr)
+			if err != nil {
+				return c.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid trip ID"})
+			}
+
+			// Usecaseを使って旅行情報とユーザーの権限を取得
+			trip, role, err := tripUsecase.GetTripForUser(c.Request().Context(), tripID, userID)
+			if err != nil {
+				if errors.Is(err, usecase.ErrTripNotFound) {
+					return c.JSON(http.StatusNotFound, map[string]string{"message": "Trip not found"})
+				}
+				if errors.Is(err, usecase.ErrTripAccessDenied) {
+					return c.JSON(http.StatusForbidden, map[string]string{"message": "Forbidden"})
+				}
+				return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Internal server error"})
+			}
+
+			// 操作に必要な権限を持っているかチェック
+			required := domain.TripRoleEditor
+			if method := c.Request().Method; method == http.MethodGet || method == http.MethodHead {
+				required = domain.TripRoleViewer
+			}
+			if !role.Includes(required) {
+				return c.JSON(http.StatusForbidden, map[string]string{"message": "Forbidden"})
+			}
+
+			// 取得した旅行情報と権限をctxに保存
+			c.Set("trip", trip)
+			c.Set("trip_role", role)
+
+			// handlerへ処理を渡す
+			return next(c)
+		}
+	}
+}
+
+// TripRoleMiddleware はルート単位で追加の権限を要求するEchoミドルウェアを生成
+// TripPermissionMiddlewareの後に使用する（削除・共有・共同編集者の管理などownerのみの操作に使用）
+func TripRoleMiddleware(required domain.TripRole) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			role, ok := c.Get("trip_role").(domain.TripRole)
+			if !ok || !role.Includes(required) {
+				return c.JSON(http.StatusForbidden, map[string]string{"message": "Forbidden"})
+			}
+
+			// handlerへ処理を渡す
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"trip_app/internal/domain"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type CalendarFeedRepository interface {
+	// Save creates the user's feed or replaces the token of the existing one
+	Save(ctx context.Context, feed *domain.CalendarFeed) error
+	FindByUserID(ctx context.Context, userID uuid.UUID) (*domain.CalendarFeed, error)
+	FindByTokenHash(ctx context.Context, tokenHash string) (*domain.CalendarFeed, error)
+	UpdateLastUsedAt(ctx context.Context, userID uuid.UUID, usedAt time.Time) error
+	// DeleteByUserID removes the user's feed. It returns false if the user has no feed.
+	DeleteByUserID(ctx context.Context, userID uuid.UUID) (bool, error)
+}
+
+type calendarFeedRepository struct {
+	db *gorm.DB
+}
+
+func NewCalendarFeedRepository(db *gorm.DB) CalendarFeedRepository {
+	return &calendarFeedRepository{db}
+}
+
+func (r *calendarFeedRepository) Save(ctx context.Context, feed *domain.CalendarFeed) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"token_hash", "last_used_at", "created_at"}),
+	}).Create(feed).Error
+}
+
+func (r *calendarFeedRepository) FindByUserID(ctx context.Context, userID uuid.UUID) (*domain.CalendarFeed, error) {
+	var feed domain.CalendarFeed
+	if err := r.db.WithContext(ctx).Where("user_id = ?", userID).First(&feed).Error; err != nil {
+		return nil, err
+	}
+	return &feed, nil
+}
+
+func (r *calendarFeedRepository) FindByTokenHash(ctx context.Context, tokenHash string) (*domain.CalendarFeed, error) {
+	var feed domain.CalendarFeed
+	if err := r.db.WithContext(ctx).Where("token_hash = ?", tokenHash).First(&feed).Error; err != nil {
+		return nil, err
+	}
+	return &feed, nil
+}
+
+func (r *calendarFeedRepository) UpdateLastUsedAt(ctx context.Context, userID uuid.UUID, usedAt time.Time) error {
+	return r.db.WithContext(ctx).Model(&domain.CalendarFeed{}).
+		Where("user_id = ?", userID).
+		Update("last_used_at", usedAt).Error
+}
+
+func (r *calendarFeedRepository) DeleteByUserID(ctx context.Context, userID uuid.UUID) (bool, error) {
+	result := r.db.WithContext(ctx).Where("user_id = ?", userID).Delete(&domain.CalendarFeed{})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
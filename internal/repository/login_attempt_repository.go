@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"trip_app/internal/domain"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type LoginAttemptRepository interface {
+	FindByKey(ctx context.Context, key string) (*domain.LoginAttempt, error)
+	// RecordFailure atomically increments the failure count of the key and returns the updated counter.
+	// The count starts over at 1 when the previous failure happened before windowStart.
+	RecordFailure(ctx context.Context, key string, failedAt, windowStart time.Time) (*domain.LoginAttempt, error)
+	Lock(ctx context.Context, key string, lockedUntil time.Time) error
+	Reset(ctx context.Context, key string) error
+}
+
+type loginAttemptRepository struct {
+	db *gorm.DB
+}
+
+func NewLoginAttemptRepository(db *gorm.DB) LoginAttemptRepository {
+	return &loginAttemptRepository{db}
+}
+
+func (r *loginAttemptRepository) FindByKey(ctx context.Context, key string) (*domain.LoginAttempt, error) {
+	var attempt domain.LoginAttempt
+	if err := r.db.WithContext(ctx).First(&attempt, "key = ?", key).Error; err != nil {
+		return nil, err
+	}
+	return &attempt, nil
+}
+
+func (r *loginAttemptRepository) RecordFailure(ctx context.Context, key string, failedAt, windowStart time.Time) (*domain.LoginAttempt, error) {
+	// upsert in a single statement so that concurrent failures are all counted
+	currentTable := clause.Table{Name: clause.CurrentTable}
+	attempt := domain.LoginAttempt{Key: key, FailureCount: 1, LastFailedAt: failedAt}
+	err := r.db.WithContext(ctx).Clauses(
+		clause.OnConflict{
+			Columns: []clause.Column{{Name: "key"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"failure_count":  gorm.Expr("CASE WHEN ?.last_failed_at < ? THEN 1 ELSE ?.failure_count + 1 END", currentTable, windowStart, currentTable),
+				"last_failed_at": failedAt,
+			}),
+		},
+		clause.Returning{},
+	).Create(&attempt).Error
+	if err != nil {
+		return nil, err
+	}
+	return &attempt, nil
+}
+
+func (r *loginAttemptRepository) Lock(ctx context.Context, key string, lockedUntil time.Time) error {
+	return r.db.WithContext(ctx).Model(&domain.LoginAttempt{}).
+		Where("key = ?", key).
+		Update("locked_until", lockedUntil).Error
+}
+
+func (r *loginAttemptRepository) Reset(ctx context.Context, key string) error {
+	return r.db.WithContext(ctx).Where("key = ?", key).Delete(&domain.LoginAttempt{}).Error
+}
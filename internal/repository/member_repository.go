@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"trip_app/internal/domain"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type MemberRepository interface {
+	Create(ctx context.Context, member *domain.Member) error
+	FindByID(ctx context.Context, tripID, memberID uuid.UUID) (*domain.Member, error)
+	// FindByUserID finds the member of the trip linked to the user
+	FindByUserID(ctx context.Context, tripID, userID uuid.UUID) (*domain.Member, error)
+	// Claim links the unlinked member to the user and makes the user a viewer of the trip unless they already have access.
+	// It returns gorm.ErrRecordNotFound if the member was linked in the meantime.
+	Claim(ctx context.Context, tripID, memberID, userID uuid.UUID, now time.Time) error
+	UpdateName(ctx context.Context, tripID, memberID uuid.UUID, name string) error
+	// Delete removes the member. It returns false if the trip has no such member.
+	Delete(ctx context.Context, tripID, memberID uuid.UUID) (bool, error)
+}
+
+type memberRepository struct {
+	db *gorm.DB
+}
+
+func NewMemberRepository(db *gorm.DB) MemberRepository {
+	return &memberRepository{db}
+}
+
+func (r *memberRepository) Create(ctx context.Context, member *domain.Member) error {
+	if err := r.db.WithContext(ctx).Omit("User").Create(member).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *memberRepository) FindByID(ctx context.Context, tripID, memberID uuid.UUID) (*domain.Member, error) {
+	var member domain.Member
+	if err := r.db.WithContext(ctx).Where("id = ? AND trip_id = ?", memberID, tripID).First(&member).Error; err != nil {
+		return nil, err
+	}
+	return &member, nil
+}
+
+func (r *memberRepository) FindByUserID(ctx context.Context, tripID, userID uuid.UUID) (*domain.Member, error) {
+	var member domain.Member
+	if err := r.db.WithContext(ctx).Where("trip_id = ? AND user_id = ?", tripID, userID).First(&member).Error; err != nil {
+		return nil, err
+	}
+	return &member, nil
+}
+
+func (r *memberRepository) Claim(ctx context.Context, tripID, memberID, userID uuid.UUID, now time.Time) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// the condition on user_id keeps two users from claiming the same member at once
+		result := tx.Model(&domain.Member{}).
+			Where("id = ? AND trip_id = ? AND user_id IS NULL", memberID, tripID).
+			Update("user_id", userID)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected != 1 {
+			return gorm.ErrRecordNotFound
+		}
+
+		// an existing collaborator keeps their role
+		collaborator := &domain.TripCollaborator{
+			TripID:    tripID,
+			UserID:    userID,
+			Role:      domain.TripRoleViewer,
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+		return tx.Omit("User").Clauses(clause.OnConflict{DoNothing: true}).Create(collaborator).Error
+	})
+}
+
+func (r *memberRepository) UpdateName(ctx context.Context, tripID, memberID uuid.UUID, name string) error {
+	return r.db.WithContext(ctx).Model(&domain.Member{}).
+		Where("id = ? AND trip_id = ?", memberID, tripID).
+		Update("name", name).Error
+}
+
+func (r *memberRepository) Delete(ctx context.Context, tripID, memberID uuid.UUID) (bool, error) {
+	result := r.db.WithContext(ctx).Where("id = ? AND trip_id = ?", memberID, tripID).Delete(&domain.Member{})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
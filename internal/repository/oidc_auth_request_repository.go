@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"trip_app/internal/domain"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type OIDCAuthRequestRepository interface {
+	Create(ctx context.Context, request *domain.OIDCAuthRequest) error
+	// Consume deletes the request and returns it, so that a state can be used only once.
+	// It returns gorm.ErrRecordNotFound if there is no such request.
+	Consume(ctx context.Context, stateHash string) (*domain.OIDCAuthRequest, error)
+	DeleteExpired(ctx context.Context, now time.Time) error
+}
+
+type oidcAuthRequestRepository struct {
+	db *gorm.DB
+}
+
+func NewOIDCAuthRequestRepository(db *gorm.DB) OIDCAuthRequestRepository {
+	return &oidcAuthRequestRepository{db}
+}
+
+func (r *oidcAuthRequestRepository) Create(ctx context.Context, request *domain.OIDCAuthRequest) error {
+	if err := r.db.WithContext(ctx).Create(request).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *oidcAuthRequestRepository) Consume(ctx context.Context, stateHash string) (*domain.OIDCAuthRequest, error) {
+	var requests []domain.OIDCAuthRequest
+	// DELETE ... RETURNING makes the lookup and the removal a single atomic step
+	result := r.db.WithContext(ctx).Clauses(clause.Returning{}).
+		Where("state_hash = ?", stateHash).
+		Delete(&requests)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if len(requests) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &requests[0], nil
+}
+
+func (r *oidcAuthRequestRepository) DeleteExpired(ctx context.Context, now time.Time) error {
+	return r.db.WithContext(ctx).Where("expires_at < ?", now).Delete(&domain.OIDCAuthRequest{}).Error
+}
@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"trip_app/internal/domain"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type PersonalAccessTokenRepository interface {
+	Create(ctx context.Context, token *domain.PersonalAccessToken) error
+	FindByTokenHash(ctx context.Context, tokenHash string) (*domain.PersonalAccessToken, error)
+	FindByUserID(ctx context.Context, userID uuid.UUID) ([]domain.PersonalAccessToken, error)
+	// Delete removes the user's token. It returns false if the user has no such token.
+	Delete(ctx context.Context, userID, tokenID uuid.UUID) (bool, error)
+	DeleteAllByUserID(ctx context.Context, userID uuid.UUID) error
+	UpdateLastUsedAt(ctx context.Context, tokenID uuid.UUID, usedAt time.Time) error
+}
+
+type personalAccessTokenRepository struct {
+	db *gorm.DB
+}
+
+func NewPersonalAccessTokenRepository(db *gorm.DB) PersonalAccessTokenRepository {
+	return &personalAccessTokenRepository{db}
+}
+
+func (r *personalAccessTokenRepository) Create(ctx context.Context, token *domain.PersonalAccessToken) error {
+	if err := r.db.WithContext(ctx).Create(token).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *personalAccessTokenRepository) FindByTokenHash(ctx context.Context, tokenHash string) (*domain.PersonalAccessToken, error) {
+	var token domain.PersonalAccessToken
+	if err := r.db.WithContext(ctx).Where("token_hash = ?", tokenHash).First(&token).Error; err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+func (r *personalAccessTokenRepository) FindByUserID(ctx context.Context, userID uuid.UUID) ([]domain.PersonalAccessToken, error) {
+	var tokens []domain.PersonalAccessToken
+	if err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("created_at").Find(&tokens).Error; err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+func (r *personalAccessTokenRepository) Delete(ctx context.Context, userID, tokenID uuid.UUID) (bool, error) {
+	result := r.db.WithContext(ctx).Where("id = ? AND user_id = ?", tokenID, userID).Delete(&domain.PersonalAccessToken{})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+func (r *personalAccessTokenRepository) DeleteAllByUserID(ctx context.Context, userID uuid.UUID) error {
+	return r.db.WithContext(ctx).Where("user_id = ?", userID).Delete(&domain.PersonalAccessToken{}).Error
+}
+
+func (r *personalAccessTokenRepository) UpdateLastUsedAt(ctx context.Context, tokenID uuid.UUID, usedAt time.Time) error {
+	return r.db.WithContext(ctx).Model(&domain.PersonalAccessToken{}).
+		Where("id = ?", tokenID).
+		Update("last_used_at", usedAt).Error
+}
@@ -9,6 +9,7 @@ import (
 
 type PublicTripRepository interface {
 	FindByShareToken(ctx context.Context, shareToken string) (*domain.Trip, error)
+	// Update saves the trip and syncs its members by id like TripRepository.Update
 	Update(ctx context.Context, trip *domain.Trip) error
 	FindWithSchedulesByShareToken(ctx context.Context, shareToken string) (*domain.Trip, error)
 }
@@ -34,7 +35,9 @@ func (r *publicTripRepository) FindByShareToken(ctx context.Context, shareToken
 }
 
 func (r *publicTripRepository) Update(ctx context.Context, trip *domain.Trip) error {
-	return r.db.WithContext(ctx).Save(trip).Error
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return updateTrip(tx, trip)
+	})
 }
 
 func (r *publicTripRepository) FindWithSchedulesByShareToken(ctx context.Context, shareToken string) (*domain.Trip, error) {
@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"trip_app/internal/domain"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type RecoveryCodeRepository interface {
+	// ReplaceAll deletes the user's existing codes and stores the new set in one transaction
+	ReplaceAll(ctx context.Context, userID uuid.UUID, recoveryCodes []domain.RecoveryCode) error
+	FindUnusedByCodeHash(ctx context.Context, userID uuid.UUID, codeHash string) (*domain.RecoveryCode, error)
+	// MarkUsed consumes the code. It returns false if the code had already been used.
+	MarkUsed(ctx context.Context, recoveryCodeID uuid.UUID, usedAt time.Time) (bool, error)
+}
+
+type recoveryCodeRepository struct {
+	db *gorm.DB
+}
+
+func NewRecoveryCodeRepository(db *gorm.DB) RecoveryCodeRepository {
+	return &recoveryCodeRepository{db}
+}
+
+func (r *recoveryCodeRepository) ReplaceAll(ctx context.Context, userID uuid.UUID, recoveryCodes []domain.RecoveryCode) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&domain.RecoveryCode{}).Error; err != nil {
+			return err
+		}
+		if len(recoveryCodes) == 0 {
+			return nil
+		}
+		return tx.Create(&recoveryCodes).Error
+	})
+}
+
+func (r *recoveryCodeRepository) FindUnusedByCodeHash(ctx context.Context, userID uuid.UUID, codeHash string) (*domain.RecoveryCode, error) {
+	var recoveryCode domain.RecoveryCode
+	if err := r.db.WithContext(ctx).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).
+		First(&recoveryCode).Error; err != nil {
+		return nil, err
+	}
+	return &recoveryCode, nil
+}
+
+func (r *recoveryCodeRepository) MarkUsed(ctx context.Context, recoveryCodeID uuid.UUID, usedAt time.Time) (bool, error) {
+	// conditional update so that a code cannot be consumed twice concurrently
+	result := r.db.WithContext(ctx).Model(&domain.RecoveryCode{}).
+		Where("id = ? AND used_at IS NULL", recoveryCodeID).
+		Update("used_at", usedAt)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
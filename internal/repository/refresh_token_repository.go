@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"trip_app/internal/domain"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type RefreshTokenRepository interface {
+	Create(ctx context.Context, refreshToken *domain.RefreshToken) error
+	FindByTokenHash(ctx context.Context, tokenHash string) (*domain.RefreshToken, error)
+	// MarkRotated marks the token as used. It returns false if the token had already been rotated.
+	MarkRotated(ctx context.Context, refreshTokenID uuid.UUID, rotatedAt time.Time) (bool, error)
+}
+
+type refreshTokenRepository struct {
+	db *gorm.DB
+}
+
+func NewRefreshTokenRepository(db *gorm.DB) RefreshTokenRepository {
+	return &refreshTokenRepository{db}
+}
+
+func (r *refreshTokenRepository) Create(ctx context.Context, refreshToken *domain.RefreshToken) error {
+	if err := r.db.WithContext(ctx).Create(refreshToken).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *refreshTokenRepository) FindByTokenHash(ctx context.Context, tokenHash string) (*domain.RefreshToken, error) {
+	var refreshToken domain.RefreshToken
+	if err := r.db.WithContext(ctx).First(&refreshToken, "token_hash = ?", tokenHash).Error; err != nil {
+		return nil, err
+	}
+	return &refreshToken, nil
+}
+
+func (r *refreshTokenRepository) MarkRotated(ctx context.Context, refreshTokenID uuid.UUID, rotatedAt time.Time) (bool, error) {
+	// conditional update so that two concurrent refreshes cannot both succeed
+	result := r.db.WithContext(ctx).Model(&domain.RefreshToken{}).
+		Where("id = ? AND rotated_at IS NULL", refreshTokenID).
+		Update("rotated_at", rotatedAt)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"context"
+
+	"trip_app/internal/domain"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type ScheduleImportRepository interface {
+	// Create records the uid of an imported event. It returns gorm.ErrDuplicatedKey if the uid was already imported to the trip.
+	Create(ctx context.Context, scheduleImport *domain.ScheduleImport) error
+	// FindImportedUIDs returns which of the uids were already imported to the trip
+	FindImportedUIDs(ctx context.Context, tripID uuid.UUID, uids []string) ([]string, error)
+}
+
+type scheduleImportRepository struct {
+	db *gorm.DB
+}
+
+func NewScheduleImportRepository(db *gorm.DB) ScheduleImportRepository {
+	return &scheduleImportRepository{db}
+}
+
+func (r *scheduleImportRepository) Create(ctx context.Context, scheduleImport *domain.ScheduleImport) error {
+	if err := r.db.WithContext(ctx).Omit("Trip", "Schedule").Create(scheduleImport).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *scheduleImportRepository) FindImportedUIDs(ctx context.Context, tripID uuid.UUID, uids []string) ([]string, error) {
+	var imported []string
+	if len(uids) == 0 {
+		return imported, nil
+	}
+	if err := r.db.WithContext(ctx).Model(&domain.ScheduleImport{}).
+		Where("trip_id = ? AND uid IN ?", tripID, uids).
+		Pluck("uid", &imported).Error; err != nil {
+		return nil, err
+	}
+	return imported, nil
+}
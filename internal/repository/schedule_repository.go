@@ -10,12 +10,20 @@ import (
 
 type ScheduleRepository interface {
 	Create(ctx context.Context, schedule *domain.Schedule) error
+	// CreateAll saves the schedules in a single transaction, so either all or none of them are created
+	CreateAll(ctx context.Context, schedules []domain.Schedule) error
 	FindByTripID(ctx context.Context, tripID uuid.UUID) ([]domain.Schedule, error)
-	FindByID(ctx context.Context, scheduleID uuid.UUID) (*domain.Schedule, error)
+	// FindByID finds the schedule only if it belongs to the trip
+	FindByID(ctx context.Context, tripID, scheduleID uuid.UUID) (*domain.Schedule, error)
+	// Update saves the schedule if it still belongs to schedule.TripID, otherwise it returns gorm.ErrRecordNotFound
 	Update(ctx context.Context, schedule *domain.Schedule) error
-	Delete(ctx context.Context, scheduleID uuid.UUID) error
+	// Delete removes the schedule. It returns false if the trip has no such schedule.
+	Delete(ctx context.Context, tripID, scheduleID uuid.UUID) (bool, error)
 }
 
+// scheduleBatchSize is the number of rows in one insert statement of CreateAll
+const scheduleBatchSize = 100
+
 type scheduleRepository struct {
 	db *gorm.DB
 }
@@ -31,6 +39,15 @@ func (r *scheduleRepository) Create(ctx context.Context, schedule *domain.Schedu
 	return nil
 }
 
+func (r *scheduleRepository) CreateAll(ctx context.Context, schedules []domain.Schedule) error {
+	if len(schedules) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return tx.CreateInBatches(&schedules, scheduleBatchSize).Error
+	})
+}
+
 func (r *scheduleRepository) FindByTripID(ctx context.Context, tripID uuid.UUID) ([]domain.Schedule, error) {
 	var schedules []domain.Schedule
 	if err := r.db.WithContext(ctx).Where("trip_id = ?", tripID).Find(&schedules).Error; err != nil {
@@ -39,24 +56,33 @@ func (r *scheduleRepository) FindByTripID(ctx context.Context, tripID uuid.UUID)
 	return schedules, nil
 }
 
-func (r *scheduleRepository) FindByID(ctx context.Context, scheduleID uuid.UUID) (*domain.Schedule, error) {
+func (r *scheduleRepository) FindByID(ctx context.Context, tripID, scheduleID uuid.UUID) (*domain.Schedule, error) {
 	var schedule domain.Schedule
-	if err := r.db.WithContext(ctx).First(&schedule, "id = ?", scheduleID).Error; err != nil {
+	if err := r.db.WithContext(ctx).First(&schedule, "id = ? AND trip_id = ?", scheduleID, tripID).Error; err != nil {
 		return nil, err
 	}
 	return &schedule, nil
 }
 
 func (r *scheduleRepository) Update(ctx context.Context, schedule *domain.Schedule) error {
-	if err := r.db.WithContext(ctx).Save(schedule).Error; err != nil {
-		return err
+	// unlike Save, this never inserts the schedule or moves it to another trip
+	result := r.db.WithContext(ctx).Model(schedule).
+		Where("trip_id = ?", schedule.TripID).
+		Select("title", "start_date_time", "end_date_time", "memo", "updated_at").
+		Updates(schedule)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected != 1 {
+		return gorm.ErrRecordNotFound
 	}
 	return nil
 }
 
-func (r *scheduleRepository) Delete(ctx context.Context, scheduleID uuid.UUID) error {
-	if err := r.db.WithContext(ctx).Delete(&domain.Schedule{}, "id = ?", scheduleID).Error; err != nil {
-		return err
+func (r *scheduleRepository) Delete(ctx context.Context, tripID, scheduleID uuid.UUID) (bool, error) {
+	result := r.db.WithContext(ctx).Where("id = ? AND trip_id = ?", scheduleID, tripID).Delete(&domain.Schedule{})
+	if result.Error != nil {
+		return false, result.Error
 	}
-	return nil
+	return result.RowsAffected == 1, nil
 }
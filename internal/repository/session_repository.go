@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"trip_app/internal/domain"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type SessionRepository interface {
+	Create(ctx context.Context, session *domain.Session) error
+	FindByID(ctx context.Context, sessionID uuid.UUID) (*domain.Session, error)
+	Revoke(ctx context.Context, sessionID uuid.UUID, revokedAt time.Time) error
+	RevokeAllByUserID(ctx context.Context, userID uuid.UUID, revokedAt time.Time) error
+}
+
+type sessionRepository struct {
+	db *gorm.DB
+}
+
+func NewSessionRepository(db *gorm.DB) SessionRepository {
+	return &sessionRepository{db}
+}
+
+func (r *sessionRepository) Create(ctx context.Context, session *domain.Session) error {
+	if err := r.db.WithContext(ctx).Create(session).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *sessionRepository) FindByID(ctx context.Context, sessionID uuid.UUID) (*domain.Session, error) {
+	var session domain.Session
+	if err := r.db.WithContext(ctx).First(&session, "id = ?", sessionID).Error; err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+func (r *sessionRepository) Revoke(ctx context.Context, sessionID uuid.UUID, revokedAt time.Time) error {
+	if err := r.db.WithContext(ctx).Model(&domain.Session{}).
+		Where("id = ? AND revoked_at IS NULL", sessionID).
+		Update("revoked_at", revokedAt).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *sessionRepository) RevokeAllByUserID(ctx context.Context, userID uuid.UUID, revokedAt time.Time) error {
+	if err := r.db.WithContext(ctx).Model(&domain.Session{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", revokedAt).Error; err != nil {
+		return err
+	}
+	return nil
+}
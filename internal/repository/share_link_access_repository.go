@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"trip_app/internal/domain"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type ShareLinkAccessRepository interface {
+	CreateBatch(ctx context.Context, accesses []domain.ShareLinkAccess) error
+	// CountDailyByTripID totals the accesses of the trip per UTC day since the time, the oldest day first
+	CountDailyByTripID(ctx context.Context, tripID uuid.UUID, since time.Time) ([]domain.ShareAccessDailyCount, error)
+	// FindRecentEditsByTripID returns the latest successful non-GET requests, the newest first
+	FindRecentEditsByTripID(ctx context.Context, tripID uuid.UUID, limit int) ([]domain.ShareLinkAccess, error)
+}
+
+type shareLinkAccessRepository struct {
+	db *gorm.DB
+}
+
+func NewShareLinkAccessRepository(db *gorm.DB) ShareLinkAccessRepository {
+	return &shareLinkAccessRepository{db}
+}
+
+func (r *shareLinkAccessRepository) CreateBatch(ctx context.Context, accesses []domain.ShareLinkAccess) error {
+	return r.db.WithContext(ctx).Omit("Trip", "ShareToken").Create(&accesses).Error
+}
+
+func (r *shareLinkAccessRepository) CountDailyByTripID(ctx context.Context, tripID uuid.UUID, since time.Time) ([]domain.ShareAccessDailyCount, error) {
+	var counts []domain.ShareAccessDailyCount
+	if err := r.db.WithContext(ctx).Model(&domain.ShareLinkAccess{}).
+		Select("(accessed_at AT TIME ZONE 'UTC')::date AS date, count(*) AS requests, count(DISTINCT ip_hash) AS visitors").
+		Where("trip_id = ? AND accessed_at >= ?", tripID, since).
+		Group("1").
+		Order("1").
+		Scan(&counts).Error; err != nil {
+		return nil, err
+	}
+	return counts, nil
+}
+
+func (r *shareLinkAccessRepository) FindRecentEditsByTripID(ctx context.Context, tripID uuid.UUID, limit int) ([]domain.ShareLinkAccess, error) {
+	var accesses []domain.ShareLinkAccess
+	if err := r.db.WithContext(ctx).
+		Where("trip_id = ? AND method NOT IN ('GET', 'HEAD') AND status < 400", tripID).
+		Order("accessed_at DESC").
+		Limit(limit).
+		Find(&accesses).Error; err != nil {
+		return nil, err
+	}
+	return accesses, nil
+}
@@ -2,7 +2,9 @@ package repository
 
 import (
 	"context"
+	"time"
 
+	"github.com/google/uuid"
 	"gorm.io/gorm"
 
 	"trip_app/internal/domain"
@@ -10,7 +12,19 @@ import (
 
 type ShareTokenRepository interface {
 	Create(ctx context.Context, shareToken *domain.ShareToken) error
-	Update(ctx context.Context, shareToken *domain.ShareToken) error
+	// FindByTripID lists the share links of the trip, the oldest first
+	FindByTripID(ctx context.Context, tripID uuid.UUID) ([]domain.ShareToken, error)
+	FindByTokenHash(ctx context.Context, tokenHash string) (*domain.ShareToken, error)
+	// FindDefault returns the default share link of the trip issued by POST /trips/{tripId}/share
+	FindDefault(ctx context.Context, tripID uuid.UUID) (*domain.ShareToken, error)
+	UpdateToken(ctx context.Context, id uuid.UUID, tokenHash string, updatedAt time.Time) error
+	// Use counts one use of the share link. It returns gorm.ErrRecordNotFound if the link has expired
+	// or reached its max uses, so concurrent requests can not use it more often than allowed.
+	Use(ctx context.Context, id uuid.UUID, now time.Time) error
+	// Delete revokes the share link. It returns false if the trip has no such link.
+	Delete(ctx context.Context, tripID, id uuid.UUID) (bool, error)
+	// DeleteByUserID revokes the share tokens of every trip owned by the user
+	DeleteByUserID(ctx context.Context, userID uuid.UUID) error
 }
 
 type shareTokenRepository struct {
@@ -25,6 +39,58 @@ func (r *shareTokenRepository) Create(ctx context.Context, shareToken *domain.Sh
 	return r.db.WithContext(ctx).Create(shareToken).Error
 }
 
-func (r *shareTokenRepository) Update(ctx context.Context, shareToken *domain.ShareToken) error {
-	return r.db.WithContext(ctx).Save(shareToken).Error
+func (r *shareTokenRepository) FindByTripID(ctx context.Context, tripID uuid.UUID) ([]domain.ShareToken, error) {
+	var shareTokens []domain.ShareToken
+	if err := r.db.WithContext(ctx).Where("trip_id = ?", tripID).Order("created_at").Find(&shareTokens).Error; err != nil {
+		return nil, err
+	}
+	return shareTokens, nil
+}
+
+func (r *shareTokenRepository) FindByTokenHash(ctx context.Context, tokenHash string) (*domain.ShareToken, error) {
+	var shareToken domain.ShareToken
+	if err := r.db.WithContext(ctx).First(&shareToken, "token_hash = ?", tokenHash).Error; err != nil {
+		return nil, err
+	}
+	return &shareToken, nil
+}
+
+func (r *shareTokenRepository) FindDefault(ctx context.Context, tripID uuid.UUID) (*domain.ShareToken, error) {
+	var shareToken domain.ShareToken
+	if err := r.db.WithContext(ctx).Where("trip_id = ? AND is_default", tripID).First(&shareToken).Error; err != nil {
+		return nil, err
+	}
+	return &shareToken, nil
+}
+
+func (r *shareTokenRepository) UpdateToken(ctx context.Context, id uuid.UUID, tokenHash string, updatedAt time.Time) error {
+	return r.db.WithContext(ctx).Model(&domain.ShareToken{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{"token_hash": tokenHash, "updated_at": updatedAt}).Error
+}
+
+func (r *shareTokenRepository) Use(ctx context.Context, id uuid.UUID, now time.Time) error {
+	result := r.db.WithContext(ctx).Model(&domain.ShareToken{}).
+		Where("id = ? AND (expires_at IS NULL OR expires_at > ?) AND (max_uses IS NULL OR use_count < max_uses)", id, now).
+		Updates(map[string]interface{}{"use_count": gorm.Expr("use_count + 1"), "last_used_at": now})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected != 1 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *shareTokenRepository) Delete(ctx context.Context, tripID, id uuid.UUID) (bool, error) {
+	result := r.db.WithContext(ctx).Where("trip_id = ? AND id = ?", tripID, id).Delete(&domain.ShareToken{})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+func (r *shareTokenRepository) DeleteByUserID(ctx context.Context, userID uuid.UUID) error {
+	tripIDs := r.db.Model(&domain.Trip{}).Select("id").Where("user_id = ?", userID)
+	return r.db.WithContext(ctx).Where("trip_id IN (?)", tripIDs).Delete(&domain.ShareToken{}).Error
 }
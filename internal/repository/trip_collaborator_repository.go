@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"trip_app/internal/domain"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type TripCollaboratorRepository interface {
+	Create(ctx context.Context, collaborator *domain.TripCollaborator) error
+	Find(ctx context.Context, tripID, userID uuid.UUID) (*domain.TripCollaborator, error)
+	// FindByTripID lists the collaborators with their user, the owner first
+	FindByTripID(ctx context.Context, tripID uuid.UUID) ([]domain.TripCollaborator, error)
+	UpdateRole(ctx context.Context, tripID, userID uuid.UUID, role domain.TripRole, updatedAt time.Time) error
+	// Delete removes the collaborator. It returns false if the user is not a collaborator of the trip.
+	Delete(ctx context.Context, tripID, userID uuid.UUID) (bool, error)
+	// TransferOwnership makes toUserID the owner of the trip and fromUserID an editor in a single transaction.
+	// It returns gorm.ErrRecordNotFound, and changes nothing, if fromUserID no longer owns the trip or toUserID has no access.
+	TransferOwnership(ctx context.Context, tripID, fromUserID, toUserID uuid.UUID, now time.Time) error
+}
+
+type tripCollaboratorRepository struct {
+	db *gorm.DB
+}
+
+func NewTripCollaboratorRepository(db *gorm.DB) TripCollaboratorRepository {
+	return &tripCollaboratorRepository{db}
+}
+
+func (r *tripCollaboratorRepository) Create(ctx context.Context, collaborator *domain.TripCollaborator) error {
+	if err := r.db.WithContext(ctx).Omit("User").Create(collaborator).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *tripCollaboratorRepository) Find(ctx context.Context, tripID, userID uuid.UUID) (*domain.TripCollaborator, error) {
+	var collaborator domain.TripCollaborator
+	if err := r.db.WithContext(ctx).Preload("User").Where("trip_id = ? AND user_id = ?", tripID, userID).First(&collaborator).Error; err != nil {
+		return nil, err
+	}
+	return &collaborator, nil
+}
+
+func (r *tripCollaboratorRepository) FindByTripID(ctx context.Context, tripID uuid.UUID) ([]domain.TripCollaborator, error) {
+	var collaborators []domain.TripCollaborator
+	if err := r.db.WithContext(ctx).
+		Preload("User").
+		Where("trip_id = ?", tripID).
+		Order("CASE role WHEN 'owner' THEN 0 WHEN 'editor' THEN 1 ELSE 2 END, created_at").
+		Find(&collaborators).Error; err != nil {
+		return nil, err
+	}
+	return collaborators, nil
+}
+
+func (r *tripCollaboratorRepository) UpdateRole(ctx context.Context, tripID, userID uuid.UUID, role domain.TripRole, updatedAt time.Time) error {
+	return r.db.WithContext(ctx).Model(&domain.TripCollaborator{}).
+		Where("trip_id = ? AND user_id = ?", tripID, userID).
+		Updates(map[string]interface{}{"role": role, "updated_at": updatedAt}).Error
+}
+
+func (r *tripCollaboratorRepository) Delete(ctx context.Context, tripID, userID uuid.UUID) (bool, error) {
+	result := r.db.WithContext(ctx).Where("trip_id = ? AND user_id = ?", tripID, userID).Delete(&domain.TripCollaborator{})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+func (r *tripCollaboratorRepository) TransferOwnership(ctx context.Context, tripID, fromUserID, toUserID uuid.UUID, now time.Time) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// move the trip first: it locks the trip row, so a concurrent transfer from the same owner waits
+		// and then matches no row, instead of promoting a second owner
+		result := tx.Model(&domain.Trip{}).
+			Where("id = ? AND user_id = ?", tripID, fromUserID).
+			Updates(map[string]interface{}{"user_id": toUserID, "updated_at": now})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected != 1 {
+			return gorm.ErrRecordNotFound
+		}
+		// demote next: a trip can have only one owner row at a time
+		if err := tx.Model(&domain.TripCollaborator{}).
+			Where("trip_id = ? AND user_id = ?", tripID, fromUserID).
+			Updates(map[string]interface{}{"role": domain.TripRoleEditor, "updated_at": now}).Error; err != nil {
+			return err
+		}
+		result = tx.Model(&domain.TripCollaborator{}).
+			Where("trip_id = ? AND user_id = ?", tripID, toUserID).
+			Updates(map[string]interface{}{"role": domain.TripRoleOwner, "updated_at": now})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected != 1 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+}
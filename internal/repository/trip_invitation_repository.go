@@ -0,0 +1,150 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"trip_app/internal/domain"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type TripInvitationRepository interface {
+	Create(ctx context.Context, invitation *domain.TripInvitation) error
+	FindByID(ctx context.Context, tripID, invitationID uuid.UUID) (*domain.TripInvitation, error)
+	FindByTokenHash(ctx context.Context, tokenHash string) (*domain.TripInvitation, error)
+	// FindPendingByTripID lists the invitations of the trip that have not been accepted, oldest first
+	FindPendingByTripID(ctx context.Context, tripID uuid.UUID) ([]domain.TripInvitation, error)
+	// FindPendingByEmail finds the pending invitation of the trip for the email, ignoring case
+	FindPendingByEmail(ctx context.Context, tripID uuid.UUID, email string) (*domain.TripInvitation, error)
+	// UpdateToken replaces the token of a pending invitation when it is sent again
+	UpdateToken(ctx context.Context, invitationID uuid.UUID, tokenHash string, expiresAt, sentAt time.Time) error
+	// Accept marks the invitation as accepted by the user, adds the user as a collaborator unless they already are one
+	// and links the user to the first unlinked member with memberName, all in a single transaction.
+	// It returns gorm.ErrRecordNotFound if the invitation was accepted in the meantime.
+	Accept(ctx context.Context, invitation *domain.TripInvitation, userID uuid.UUID, acceptedAt time.Time) error
+	// Delete removes a pending invitation. It returns false if the trip has no such pending invitation.
+	Delete(ctx context.Context, tripID, invitationID uuid.UUID) (bool, error)
+}
+
+type tripInvitationRepository struct {
+	db *gorm.DB
+}
+
+func NewTripInvitationRepository(db *gorm.DB) TripInvitationRepository {
+	return &tripInvitationRepository{db}
+}
+
+func (r *tripInvitationRepository) Create(ctx context.Context, invitation *domain.TripInvitation) error {
+	if err := r.db.WithContext(ctx).Omit("Trip").Create(invitation).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *tripInvitationRepository) FindByID(ctx context.Context, tripID, invitationID uuid.UUID) (*domain.TripInvitation, error) {
+	var invitation domain.TripInvitation
+	if err := r.db.WithContext(ctx).Where("id = ? AND trip_id = ?", invitationID, tripID).First(&invitation).Error; err != nil {
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+func (r *tripInvitationRepository) FindByTokenHash(ctx context.Context, tokenHash string) (*domain.TripInvitation, error) {
+	var invitation domain.TripInvitation
+	if err := r.db.WithContext(ctx).Where("token_hash = ?", tokenHash).First(&invitation).Error; err != nil {
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+func (r *tripInvitationRepository) FindPendingByTripID(ctx context.Context, tripID uuid.UUID) ([]domain.TripInvitation, error) {
+	var invitations []domain.TripInvitation
+	if err := r.db.WithContext(ctx).
+		Where("trip_id = ? AND accepted_at IS NULL", tripID).
+		Order("created_at").
+		Find(&invitations).Error; err != nil {
+		return nil, err
+	}
+	return invitations, nil
+}
+
+func (r *tripInvitationRepository) FindPendingByEmail(ctx context.Context, tripID uuid.UUID, email string) (*domain.TripInvitation, error) {
+	var invitation domain.TripInvitation
+	if err := r.db.WithContext(ctx).
+		Where("trip_id = ? AND lower(email) = lower(?) AND accepted_at IS NULL", tripID, email).
+		First(&invitation).Error; err != nil {
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+func (r *tripInvitationRepository) UpdateToken(ctx context.Context, invitationID uuid.UUID, tokenHash string, expiresAt, sentAt time.Time) error {
+	return r.db.WithContext(ctx).Model(&domain.TripInvitation{}).
+		Where("id = ? AND accepted_at IS NULL", invitationID).
+		Updates(map[string]interface{}{"token_hash": tokenHash, "expires_at": expiresAt, "sent_at": sentAt}).Error
+}
+
+func (r *tripInvitationRepository) Accept(ctx context.Context, invitation *domain.TripInvitation, userID uuid.UUID, acceptedAt time.Time) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// the condition on accepted_at makes a token usable only once even under concurrent requests
+		result := tx.Model(&domain.TripInvitation{}).
+			Where("id = ? AND accepted_at IS NULL", invitation.ID).
+			Updates(map[string]interface{}{"accepted_at": acceptedAt, "accepted_by": userID})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected != 1 {
+			return gorm.ErrRecordNotFound
+		}
+
+		// an existing collaborator keeps their role, so accepting never downgrades anyone
+		collaborator := &domain.TripCollaborator{
+			TripID:    invitation.TripID,
+			UserID:    userID,
+			Role:      invitation.Role,
+			CreatedAt: acceptedAt,
+			UpdatedAt: acceptedAt,
+		}
+		if err := tx.Omit("User").Clauses(clause.OnConflict{DoNothing: true}).Create(collaborator).Error; err != nil {
+			return err
+		}
+
+		if invitation.MemberName == nil {
+			return nil
+		}
+		// a user stands for at most one member of a trip
+		var linked int64
+		if err := tx.Model(&domain.Member{}).Where("trip_id = ? AND user_id = ?", invitation.TripID, userID).Count(&linked).Error; err != nil {
+			return err
+		}
+		if linked > 0 {
+			return nil
+		}
+		var member domain.Member
+		err := tx.Where("trip_id = ? AND name = ? AND user_id IS NULL", invitation.TripID, *invitation.MemberName).
+			Order("id").
+			First(&member).Error
+		if err != nil {
+			// the member may have been renamed or removed since the invitation was sent
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil
+			}
+			return err
+		}
+		return tx.Model(&domain.Member{}).Where("id = ?", member.ID).Update("user_id", userID).Error
+	})
+}
+
+func (r *tripInvitationRepository) Delete(ctx context.Context, tripID, invitationID uuid.UUID) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Where("id = ? AND trip_id = ? AND accepted_at IS NULL", invitationID, tripID).
+		Delete(&domain.TripInvitation{})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
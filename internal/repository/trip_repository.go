@@ -12,10 +12,20 @@ import (
 type TripRepository interface {
 	Create(ctx context.Context, trip *domain.Trip) error
 	FindByUserID(ctx context.Context, userID uuid.UUID) ([]domain.Trip, error)
+	// FindAccessibleByUserID loads the trips the user owns or collaborates on, with the user's own collaborator entry
+	FindAccessibleByUserID(ctx context.Context, userID uuid.UUID) ([]domain.Trip, error)
+	// FindAccessibleWithSchedulesByUserID loads the trips the user owns or collaborates on, with their schedules
+	FindAccessibleWithSchedulesByUserID(ctx context.Context, userID uuid.UUID) ([]domain.Trip, error)
+	// FindByMemberUserID loads the trips where the user is linked to a member, with the user's own collaborator entry
+	FindByMemberUserID(ctx context.Context, userID uuid.UUID) ([]domain.Trip, error)
 	FindByID(ctx context.Context, tripID uuid.UUID) (*domain.Trip, error)
+	// Update saves the title and dates of the trip. Unless trip.Members is nil, the members are synced by id in the
+	// same transaction: members with an id are renamed, members without one are added and the others are removed.
 	Update(ctx context.Context, trip *domain.Trip) error
 	FindWithSchedulesByID(ctx context.Context, tripID uuid.UUID) (*domain.Trip, error)
 	Delete(ctx context.Context, tripID uuid.UUID) error
+	// FindAllWithDetailsByUserID loads every trip of the user with members, schedules and share token
+	FindAllWithDetailsByUserID(ctx context.Context, userID uuid.UUID) ([]domain.Trip, error)
 }
 
 type tripRepository struct {
@@ -41,6 +51,47 @@ func (r *tripRepository) FindByUserID(ctx context.Context, userID uuid.UUID) ([]
 	return trips, nil
 }
 
+func (r *tripRepository) FindAccessibleByUserID(ctx context.Context, userID uuid.UUID) ([]domain.Trip, error) {
+	var trips []domain.Trip
+	collaborations := r.db.Model(&domain.TripCollaborator{}).Select("trip_id").Where("user_id = ?", userID)
+	if err := r.db.WithContext(ctx).
+		Preload("Members").
+		Preload("Collaborators", "user_id = ?", userID).
+		Where("user_id = ? OR id IN (?)", userID, collaborations).
+		Order("start_date").
+		Find(&trips).Error; err != nil {
+		return nil, err
+	}
+	return trips, nil
+}
+
+func (r *tripRepository) FindAccessibleWithSchedulesByUserID(ctx context.Context, userID uuid.UUID) ([]domain.Trip, error) {
+	var trips []domain.Trip
+	collaborations := r.db.Model(&domain.TripCollaborator{}).Select("trip_id").Where("user_id = ?", userID)
+	if err := r.db.WithContext(ctx).
+		Preload("Schedules", func(db *gorm.DB) *gorm.DB { return db.Order("start_date_time") }).
+		Where("user_id = ? OR id IN (?)", userID, collaborations).
+		Order("start_date").
+		Find(&trips).Error; err != nil {
+		return nil, err
+	}
+	return trips, nil
+}
+
+func (r *tripRepository) FindByMemberUserID(ctx context.Context, userID uuid.UUID) ([]domain.Trip, error) {
+	var trips []domain.Trip
+	memberships := r.db.Model(&domain.Member{}).Select("trip_id").Where("user_id = ?", userID)
+	if err := r.db.WithContext(ctx).
+		Preload("Members").
+		Preload("Collaborators", "user_id = ?", userID).
+		Where("id IN (?)", memberships).
+		Order("start_date").
+		Find(&trips).Error; err != nil {
+		return nil, err
+	}
+	return trips, nil
+}
+
 func (r *tripRepository) FindByID(ctx context.Context, tripID uuid.UUID) (*domain.Trip, error) {
 	var trip domain.Trip
 	if err := r.db.WithContext(ctx).Preload("Members").First(&trip, "id = ?", tripID).Error; err != nil {
@@ -50,10 +101,59 @@ func (r *tripRepository) FindByID(ctx context.Context, tripID uuid.UUID) (*domai
 }
 
 func (r *tripRepository) Update(ctx context.Context, trip *domain.Trip) error {
-	if err := r.db.WithContext(ctx).Save(trip).Error; err != nil {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return updateTrip(tx, trip)
+	})
+}
+
+// updateTrip saves the trip and syncs its members by id, keeping the ids (and linked users) of the members that stay.
+// It returns gorm.ErrRecordNotFound if a member id does not belong to the trip.
+func updateTrip(tx *gorm.DB, trip *domain.Trip) error {
+	if err := tx.Model(&domain.Trip{}).Where("id = ?", trip.ID).Updates(map[string]interface{}{
+		"title":      trip.Title,
+		"start_date": trip.StartDate,
+		"end_date":   trip.EndDate,
+	}).Error; err != nil {
 		return err
 	}
-	return nil
+	if trip.Members == nil {
+		return nil
+	}
+
+	var keep []uuid.UUID
+	for _, m := range trip.Members {
+		if m.ID != uuid.Nil {
+			keep = append(keep, m.ID)
+		}
+	}
+	removed := tx.Where("trip_id = ?", trip.ID)
+	if len(keep) > 0 {
+		removed = removed.Where("id NOT IN ?", keep)
+	}
+	if err := removed.Delete(&domain.Member{}).Error; err != nil {
+		return err
+	}
+
+	for i := range trip.Members {
+		member := &trip.Members[i]
+		member.TripID = trip.ID
+		if member.ID == uuid.Nil {
+			if err := tx.Omit("User").Create(member).Error; err != nil {
+				return err
+			}
+			continue
+		}
+		result := tx.Model(&domain.Member{}).Where("id = ? AND trip_id = ?", member.ID, trip.ID).Update("name", member.Name)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected != 1 {
+			return gorm.ErrRecordNotFound
+		}
+	}
+
+	// reload so the members carry their linked users
+	return tx.Where("trip_id = ?", trip.ID).Order("id").Find(&trip.Members).Error
 }
 
 func (r *tripRepository) FindWithSchedulesByID(ctx context.Context, tripID uuid.UUID) (*domain.Trip, error) {
@@ -70,3 +170,17 @@ func (r *tripRepository) Delete(ctx context.Context, tripID uuid.UUID) error {
 	}
 	return nil
 }
+
+func (r *tripRepository) FindAllWithDetailsByUserID(ctx context.Context, userID uuid.UUID) ([]domain.Trip, error) {
+	var trips []domain.Trip
+	if err := r.db.WithContext(ctx).
+		Preload("Members").
+		Preload("Schedules").
+		Preload("ShareTokens", func(db *gorm.DB) *gorm.DB { return db.Order("created_at") }).
+		Where("user_id = ?", userID).
+		Order("start_date").
+		Find(&trips).Error; err != nil {
+		return nil, err
+	}
+	return trips, nil
+}
@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"trip_app/internal/domain"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type UserIdentityRepository interface {
+	Create(ctx context.Context, identity *domain.UserIdentity) error
+	FindByProviderSubject(ctx context.Context, provider, subject string) (*domain.UserIdentity, error)
+	FindByUserID(ctx context.Context, userID uuid.UUID) ([]domain.UserIdentity, error)
+	// UpdateLogin records a login and the email the provider currently reports
+	UpdateLogin(ctx context.Context, identityID uuid.UUID, email string, loggedInAt time.Time) error
+}
+
+type userIdentityRepository struct {
+	db *gorm.DB
+}
+
+func NewUserIdentityRepository(db *gorm.DB) UserIdentityRepository {
+	return &userIdentityRepository{db}
+}
+
+func (r *userIdentityRepository) Create(ctx context.Context, identity *domain.UserIdentity) error {
+	if err := r.db.WithContext(ctx).Create(identity).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *userIdentityRepository) FindByProviderSubject(ctx context.Context, provider, subject string) (*domain.UserIdentity, error) {
+	var identity domain.UserIdentity
+	if err := r.db.WithContext(ctx).Where("provider = ? AND subject = ?", provider, subject).First(&identity).Error; err != nil {
+		return nil, err
+	}
+	return &identity, nil
+}
+
+func (r *userIdentityRepository) FindByUserID(ctx context.Context, userID uuid.UUID) ([]domain.UserIdentity, error) {
+	var identities []domain.UserIdentity
+	if err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("created_at").Find(&identities).Error; err != nil {
+		return nil, err
+	}
+	return identities, nil
+}
+
+func (r *userIdentityRepository) UpdateLogin(ctx context.Context, identityID uuid.UUID, email string, loggedInAt time.Time) error {
+	return r.db.WithContext(ctx).Model(&domain.UserIdentity{}).
+		Where("id = ?", identityID).
+		Updates(map[string]interface{}{"email": email, "last_login_at": loggedInAt}).Error
+}
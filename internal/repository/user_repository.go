@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 	"trip_app/internal/domain"
@@ -13,7 +14,12 @@ type UserRepository interface {
 	FindByEmail(ctx context.Context, email string) (*domain.User, error)
 	FindByID(ctx context.Context, id uuid.UUID) (*domain.User, error)
 	FindByVerificationToken(ctx context.Context, tokenHash string) (*domain.User, error)
+	FindByPasswordResetToken(ctx context.Context, tokenHash string) (*domain.User, error)
+	FindByEmailChangeToken(ctx context.Context, tokenHash string) (*domain.User, error)
 	Update(ctx context.Context, user *domain.User) error
+	// FindDeletionDue returns the users whose scheduled deletion time is at or before now
+	FindDeletionDue(ctx context.Context, now time.Time) ([]domain.User, error)
+	Delete(ctx context.Context, id uuid.UUID) error
 }
 
 type userRepository struct {
@@ -55,9 +61,42 @@ func (r *userRepository) FindByVerificationToken(ctx context.Context, tokenHash
 	return &user, nil
 }
 
+func (r *userRepository) FindByPasswordResetToken(ctx context.Context, tokenHash string) (*domain.User, error) {
+	var user domain.User
+	if err := r.db.WithContext(ctx).Where("password_reset_token_hash = ?", tokenHash).First(&user).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (r *userRepository) FindByEmailChangeToken(ctx context.Context, tokenHash string) (*domain.User, error) {
+	var user domain.User
+	if err := r.db.WithContext(ctx).Where("email_change_token_hash = ?", tokenHash).First(&user).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
 func (r *userRepository) Update(ctx context.Context, user *domain.User) error {
 	if err := r.db.WithContext(ctx).Save(user).Error; err != nil {
 		return err
 	}
 	return nil
 }
+
+func (r *userRepository) FindDeletionDue(ctx context.Context, now time.Time) ([]domain.User, error) {
+	var users []domain.User
+	if err := r.db.WithContext(ctx).Where("deletion_scheduled_at IS NOT NULL AND deletion_scheduled_at <= ?", now).Find(&users).Error; err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// Delete removes the user row. trips, schedules, members, share tokens, sessions and
+// recovery codes are removed by the ON DELETE CASCADE foreign keys.
+func (r *userRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	if err := r.db.WithContext(ctx).Delete(&domain.User{}, "id = ?", id).Error; err != nil {
+		return err
+	}
+	return nil
+}
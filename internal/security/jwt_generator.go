@@ -1,46 +1,197 @@
 package security
 
 import (
+	"errors"
 	"time"
 
 	"trip_app/internal/domain"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 )
 
+// AccessTokenTTL はアクセストークンの有効期間（期限切れ後はリフレッシュトークンで再発行）
+const AccessTokenTTL = 15 * time.Minute
+
+// TwoFactorChallengeTTL は2段階認証のチャレンジトークンの有効期間
+const TwoFactorChallengeTTL = 5 * time.Minute
+
+// twoFactorChallengeAudience はチャレンジトークンをアクセストークンと区別するためのaud
+const twoFactorChallengeAudience = "two_factor_challenge"
+
+// ErrInvalidChallengeToken はチャレンジトークンが不正・期限切れの場合のエラー
+var ErrInvalidChallengeToken = errors.New("invalid or expired challenge token")
+
+// ShareGrantTTL はパスフレーズ付き共有リンクのアクセス許可トークンの有効期間
+const ShareGrantTTL = 30 * time.Minute
+
+// shareGrantAudience はアクセス許可トークンを他のトークンと区別するためのaud
+const shareGrantAudience = "share_grant"
+
+// ErrInvalidShareGrant はアクセス許可トークンが不正・期限切れの場合のエラー
+var ErrInvalidShareGrant = errors.New("invalid or expired share grant")
+
+// ShareVisitTTL は共有リンクの訪問トークンの有効期間（訪問中の詳細やスケジュールの取得に使う）
+const ShareVisitTTL = 30 * time.Minute
+
+// shareVisitAudience は訪問トークンを他のトークンと区別するためのaud
+const shareVisitAudience = "share_visit"
+
+// ErrInvalidShareVisit は訪問トークンが不正・期限切れの場合のエラー
+var ErrInvalidShareVisit = errors.New("invalid or expired share visit")
+
 type JwtCustomClaims struct {
 	UserID string `json:"user_id"`
 	jwt.RegisteredClaims
 }
 
 type AuthTokenGenerator interface {
-	GenerateAccessToken(user *domain.User) (string, error)
+	GenerateAccessToken(user *domain.User, sessionID uuid.UUID) (string, time.Time, error)
+	GenerateTwoFactorChallengeToken(user *domain.User) (string, time.Time, error)
+	ParseTwoFactorChallengeToken(tokenString string) (uuid.UUID, error)
+	// GenerateShareGrantToken はパスフレーズを確認した共有リンクにだけ使えるアクセス許可トークンを発行する
+	GenerateShareGrantToken(shareLinkID uuid.UUID) (string, time.Time, error)
+	// ParseShareGrantToken はアクセス許可トークンを検証し、対象の共有リンクのIDを返す
+	ParseShareGrantToken(tokenString string) (uuid.UUID, error)
+	// GenerateShareVisitToken は利用回数に数えた訪問の間だけ共有リンクに使える訪問トークンを発行する
+	GenerateShareVisitToken(shareLinkID uuid.UUID) (string, time.Time, error)
+	// ParseShareVisitToken は訪問トークンを検証し、対象の共有リンクのIDを返す
+	ParseShareVisitToken(tokenString string) (uuid.UUID, error)
 }
 
 type jwtGenerator struct {
-	jwtSecret string
+	keys KeySet
 }
 
-func NewAuthTokenGenerator(jwtSecret string) AuthTokenGenerator {
-	return &jwtGenerator{jwtSecret: jwtSecret}
+// NewAuthTokenGenerator は鍵セットの署名鍵でトークンを発行するジェネレーターを作成
+func NewAuthTokenGenerator(keys KeySet) AuthTokenGenerator {
+	return &jwtGenerator{keys: keys}
 }
 
-func (g *jwtGenerator) GenerateAccessToken(user *domain.User) (string, error) {
+func (g *jwtGenerator) GenerateAccessToken(user *domain.User, sessionID uuid.UUID) (string, time.Time, error) {
+	expiresAt := time.Now().Add(AccessTokenTTL)
+
 	// jwtに埋め込むデータ(クレーム)を作成
+	// jtiにはセッションIDを設定し、サーバー側で失効できるようにする
 	claims := &JwtCustomClaims{
 		UserID: user.ID.String(),
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour * 72)),
+			ID:        sessionID.String(),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	// ヘッダーのkidで検証側が鍵を選べるようにする
+	tokenString, err := g.keys.Sign(claims)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	return tokenString, expiresAt, nil
+}
+
+func (g *jwtGenerator) GenerateTwoFactorChallengeToken(user *domain.User) (string, time.Time, error) {
+	expiresAt := time.Now().Add(TwoFactorChallengeTTL)
+
+	// パスワード認証済みであることだけを示す短命なトークン
+	// audで用途を限定し、jti（セッション）を持たないためアクセストークンとしては使用できない
+	claims := &JwtCustomClaims{
+		UserID: user.ID.String(),
+		RegisteredClaims: jwt.RegisteredClaims{
+			Audience:  jwt.ClaimStrings{twoFactorChallengeAudience},
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+		},
+	}
+
+	tokenString, err := g.keys.Sign(claims)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	return tokenString, expiresAt, nil
+}
+
+func (g *jwtGenerator) ParseTwoFactorChallengeToken(tokenString string) (uuid.UUID, error) {
+	claims := &JwtCustomClaims{}
+	_, err := jwt.ParseWithClaims(tokenString, claims, g.keys.Keyfunc,
+		jwt.WithValidMethods(g.keys.Algorithms()),
+		jwt.WithAudience(twoFactorChallengeAudience),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return uuid.Nil, ErrInvalidChallengeToken
+	}
+
+	userID, err := uuid.Parse(claims.UserID)
+	if err != nil {
+		return uuid.Nil, ErrInvalidChallengeToken
+	}
+
+	return userID, nil
+}
+
+func (g *jwtGenerator) GenerateShareGrantToken(shareLinkID uuid.UUID) (string, time.Time, error) {
+	return g.generateShareLinkToken(shareLinkID, shareGrantAudience, ShareGrantTTL)
+}
+
+func (g *jwtGenerator) ParseShareGrantToken(tokenString string) (uuid.UUID, error) {
+	shareLinkID, ok := g.parseShareLinkToken(tokenString, shareGrantAudience)
+	if !ok {
+		return uuid.Nil, ErrInvalidShareGrant
+	}
+	return shareLinkID, nil
+}
+
+func (g *jwtGenerator) GenerateShareVisitToken(shareLinkID uuid.UUID) (string, time.Time, error) {
+	return g.generateShareLinkToken(shareLinkID, shareVisitAudience, ShareVisitTTL)
+}
+
+func (g *jwtGenerator) ParseShareVisitToken(tokenString string) (uuid.UUID, error) {
+	shareLinkID, ok := g.parseShareLinkToken(tokenString, shareVisitAudience)
+	if !ok {
+		return uuid.Nil, ErrInvalidShareVisit
+	}
+	return shareLinkID, nil
+}
+
+// generateShareLinkToken は1つの共有リンクにだけ使えるトークンを発行する
+func (g *jwtGenerator) generateShareLinkToken(shareLinkID uuid.UUID, audience string, ttl time.Duration) (string, time.Time, error) {
+	expiresAt := time.Now().Add(ttl)
+
+	// subに共有リンクのIDを、audに用途を設定し、他の共有リンクや他の用途のトークンとしては使用できないようにする
+	claims := &jwt.RegisteredClaims{
+		Subject:   shareLinkID.String(),
+		Audience:  jwt.ClaimStrings{audience},
+		ExpiresAt: jwt.NewNumericDate(expiresAt),
+		IssuedAt:  jwt.NewNumericDate(time.Now()),
+	}
+
+	tokenString, err := g.keys.Sign(claims)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	return tokenString, expiresAt, nil
+}
+
+// parseShareLinkToken はaudが一致する共有リンクのトークンを検証し、共有リンクのIDを返す
+func (g *jwtGenerator) parseShareLinkToken(tokenString, audience string) (uuid.UUID, bool) {
+	claims := &jwt.RegisteredClaims{}
+	_, err := jwt.ParseWithClaims(tokenString, claims, g.keys.Keyfunc,
+		jwt.WithValidMethods(g.keys.Algorithms()),
+		jwt.WithAudience(audience),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return uuid.Nil, false
+	}
 
-	tokenString, err := token.SignedString([]byte(g.jwtSecret))
+	shareLinkID, err := uuid.Parse(claims.Subject)
 	if err != nil {
-		return "", err
+		return uuid.Nil, false
 	}
 
-	return tokenString, nil
+	return shareLinkID, true
 }
@@ -0,0 +1,250 @@
+package security
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// minRSAKeyBits はRS256で受け付けるRSA鍵の最小ビット長
+const minRSAKeyBits = 2048
+
+// ErrUnknownSigningKey はJWTのkidに対応する検証鍵が存在しない場合のエラー
+var ErrUnknownSigningKey = errors.New("unknown signing key")
+
+// Key はkidと鍵の組。PrivateKeyがnilの鍵は検証専用（ローテーションで退役した鍵）
+type Key struct {
+	ID         string
+	PrivateKey crypto.Signer
+	PublicKey  crypto.PublicKey
+}
+
+// JSONWebKey はJWKSで公開する公開鍵（RFC 7517）
+// RSA鍵はN・E、Ed25519鍵はCrv・Xを持つ
+type JSONWebKey struct {
+	Kty string
+	Kid string
+	Use string
+	Alg string
+	N   string
+	E   string
+	Crv string
+	X   string
+}
+
+// KeySet はJWTの署名鍵と検証鍵の集合
+// 署名には1つの鍵のみを使用し、検証はkidヘッダーで選ばれた鍵で行う
+type KeySet interface {
+	// Sign は署名鍵でクレームに署名し、ヘッダーにkidを設定する
+	Sign(claims jwt.Claims) (string, error)
+	// Keyfunc はjwtパッケージ用の検証鍵の解決関数。kidとアルゴリズムが一致しない場合は失敗する
+	Keyfunc(token *jwt.Token) (interface{}, error)
+	// Algorithms は検証を許可する署名アルゴリズムの一覧
+	Algorithms() []string
+	// SigningKeyID は現在の署名鍵のkid
+	SigningKeyID() string
+	// PublicJWKs は全ての検証鍵をkid順で返す
+	PublicJWKs() []JSONWebKey
+}
+
+type keyEntry struct {
+	id         string
+	method     jwt.SigningMethod
+	privateKey crypto.Signer
+	publicKey  crypto.PublicKey
+}
+
+type keySet struct {
+	signing *keyEntry
+	keys    map[string]*keyEntry
+	ids     []string
+}
+
+// NewKeySet は鍵の一覧から署名・検証用の鍵セットを作成
+// signingKeyIDが空の場合は、秘密鍵を持つ鍵のうちkidが辞書順で最大のものを署名に使用する
+func NewKeySet(keys []Key, signingKeyID string) (KeySet, error) {
+	ks := &keySet{keys: make(map[string]*keyEntry)}
+
+	for _, key := range keys {
+		if key.ID == "" {
+			return nil, errors.New("signing key without id")
+		}
+		if _, exists := ks.keys[key.ID]; exists {
+			return nil, fmt.Errorf("duplicate signing key id %q", key.ID)
+		}
+
+		publicKey := key.PublicKey
+		if key.PrivateKey != nil {
+			publicKey = key.PrivateKey.Public()
+		}
+		method, err := signingMethodFor(publicKey)
+		if err != nil {
+			return nil, fmt.Errorf("signing key %q: %w", key.ID, err)
+		}
+
+		ks.keys[key.ID] = &keyEntry{id: key.ID, method: method, privateKey: key.PrivateKey, publicKey: publicKey}
+		ks.ids = append(ks.ids, key.ID)
+	}
+	slices.Sort(ks.ids)
+
+	if signingKeyID == "" {
+		for _, id := range ks.ids {
+			if ks.keys[id].privateKey != nil {
+				signingKeyID = id
+			}
+		}
+		if signingKeyID == "" {
+			return nil, errors.New("no private key available for signing")
+		}
+	}
+
+	signing, ok := ks.keys[signingKeyID]
+	if !ok {
+		return nil, fmt.Errorf("signing key %q not found", signingKeyID)
+	}
+	if signing.privateKey == nil {
+		return nil, fmt.Errorf("signing key %q has no private key", signingKeyID)
+	}
+	ks.signing = signing
+
+	return ks, nil
+}
+
+// LoadKeySet はディレクトリ内の"<kid>.pem"ファイルから鍵セットを読み込む
+// 秘密鍵（PKCS#8またはPKCS#1）のファイルは署名・検証に、公開鍵（PKIX）のファイルは検証のみに使用する
+func LoadKeySet(dir, signingKeyID string) (KeySet, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil {
+		return nil, err
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no *.pem files in %s", dir)
+	}
+
+	keys := make([]Key, 0, len(paths))
+	for _, path := range paths {
+		key, err := loadKey(path)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		keys = append(keys, key)
+	}
+
+	return NewKeySet(keys, signingKeyID)
+}
+
+func loadKey(path string) (Key, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Key{}, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return Key{}, errors.New("no PEM block found")
+	}
+
+	key := Key{ID: strings.TrimSuffix(filepath.Base(path), ".pem")}
+
+	var parsed interface{}
+	switch block.Type {
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+	default:
+		return Key{}, fmt.Errorf("unsupported PEM block type %q", block.Type)
+	}
+	if err != nil {
+		return Key{}, err
+	}
+
+	if signer, ok := parsed.(crypto.Signer); ok {
+		key.PrivateKey = signer
+	} else {
+		key.PublicKey = parsed
+	}
+	return key, nil
+}
+
+// signingMethodFor は公開鍵の種類から署名アルゴリズムを決定する
+func signingMethodFor(publicKey crypto.PublicKey) (jwt.SigningMethod, error) {
+	switch pub := publicKey.(type) {
+	case *rsa.PublicKey:
+		if pub.N.BitLen() < minRSAKeyBits {
+			return nil, fmt.Errorf("RSA key must be at least %d bits", minRSAKeyBits)
+		}
+		return jwt.SigningMethodRS256, nil
+	case ed25519.PublicKey:
+		return jwt.SigningMethodEdDSA, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %T (use RSA or Ed25519)", publicKey)
+	}
+}
+
+func (ks *keySet) Sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(ks.signing.method, claims)
+	token.Header["kid"] = ks.signing.id
+	return token.SignedString(ks.signing.privateKey)
+}
+
+func (ks *keySet) Keyfunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	key, ok := ks.keys[kid]
+	if !ok {
+		return nil, ErrUnknownSigningKey
+	}
+	// 鍵の種類と異なるアルゴリズムを指定したトークン（alg混同攻撃）を拒否
+	if token.Method.Alg() != key.method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method %s for key %q", token.Method.Alg(), kid)
+	}
+	return key.publicKey, nil
+}
+
+func (ks *keySet) Algorithms() []string {
+	algs := []string{}
+	for _, id := range ks.ids {
+		alg := ks.keys[id].method.Alg()
+		if !slices.Contains(algs, alg) {
+			algs = append(algs, alg)
+		}
+	}
+	return algs
+}
+
+func (ks *keySet) SigningKeyID() string {
+	return ks.signing.id
+}
+
+func (ks *keySet) PublicJWKs() []JSONWebKey {
+	jwks := make([]JSONWebKey, 0, len(ks.ids))
+	for _, id := range ks.ids {
+		key := ks.keys[id]
+		jwk := JSONWebKey{Kid: key.id, Use: "sig", Alg: key.method.Alg()}
+		switch pub := key.publicKey.(type) {
+		case *rsa.PublicKey:
+			jwk.Kty = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.Kty = "OKP"
+			jwk.Crv = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(pub)
+		}
+		jwks = append(jwks, jwk)
+	}
+	return jwks
+}
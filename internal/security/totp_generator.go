@@ -0,0 +1,135 @@
+package security
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// RFC 6238 の既定値（Google Authenticator等の認証アプリと互換）
+	totpPeriod = 30
+	totpDigits = 6
+	// 端末の時刻ずれを考慮して前後1ステップまで許容
+	totpSkew = 1
+	// リカバリーコードの発行数
+	recoveryCodeCount = 10
+)
+
+var base32NoPadding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+type TOTPGenerator interface {
+	GenerateSecret() (string, error)
+	ProvisioningURI(secret, accountName string) string
+	GenerateCode(secret string, at time.Time) (string, error)
+	// Verify はコードが一致した時間ステップを返す（リプレイ検知に使用）
+	Verify(secret, code string, at time.Time) (step int64, ok bool)
+	GenerateRecoveryCodes() ([]string, error)
+	// NormalizeRecoveryCode は入力揺れ（大文字小文字・ハイフン・空白）を吸収する
+	NormalizeRecoveryCode(code string) string
+}
+
+type totpGenerator struct {
+	issuer string
+}
+
+func NewTOTPGenerator(issuer string) TOTPGenerator {
+	return &totpGenerator{issuer: issuer}
+}
+
+func (g *totpGenerator) GenerateSecret() (string, error) {
+	// RFC 4226 推奨の160bitの共有鍵
+	bytes := make([]byte, 20)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	return base32NoPadding.EncodeToString(bytes), nil
+}
+
+func (g *totpGenerator) ProvisioningURI(secret, accountName string) string {
+	// otpauth://totp/{issuer}:{account}?secret=...&issuer=...
+	label := url.PathEscape(g.issuer + ":" + accountName)
+	params := url.Values{}
+	params.Set("secret", secret)
+	params.Set("issuer", g.issuer)
+	params.Set("algorithm", "SHA1")
+	params.Set("digits", fmt.Sprint(totpDigits))
+	params.Set("period", fmt.Sprint(totpPeriod))
+	return "otpauth://totp/" + label + "?" + params.Encode()
+}
+
+func (g *totpGenerator) GenerateCode(secret string, at time.Time) (string, error) {
+	key, err := decodeSecret(secret)
+	if err != nil {
+		return "", err
+	}
+	return hotp(key, uint64(at.Unix()/totpPeriod)), nil
+}
+
+func (g *totpGenerator) Verify(secret, code string, at time.Time) (int64, bool) {
+	key, err := decodeSecret(secret)
+	if err != nil || len(code) != totpDigits {
+		return 0, false
+	}
+
+	current := at.Unix() / totpPeriod
+	for i := -totpSkew; i <= totpSkew; i++ {
+		step := current + int64(i)
+		if step < 0 {
+			continue
+		}
+		if subtle.ConstantTimeCompare([]byte(hotp(key, uint64(step))), []byte(code)) == 1 {
+			return step, true
+		}
+	}
+	return 0, false
+}
+
+func (g *totpGenerator) GenerateRecoveryCodes() ([]string, error) {
+	codes := make([]string, 0, recoveryCodeCount)
+	for i := 0; i < recoveryCodeCount; i++ {
+		bytes := make([]byte, 5)
+		if _, err := rand.Read(bytes); err != nil {
+			return nil, err
+		}
+		// 読みやすさのため XXXX-XXXX 形式で表示
+		encoded := base32NoPadding.EncodeToString(bytes)
+		codes = append(codes, encoded[:4]+"-"+encoded[4:])
+	}
+	return codes, nil
+}
+
+func (g *totpGenerator) NormalizeRecoveryCode(code string) string {
+	code = strings.ToUpper(code)
+	code = strings.ReplaceAll(code, "-", "")
+	return strings.ReplaceAll(code, " ", "")
+}
+
+// decodeSecret はBase32の共有鍵をデコード（パディング有無・小文字を許容）
+func decodeSecret(secret string) ([]byte, error) {
+	secret = strings.ToUpper(strings.TrimRight(secret, "="))
+	return base32NoPadding.DecodeString(secret)
+}
+
+// hotp はRFC 4226のHOTP値を計算
+func hotp(key []byte, counter uint64) string {
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], counter)
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+
+	// dynamic truncation
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	return fmt.Sprintf("%0*d", totpDigits, value%1000000)
+}
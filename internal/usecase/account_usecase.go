@@ -0,0 +1,143 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"trip_app/internal/domain"
+	"trip_app/internal/repository"
+	"trip_app/internal/security"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type AccountUsecase interface {
+	ExportPersonalData(ctx context.Context, userID uuid.UUID) (*PersonalDataExport, error)
+	// RequestDeletion schedules the account for deletion after the grace period.
+	// It returns the scheduled time, or nil when the account was deleted immediately.
+	RequestDeletion(ctx context.Context, userID uuid.UUID, password string) (*time.Time, error)
+	// PurgeScheduledDeletions deletes the accounts whose grace period has ended and returns how many were deleted
+	PurgeScheduledDeletions(ctx context.Context, now time.Time) (int, error)
+}
+
+// PersonalDataExport is everything stored about a user, as handed out by the data export
+type PersonalDataExport struct {
+	ExportedAt time.Time
+	User       *domain.User
+	// Trips are loaded with their members, schedules and share token
+	Trips []domain.Trip
+}
+
+// DefaultAccountDeletionGracePeriod is how long a deletion request can be cancelled by logging in
+const DefaultAccountDeletionGracePeriod = 30 * 24 * time.Hour
+
+// AccountUsecaseConfig holds the settings of the account usecase that can be changed per deployment
+type AccountUsecaseConfig struct {
+	// DeletionGracePeriod delays the deletion of an account. Zero deletes the account immediately.
+	DeletionGracePeriod time.Duration
+}
+
+type accountUsecase struct {
+	ur  repository.UserRepository
+	tr  repository.TripRepository
+	str repository.ShareTokenRepository
+	sr  repository.SessionRepository
+	pr  repository.PersonalAccessTokenRepository
+	cfr repository.CalendarFeedRepository
+	up  security.PasswordGenerator
+	cfg AccountUsecaseConfig
+}
+
+func NewAccountUsecase(ur repository.UserRepository, tr repository.TripRepository, str repository.ShareTokenRepository, sr repository.SessionRepository, pr repository.PersonalAccessTokenRepository, cfr repository.CalendarFeedRepository, up security.PasswordGenerator, cfg AccountUsecaseConfig) AccountUsecase {
+	return &accountUsecase{ur, tr, str, sr, pr, cfr, up, cfg}
+}
+
+func (au *accountUsecase) ExportPersonalData(ctx context.Context, userID uuid.UUID) (*PersonalDataExport, error) {
+	user, err := au.ur.FindByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	trips, err := au.tr.FindAllWithDetailsByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PersonalDataExport{
+		ExportedAt: time.Now(),
+		User:       user,
+		Trips:      trips,
+	}, nil
+}
+
+func (au *accountUsecase) RequestDeletion(ctx context.Context, userID uuid.UUID, password string) (*time.Time, error) {
+	user, err := au.ur.FindByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	if err := au.up.ComparePassword(user.PasswordHash, password); err != nil {
+		return nil, ErrIncorrectCurrentPassword
+	}
+
+	// shared links and all credentials stop working right away, even while the deletion can still be cancelled
+	if err := au.str.DeleteByUserID(ctx, user.ID); err != nil {
+		return nil, err
+	}
+	if err := au.sr.RevokeAllByUserID(ctx, user.ID, time.Now()); err != nil {
+		return nil, err
+	}
+	if err := au.pr.DeleteAllByUserID(ctx, user.ID); err != nil {
+		return nil, err
+	}
+	if _, err := au.cfr.DeleteByUserID(ctx, user.ID); err != nil {
+		return nil, err
+	}
+
+	if au.cfg.DeletionGracePeriod <= 0 {
+		if err := au.ur.Delete(ctx, user.ID); err != nil {
+			return nil, err
+		}
+		return nil, nil
+	}
+
+	// repeating the request keeps the original schedule
+	if user.DeletionScheduledAt == nil {
+		scheduledAt := time.Now().Add(au.cfg.DeletionGracePeriod)
+		user.DeletionScheduledAt = &scheduledAt
+		if err := au.ur.Update(ctx, user); err != nil {
+			return nil, err
+		}
+	}
+
+	return user.DeletionScheduledAt, nil
+}
+
+func (au *accountUsecase) PurgeScheduledDeletions(ctx context.Context, now time.Time) (int, error) {
+	users, err := au.ur.FindDeletionDue(ctx, now)
+	if err != nil {
+		return 0, err
+	}
+
+	deleted := 0
+	for _, user := range users {
+		if err := au.ur.Delete(ctx, user.ID); err != nil {
+			return deleted, err
+		}
+		deleted++
+	}
+
+	if deleted > 0 {
+		log.Printf("deleted %d account(s) whose deletion grace period has ended", deleted)
+	}
+	return deleted, nil
+}
@@ -0,0 +1,96 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"trip_app/internal/domain"
+	"trip_app/internal/repository"
+	"trip_app/internal/security"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type CalendarFeedUsecase interface {
+	// Create issues the user's feed token, replacing the previous one. The raw token is returned only once and never stored.
+	Create(ctx context.Context, userID uuid.UUID) (*domain.CalendarFeed, string, error)
+	Get(ctx context.Context, userID uuid.UUID) (*domain.CalendarFeed, error)
+	Revoke(ctx context.Context, userID uuid.UUID) error
+	// GetFeedTrips resolves a raw feed token and returns every trip the user can view, with its schedules
+	GetFeedTrips(ctx context.Context, rawToken string) ([]domain.Trip, error)
+}
+
+var ErrCalendarFeedNotFound = errors.New("calendar feed not found")
+
+type calendarFeedUsecase struct {
+	cfr repository.CalendarFeedRepository
+	tr  repository.TripRepository
+	us  security.TokenGenerator
+}
+
+func NewCalendarFeedUsecase(cfr repository.CalendarFeedRepository, tr repository.TripRepository, us security.TokenGenerator) CalendarFeedUsecase {
+	return &calendarFeedUsecase{cfr, tr, us}
+}
+
+func (cu *calendarFeedUsecase) Create(ctx context.Context, userID uuid.UUID) (*domain.CalendarFeed, string, error) {
+	rawToken, hashedToken, err := cu.us.GenerateToken()
+	if err != nil {
+		return nil, "", err
+	}
+
+	feed := &domain.CalendarFeed{
+		UserID:    userID,
+		TokenHash: hashedToken,
+		CreatedAt: time.Now(),
+	}
+	if err := cu.cfr.Save(ctx, feed); err != nil {
+		return nil, "", err
+	}
+
+	return feed, rawToken, nil
+}
+
+func (cu *calendarFeedUsecase) Get(ctx context.Context, userID uuid.UUID) (*domain.CalendarFeed, error) {
+	feed, err := cu.cfr.FindByUserID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCalendarFeedNotFound
+		}
+		return nil, err
+	}
+	return feed, nil
+}
+
+func (cu *calendarFeedUsecase) Revoke(ctx context.Context, userID uuid.UUID) error {
+	deleted, err := cu.cfr.DeleteByUserID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrCalendarFeedNotFound
+	}
+	return nil
+}
+
+func (cu *calendarFeedUsecase) GetFeedTrips(ctx context.Context, rawToken string) ([]domain.Trip, error) {
+	feed, err := cu.cfr.FindByTokenHash(ctx, cu.us.HashToken(rawToken))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCalendarFeedNotFound
+		}
+		return nil, err
+	}
+
+	trips, err := cu.tr.FindAccessibleWithSchedulesByUserID(ctx, feed.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cu.cfr.UpdateLastUsedAt(ctx, feed.UserID, time.Now()); err != nil {
+		return nil, err
+	}
+
+	return trips, nil
+}
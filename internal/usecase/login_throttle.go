@@ -0,0 +1,142 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strings"
+	"time"
+
+	"trip_app/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+// LoginThrottlePolicy controls how failed logins and wrong share link passphrases are rate limited.
+// A zero MaxAccountFailures, MaxIPFailures or MaxShareUnlockFailures disables tracking for that kind of key.
+type LoginThrottlePolicy struct {
+	// MaxAccountFailures is the number of failures per email before the account is locked
+	MaxAccountFailures int
+	// MaxIPFailures is the number of failures per client IP before the IP is locked
+	MaxIPFailures int
+	// MaxShareUnlockFailures is the number of wrong passphrases per share link before the link is locked.
+	// It is counted per link, so guessing the passphrase from many IPs does not help.
+	MaxShareUnlockFailures int
+	// BaseLockout is the first lockout duration; it doubles with every further failure
+	BaseLockout time.Duration
+	// MaxLockout caps the exponential backoff
+	MaxLockout time.Duration
+	// FailureWindow is how long a failure is remembered
+	FailureWindow time.Duration
+}
+
+// DefaultLoginThrottlePolicy returns the policy used when nothing is configured
+func DefaultLoginThrottlePolicy() LoginThrottlePolicy {
+	return LoginThrottlePolicy{
+		MaxAccountFailures:     5,
+		MaxIPFailures:          20,
+		MaxShareUnlockFailures: 5,
+		BaseLockout:            30 * time.Second,
+		MaxLockout:             15 * time.Minute,
+		FailureWindow:          time.Hour,
+	}
+}
+
+var ErrTooManyLoginAttempts = errors.New("too many failed login attempts. please try again later")
+
+// LoginThrottledError is returned while the account or the client IP is locked out.
+// RetryAfter tells the client how long to wait.
+type LoginThrottledError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LoginThrottledError) Error() string {
+	return ErrTooManyLoginAttempts.Error()
+}
+
+func (e *LoginThrottledError) Unwrap() error {
+	return ErrTooManyLoginAttempts
+}
+
+// loginThrottleKey identifies one failure counter and its limit
+type loginThrottleKey struct {
+	key         string
+	maxFailures int
+}
+
+func (uu *userUsecase) loginThrottleKeys(email, clientIP string) []loginThrottleKey {
+	policy := uu.cfg.LoginThrottle
+	var keys []loginThrottleKey
+	if policy.MaxAccountFailures > 0 {
+		keys = append(keys, loginThrottleKey{"email:" + strings.ToLower(email), policy.MaxAccountFailures})
+	}
+	if policy.MaxIPFailures > 0 && clientIP != "" {
+		keys = append(keys, loginThrottleKey{"ip:" + clientIP, policy.MaxIPFailures})
+	}
+	return keys
+}
+
+// checkLoginThrottle rejects the attempt before any password hash is compared
+func (uu *userUsecase) checkLoginThrottle(ctx context.Context, email, clientIP string) error {
+	now := time.Now()
+	var retryAfter time.Duration
+	for _, k := range uu.loginThrottleKeys(email, clientIP) {
+		attempt, err := uu.lar.FindByKey(ctx, k.key)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				continue
+			}
+			return err
+		}
+		if attempt.LockedUntil != nil && now.Before(*attempt.LockedUntil) {
+			if wait := attempt.LockedUntil.Sub(now); wait > retryAfter {
+				retryAfter = wait
+			}
+		}
+	}
+
+	if retryAfter > 0 {
+		return &LoginThrottledError{RetryAfter: retryAfter}
+	}
+	return nil
+}
+
+// recordLoginFailure counts a failure for the account and the client IP
+func (uu *userUsecase) recordLoginFailure(ctx context.Context, email, clientIP string) error {
+	now := time.Now()
+	for _, k := range uu.loginThrottleKeys(email, clientIP) {
+		if err := recordThrottledFailure(ctx, uu.lar, k.key, k.maxFailures, uu.cfg.LoginThrottle, now); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// recordThrottledFailure counts a failure for the key and locks it once it exceeds maxFailures,
+// doubling the lockout for every further failure within the window of the policy
+func recordThrottledFailure(ctx context.Context, lar repository.LoginAttemptRepository, key string, maxFailures int, policy LoginThrottlePolicy, now time.Time) error {
+	attempt, err := lar.RecordFailure(ctx, key, now, now.Add(-policy.FailureWindow))
+	if err != nil {
+		return err
+	}
+	if attempt.FailureCount < maxFailures {
+		return nil
+	}
+
+	exponent := float64(attempt.FailureCount - maxFailures)
+	lockout := time.Duration(float64(policy.BaseLockout) * math.Pow(2, exponent))
+	if lockout <= 0 || lockout > policy.MaxLockout {
+		lockout = policy.MaxLockout
+	}
+	return lar.Lock(ctx, key, now.Add(lockout))
+}
+
+// resetLoginThrottle clears the account counter after a successful login.
+// The IP counter is left alone so that an attacker cannot clear it by
+// interleaving logins to an account they own.
+func (uu *userUsecase) resetLoginThrottle(ctx context.Context, email string) error {
+	if uu.cfg.LoginThrottle.MaxAccountFailures <= 0 {
+		return nil
+	}
+	return uu.lar.Reset(ctx, "email:"+strings.ToLower(email))
+}
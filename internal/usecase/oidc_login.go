@@ -0,0 +1,201 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"trip_app/internal/domain"
+	"trip_app/internal/infrastructure/oidc"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// oidcAuthRequestTTL is how long the user has to finish the login at the identity provider
+const oidcAuthRequestTTL = 10 * time.Minute
+
+var ErrOIDCProviderNotFound = errors.New("unknown identity provider")
+var ErrOIDCProviderUnavailable = errors.New("identity provider is unavailable")
+var ErrInvalidOIDCState = errors.New("invalid or expired login state")
+var ErrOIDCAuthenticationFailed = errors.New("authentication with the identity provider failed")
+var ErrOIDCEmailNotVerified = errors.New("the identity provider did not confirm the email address")
+
+func (uu *userUsecase) StartOIDCLogin(ctx context.Context, providerName string) (string, error) {
+	provider, ok := uu.op[providerName]
+	if !ok {
+		return "", ErrOIDCProviderNotFound
+	}
+
+	// abandoned logins are cleaned up whenever a new one starts
+	if err := uu.oar.DeleteExpired(ctx, time.Now()); err != nil {
+		return "", err
+	}
+
+	state, stateHash, err := uu.us.GenerateToken()
+	if err != nil {
+		return "", err
+	}
+	nonce, _, err := uu.us.GenerateToken()
+	if err != nil {
+		return "", err
+	}
+	codeVerifier, _, err := uu.us.GenerateToken()
+	if err != nil {
+		return "", err
+	}
+	// PKCE verifiers may only use unreserved characters, so drop the base64 padding
+	codeVerifier = strings.TrimRight(codeVerifier, "=")
+
+	now := time.Now()
+	request := &domain.OIDCAuthRequest{
+		StateHash:    stateHash,
+		Provider:     providerName,
+		Nonce:        nonce,
+		CodeVerifier: codeVerifier,
+		ExpiresAt:    now.Add(oidcAuthRequestTTL),
+		CreatedAt:    now,
+	}
+	if err := uu.oar.Create(ctx, request); err != nil {
+		return "", err
+	}
+
+	authURL, err := provider.AuthCodeURL(ctx, state, nonce, oidc.CodeChallenge(codeVerifier))
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrOIDCProviderUnavailable, err)
+	}
+	return authURL, nil
+}
+
+func (uu *userUsecase) CompleteOIDCLogin(ctx context.Context, providerName, state, code string) (*domain.User, *AuthTokens, error) {
+	provider, ok := uu.op[providerName]
+	if !ok {
+		return nil, nil, ErrOIDCProviderNotFound
+	}
+
+	// a state can be used only once, whether the login succeeds or not
+	request, err := uu.oar.Consume(ctx, uu.us.HashToken(state))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, ErrInvalidOIDCState
+		}
+		return nil, nil, err
+	}
+	if request.Provider != providerName || !time.Now().Before(request.ExpiresAt) {
+		return nil, nil, ErrInvalidOIDCState
+	}
+
+	claims, err := provider.Exchange(ctx, code, request.CodeVerifier, request.Nonce)
+	if err != nil {
+		log.Printf("oidc login with %s failed: %v", providerName, err)
+		return nil, nil, ErrOIDCAuthenticationFailed
+	}
+
+	user, err := uu.findOrLinkOIDCUser(ctx, providerName, claims)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if !user.IsActive {
+		return nil, nil, ErrUserNotActive
+	}
+
+	// the provider replaces the password, not the second factor
+	if user.TwoFactorEnabled {
+		challengeToken, expiresAt, err := uu.atg.GenerateTwoFactorChallengeToken(user)
+		if err != nil {
+			return nil, nil, err
+		}
+		return nil, nil, &TwoFactorChallengeError{ChallengeToken: challengeToken, ExpiresAt: expiresAt}
+	}
+
+	tokens, err := uu.startSession(ctx, user)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return user, tokens, nil
+}
+
+// findOrLinkOIDCUser resolves the user of an external account. An unknown account is linked to
+// the user with the same email, or to a new user, but only if the provider verified the email.
+func (uu *userUsecase) findOrLinkOIDCUser(ctx context.Context, providerName string, claims *oidc.Claims) (*domain.User, error) {
+	now := time.Now()
+
+	identity, err := uu.ir.FindByProviderSubject(ctx, providerName, claims.Subject)
+	if err == nil {
+		user, err := uu.ur.FindByID(ctx, identity.UserID)
+		if err != nil {
+			return nil, err
+		}
+		if err := uu.ir.UpdateLogin(ctx, identity.ID, claims.Email, now); err != nil {
+			return nil, err
+		}
+		return user, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	// linking by an unverified email would let anyone take over the account with that address
+	if claims.Email == "" || !claims.EmailVerified {
+		return nil, ErrOIDCEmailNotVerified
+	}
+
+	user, err := uu.ur.FindByEmail(ctx, claims.Email)
+	switch {
+	case err == nil:
+		// the provider proved ownership of the address, which is all the verification email would do
+		if !user.IsActive {
+			user.IsActive = true
+			user.VerificationTokenHash = nil
+			user.VerificationTokenExpiresAt = nil
+			user.UpdatedAt = now
+			if err := uu.ur.Update(ctx, user); err != nil {
+				return nil, err
+			}
+		}
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		// the generated password is only a placeholder; a password can be set later via password reset
+		_, hashPassword, err := uu.up.GeneratePassword()
+		if err != nil {
+			return nil, err
+		}
+		name := claims.Name
+		if name == "" {
+			name, _, _ = strings.Cut(claims.Email, "@")
+		}
+		user = &domain.User{
+			ID:           uuid.New(),
+			Name:         name,
+			Email:        claims.Email,
+			PasswordHash: hashPassword,
+			IsActive:     true,
+			CreatedAt:    now,
+			UpdatedAt:    now,
+		}
+		if err := uu.ur.Create(ctx, user); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, err
+	}
+
+	identity = &domain.UserIdentity{
+		ID:          uuid.New(),
+		UserID:      user.ID,
+		Provider:    providerName,
+		Subject:     claims.Subject,
+		Email:       claims.Email,
+		CreatedAt:   now,
+		LastLoginAt: now,
+	}
+	if err := uu.ir.Create(ctx, identity); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
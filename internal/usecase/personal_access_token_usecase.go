@@ -0,0 +1,123 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"trip_app/internal/domain"
+	"trip_app/internal/repository"
+	"trip_app/internal/security"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type PersonalAccessTokenUsecase interface {
+	// Create issues a new token. The raw token is returned only once and never stored.
+	Create(ctx context.Context, userID uuid.UUID, name string, scopes []string, expiresAt *time.Time) (*domain.PersonalAccessToken, string, error)
+	List(ctx context.Context, userID uuid.UUID) ([]domain.PersonalAccessToken, error)
+	Revoke(ctx context.Context, userID, tokenID uuid.UUID) error
+	// Authenticate resolves a raw token sent in the Authorization header and records its use
+	Authenticate(ctx context.Context, rawToken string) (*domain.PersonalAccessToken, error)
+}
+
+// PersonalAccessTokenPrefix marks personal access tokens so that they can be told apart from JWTs
+const PersonalAccessTokenPrefix = "tapat_"
+
+var ErrPersonalAccessTokenNotFound = errors.New("personal access token not found")
+var ErrInvalidPersonalAccessToken = errors.New("invalid or expired personal access token")
+
+type personalAccessTokenUsecase struct {
+	pr repository.PersonalAccessTokenRepository
+	us security.TokenGenerator
+}
+
+func NewPersonalAccessTokenUsecase(pr repository.PersonalAccessTokenRepository, us security.TokenGenerator) PersonalAccessTokenUsecase {
+	return &personalAccessTokenUsecase{pr, us}
+}
+
+func (pu *personalAccessTokenUsecase) Create(ctx context.Context, userID uuid.UUID, name string, scopes []string, expiresAt *time.Time) (*domain.PersonalAccessToken, string, error) {
+	if len(scopes) == 0 {
+		return nil, "", fmt.Errorf("%w: at least one scope is required", ErrValidation)
+	}
+	for _, scope := range scopes {
+		if !slices.Contains(domain.PersonalAccessTokenScopes, scope) {
+			return nil, "", fmt.Errorf("%w: unknown scope %q", ErrValidation, scope)
+		}
+	}
+	if expiresAt != nil && !expiresAt.After(time.Now()) {
+		return nil, "", fmt.Errorf("%w: expiresAt must be in the future", ErrValidation)
+	}
+
+	rawToken, _, err := pu.us.GenerateToken()
+	if err != nil {
+		return nil, "", err
+	}
+	rawToken = PersonalAccessTokenPrefix + rawToken
+
+	// keep the scopes in a stable order and without duplicates
+	scopes = slices.Clone(scopes)
+	slices.Sort(scopes)
+	scopes = slices.Compact(scopes)
+
+	token := &domain.PersonalAccessToken{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Name:      name,
+		TokenHash: pu.us.HashToken(rawToken),
+		Scopes:    strings.Join(scopes, " "),
+		ExpiresAt: expiresAt,
+		CreatedAt: time.Now(),
+	}
+	if err := pu.pr.Create(ctx, token); err != nil {
+		return nil, "", err
+	}
+
+	return token, rawToken, nil
+}
+
+func (pu *personalAccessTokenUsecase) List(ctx context.Context, userID uuid.UUID) ([]domain.PersonalAccessToken, error) {
+	return pu.pr.FindByUserID(ctx, userID)
+}
+
+func (pu *personalAccessTokenUsecase) Revoke(ctx context.Context, userID, tokenID uuid.UUID) error {
+	deleted, err := pu.pr.Delete(ctx, userID, tokenID)
+	if err != nil {
+		return err
+	}
+	// another user's token is reported as missing so that its existence is not revealed
+	if !deleted {
+		return ErrPersonalAccessTokenNotFound
+	}
+	return nil
+}
+
+func (pu *personalAccessTokenUsecase) Authenticate(ctx context.Context, rawToken string) (*domain.PersonalAccessToken, error) {
+	if !strings.HasPrefix(rawToken, PersonalAccessTokenPrefix) {
+		return nil, ErrInvalidPersonalAccessToken
+	}
+
+	token, err := pu.pr.FindByTokenHash(ctx, pu.us.HashToken(rawToken))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidPersonalAccessToken
+		}
+		return nil, err
+	}
+
+	now := time.Now()
+	if token.ExpiresAt != nil && !now.Before(*token.ExpiresAt) {
+		return nil, ErrInvalidPersonalAccessToken
+	}
+
+	if err := pu.pr.UpdateLastUsedAt(ctx, token.ID, now); err != nil {
+		return nil, err
+	}
+	token.LastUsedAt = &now
+
+	return token, nil
+}
@@ -12,30 +12,94 @@ import (
 )
 
 type PublicTripUsecase interface {
-	GetTripByShareToken(ctx context.Context, shareToken string) (*domain.Trip, error)
+	// GetTripByShareToken checks the share link allows the required scope and has uses left.
+	// countUse counts the request as one use of the link; only the entry of a visit (the trip itself or its html page)
+	// is counted, so the requests a visit makes after it do not use the link up.
+	// grant is the token from UnlockShareLink; it is only required for a share link with a passphrase.
+	// visit is the token from StartShareVisit; a request with it is allowed even when the counted visit used the last use.
+	GetTripByShareToken(ctx context.Context, shareToken, grant, visit string, required domain.ShareScope, countUse bool) (*domain.Trip, *domain.ShareToken, error)
+	// StartShareVisit returns a short-lived token for the requests of a visit that GetTripByShareToken counted
+	StartShareVisit(shareLink *domain.ShareToken) (string, time.Time, error)
+	// UnlockShareLink checks the passphrase of the share link and returns a short-lived grant that only works for that link.
+	// Wrong passphrases are throttled per share link.
+	UnlockShareLink(ctx context.Context, shareToken, passphrase string) (string, time.Time, error)
+	// UpdateTripByShareToken updates the trip like TripUsecase.UpdateTrip
 	UpdateTripByShareToken(ctx context.Context, shareToken string, title string, startDate, endDate time.Time, members []domain.Member) (*domain.Trip, error)
 	GetTripDetailsByShareToken(ctx context.Context, shareToken string) (*domain.Trip, error)
 }
 
 type publicTripUsecase struct {
-	pt repository.PublicTripRepository
-	tg security.TokenGenerator
+	pt  repository.PublicTripRepository
+	str repository.ShareTokenRepository
+	lar repository.LoginAttemptRepository
+	tg  security.TokenGenerator
+	up  security.PasswordGenerator
+	atg security.AuthTokenGenerator
+	// throttle limits wrong passphrases of share links (MaxShareUnlockFailures)
+	throttle LoginThrottlePolicy
 }
 
-func NewPublicTripUsecase(pt repository.PublicTripRepository, tg security.TokenGenerator) PublicTripUsecase {
-	return &publicTripUsecase{pt, tg}
+func NewPublicTripUsecase(pt repository.PublicTripRepository, str repository.ShareTokenRepository, lar repository.LoginAttemptRepository, tg security.TokenGenerator, up security.PasswordGenerator, atg security.AuthTokenGenerator, throttle LoginThrottlePolicy) PublicTripUsecase {
+	return &publicTripUsecase{pt, str, lar, tg, up, atg, throttle}
 }
 
-func (pu *publicTripUsecase) GetTripByShareToken(ctx context.Context, shareToken string) (*domain.Trip, error) {
+func (pu *publicTripUsecase) GetTripByShareToken(ctx context.Context, shareToken, grant, visit string, required domain.ShareScope, countUse bool) (*domain.Trip, *domain.ShareToken, error) {
 	tokenHash := pu.tg.HashToken(shareToken)
+	link, err := pu.str.FindByTokenHash(ctx, tokenHash)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, ErrTripNotFound
+		}
+		return nil, nil, err
+	}
+
+	now := time.Now()
+	if link.IsExpired(now) {
+		return nil, nil, ErrShareLinkExpired
+	}
+	// the requests of a visit that was already counted do not need a use left
+	if !link.HasUsesLeft() && (countUse || !pu.isShareVisit(link, visit)) {
+		return nil, nil, ErrShareLinkExpired
+	}
+	if err := pu.checkShareGrant(link, grant); err != nil {
+		return nil, nil, err
+	}
+	// a request the link is not allowed to make does not use it up
+	if !link.Scope.Includes(required) {
+		return nil, nil, ErrShareScopeInsufficient
+	}
+	if countUse {
+		if err := pu.str.Use(ctx, link.ID, now); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, nil, ErrShareLinkExpired
+			}
+			return nil, nil, err
+		}
+		link.UseCount++
+		link.LastUsedAt = &now
+	}
+
 	trip, err := pu.pt.FindByShareToken(ctx, tokenHash)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, ErrTripNotFound
+			return nil, nil, ErrTripNotFound
 		}
-		return nil, err
+		return nil, nil, err
 	}
-	return trip, nil
+	return trip, link, nil
+}
+
+func (pu *publicTripUsecase) StartShareVisit(shareLink *domain.ShareToken) (string, time.Time, error) {
+	return pu.atg.GenerateShareVisitToken(shareLink.ID)
+}
+
+// isShareVisit checks the visit token was issued for this share link by StartShareVisit
+func (pu *publicTripUsecase) isShareVisit(link *domain.ShareToken, visit string) bool {
+	if visit == "" {
+		return false
+	}
+	shareLinkID, err := pu.atg.ParseShareVisitToken(visit)
+	return err == nil && shareLinkID == link.ID
 }
 
 func (pu *publicTripUsecase) UpdateTripByShareToken(ctx context.Context, shareToken string, title string, startDate, endDate time.Time, members []domain.Member) (*domain.Trip, error) {
@@ -48,12 +112,22 @@ func (pu *publicTripUsecase) UpdateTripByShareToken(ctx context.Context, shareTo
 		return nil, err
 	}
 
+	if members != nil {
+		if err := validateMemberIDs(trip, members); err != nil {
+			return nil, err
+		}
+	}
+
 	trip.Title = title
 	trip.StartDate = startDate
 	trip.EndDate = endDate
 	trip.Members = members
 
 	if err := pu.pt.Update(ctx, trip); err != nil {
+		// a member was removed by another request in the meantime
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrMemberNotFound
+		}
 		return nil, err
 	}
 
@@ -0,0 +1,126 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+	"unicode/utf8"
+
+	"trip_app/internal/domain"
+	"trip_app/internal/infrastructure/schedulecsv"
+	"trip_app/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+type ScheduleCSVUsecase interface {
+	// Import creates a schedule for every row of the CSV. Times without a time zone column are read in loc.
+	// Each row is validated with the same rules as CreateSchedule and an invalid row is reported in its result.
+	// Without atomic the valid rows are created; with atomic a single invalid row skips every row.
+	// The created rows are saved in one transaction either way.
+	Import(ctx context.Context, tripID uuid.UUID, r io.Reader, loc *time.Location, atomic bool) ([]ScheduleCSVResult, error)
+}
+
+type ScheduleCSVStatus string
+
+const (
+	ScheduleCSVCreated ScheduleCSVStatus = "created"
+	ScheduleCSVFailed  ScheduleCSVStatus = "error"
+	// ScheduleCSVSkipped is a valid row that was not created because another row of an atomic import failed
+	ScheduleCSVSkipped ScheduleCSVStatus = "skipped"
+)
+
+// MaxCSVRows is the most rows a single csv import can contain
+const MaxCSVRows = 1000
+
+// ScheduleCSVResult is the outcome of one row, in the order of the file
+type ScheduleCSVResult struct {
+	// Line is the line of the row in the file, the header being line 1
+	Line     int
+	Title    string
+	Status   ScheduleCSVStatus
+	Schedule *domain.Schedule
+	// Err tells why the row was not imported when Status is ScheduleCSVFailed
+	Err error
+}
+
+type scheduleCSVUsecase struct {
+	sr repository.ScheduleRepository
+	sv ScheduleUsecaseValidator
+}
+
+func NewScheduleCSVUsecase(sr repository.ScheduleRepository, sv ScheduleUsecaseValidator) ScheduleCSVUsecase {
+	return &scheduleCSVUsecase{sr, sv}
+}
+
+func (scu *scheduleCSVUsecase) Import(ctx context.Context, tripID uuid.UUID, r io.Reader, loc *time.Location, atomic bool) ([]ScheduleCSVResult, error) {
+	rows, err := schedulecsv.Read(r, loc)
+	if err != nil {
+		if errors.Is(err, schedulecsv.ErrInvalidCSV) {
+			return nil, fmt.Errorf("%w: %w", ErrValidation, err)
+		}
+		return nil, err
+	}
+	if len(rows) > MaxCSVRows {
+		return nil, fmt.Errorf("%w: a file can contain at most %d rows", ErrValidation, MaxCSVRows)
+	}
+
+	now := time.Now()
+	results := make([]ScheduleCSVResult, len(rows))
+	schedules := make([]domain.Schedule, 0, len(rows))
+	// valid holds the index in results of each schedule
+	valid := make([]int, 0, len(rows))
+	for i, row := range rows {
+		results[i] = ScheduleCSVResult{Line: row.Line, Title: row.Title}
+		if err := scu.validateRow(row); err != nil {
+			results[i].Status = ScheduleCSVFailed
+			results[i].Err = err
+			continue
+		}
+		schedules = append(schedules, domain.Schedule{
+			TripID:        tripID,
+			Title:         row.Title,
+			StartDateTime: row.Start,
+			EndDateTime:   row.End,
+			Memo:          row.Memo,
+			CreatedAt:     now,
+			UpdatedAt:     now,
+		})
+		valid = append(valid, i)
+	}
+
+	if atomic && len(valid) < len(rows) {
+		for _, i := range valid {
+			results[i].Status = ScheduleCSVSkipped
+		}
+		return results, nil
+	}
+
+	if err := scu.sr.CreateAll(ctx, schedules); err != nil {
+		return nil, err
+	}
+	for j, i := range valid {
+		results[i].Status = ScheduleCSVCreated
+		results[i].Schedule = &schedules[j]
+	}
+	return results, nil
+}
+
+// validateRow checks the row with the rules of CreateSchedule
+func (scu *scheduleCSVUsecase) validateRow(row schedulecsv.Row) error {
+	if row.Err != nil {
+		return fmt.Errorf("%w: %w", ErrValidation, row.Err)
+	}
+	if row.Title == "" {
+		return fmt.Errorf("%w: title is required", ErrValidation)
+	}
+	if utf8.RuneCountInString(row.Title) > maxScheduleTitleLength {
+		return fmt.Errorf("%w: title must be at most %d characters", ErrValidation, maxScheduleTitleLength)
+	}
+	if err := scu.sv.ValidateCreateSchedule(row.Start, row.End); err != nil {
+		return fmt.Errorf("%w: end must be after start", ErrValidation)
+	}
+	return nil
+}
@@ -0,0 +1,223 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"trip_app/internal/domain"
+	"trip_app/internal/infrastructure/ical"
+	"trip_app/internal/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type ScheduleImportUsecase interface {
+	// Import creates a schedule for every VEVENT of the iCalendar data through CreateSchedule.
+	// Floating times and all-day events are read in loc. Events that were already imported to the trip,
+	// or that were exported from it, are skipped as duplicates by their uid. An event that can not be
+	// imported is reported in its result and does not stop the others. With dryRun nothing is saved.
+	Import(ctx context.Context, tripID uuid.UUID, r io.Reader, loc *time.Location, dryRun bool) ([]ScheduleImportResult, error)
+}
+
+type ScheduleImportStatus string
+
+const (
+	ScheduleImportCreated     ScheduleImportStatus = "created"
+	ScheduleImportWouldCreate ScheduleImportStatus = "would_create"
+	ScheduleImportDuplicate   ScheduleImportStatus = "duplicate"
+	ScheduleImportFailed      ScheduleImportStatus = "error"
+)
+
+// MaxImportEvents is the most events a single import can contain
+const MaxImportEvents = 500
+
+// maxImportUIDLength is the size of ScheduleImport.uid
+const maxImportUIDLength = 255
+
+// maxScheduleTitleLength is the size of Schedule.title
+const maxScheduleTitleLength = 255
+
+// ScheduleImportResult is the outcome of one VEVENT, in the order of the file
+type ScheduleImportResult struct {
+	UID     string
+	Summary string
+	Status  ScheduleImportStatus
+	// Schedule is the created schedule, or in a dry run the schedule that would be created (without an id)
+	Schedule *domain.Schedule
+	// Err tells why the event was not imported when Status is ScheduleImportFailed
+	Err error
+}
+
+type scheduleImportUsecase struct {
+	su  ScheduleUsecase
+	sir repository.ScheduleImportRepository
+	sv  ScheduleUsecaseValidator
+}
+
+func NewScheduleImportUsecase(su ScheduleUsecase, sir repository.ScheduleImportRepository, sv ScheduleUsecaseValidator) ScheduleImportUsecase {
+	return &scheduleImportUsecase{su, sir, sv}
+}
+
+func (siu *scheduleImportUsecase) Import(ctx context.Context, tripID uuid.UUID, r io.Reader, loc *time.Location, dryRun bool) ([]ScheduleImportResult, error) {
+	events, err := ical.Parse(r, loc)
+	if err != nil {
+		if errors.Is(err, ical.ErrInvalidCalendar) {
+			return nil, fmt.Errorf("%w: %w", ErrValidation, err)
+		}
+		return nil, err
+	}
+	if len(events) > MaxImportEvents {
+		return nil, fmt.Errorf("%w: a file can contain at most %d events", ErrValidation, MaxImportEvents)
+	}
+
+	uids := make([]string, 0, len(events))
+	for _, event := range events {
+		if event.UID != "" {
+			uids = append(uids, event.UID)
+		}
+	}
+	imported, err := siu.sir.FindImportedUIDs(ctx, tripID, uids)
+	if err != nil {
+		return nil, err
+	}
+	// seen holds the uids that are already in the trip or earlier in the file
+	seen := make(map[string]bool, len(events))
+	for _, uid := range imported {
+		seen[uid] = true
+	}
+
+	results := make([]ScheduleImportResult, len(events))
+	for i, event := range events {
+		result := &results[i]
+		result.UID = event.UID
+		result.Summary = event.Summary
+
+		if err := siu.validateImportEvent(event); err != nil {
+			result.Status = ScheduleImportFailed
+			result.Err = err
+			continue
+		}
+
+		duplicate, err := siu.isDuplicate(ctx, tripID, event.UID, seen)
+		if err != nil {
+			return nil, err
+		}
+		if duplicate {
+			result.Status = ScheduleImportDuplicate
+			continue
+		}
+
+		if dryRun {
+			result.Status = ScheduleImportWouldCreate
+			result.Schedule = &domain.Schedule{
+				TripID:        tripID,
+				Title:         strings.TrimSpace(event.Summary),
+				StartDateTime: event.Start,
+				EndDateTime:   event.End,
+				Memo:          event.Description,
+			}
+		} else {
+			schedule, err := siu.create(ctx, tripID, event)
+			if err != nil {
+				switch {
+				case errors.Is(err, ErrValidation):
+					result.Status = ScheduleImportFailed
+					result.Err = err
+					continue
+				case errors.Is(err, errImportedConcurrently):
+					result.Status = ScheduleImportDuplicate
+				default:
+					// the events before were created and recorded, so importing the file again skips them
+					return nil, err
+				}
+			} else {
+				result.Status = ScheduleImportCreated
+				result.Schedule = schedule
+			}
+		}
+		if event.UID != "" {
+			seen[event.UID] = true
+		}
+	}
+
+	return results, nil
+}
+
+// errImportedConcurrently means another import created the same event while this one was running
+var errImportedConcurrently = errors.New("the event was imported by another request")
+
+func (siu *scheduleImportUsecase) create(ctx context.Context, tripID uuid.UUID, event ical.ParsedEvent) (*domain.Schedule, error) {
+	schedule, err := siu.su.CreateSchedule(ctx, tripID, strings.TrimSpace(event.Summary), event.Start, event.End, event.Description)
+	if err != nil {
+		return nil, err
+	}
+	if event.UID == "" {
+		return schedule, nil
+	}
+
+	if err := siu.sir.Create(ctx, &domain.ScheduleImport{
+		TripID:     tripID,
+		UID:        event.UID,
+		ScheduleID: schedule.ID,
+		ImportedAt: time.Now(),
+	}); err != nil {
+		if !errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, err
+		}
+		// keep the schedule of the other import only
+		if err := siu.su.DeleteSchedule(ctx, tripID, schedule.ID); err != nil {
+			return nil, err
+		}
+		return nil, errImportedConcurrently
+	}
+	return schedule, nil
+}
+
+// isDuplicate reports whether the event is already in the trip: imported before, earlier in the file,
+// or exported from one of the trip's own schedules
+func (siu *scheduleImportUsecase) isDuplicate(ctx context.Context, tripID uuid.UUID, uid string, seen map[string]bool) (bool, error) {
+	if uid == "" {
+		return false, nil
+	}
+	if seen[uid] {
+		return true, nil
+	}
+	scheduleID, ok := domain.ScheduleIDFromCalendarUID(uid)
+	if !ok {
+		return false, nil
+	}
+	if _, err := siu.su.GetScheduleByID(ctx, tripID, scheduleID); err != nil {
+		if errors.Is(err, ErrScheduleNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// validateImportEvent checks the event can become a schedule, so a dry run reports the same errors as an import
+func (siu *scheduleImportUsecase) validateImportEvent(event ical.ParsedEvent) error {
+	if event.Err != nil {
+		return fmt.Errorf("%w: %w", ErrValidation, event.Err)
+	}
+	if utf8.RuneCountInString(event.UID) > maxImportUIDLength {
+		return fmt.Errorf("%w: UID must be at most %d characters", ErrValidation, maxImportUIDLength)
+	}
+	title := strings.TrimSpace(event.Summary)
+	if title == "" {
+		return fmt.Errorf("%w: SUMMARY is missing", ErrValidation)
+	}
+	if utf8.RuneCountInString(title) > maxScheduleTitleLength {
+		return fmt.Errorf("%w: SUMMARY must be at most %d characters", ErrValidation, maxScheduleTitleLength)
+	}
+	if err := siu.sv.ValidateCreateSchedule(event.Start, event.End); err != nil {
+		return fmt.Errorf("%w: the event must end after it starts", ErrValidation)
+	}
+	return nil
+}
@@ -25,9 +25,11 @@ type UpdateScheduleParams struct {
 type ScheduleUsecase interface {
 	CreateSchedule(ctx context.Context, tripID uuid.UUID, title string, startDateTime, endDateTime time.Time, memo string) (*domain.Schedule, error)
 	GetSchedulesByTripID(ctx context.Context, tripID uuid.UUID) ([]domain.Schedule, error)
-	GetScheduleByID(ctx context.Context, scheduleID uuid.UUID) (*domain.Schedule, error)
-	UpdateSchedule(ctx context.Context, scheduleID uuid.UUID, params UpdateScheduleParams) (*domain.Schedule, error)
-	DeleteSchedule(ctx context.Context, scheduleID uuid.UUID) error
+	// GetScheduleByID, UpdateSchedule and DeleteSchedule return ErrScheduleNotFound
+	// when the schedule belongs to another trip, so a trip's access never reaches other trips
+	GetScheduleByID(ctx context.Context, tripID, scheduleID uuid.UUID) (*domain.Schedule, error)
+	UpdateSchedule(ctx context.Context, tripID, scheduleID uuid.UUID, params UpdateScheduleParams) (*domain.Schedule, error)
+	DeleteSchedule(ctx context.Context, tripID, scheduleID uuid.UUID) error
 }
 
 type scheduleUsecase struct {
@@ -44,12 +46,15 @@ func (su *scheduleUsecase) CreateSchedule(ctx context.Context, tripID uuid.UUID,
 		return nil, fmt.Errorf("%w: %w", ErrValidation, err)
 	}
 
+	now := time.Now()
 	schedule := &domain.Schedule{
 		TripID:        tripID,
 		Title:         title,
 		StartDateTime: startDateTime,
 		EndDateTime:   endDateTime,
 		Memo:          memo,
+		CreatedAt:     now,
+		UpdatedAt:     now,
 	}
 
 	if err := su.sr.Create(ctx, schedule); err != nil {
@@ -67,8 +72,8 @@ func (su *scheduleUsecase) GetSchedulesByTripID(ctx context.Context, tripID uuid
 	return schedules, nil
 }
 
-func (su *scheduleUsecase) GetScheduleByID(ctx context.Context, scheduleID uuid.UUID) (*domain.Schedule, error) {
-	schedule, err := su.sr.FindByID(ctx, scheduleID)
+func (su *scheduleUsecase) GetScheduleByID(ctx context.Context, tripID, scheduleID uuid.UUID) (*domain.Schedule, error) {
+	schedule, err := su.sr.FindByID(ctx, tripID, scheduleID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, ErrScheduleNotFound
@@ -78,8 +83,8 @@ func (su *scheduleUsecase) GetScheduleByID(ctx context.Context, scheduleID uuid.
 	return schedule, nil
 }
 
-func (su *scheduleUsecase) UpdateSchedule(ctx context.Context, scheduleID uuid.UUID, params UpdateScheduleParams) (*domain.Schedule, error) {
-	schedule, err := su.sr.FindByID(ctx, scheduleID)
+func (su *scheduleUsecase) UpdateSchedule(ctx context.Context, tripID, scheduleID uuid.UUID, params UpdateScheduleParams) (*domain.Schedule, error) {
+	schedule, err := su.sr.FindByID(ctx, tripID, scheduleID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, ErrScheduleNotFound
@@ -115,24 +120,27 @@ func (su *scheduleUsecase) UpdateSchedule(ctx context.Context, scheduleID uuid.U
 	if params.Memo != nil {
 		schedule.Memo = *params.Memo
 	}
+	// calendar clients compare DTSTAMP, which comes from updated_at
+	schedule.UpdatedAt = time.Now()
 
 	if err := su.sr.Update(ctx, schedule); err != nil {
+		// deleted by another request in the meantime
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrScheduleNotFound
+		}
 		return nil, err
 	}
 
 	return schedule, nil
 }
 
-func (su *scheduleUsecase) DeleteSchedule(ctx context.Context, scheduleID uuid.UUID) error {
-	_, err := su.sr.FindByID(ctx, scheduleID)
+func (su *scheduleUsecase) DeleteSchedule(ctx context.Context, tripID, scheduleID uuid.UUID) error {
+	deleted, err := su.sr.Delete(ctx, tripID, scheduleID)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return ErrScheduleNotFound
-		}
 		return err
 	}
-	if err := su.sr.Delete(ctx, scheduleID); err != nil {
-		return err
+	if !deleted {
+		return ErrScheduleNotFound
 	}
 	return nil
 }
@@ -0,0 +1,194 @@
+package usecase
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"trip_app/internal/domain"
+	"trip_app/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// ShareActivityConfig holds the settings of the share link access log
+type ShareActivityConfig struct {
+	// IPHashKey is the HMAC key for client IPs, so the stored hashes can not be reversed by hashing every address
+	IPHashKey []byte
+	// QueueSize is how many accesses can wait to be written. Accesses beyond it are dropped instead of slowing down requests.
+	QueueSize int
+}
+
+// DefaultShareActivityQueueSize is the queue size used when nothing is configured
+const DefaultShareActivityQueueSize = 1024
+
+// shareAccessBatchSize caps the accesses written in one insert
+const shareAccessBatchSize = 100
+
+// limits of GetActivity
+const (
+	defaultShareActivityDays      = 30
+	maxShareActivityDays          = 90
+	defaultShareActivityEditLimit = 20
+	maxShareActivityEditLimit     = 100
+)
+
+// ShareActivity is the usage of the share links of a trip
+type ShareActivity struct {
+	// Daily has one entry per UTC day of the period, including days without accesses
+	Daily       []domain.ShareAccessDailyCount
+	RecentEdits []domain.ShareLinkAccess
+}
+
+type ShareActivityUsecase interface {
+	// RecordAccess queues a request made through the share link. It never waits for the database.
+	RecordAccess(shareLink *domain.ShareToken, method, route string, status int, clientIP, userAgent string)
+	// Run writes the queued accesses until ctx is done
+	Run(ctx context.Context)
+	// GetActivity totals the accesses of the last days per day and returns the latest edits.
+	// Zero days or editLimit use the defaults.
+	GetActivity(ctx context.Context, tripID uuid.UUID, days, editLimit int) (*ShareActivity, error)
+}
+
+type shareActivityUsecase struct {
+	slar  repository.ShareLinkAccessRepository
+	cfg   ShareActivityConfig
+	queue chan domain.ShareLinkAccess
+}
+
+func NewShareActivityUsecase(slar repository.ShareLinkAccessRepository, cfg ShareActivityConfig) ShareActivityUsecase {
+	queueSize := cfg.QueueSize
+	if queueSize <= 0 {
+		queueSize = DefaultShareActivityQueueSize
+	}
+	return &shareActivityUsecase{slar, cfg, make(chan domain.ShareLinkAccess, queueSize)}
+}
+
+func (su *shareActivityUsecase) RecordAccess(shareLink *domain.ShareToken, method, route string, status int, clientIP, userAgent string) {
+	shareTokenID := shareLink.ID
+	access := domain.ShareLinkAccess{
+		TripID:          shareLink.TripID,
+		ShareTokenID:    &shareTokenID,
+		ShareLinkName:   shareLink.Name,
+		Method:          method,
+		Route:           route,
+		Status:          status,
+		IPHash:          su.hashIP(clientIP),
+		UserAgentFamily: userAgentFamily(userAgent),
+		AccessedAt:      time.Now(),
+	}
+
+	select {
+	case su.queue <- access:
+	default:
+		log.Printf("share link access log queue is full, dropping an access to trip %s", shareLink.TripID)
+	}
+}
+
+func (su *shareActivityUsecase) Run(ctx context.Context) {
+	batch := make([]domain.ShareLinkAccess, 0, shareAccessBatchSize)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case access := <-su.queue:
+			batch = append(batch[:0], access)
+			// write whatever else is already waiting in the same insert
+		drain:
+			for len(batch) < shareAccessBatchSize {
+				select {
+				case access := <-su.queue:
+					batch = append(batch, access)
+				default:
+					break drain
+				}
+			}
+			if err := su.slar.CreateBatch(context.Background(), batch); err != nil {
+				log.Printf("failed to write %d share link accesses: %v", len(batch), err)
+			}
+		}
+	}
+}
+
+func (su *shareActivityUsecase) GetActivity(ctx context.Context, tripID uuid.UUID, days, editLimit int) (*ShareActivity, error) {
+	if days == 0 {
+		days = defaultShareActivityDays
+	}
+	if days < 1 || days > maxShareActivityDays {
+		return nil, fmt.Errorf("%w: days must be between 1 and %d", ErrValidation, maxShareActivityDays)
+	}
+	if editLimit == 0 {
+		editLimit = defaultShareActivityEditLimit
+	}
+	if editLimit < 1 || editLimit > maxShareActivityEditLimit {
+		return nil, fmt.Errorf("%w: limit must be between 1 and %d", ErrValidation, maxShareActivityEditLimit)
+	}
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	since := today.AddDate(0, 0, -(days - 1))
+	counts, err := su.slar.CountDailyByTripID(ctx, tripID, since)
+	if err != nil {
+		return nil, err
+	}
+	countsByDate := make(map[string]domain.ShareAccessDailyCount, len(counts))
+	for _, c := range counts {
+		countsByDate[c.Date.Format(time.DateOnly)] = c
+	}
+	daily := make([]domain.ShareAccessDailyCount, days)
+	for i := range daily {
+		date := since.AddDate(0, 0, i)
+		daily[i] = countsByDate[date.Format(time.DateOnly)]
+		daily[i].Date = date
+	}
+
+	edits, err := su.slar.FindRecentEditsByTripID(ctx, tripID, editLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ShareActivity{Daily: daily, RecentEdits: edits}, nil
+}
+
+func (su *shareActivityUsecase) hashIP(clientIP string) string {
+	mac := hmac.New(sha256.New, su.cfg.IPHashKey)
+	mac.Write([]byte(clientIP))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// userAgentFamilies maps a marker in the User-Agent header to its family. The order matters:
+// Edge and Opera also contain "Chrome/", and Chrome also contains "Safari/".
+var userAgentFamilies = []struct {
+	marker string
+	family string
+}{
+	{"bot", "Bot"},
+	{"spider", "Bot"},
+	{"crawler", "Bot"},
+	{"edg/", "Edge"},
+	{"opr/", "Opera"},
+	{"firefox/", "Firefox"},
+	{"fxios/", "Firefox"},
+	{"chrome/", "Chrome"},
+	{"crios/", "Chrome"},
+	{"safari/", "Safari"},
+	{"curl/", "curl"},
+}
+
+// userAgentFamily reduces the User-Agent header to the browser family, so the log does not keep a fingerprint
+func userAgentFamily(userAgent string) string {
+	if userAgent == "" {
+		return "Unknown"
+	}
+	ua := strings.ToLower(userAgent)
+	for _, f := range userAgentFamilies {
+		if strings.Contains(ua, f.marker) {
+			return f.family
+		}
+	}
+	return "Other"
+}
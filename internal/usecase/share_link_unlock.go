@@ -0,0 +1,95 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"trip_app/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+var ErrShareLinkLocked = errors.New("share link is protected by a passphrase. unlock it first")
+var ErrShareLinkNotProtected = errors.New("share link is not protected by a passphrase")
+var ErrInvalidSharePassphrase = errors.New("invalid passphrase")
+var ErrTooManyUnlockAttempts = errors.New("too many wrong passphrases. please try again later")
+
+// ShareUnlockThrottledError is returned while the share link is locked after too many wrong passphrases.
+// RetryAfter tells the client how long to wait.
+type ShareUnlockThrottledError struct {
+	RetryAfter time.Duration
+}
+
+func (e *ShareUnlockThrottledError) Error() string {
+	return ErrTooManyUnlockAttempts.Error()
+}
+
+func (e *ShareUnlockThrottledError) Unwrap() error {
+	return ErrTooManyUnlockAttempts
+}
+
+func shareUnlockThrottleKey(shareLink *domain.ShareToken) string {
+	return "share:" + shareLink.ID.String()
+}
+
+func (pu *publicTripUsecase) UnlockShareLink(ctx context.Context, shareToken, passphrase string) (string, time.Time, error) {
+	link, err := pu.str.FindByTokenHash(ctx, pu.tg.HashToken(shareToken))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", time.Time{}, ErrTripNotFound
+		}
+		return "", time.Time{}, err
+	}
+	now := time.Now()
+	if !link.IsUsable(now) {
+		return "", time.Time{}, ErrShareLinkExpired
+	}
+	if !link.IsPasswordProtected() {
+		return "", time.Time{}, ErrShareLinkNotProtected
+	}
+
+	// reject the attempt before the passphrase hash is compared
+	key := shareUnlockThrottleKey(link)
+	throttled := pu.throttle.MaxShareUnlockFailures > 0
+	if throttled {
+		attempt, err := pu.lar.FindByKey(ctx, key)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", time.Time{}, err
+		}
+		if attempt != nil && attempt.LockedUntil != nil && now.Before(*attempt.LockedUntil) {
+			return "", time.Time{}, &ShareUnlockThrottledError{RetryAfter: attempt.LockedUntil.Sub(now)}
+		}
+	}
+
+	if err := pu.up.ComparePassword(*link.PassphraseHash, passphrase); err != nil {
+		if throttled {
+			if err := recordThrottledFailure(ctx, pu.lar, key, pu.throttle.MaxShareUnlockFailures, pu.throttle, now); err != nil {
+				return "", time.Time{}, err
+			}
+		}
+		return "", time.Time{}, ErrInvalidSharePassphrase
+	}
+	if throttled {
+		if err := pu.lar.Reset(ctx, key); err != nil {
+			return "", time.Time{}, err
+		}
+	}
+
+	return pu.atg.GenerateShareGrantToken(link.ID)
+}
+
+// checkShareGrant checks the grant was issued for this share link by UnlockShareLink
+func (pu *publicTripUsecase) checkShareGrant(link *domain.ShareToken, grant string) error {
+	if !link.IsPasswordProtected() {
+		return nil
+	}
+	if grant == "" {
+		return ErrShareLinkLocked
+	}
+	shareLinkID, err := pu.atg.ParseShareGrantToken(grant)
+	if err != nil || shareLinkID != link.ID {
+		return ErrShareLinkLocked
+	}
+	return nil
+}
@@ -2,49 +2,154 @@ package usecase
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strings"
 	"time"
 	"trip_app/internal/domain"
 	"trip_app/internal/repository"
 	"trip_app/internal/security"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 type ShareTokenUsecase interface {
+	// CreateShareToken issues the trip's default share link, which can edit the whole trip and never expires.
+	// regenerate replaces its token; without it an existing default link is an error.
 	CreateShareToken(ctx context.Context, tripID uuid.UUID, regenerate bool) (*domain.ShareToken, string, error)
+	// CreateShareLink issues a named share link limited to the scope. expiresAt, maxUses and passphrase are optional.
+	// A link with a passphrase can only be used after unlocking it with PublicTripUsecase.UnlockShareLink.
+	CreateShareLink(ctx context.Context, tripID uuid.UUID, name string, scope domain.ShareScope, expiresAt *time.Time, maxUses *int, passphrase *string) (*domain.ShareToken, string, error)
+	ListShareLinks(ctx context.Context, tripID uuid.UUID) ([]domain.ShareToken, error)
+	RevokeShareLink(ctx context.Context, tripID, shareLinkID uuid.UUID) error
 }
 
+var ErrShareLinkNotFound = errors.New("share link not found")
+var ErrShareLinkAlreadyExists = errors.New("share token already exists")
+var ErrShareLinkExpired = errors.New("share link has expired or reached its max uses")
+var ErrShareScopeInsufficient = errors.New("share link does not allow this operation")
+
+// share link passphrases are hashed with bcrypt, which only uses the first 72 bytes
+const (
+	minShareLinkPassphraseLength = 8
+	maxShareLinkPassphraseBytes  = 72
+)
+
 type shareTokenUsecase struct {
 	ur repository.ShareTokenRepository
 	us security.TokenGenerator
+	up security.PasswordGenerator
 }
 
-func NewShareTokenUsecase(ur repository.ShareTokenRepository, us security.TokenGenerator) ShareTokenUsecase {
-	return &shareTokenUsecase{ur, us}
+func NewShareTokenUsecase(ur repository.ShareTokenRepository, us security.TokenGenerator, up security.PasswordGenerator) ShareTokenUsecase {
+	return &shareTokenUsecase{ur, us, up}
 }
 
 func (u *shareTokenUsecase) CreateShareToken(ctx context.Context, tripID uuid.UUID, regenerate bool) (*domain.ShareToken, string, error) {
+	existing, err := u.ur.FindDefault(ctx, tripID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, "", err
+	}
+	if existing == nil {
+		shareToken, rawShareToken, err := u.create(ctx, tripID, domain.DefaultShareLinkName, domain.ShareScopeEditTrip, nil, nil, nil, true)
+		// another request created the default link first
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, "", ErrShareLinkAlreadyExists
+		}
+		return shareToken, rawShareToken, err
+	}
+	if !regenerate {
+		return nil, "", ErrShareLinkAlreadyExists
+	}
+
 	rawShareToken, hashToken, err := u.us.GenerateToken()
 	if err != nil {
 		return nil, "", err
 	}
+	now := time.Now()
+	if err := u.ur.UpdateToken(ctx, existing.ID, hashToken, now); err != nil {
+		return nil, "", err
+	}
+	existing.TokenHash = hashToken
+	existing.UpdatedAt = now
 
-	shareToken := &domain.ShareToken{
-		TripID:    tripID,
-		TokenHash: hashToken,
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
+	return existing, rawShareToken, nil
+}
+
+func (u *shareTokenUsecase) CreateShareLink(ctx context.Context, tripID uuid.UUID, name string, scope domain.ShareScope, expiresAt *time.Time, maxUses *int, passphrase *string) (*domain.ShareToken, string, error) {
+	if strings.TrimSpace(name) == "" {
+		return nil, "", fmt.Errorf("%w: name is required", ErrValidation)
+	}
+	if len([]rune(name)) > 100 {
+		return nil, "", fmt.Errorf("%w: name must be at most 100 characters", ErrValidation)
+	}
+	if !scope.Valid() {
+		return nil, "", fmt.Errorf("%w: scope must be one of %q, %q, %q or %q", ErrValidation,
+			domain.ShareScopeView, domain.ShareScopeComment, domain.ShareScopeEditSchedules, domain.ShareScopeEditTrip)
+	}
+	if expiresAt != nil && !expiresAt.After(time.Now()) {
+		return nil, "", fmt.Errorf("%w: expiresAt must be in the future", ErrValidation)
+	}
+	if maxUses != nil && *maxUses < 1 {
+		return nil, "", fmt.Errorf("%w: maxUses must be at least 1", ErrValidation)
 	}
 
-	if regenerate {
-		err = u.ur.Update(ctx, shareToken)
-	} else {
-		err = u.ur.Create(ctx, shareToken)
+	var passphraseHash *string
+	if passphrase != nil {
+		if len([]rune(*passphrase)) < minShareLinkPassphraseLength {
+			return nil, "", fmt.Errorf("%w: passphrase must be at least %d characters", ErrValidation, minShareLinkPassphraseLength)
+		}
+		if len(*passphrase) > maxShareLinkPassphraseBytes {
+			return nil, "", fmt.Errorf("%w: passphrase must be at most %d bytes", ErrValidation, maxShareLinkPassphraseBytes)
+		}
+		hash, err := u.up.HashPassword(*passphrase)
+		if err != nil {
+			return nil, "", err
+		}
+		passphraseHash = &hash
 	}
 
+	return u.create(ctx, tripID, name, scope, expiresAt, maxUses, passphraseHash, false)
+}
+
+func (u *shareTokenUsecase) create(ctx context.Context, tripID uuid.UUID, name string, scope domain.ShareScope, expiresAt *time.Time, maxUses *int, passphraseHash *string, isDefault bool) (*domain.ShareToken, string, error) {
+	rawShareToken, hashToken, err := u.us.GenerateToken()
 	if err != nil {
 		return nil, "", err
 	}
 
+	now := time.Now()
+	shareToken := &domain.ShareToken{
+		TripID:         tripID,
+		Name:           name,
+		Scope:          scope,
+		TokenHash:      hashToken,
+		ExpiresAt:      expiresAt,
+		MaxUses:        maxUses,
+		PassphraseHash: passphraseHash,
+		IsDefault:      isDefault,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	if err := u.ur.Create(ctx, shareToken); err != nil {
+		return nil, "", err
+	}
+
 	return shareToken, rawShareToken, nil
 }
+
+func (u *shareTokenUsecase) ListShareLinks(ctx context.Context, tripID uuid.UUID) ([]domain.ShareToken, error) {
+	return u.ur.FindByTripID(ctx, tripID)
+}
+
+func (u *shareTokenUsecase) RevokeShareLink(ctx context.Context, tripID, shareLinkID uuid.UUID) error {
+	deleted, err := u.ur.Delete(ctx, tripID, shareLinkID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrShareLinkNotFound
+	}
+	return nil
+}
@@ -0,0 +1,159 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"trip_app/internal/domain"
+	"trip_app/internal/infrastructure/tripbackup"
+	"trip_app/internal/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type TripBackupUsecase interface {
+	// Export returns the trip with its members and schedules in the current backup format.
+	// Ids are left out; the member linked to the owner is referenced by its ref.
+	Export(ctx context.Context, tripID uuid.UUID) (*tripbackup.Document, error)
+	// Import creates a new trip owned by the user from a backup, reading older format versions through
+	// their migrations. Everything gets fresh ids and the member the owner was linked to is linked to the user.
+	Import(ctx context.Context, userID uuid.UUID, r io.Reader) (*domain.Trip, error)
+}
+
+type tripBackupUsecase struct {
+	tr repository.TripRepository
+	sv ScheduleUsecaseValidator
+}
+
+func NewTripBackupUsecase(tr repository.TripRepository, sv ScheduleUsecaseValidator) TripBackupUsecase {
+	return &tripBackupUsecase{tr, sv}
+}
+
+func (tbu *tripBackupUsecase) Export(ctx context.Context, tripID uuid.UUID) (*tripbackup.Document, error) {
+	trip, err := tbu.tr.FindWithSchedulesByID(ctx, tripID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTripNotFound
+		}
+		return nil, err
+	}
+
+	doc := tripbackup.NewDocument(time.Now().UTC())
+	doc.Trip = tripbackup.Trip{
+		Title:     trip.Title,
+		StartDate: trip.StartDate.Format(time.DateOnly),
+		EndDate:   trip.EndDate.Format(time.DateOnly),
+	}
+	for i, member := range trip.Members {
+		ref := fmt.Sprintf("m%d", i+1)
+		doc.Members = append(doc.Members, tripbackup.Member{Ref: ref, Name: member.Name})
+		if member.UserID != nil && *member.UserID == trip.UserID {
+			doc.Trip.OwnerMemberRef = &ref
+		}
+	}
+
+	schedules := trip.Schedules
+	sort.SliceStable(schedules, func(i, j int) bool {
+		return schedules[i].StartDateTime.Before(schedules[j].StartDateTime)
+	})
+	for _, schedule := range schedules {
+		doc.Schedules = append(doc.Schedules, tripbackup.Schedule{
+			Title:         schedule.Title,
+			StartDateTime: schedule.StartDateTime.UTC(),
+			EndDateTime:   schedule.EndDateTime.UTC(),
+			Memo:          schedule.Memo,
+		})
+	}
+	return doc, nil
+}
+
+func (tbu *tripBackupUsecase) Import(ctx context.Context, userID uuid.UUID, r io.Reader) (*domain.Trip, error) {
+	doc, err := tripbackup.Decode(r)
+	if err != nil {
+		if errors.Is(err, tripbackup.ErrInvalidDocument) || errors.Is(err, tripbackup.ErrUnsupportedVersion) {
+			return nil, fmt.Errorf("%w: %w", ErrValidation, err)
+		}
+		return nil, err
+	}
+	if err := tbu.validate(doc); err != nil {
+		return nil, err
+	}
+
+	// Decode has checked the dates
+	startDate, _ := time.Parse(time.DateOnly, doc.Trip.StartDate)
+	endDate, _ := time.Parse(time.DateOnly, doc.Trip.EndDate)
+	now := time.Now()
+	trip := &domain.Trip{
+		UserID:    userID,
+		Title:     doc.Trip.Title,
+		StartDate: startDate,
+		EndDate:   endDate,
+		CreatedAt: now,
+		UpdatedAt: now,
+		Members:   make([]domain.Member, len(doc.Members)),
+		Schedules: make([]domain.Schedule, len(doc.Schedules)),
+		Collaborators: []domain.TripCollaborator{
+			{UserID: userID, Role: domain.TripRoleOwner, CreatedAt: now, UpdatedAt: now},
+		},
+	}
+	// the members get new ids from the database; the refs only tell which of them the owner was
+	for i, member := range doc.Members {
+		trip.Members[i] = domain.Member{Name: member.Name}
+		if ref := doc.Trip.OwnerMemberRef; ref != nil && *ref == member.Ref {
+			trip.Members[i].UserID = &userID
+		}
+	}
+	for i, schedule := range doc.Schedules {
+		trip.Schedules[i] = domain.Schedule{
+			Title:         schedule.Title,
+			StartDateTime: schedule.StartDateTime,
+			EndDateTime:   schedule.EndDateTime,
+			Memo:          schedule.Memo,
+			CreatedAt:     now,
+			UpdatedAt:     now,
+		}
+	}
+
+	// the trip, its members and schedules are created in one transaction
+	if err := tbu.tr.Create(ctx, trip); err != nil {
+		return nil, err
+	}
+	return trip, nil
+}
+
+// validate applies the rules of creating a trip, its members and schedules to the backup
+func (tbu *tripBackupUsecase) validate(doc *tripbackup.Document) error {
+	if strings.TrimSpace(doc.Trip.Title) == "" {
+		return fmt.Errorf("%w: trip.title is required", ErrValidation)
+	}
+	if utf8.RuneCountInString(doc.Trip.Title) > 255 {
+		return fmt.Errorf("%w: trip.title must be at most 255 characters", ErrValidation)
+	}
+	if doc.Trip.EndDate < doc.Trip.StartDate {
+		return fmt.Errorf("%w: trip.endDate must not be before trip.startDate", ErrValidation)
+	}
+	for i, member := range doc.Members {
+		if err := validateMemberName(member.Name); err != nil {
+			return fmt.Errorf("%w (members[%d])", err, i)
+		}
+	}
+	for i, schedule := range doc.Schedules {
+		if strings.TrimSpace(schedule.Title) == "" {
+			return fmt.Errorf("%w: schedules[%d].title is required", ErrValidation, i)
+		}
+		if utf8.RuneCountInString(schedule.Title) > maxScheduleTitleLength {
+			return fmt.Errorf("%w: schedules[%d].title must be at most %d characters", ErrValidation, i, maxScheduleTitleLength)
+		}
+		if err := tbu.sv.ValidateCreateSchedule(schedule.StartDateTime, schedule.EndDateTime); err != nil {
+			return fmt.Errorf("%w: schedules[%d].endDateTime must be after startDateTime", ErrValidation, i)
+		}
+	}
+	return nil
+}
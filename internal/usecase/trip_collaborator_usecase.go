@@ -0,0 +1,166 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"trip_app/internal/domain"
+	"trip_app/internal/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type TripCollaboratorUsecase interface {
+	List(ctx context.Context, tripID uuid.UUID) ([]domain.TripCollaborator, error)
+	// Add gives the registered user with the email access to the trip as an editor or viewer
+	Add(ctx context.Context, tripID uuid.UUID, email string, role domain.TripRole) (*domain.TripCollaborator, error)
+	UpdateRole(ctx context.Context, tripID, userID uuid.UUID, role domain.TripRole) (*domain.TripCollaborator, error)
+	Remove(ctx context.Context, tripID, userID uuid.UUID) error
+	// TransferOwnership hands the trip over to one of its collaborators. The previous owner stays on as an editor.
+	TransferOwnership(ctx context.Context, tripID, newOwnerID uuid.UUID) error
+}
+
+var ErrCollaboratorNotFound = errors.New("collaborator not found")
+var ErrCollaboratorAlreadyExists = errors.New("user is already a collaborator of this trip")
+var ErrOwnerRoleImmutable = errors.New("the owner can only change by transferring ownership")
+
+type tripCollaboratorUsecase struct {
+	tcr repository.TripCollaboratorRepository
+	tr  repository.TripRepository
+	ur  repository.UserRepository
+}
+
+func NewTripCollaboratorUsecase(tcr repository.TripCollaboratorRepository, tr repository.TripRepository, ur repository.UserRepository) TripCollaboratorUsecase {
+	return &tripCollaboratorUsecase{tcr, tr, ur}
+}
+
+// validateAssignableRole checks the role can be given to a collaborator; owner is only given by a transfer
+func validateAssignableRole(role domain.TripRole) error {
+	if role != domain.TripRoleEditor && role != domain.TripRoleViewer {
+		return fmt.Errorf("%w: role must be %q or %q", ErrValidation, domain.TripRoleEditor, domain.TripRoleViewer)
+	}
+	return nil
+}
+
+func (tcu *tripCollaboratorUsecase) List(ctx context.Context, tripID uuid.UUID) ([]domain.TripCollaborator, error) {
+	return tcu.tcr.FindByTripID(ctx, tripID)
+}
+
+func (tcu *tripCollaboratorUsecase) Add(ctx context.Context, tripID uuid.UUID, email string, role domain.TripRole) (*domain.TripCollaborator, error) {
+	if err := validateAssignableRole(role); err != nil {
+		return nil, err
+	}
+
+	user, err := tcu.ur.FindByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+	if !user.IsActive {
+		return nil, ErrUserNotFound
+	}
+
+	now := time.Now()
+	collaborator := &domain.TripCollaborator{
+		TripID:    tripID,
+		UserID:    user.ID,
+		Role:      role,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := tcu.tcr.Create(ctx, collaborator); err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, ErrCollaboratorAlreadyExists
+		}
+		return nil, err
+	}
+	collaborator.User = *user
+
+	return collaborator, nil
+}
+
+func (tcu *tripCollaboratorUsecase) UpdateRole(ctx context.Context, tripID, userID uuid.UUID, role domain.TripRole) (*domain.TripCollaborator, error) {
+	if err := validateAssignableRole(role); err != nil {
+		return nil, err
+	}
+
+	collaborator, err := tcu.find(ctx, tripID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if collaborator.Role == domain.TripRoleOwner {
+		return nil, ErrOwnerRoleImmutable
+	}
+
+	now := time.Now()
+	if err := tcu.tcr.UpdateRole(ctx, tripID, userID, role, now); err != nil {
+		return nil, err
+	}
+	collaborator.Role = role
+	collaborator.UpdatedAt = now
+
+	return collaborator, nil
+}
+
+func (tcu *tripCollaboratorUsecase) Remove(ctx context.Context, tripID, userID uuid.UUID) error {
+	collaborator, err := tcu.find(ctx, tripID, userID)
+	if err != nil {
+		return err
+	}
+	// a trip always keeps its owner; the owner leaves by transferring ownership or deleting the trip
+	if collaborator.Role == domain.TripRoleOwner {
+		return ErrOwnerRoleImmutable
+	}
+
+	deleted, err := tcu.tcr.Delete(ctx, tripID, userID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrCollaboratorNotFound
+	}
+	return nil
+}
+
+func (tcu *tripCollaboratorUsecase) TransferOwnership(ctx context.Context, tripID, newOwnerID uuid.UUID) error {
+	trip, err := tcu.tr.FindByID(ctx, tripID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrTripNotFound
+		}
+		return err
+	}
+	if trip.UserID == newOwnerID {
+		return fmt.Errorf("%w: the user already owns this trip", ErrValidation)
+	}
+
+	// ownership can only go to someone who already has access, which also proves the account exists
+	if _, err := tcu.find(ctx, tripID, newOwnerID); err != nil {
+		return err
+	}
+
+	if err := tcu.tcr.TransferOwnership(ctx, tripID, trip.UserID, newOwnerID, time.Now()); err != nil {
+		// the collaborator was removed, or another transfer moved the trip in the meantime
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrCollaboratorNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+func (tcu *tripCollaboratorUsecase) find(ctx context.Context, tripID, userID uuid.UUID) (*domain.TripCollaborator, error) {
+	collaborator, err := tcu.tcr.Find(ctx, tripID, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCollaboratorNotFound
+		}
+		return nil, err
+	}
+	return collaborator, nil
+}
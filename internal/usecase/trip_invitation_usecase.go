@@ -0,0 +1,279 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"trip_app/internal/domain"
+	"trip_app/internal/infrastructure/email"
+	"trip_app/internal/repository"
+	"trip_app/internal/security"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type TripInvitationUsecase interface {
+	// Invite emails an invitation to join the trip as an editor or viewer.
+	// memberName optionally selects the member the invitee will be linked to on acceptance.
+	Invite(ctx context.Context, tripID, inviterID uuid.UUID, email string, role domain.TripRole, memberName *string) (*domain.TripInvitation, error)
+	// ListPending returns the invitations of the trip that have not been accepted yet, including expired ones
+	ListPending(ctx context.Context, tripID uuid.UUID) ([]domain.TripInvitation, error)
+	// Resend emails a new token for a pending invitation and restarts its expiry. The previous token stops working.
+	Resend(ctx context.Context, tripID, invitationID uuid.UUID) (*domain.TripInvitation, error)
+	Revoke(ctx context.Context, tripID, invitationID uuid.UUID) error
+	// Accept makes the user a collaborator of the invited trip and returns the trip with the user's role
+	Accept(ctx context.Context, userID uuid.UUID, rawToken string) (*domain.Trip, domain.TripRole, error)
+}
+
+// tripInvitationTTL is how long an invitation token can be accepted
+const tripInvitationTTL = 7 * 24 * time.Hour
+
+// tripInvitationResendCooldown is the minimum time between two emails for the same invitation
+const tripInvitationResendCooldown = time.Minute
+
+var ErrInvitationNotFound = errors.New("invitation not found")
+var ErrInvitationAlreadyExists = errors.New("an invitation for this email is already pending. resend it instead")
+var ErrInvalidInvitationToken = errors.New("invalid or expired invitation token")
+var ErrInvitationEmailMismatch = errors.New("the invitation was sent to a different email address")
+var ErrTooManyInvitationEmails = errors.New("the invitation was sent recently. please try again later")
+
+// InvitationResendThrottledError is returned while the invitation is in its resend cooldown.
+// RetryAfter tells the client how long to wait.
+type InvitationResendThrottledError struct {
+	RetryAfter time.Duration
+}
+
+func (e *InvitationResendThrottledError) Error() string {
+	return ErrTooManyInvitationEmails.Error()
+}
+
+func (e *InvitationResendThrottledError) Unwrap() error {
+	return ErrTooManyInvitationEmails
+}
+
+type tripInvitationUsecase struct {
+	tir repository.TripInvitationRepository
+	tr  repository.TripRepository
+	tcr repository.TripCollaboratorRepository
+	ur  repository.UserRepository
+	us  security.TokenGenerator
+	ue  email.Sender
+}
+
+func NewTripInvitationUsecase(tir repository.TripInvitationRepository, tr repository.TripRepository, tcr repository.TripCollaboratorRepository, ur repository.UserRepository, us security.TokenGenerator, ue email.Sender) TripInvitationUsecase {
+	return &tripInvitationUsecase{tir, tr, tcr, ur, us, ue}
+}
+
+func (tiu *tripInvitationUsecase) Invite(ctx context.Context, tripID, inviterID uuid.UUID, email string, role domain.TripRole, memberName *string) (*domain.TripInvitation, error) {
+	if err := validateAssignableRole(role); err != nil {
+		return nil, err
+	}
+
+	trip, err := tiu.tr.FindByID(ctx, tripID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTripNotFound
+		}
+		return nil, err
+	}
+	if memberName != nil {
+		if err := validateInvitationMember(trip, *memberName); err != nil {
+			return nil, err
+		}
+	}
+
+	// someone who already has access does not need an invitation
+	invitee, err := tiu.ur.FindByEmail(ctx, email)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if invitee != nil {
+		if _, err := tiu.tcr.Find(ctx, tripID, invitee.ID); err == nil {
+			return nil, ErrCollaboratorAlreadyExists
+		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+	}
+
+	if _, err := tiu.tir.FindPendingByEmail(ctx, tripID, email); err == nil {
+		return nil, ErrInvitationAlreadyExists
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	inviter, err := tiu.ur.FindByID(ctx, inviterID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	rawToken, hashedToken, err := tiu.us.GenerateToken()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	invitation := &domain.TripInvitation{
+		TripID:     tripID,
+		Email:      email,
+		Role:       role,
+		MemberName: memberName,
+		TokenHash:  hashedToken,
+		InvitedBy:  inviterID,
+		ExpiresAt:  now.Add(tripInvitationTTL),
+		SentAt:     now,
+		CreatedAt:  now,
+	}
+	if err := tiu.tir.Create(ctx, invitation); err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, ErrInvitationAlreadyExists
+		}
+		return nil, err
+	}
+
+	// the invitation stays pending when delivery fails, so the owner can resend it
+	if err := tiu.ue.SendTripInvitationEmail(ctx, invitation.Email, inviter.Name, trip.Title, rawToken); err != nil {
+		return nil, err
+	}
+
+	return invitation, nil
+}
+
+// validateInvitationMember checks the trip has a member with the name that is not linked to a user yet
+func validateInvitationMember(trip *domain.Trip, memberName string) error {
+	for _, member := range trip.Members {
+		if member.Name == memberName && member.UserID == nil {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: the trip has no unlinked member named %q", ErrValidation, memberName)
+}
+
+func (tiu *tripInvitationUsecase) ListPending(ctx context.Context, tripID uuid.UUID) ([]domain.TripInvitation, error) {
+	return tiu.tir.FindPendingByTripID(ctx, tripID)
+}
+
+func (tiu *tripInvitationUsecase) Resend(ctx context.Context, tripID, invitationID uuid.UUID) (*domain.TripInvitation, error) {
+	invitation, err := tiu.findPending(ctx, tripID, invitationID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if wait := invitation.SentAt.Add(tripInvitationResendCooldown).Sub(now); wait > 0 {
+		return nil, &InvitationResendThrottledError{RetryAfter: wait}
+	}
+
+	trip, err := tiu.tr.FindByID(ctx, tripID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTripNotFound
+		}
+		return nil, err
+	}
+	inviter, err := tiu.ur.FindByID(ctx, invitation.InvitedBy)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	rawToken, hashedToken, err := tiu.us.GenerateToken()
+	if err != nil {
+		return nil, err
+	}
+	expiresAt := now.Add(tripInvitationTTL)
+	if err := tiu.tir.UpdateToken(ctx, invitation.ID, hashedToken, expiresAt, now); err != nil {
+		return nil, err
+	}
+	invitation.TokenHash = hashedToken
+	invitation.ExpiresAt = expiresAt
+	invitation.SentAt = now
+
+	if err := tiu.ue.SendTripInvitationEmail(ctx, invitation.Email, inviter.Name, trip.Title, rawToken); err != nil {
+		return nil, err
+	}
+
+	return invitation, nil
+}
+
+func (tiu *tripInvitationUsecase) Revoke(ctx context.Context, tripID, invitationID uuid.UUID) error {
+	deleted, err := tiu.tir.Delete(ctx, tripID, invitationID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrInvitationNotFound
+	}
+	return nil
+}
+
+func (tiu *tripInvitationUsecase) Accept(ctx context.Context, userID uuid.UUID, rawToken string) (*domain.Trip, domain.TripRole, error) {
+	invitation, err := tiu.tir.FindByTokenHash(ctx, tiu.us.HashToken(rawToken))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, "", ErrInvalidInvitationToken
+		}
+		return nil, "", err
+	}
+	now := time.Now()
+	if !invitation.IsPending() || now.After(invitation.ExpiresAt) {
+		return nil, "", ErrInvalidInvitationToken
+	}
+
+	user, err := tiu.ur.FindByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, "", ErrUserNotFound
+		}
+		return nil, "", err
+	}
+	// the token alone is not enough: a forwarded invitation must not grant access to another account
+	if !strings.EqualFold(user.Email, invitation.Email) {
+		return nil, "", ErrInvitationEmailMismatch
+	}
+
+	if err := tiu.tir.Accept(ctx, invitation, userID, now); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, "", ErrInvalidInvitationToken
+		}
+		return nil, "", err
+	}
+
+	trip, err := tiu.tr.FindByID(ctx, invitation.TripID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, "", ErrTripNotFound
+		}
+		return nil, "", err
+	}
+	if trip.UserID == userID {
+		return trip, domain.TripRoleOwner, nil
+	}
+	collaborator, err := tiu.tcr.Find(ctx, trip.ID, userID)
+	if err != nil {
+		return nil, "", err
+	}
+	return trip, collaborator.Role, nil
+}
+
+func (tiu *tripInvitationUsecase) findPending(ctx context.Context, tripID, invitationID uuid.UUID) (*domain.TripInvitation, error) {
+	invitation, err := tiu.tir.FindByID(ctx, tripID, invitationID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvitationNotFound
+		}
+		return nil, err
+	}
+	if !invitation.IsPending() {
+		return nil, ErrInvitationNotFound
+	}
+	return invitation, nil
+}
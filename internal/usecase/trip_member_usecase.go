@@ -0,0 +1,157 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"trip_app/internal/domain"
+	"trip_app/internal/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type TripMemberUsecase interface {
+	Add(ctx context.Context, tripID uuid.UUID, name string) (*domain.Member, error)
+	// Rename changes the name of the member. The id and the linked user stay the same.
+	Rename(ctx context.Context, tripID, memberID uuid.UUID, name string) (*domain.Member, error)
+	Remove(ctx context.Context, tripID, memberID uuid.UUID) error
+	// ListMemberships returns the trips where the user is linked to a member, with their members
+	ListMemberships(ctx context.Context, userID uuid.UUID) ([]domain.Trip, error)
+	// Claim links the member to the user, who also becomes a viewer of the trip unless they already have access
+	Claim(ctx context.Context, tripID, memberID, userID uuid.UUID) (*domain.Member, error)
+}
+
+var ErrMemberNotFound = errors.New("member not found")
+var ErrMemberAlreadyClaimed = errors.New("member is already linked to another user")
+var ErrAlreadyTripMember = errors.New("you are already linked to another member of this trip")
+
+type tripMemberUsecase struct {
+	mr repository.MemberRepository
+	tr repository.TripRepository
+}
+
+func NewTripMemberUsecase(mr repository.MemberRepository, tr repository.TripRepository) TripMemberUsecase {
+	return &tripMemberUsecase{mr, tr}
+}
+
+// validateMemberName checks the name of a member is not blank and fits in the column
+func validateMemberName(name string) error {
+	if strings.TrimSpace(name) == "" {
+		return fmt.Errorf("%w: member name is required", ErrValidation)
+	}
+	if len([]rune(name)) > 255 {
+		return fmt.Errorf("%w: member name must be at most 255 characters", ErrValidation)
+	}
+	return nil
+}
+
+// validateMemberIDs checks the members sent in a trip update refer to members of the trip, each at most once.
+// Members without an id are new.
+func validateMemberIDs(trip *domain.Trip, members []domain.Member) error {
+	existing := make(map[uuid.UUID]bool, len(trip.Members))
+	for _, m := range trip.Members {
+		existing[m.ID] = true
+	}
+	seen := make(map[uuid.UUID]bool, len(members))
+	for _, m := range members {
+		if m.ID == uuid.Nil {
+			continue
+		}
+		if !existing[m.ID] {
+			return fmt.Errorf("%w: member %s does not belong to this trip", ErrValidation, m.ID)
+		}
+		if seen[m.ID] {
+			return fmt.Errorf("%w: member %s is listed more than once", ErrValidation, m.ID)
+		}
+		seen[m.ID] = true
+	}
+	return nil
+}
+
+func (tmu *tripMemberUsecase) Add(ctx context.Context, tripID uuid.UUID, name string) (*domain.Member, error) {
+	if err := validateMemberName(name); err != nil {
+		return nil, err
+	}
+
+	member := &domain.Member{TripID: tripID, Name: name}
+	if err := tmu.mr.Create(ctx, member); err != nil {
+		return nil, err
+	}
+	return member, nil
+}
+
+func (tmu *tripMemberUsecase) Rename(ctx context.Context, tripID, memberID uuid.UUID, name string) (*domain.Member, error) {
+	if err := validateMemberName(name); err != nil {
+		return nil, err
+	}
+
+	member, err := tmu.mr.FindByID(ctx, tripID, memberID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrMemberNotFound
+		}
+		return nil, err
+	}
+	if err := tmu.mr.UpdateName(ctx, tripID, memberID, name); err != nil {
+		return nil, err
+	}
+	member.Name = name
+
+	return member, nil
+}
+
+func (tmu *tripMemberUsecase) Remove(ctx context.Context, tripID, memberID uuid.UUID) error {
+	deleted, err := tmu.mr.Delete(ctx, tripID, memberID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrMemberNotFound
+	}
+	return nil
+}
+
+func (tmu *tripMemberUsecase) ListMemberships(ctx context.Context, userID uuid.UUID) ([]domain.Trip, error) {
+	return tmu.tr.FindByMemberUserID(ctx, userID)
+}
+
+func (tmu *tripMemberUsecase) Claim(ctx context.Context, tripID, memberID, userID uuid.UUID) (*domain.Member, error) {
+	member, err := tmu.mr.FindByID(ctx, tripID, memberID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrMemberNotFound
+		}
+		return nil, err
+	}
+	if member.UserID != nil {
+		// claiming the own member again is not an error
+		if *member.UserID == userID {
+			return member, nil
+		}
+		return nil, ErrMemberAlreadyClaimed
+	}
+
+	// a user stands for at most one member of a trip
+	if _, err := tmu.mr.FindByUserID(ctx, tripID, userID); err == nil {
+		return nil, ErrAlreadyTripMember
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	if err := tmu.mr.Claim(ctx, tripID, memberID, userID, time.Now()); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrMemberAlreadyClaimed
+		}
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, ErrAlreadyTripMember
+		}
+		return nil, err
+	}
+	member.UserID = &userID
+
+	return member, nil
+}
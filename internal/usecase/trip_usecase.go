@@ -13,32 +13,43 @@ import (
 )
 
 var ErrTripNotFound = errors.New("trip not found")
+var ErrTripAccessDenied = errors.New("no access to this trip")
 
 type TripUsecase interface {
 	CreateTrip(ctx context.Context, userID uuid.UUID, title string, startDate, endDate time.Time, members []domain.Member) (*domain.Trip, error)
+	// GetTripsByUserID returns the trips the user owns or collaborates on
 	GetTripsByUserID(ctx context.Context, userID uuid.UUID) ([]domain.Trip, error)
 	GetTripByTripID(ctx context.Context, tripID uuid.UUID) (*domain.Trip, error)
+	// GetTripForUser returns the trip with the user's role, or ErrTripAccessDenied if the user is not a collaborator
+	GetTripForUser(ctx context.Context, tripID, userID uuid.UUID) (*domain.Trip, domain.TripRole, error)
+	// UpdateTrip updates the trip and syncs the members by id (see TripRepository.Update). nil members are left unchanged.
 	UpdateTrip(ctx context.Context, tripID uuid.UUID, title string, startDate, endDate time.Time, members []domain.Member) (*domain.Trip, error)
 	GetTripDetailsByID(ctx context.Context, tripID uuid.UUID) (*domain.Trip, error)
 	DeleteTrip(ctx context.Context, tripID uuid.UUID) error
 }
 
 type tripUsecase struct {
-	tr repository.TripRepository
-	us security.TokenGenerator
+	tr  repository.TripRepository
+	tcr repository.TripCollaboratorRepository
+	us  security.TokenGenerator
 }
 
-func NewTripUsecase(tr repository.TripRepository, us security.TokenGenerator) TripUsecase {
-	return &tripUsecase{tr, us}
+func NewTripUsecase(tr repository.TripRepository, tcr repository.TripCollaboratorRepository, us security.TokenGenerator) TripUsecase {
+	return &tripUsecase{tr, tcr, us}
 }
 
 func (tu *tripUsecase) CreateTrip(ctx context.Context, userID uuid.UUID, title string, startDate, endDate time.Time, members []domain.Member) (*domain.Trip, error) {
+	now := time.Now()
 	trip := &domain.Trip{
 		UserID:    userID,
 		Title:     title,
 		StartDate: startDate,
 		EndDate:   endDate,
 		Members:   members,
+		// the creator is the owner; the collaborator entry is created along with the trip
+		Collaborators: []domain.TripCollaborator{
+			{UserID: userID, Role: domain.TripRoleOwner, CreatedAt: now, UpdatedAt: now},
+		},
 	}
 
 	if err := tu.tr.Create(ctx, trip); err != nil {
@@ -49,7 +60,7 @@ func (tu *tripUsecase) CreateTrip(ctx context.Context, userID uuid.UUID, title s
 }
 
 func (tu *tripUsecase) GetTripsByUserID(ctx context.Context, userID uuid.UUID) ([]domain.Trip, error) {
-	trips, err := tu.tr.FindByUserID(ctx, userID)
+	trips, err := tu.tr.FindAccessibleByUserID(ctx, userID)
 	if err != nil {
 		return nil, err
 	}
@@ -67,6 +78,25 @@ func (tu *tripUsecase) GetTripByTripID(ctx context.Context, tripID uuid.UUID) (*
 	return trip, nil
 }
 
+func (tu *tripUsecase) GetTripForUser(ctx context.Context, tripID, userID uuid.UUID) (*domain.Trip, domain.TripRole, error) {
+	trip, err := tu.GetTripByTripID(ctx, tripID)
+	if err != nil {
+		return nil, "", err
+	}
+	if trip.UserID == userID {
+		return trip, domain.TripRoleOwner, nil
+	}
+
+	collaborator, err := tu.tcr.Find(ctx, tripID, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, "", ErrTripAccessDenied
+		}
+		return nil, "", err
+	}
+	return trip, collaborator.Role, nil
+}
+
 func (tu *tripUsecase) UpdateTrip(ctx context.Context, tripID uuid.UUID, title string, startDate, endDate time.Time, members []domain.Member) (*domain.Trip, error) {
 	trip, err := tu.tr.FindByID(ctx, tripID)
 	if err != nil {
@@ -76,12 +106,22 @@ func (tu *tripUsecase) UpdateTrip(ctx context.Context, tripID uuid.UUID, title s
 		return nil, err
 	}
 
+	if members != nil {
+		if err := validateMemberIDs(trip, members); err != nil {
+			return nil, err
+		}
+	}
+
 	trip.Title = title
 	trip.StartDate = startDate
 	trip.EndDate = endDate
 	trip.Members = members
 
 	if err := tu.tr.Update(ctx, trip); err != nil {
+		// a member was removed by another request in the meantime
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrMemberNotFound
+		}
 		return nil, err
 	}
 
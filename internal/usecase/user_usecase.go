@@ -4,10 +4,13 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log"
+	"strings"
 	"time"
 
 	"trip_app/internal/domain"
 	"trip_app/internal/infrastructure/email"
+	"trip_app/internal/infrastructure/oidc"
 	"trip_app/internal/repository"
 	"trip_app/internal/security"
 
@@ -17,24 +20,97 @@ import (
 
 type UserUsecase interface {
 	SignUp(ctx context.Context, name, email string) (*domain.User, error)
-	VerifyEmail(ctx context.Context, token string) (string, error)
-	Login(ctx context.Context, email, password string) (*domain.User, string, error)
-	Logout(ctx context.Context, userID uuid.UUID) error
+	VerifyEmail(ctx context.Context, token, password string) (string, error)
+	ResendVerificationEmail(ctx context.Context, email string) error
+	Login(ctx context.Context, email, password, clientIP string) (*domain.User, *AuthTokens, error)
+	LoginWithTwoFactor(ctx context.Context, challengeToken, code, clientIP string) (*domain.User, *AuthTokens, error)
+	// StartOIDCLogin begins an authorization code flow with PKCE and returns the URL of the identity provider
+	StartOIDCLogin(ctx context.Context, provider string) (string, error)
+	// CompleteOIDCLogin finishes the flow started by StartOIDCLogin and logs in the linked user
+	CompleteOIDCLogin(ctx context.Context, provider, state, code string) (*domain.User, *AuthTokens, error)
+	RefreshTokens(ctx context.Context, rawRefreshToken string) (*domain.User, *AuthTokens, error)
+	Logout(ctx context.Context, userID, sessionID uuid.UUID) error
+	LogoutAllSessions(ctx context.Context, userID uuid.UUID) error
+	ValidateSession(ctx context.Context, userID, sessionID uuid.UUID) error
 	GetProfile(ctx context.Context, userID uuid.UUID) (*domain.User, error)
 	ChangePassword(ctx context.Context, userID uuid.UUID, currentPassword, newPassword string) error
+	RequestPasswordReset(ctx context.Context, email string) error
+	ResetPassword(ctx context.Context, token, newPassword string) error
+	RequestEmailChange(ctx context.Context, userID uuid.UUID, currentPassword, newEmail string) error
+	ConfirmEmailChange(ctx context.Context, token string) (*domain.User, error)
+	SetupTwoFactor(ctx context.Context, userID uuid.UUID) (*TwoFactorSetup, error)
+	ConfirmTwoFactor(ctx context.Context, userID uuid.UUID, code string) ([]string, error)
+}
+
+// AuthTokens is the pair of tokens returned on login and on refresh
+type AuthTokens struct {
+	AccessToken          string
+	AccessTokenExpiresAt time.Time
+	RefreshToken         string
+}
+
+// TwoFactorSetup is the enrollment data shown to the user before confirming 2FA
+type TwoFactorSetup struct {
+	Secret          string
+	ProvisioningURI string
+}
+
+// TwoFactorChallengeError is returned by Login when the password was correct but
+// a second factor is still required. The challenge token has to be presented to
+// LoginWithTwoFactor together with a TOTP or recovery code.
+type TwoFactorChallengeError struct {
+	ChallengeToken string
+	ExpiresAt      time.Time
+}
+
+func (e *TwoFactorChallengeError) Error() string {
+	return ErrTwoFactorRequired.Error()
+}
+
+func (e *TwoFactorChallengeError) Unwrap() error {
+	return ErrTwoFactorRequired
+}
+
+// refreshTokenTTL is the absolute lifetime of a session (refresh token family)
+const refreshTokenTTL = 30 * 24 * time.Hour
+
+// passwordResetTokenTTL is the lifetime of a password reset token
+const passwordResetTokenTTL = 30 * time.Minute
+
+// emailChangeTokenTTL is the lifetime of an email change confirmation token
+const emailChangeTokenTTL = 30 * time.Minute
+
+// UserUsecaseConfig holds the settings of the user usecase that can be changed per deployment
+type UserUsecaseConfig struct {
+	// LegacyInitialPassword keeps the old signup flow that emails a generated initial password.
+	// When false, the user chooses a password at verification and no credential is emailed.
+	LegacyInitialPassword bool
+	// LoginThrottle limits failed login attempts per email and per client IP
+	LoginThrottle LoginThrottlePolicy
+	// Verification sets the verification token lifetime and the resend limits
+	Verification VerificationPolicy
 }
 
 type userUsecase struct {
 	ur  repository.UserRepository
+	sr  repository.SessionRepository
+	rr  repository.RefreshTokenRepository
+	rcr repository.RecoveryCodeRepository
+	lar repository.LoginAttemptRepository
+	ir  repository.UserIdentityRepository
+	oar repository.OIDCAuthRequestRepository
 	uv  UserUsecaseValidator
 	up  security.PasswordGenerator
 	us  security.TokenGenerator
 	atg security.AuthTokenGenerator
+	otp security.TOTPGenerator
 	ue  email.Sender
+	op  map[string]oidc.Provider
+	cfg UserUsecaseConfig
 }
 
-func NewUserUsecase(ur repository.UserRepository, uv UserUsecaseValidator, up security.PasswordGenerator, us security.TokenGenerator, atg security.AuthTokenGenerator, ue email.Sender) UserUsecase {
-	return &userUsecase{ur, uv, up, us, atg, ue}
+func NewUserUsecase(ur repository.UserRepository, sr repository.SessionRepository, rr repository.RefreshTokenRepository, rcr repository.RecoveryCodeRepository, lar repository.LoginAttemptRepository, ir repository.UserIdentityRepository, oar repository.OIDCAuthRequestRepository, uv UserUsecaseValidator, up security.PasswordGenerator, us security.TokenGenerator, atg security.AuthTokenGenerator, otp security.TOTPGenerator, ue email.Sender, op map[string]oidc.Provider, cfg UserUsecaseConfig) UserUsecase {
+	return &userUsecase{ur, sr, rr, rcr, lar, ir, oar, uv, up, us, atg, otp, ue, op, cfg}
 }
 
 // error definitions
@@ -45,6 +121,18 @@ var ErrInvalidVerificationToken = errors.New("invalid verification token")
 var ErrVerificationTokenExpired = errors.New("verification token has expired")
 var ErrUserNotFound = errors.New("user not found")
 var ErrIncorrectCurrentPassword = errors.New("incorrect current password")
+var ErrSessionRevoked = errors.New("session has been revoked or expired")
+var ErrInvalidRefreshToken = errors.New("invalid or expired refresh token")
+var ErrRefreshTokenReused = errors.New("refresh token has already been used")
+var ErrInvalidPasswordResetToken = errors.New("invalid or expired password reset token")
+var ErrPasswordRequired = errors.New("password is required to activate the account")
+var ErrSameEmail = errors.New("new email is the same as the current email")
+var ErrInvalidEmailChangeToken = errors.New("invalid or expired email change token")
+var ErrTwoFactorRequired = errors.New("two-factor authentication code is required")
+var ErrInvalidTwoFactorChallenge = errors.New("invalid or expired two-factor challenge")
+var ErrInvalidTwoFactorCode = errors.New("invalid two-factor authentication code")
+var ErrTwoFactorAlreadyEnabled = errors.New("two-factor authentication is already enabled")
+var ErrTwoFactorNotSetUp = errors.New("two-factor authentication setup has not been started")
 
 func (uu *userUsecase) SignUp(ctx context.Context, name, email string) (*domain.User, error) {
 
@@ -57,17 +145,36 @@ func (uu *userUsecase) SignUp(ctx context.Context, name, email string) (*domain.
 			// find no error means email already exists
 			return nil, ErrEmailConflict
 		}
+		// signing up again sends another verification email, so it is throttled like a resend.
+		// a throttled signup answers like any other without sending, as an error would
+		// reveal that the email has an unverified account
+		now := time.Now()
+		if err := uu.reserveVerificationEmail(foundUser, now); err != nil {
+			if !errors.Is(err, ErrTooManyVerificationEmails) {
+				return nil, err
+			}
+			foundUser.Name = name
+			if err := uu.ur.Update(ctx, foundUser); err != nil {
+				return nil, err
+			}
+			return foundUser, nil
+		}
 		// initPassword & hashPassword
 		rawPassword, hashPassword, err := uu.up.GeneratePassword()
 		if err != nil {
 			return nil, err
 		}
+		// unless the legacy flow is enabled, the generated password is only a placeholder
+		// that is never sent; the user chooses the real one at verification
+		if !uu.cfg.LegacyInitialPassword {
+			rawPassword = ""
+		}
 		// generate verification token
 		rawToken, hashToken, err := uu.us.GenerateToken()
 		if err != nil {
 			return nil, err
 		}
-		expiresAt := time.Now().Add(30 * time.Minute)
+		expiresAt := now.Add(uu.cfg.Verification.TokenTTL)
 
 		// update foundUser's old data
 		foundUser.Name = name
@@ -95,13 +202,19 @@ func (uu *userUsecase) SignUp(ctx context.Context, name, email string) (*domain.
 		if err != nil {
 			return nil, err
 		}
+		// unless the legacy flow is enabled, the generated password is only a placeholder
+		// that is never sent; the user chooses the real one at verification
+		if !uu.cfg.LegacyInitialPassword {
+			rawPassword = ""
+		}
 
 		// generate verification token
 		rawToken, hashToken, err := uu.us.GenerateToken()
 		if err != nil {
 			return nil, err
 		}
-		expiresAt := time.Now().Add(30 * time.Minute)
+		now := time.Now()
+		expiresAt := now.Add(uu.cfg.Verification.TokenTTL)
 
 		// create user
 		user := &domain.User{
@@ -112,8 +225,9 @@ func (uu *userUsecase) SignUp(ctx context.Context, name, email string) (*domain.
 			IsActive:                   false,
 			VerificationTokenHash:      &hashToken,
 			VerificationTokenExpiresAt: &expiresAt,
-			CreatedAt:                  time.Now(),
-			UpdatedAt:                  time.Now(),
+			VerificationSentAt:         &now,
+			CreatedAt:                  now,
+			UpdatedAt:                  now,
 		}
 
 		if err := uu.ur.Create(ctx, user); err != nil {
@@ -132,7 +246,7 @@ func (uu *userUsecase) SignUp(ctx context.Context, name, email string) (*domain.
 	return nil, err
 }
 
-func (uu *userUsecase) VerifyEmail(ctx context.Context, token string) (string, error) {
+func (uu *userUsecase) VerifyEmail(ctx context.Context, token, password string) (string, error) {
 	// hash the token
 	tokenHash := uu.us.HashToken(token)
 
@@ -150,6 +264,22 @@ func (uu *userUsecase) VerifyEmail(ctx context.Context, token string) (string, e
 		return "", ErrVerificationTokenExpired
 	}
 
+	// set the password chosen by the user
+	// (optional only while the legacy initial password flow is enabled)
+	if password == "" && !uu.cfg.LegacyInitialPassword {
+		return "", ErrPasswordRequired
+	}
+	if password != "" {
+		if err := uu.uv.ValidatePassword(password); err != nil {
+			return "", fmt.Errorf("%w: %w", ErrValidation, err)
+		}
+		passwordHash, err := uu.up.HashPassword(password)
+		if err != nil {
+			return "", err
+		}
+		user.PasswordHash = passwordHash
+	}
+
 	// activate user
 	user.IsActive = true
 	user.VerificationTokenHash = nil
@@ -164,36 +294,268 @@ func (uu *userUsecase) VerifyEmail(ctx context.Context, token string) (string, e
 	return message, nil
 }
 
-func (uu *userUsecase) Login(ctx context.Context, email, password string) (*domain.User, string, error) {
+func (uu *userUsecase) Login(ctx context.Context, email, password, clientIP string) (*domain.User, *AuthTokens, error) {
+	// locked out accounts and IPs are rejected before comparing any password hash
+	if err := uu.checkLoginThrottle(ctx, email, clientIP); err != nil {
+		return nil, nil, err
+	}
 
 	user, err := uu.ur.FindByEmail(ctx, email)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, "", ErrInvalidCredentials
+			// unknown emails count as failures too, so they cannot be probed for free
+			if err := uu.recordLoginFailure(ctx, email, clientIP); err != nil {
+				return nil, nil, err
+			}
+			return nil, nil, ErrInvalidCredentials
 		}
-		return nil, "", err
+		return nil, nil, err
 	}
 
 	if !user.IsActive {
-		return nil, "", ErrUserNotActive
+		return nil, nil, ErrUserNotActive
 	}
 
 	if err := uu.up.ComparePassword(user.PasswordHash, password); err != nil {
-		return nil, "", ErrInvalidCredentials
+		if err := uu.recordLoginFailure(ctx, email, clientIP); err != nil {
+			return nil, nil, err
+		}
+		return nil, nil, ErrInvalidCredentials
+	}
+
+	// with 2FA enabled the password alone only earns a short-lived challenge
+	if user.TwoFactorEnabled {
+		challengeToken, expiresAt, err := uu.atg.GenerateTwoFactorChallengeToken(user)
+		if err != nil {
+			return nil, nil, err
+		}
+		return nil, nil, &TwoFactorChallengeError{ChallengeToken: challengeToken, ExpiresAt: expiresAt}
+	}
+
+	if err := uu.resetLoginThrottle(ctx, user.Email); err != nil {
+		return nil, nil, err
+	}
+
+	tokens, err := uu.startSession(ctx, user)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return user, tokens, nil
+}
+
+func (uu *userUsecase) LoginWithTwoFactor(ctx context.Context, challengeToken, code, clientIP string) (*domain.User, *AuthTokens, error) {
+	userID, err := uu.atg.ParseTwoFactorChallengeToken(challengeToken)
+	if err != nil {
+		return nil, nil, ErrInvalidTwoFactorChallenge
+	}
+
+	user, err := uu.ur.FindByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, ErrInvalidTwoFactorChallenge
+		}
+		return nil, nil, err
+	}
+
+	if !user.IsActive {
+		return nil, nil, ErrUserNotActive
+	}
+	if !user.TwoFactorEnabled || user.TwoFactorSecret == nil {
+		return nil, nil, ErrInvalidTwoFactorChallenge
+	}
+
+	// wrong codes share the password failure counters so the challenge cannot be brute forced
+	if err := uu.checkLoginThrottle(ctx, user.Email, clientIP); err != nil {
+		return nil, nil, err
+	}
+
+	// accept either a TOTP code or one of the recovery codes
+	if err := uu.verifyTOTP(ctx, user, code); err != nil {
+		if !errors.Is(err, ErrInvalidTwoFactorCode) {
+			return nil, nil, err
+		}
+		if err := uu.consumeRecoveryCode(ctx, user, code); err != nil {
+			if errors.Is(err, ErrInvalidTwoFactorCode) {
+				if err := uu.recordLoginFailure(ctx, user.Email, clientIP); err != nil {
+					return nil, nil, err
+				}
+			}
+			return nil, nil, err
+		}
+	}
+
+	if err := uu.resetLoginThrottle(ctx, user.Email); err != nil {
+		return nil, nil, err
+	}
+
+	tokens, err := uu.startSession(ctx, user)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return user, tokens, nil
+}
+
+// startSession starts a new session and issues the first token pair of its family
+func (uu *userUsecase) startSession(ctx context.Context, user *domain.User) (*AuthTokens, error) {
+	// logging in during the grace period cancels a scheduled account deletion
+	if user.DeletionScheduledAt != nil {
+		user.DeletionScheduledAt = nil
+		if err := uu.ur.Update(ctx, user); err != nil {
+			return nil, err
+		}
+	}
+
+	session := &domain.Session{
+		ID:        uuid.New(),
+		UserID:    user.ID,
+		ExpiresAt: time.Now().Add(refreshTokenTTL),
+		CreatedAt: time.Now(),
+	}
+	if err := uu.sr.Create(ctx, session); err != nil {
+		return nil, err
+	}
+
+	return uu.issueTokens(ctx, user, session)
+}
+
+func (uu *userUsecase) RefreshTokens(ctx context.Context, rawRefreshToken string) (*domain.User, *AuthTokens, error) {
+	// hash the token
+	tokenHash := uu.us.HashToken(rawRefreshToken)
+
+	refreshToken, err := uu.rr.FindByTokenHash(ctx, tokenHash)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, ErrInvalidRefreshToken
+		}
+		return nil, nil, err
 	}
 
-	// generate access token
-	accessToken, err := uu.atg.GenerateAccessToken(user)
+	// reuse of an already-rotated token means it has leaked, so revoke the whole family
+	if refreshToken.RotatedAt != nil {
+		if err := uu.sr.Revoke(ctx, refreshToken.SessionID, time.Now()); err != nil {
+			return nil, nil, err
+		}
+		return nil, nil, ErrRefreshTokenReused
+	}
+
+	if time.Now().After(refreshToken.ExpiresAt) {
+		return nil, nil, ErrInvalidRefreshToken
+	}
+
+	// the family is dead once its session has been revoked (logout, password change, ...)
+	session, err := uu.sr.FindByID(ctx, refreshToken.SessionID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, ErrInvalidRefreshToken
+		}
+		return nil, nil, err
+	}
+	if session.RevokedAt != nil || time.Now().After(session.ExpiresAt) {
+		return nil, nil, ErrInvalidRefreshToken
+	}
+
+	// rotate: the presented token can never be used again
+	rotated, err := uu.rr.MarkRotated(ctx, refreshToken.ID, time.Now())
+	if err != nil {
+		return nil, nil, err
+	}
+	if !rotated {
+		// lost a race against another refresh with the same token
+		if err := uu.sr.Revoke(ctx, session.ID, time.Now()); err != nil {
+			return nil, nil, err
+		}
+		return nil, nil, ErrRefreshTokenReused
+	}
+
+	user, err := uu.ur.FindByID(ctx, refreshToken.UserID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, ErrInvalidRefreshToken
+		}
+		return nil, nil, err
+	}
+
+	if !user.IsActive {
+		return nil, nil, ErrUserNotActive
+	}
+
+	tokens, err := uu.issueTokens(ctx, user, session)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return user, tokens, nil
+}
+
+// issueTokens generates a short-lived access token and a new refresh token for the session
+func (uu *userUsecase) issueTokens(ctx context.Context, user *domain.User, session *domain.Session) (*AuthTokens, error) {
+	// generate access token bound to the session (jti = session ID)
+	accessToken, accessTokenExpiresAt, err := uu.atg.GenerateAccessToken(user, session.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// generate opaque refresh token, only its hash is stored
+	rawRefreshToken, hashRefreshToken, err := uu.us.GenerateToken()
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken := &domain.RefreshToken{
+		ID:        uuid.New(),
+		SessionID: session.ID,
+		UserID:    user.ID,
+		TokenHash: hashRefreshToken,
+		ExpiresAt: session.ExpiresAt,
+		CreatedAt: time.Now(),
+	}
+	if err := uu.rr.Create(ctx, refreshToken); err != nil {
+		return nil, err
+	}
+
+	return &AuthTokens{
+		AccessToken:          accessToken,
+		AccessTokenExpiresAt: accessTokenExpiresAt,
+		RefreshToken:         rawRefreshToken,
+	}, nil
+}
+
+func (uu *userUsecase) Logout(ctx context.Context, userID, sessionID uuid.UUID) error {
+	session, err := uu.sr.FindByID(ctx, sessionID)
 	if err != nil {
-		return nil, "", err
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrSessionRevoked
+		}
+		return err
+	}
+
+	// a user can only revoke their own session
+	if session.UserID != userID {
+		return ErrSessionRevoked
 	}
 
-	return user, accessToken, nil
+	return uu.sr.Revoke(ctx, sessionID, time.Now())
+}
+
+func (uu *userUsecase) LogoutAllSessions(ctx context.Context, userID uuid.UUID) error {
+	return uu.sr.RevokeAllByUserID(ctx, userID, time.Now())
 }
 
-func (uu *userUsecase) Logout(ctx context.Context, userID uuid.UUID) error {
-	// currently, client-side just deletes the token, so nothing to do server-side
-	// in the future, we might want to implement token blacklisting or expiration by redis
+func (uu *userUsecase) ValidateSession(ctx context.Context, userID, sessionID uuid.UUID) error {
+	session, err := uu.sr.FindByID(ctx, sessionID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrSessionRevoked
+		}
+		return err
+	}
+
+	if session.UserID != userID || session.RevokedAt != nil || time.Now().After(session.ExpiresAt) {
+		return ErrSessionRevoked
+	}
+
 	return nil
 }
 
@@ -235,5 +597,299 @@ func (uu *userUsecase) ChangePassword(ctx context.Context, userID uuid.UUID, cur
 		return err
 	}
 
+	// revoke every session so that stolen tokens stop working after a password change
+	if err := uu.sr.RevokeAllByUserID(ctx, user.ID, time.Now()); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (uu *userUsecase) RequestPasswordReset(ctx context.Context, email string) error {
+	user, err := uu.ur.FindByEmail(ctx, email)
+	if err != nil {
+		// do not reveal whether the email is registered
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	// inactive accounts have to finish signup first
+	if !user.IsActive {
+		return nil
+	}
+
+	// generate reset token
+	rawToken, hashToken, err := uu.us.GenerateToken()
+	if err != nil {
+		return err
+	}
+	expiresAt := time.Now().Add(passwordResetTokenTTL)
+
+	user.PasswordResetTokenHash = &hashToken
+	user.PasswordResetTokenExpiresAt = &expiresAt
+
+	if err := uu.ur.Update(ctx, user); err != nil {
+		return err
+	}
+
+	// send in the background, so neither the smtp round trip nor a delivery failure
+	// makes a registered email distinguishable from an unknown one
+	go func(ctx context.Context, recipient string) {
+		if err := uu.ue.SendPasswordResetEmail(ctx, recipient, rawToken); err != nil {
+			log.Printf("failed to send password reset email: %v", err)
+		}
+	}(context.WithoutCancel(ctx), user.Email)
+
+	return nil
+}
+
+func (uu *userUsecase) ResetPassword(ctx context.Context, token, newPassword string) error {
+	// hash the token
+	tokenHash := uu.us.HashToken(token)
+
+	user, err := uu.ur.FindByPasswordResetToken(ctx, tokenHash)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrInvalidPasswordResetToken
+		}
+		return err
+	}
+
+	// check if token is expired
+	if user.PasswordResetTokenExpiresAt == nil || time.Now().After(*user.PasswordResetTokenExpiresAt) {
+		return ErrInvalidPasswordResetToken
+	}
+
+	if err := uu.uv.ValidatePassword(newPassword); err != nil {
+		return fmt.Errorf("%w: %w", ErrValidation, err)
+	}
+
+	newPasswordHash, err := uu.up.HashPassword(newPassword)
+	if err != nil {
+		return err
+	}
+
+	// the token is single-use
+	user.PasswordHash = newPasswordHash
+	user.PasswordResetTokenHash = nil
+	user.PasswordResetTokenExpiresAt = nil
+
+	if err := uu.ur.Update(ctx, user); err != nil {
+		return err
+	}
+
+	// whoever knew the old password must be logged out everywhere
+	if err := uu.sr.RevokeAllByUserID(ctx, user.ID, time.Now()); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (uu *userUsecase) RequestEmailChange(ctx context.Context, userID uuid.UUID, currentPassword, newEmail string) error {
+	user, err := uu.ur.FindByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrUserNotFound
+		}
+		return err
+	}
+
+	if err := uu.up.ComparePassword(user.PasswordHash, currentPassword); err != nil {
+		return ErrIncorrectCurrentPassword
+	}
+
+	if strings.EqualFold(user.Email, newEmail) {
+		return ErrSameEmail
+	}
+
+	// fail early if the address is already taken (including unverified signups);
+	// the unique index is still the final guard when the change is confirmed
+	if _, err := uu.ur.FindByEmail(ctx, newEmail); err == nil {
+		return ErrEmailConflict
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	// generate confirmation token
+	rawToken, hashToken, err := uu.us.GenerateToken()
+	if err != nil {
+		return err
+	}
+	expiresAt := time.Now().Add(emailChangeTokenTTL)
+
+	// a new request replaces any previous pending change
+	user.PendingEmail = &newEmail
+	user.EmailChangeTokenHash = &hashToken
+	user.EmailChangeTokenExpiresAt = &expiresAt
+
+	if err := uu.ur.Update(ctx, user); err != nil {
+		return err
+	}
+
+	// the new address has to prove ownership before it replaces the current one
+	if err := uu.ue.SendEmailChangeConfirmationEmail(ctx, newEmail, rawToken); err != nil {
+		return err
+	}
+
+	// let the owner of the current address know in case the account was taken over
+	if err := uu.ue.SendEmailChangeNoticeEmail(ctx, user.Email, newEmail); err != nil {
+		log.Printf("failed to send email change notice: %v", err)
+	}
+
+	return nil
+}
+
+func (uu *userUsecase) ConfirmEmailChange(ctx context.Context, token string) (*domain.User, error) {
+	// hash the token
+	tokenHash := uu.us.HashToken(token)
+
+	user, err := uu.ur.FindByEmailChangeToken(ctx, tokenHash)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidEmailChangeToken
+		}
+		return nil, err
+	}
+
+	// check if token is expired
+	if user.PendingEmail == nil || user.EmailChangeTokenExpiresAt == nil || time.Now().After(*user.EmailChangeTokenExpiresAt) {
+		return nil, ErrInvalidEmailChangeToken
+	}
+
+	// the token is single-use
+	user.Email = *user.PendingEmail
+	user.PendingEmail = nil
+	user.EmailChangeTokenHash = nil
+	user.EmailChangeTokenExpiresAt = nil
+
+	if err := uu.ur.Update(ctx, user); err != nil {
+		// another account (e.g. a concurrent signup) took the address after the request
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, ErrEmailConflict
+		}
+		return nil, err
+	}
+
+	return user, nil
+}
+
+func (uu *userUsecase) SetupTwoFactor(ctx context.Context, userID uuid.UUID) (*TwoFactorSetup, error) {
+	user, err := uu.ur.FindByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	if user.TwoFactorEnabled {
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+
+	// the secret stays pending (TwoFactorEnabled = false) until a code is confirmed,
+	// so calling setup again simply replaces it
+	secret, err := uu.otp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+	user.TwoFactorSecret = &secret
+	user.TwoFactorLastUsedStep = 0
+
+	if err := uu.ur.Update(ctx, user); err != nil {
+		return nil, err
+	}
+
+	return &TwoFactorSetup{
+		Secret:          secret,
+		ProvisioningURI: uu.otp.ProvisioningURI(secret, user.Email),
+	}, nil
+}
+
+func (uu *userUsecase) ConfirmTwoFactor(ctx context.Context, userID uuid.UUID, code string) ([]string, error) {
+	user, err := uu.ur.FindByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	if user.TwoFactorEnabled {
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+	if user.TwoFactorSecret == nil {
+		return nil, ErrTwoFactorNotSetUp
+	}
+
+	// proves the authenticator app was configured with the pending secret
+	if err := uu.verifyTOTP(ctx, user, code); err != nil {
+		return nil, err
+	}
+
+	// recovery codes are shown once; only their hashes are stored
+	rawCodes, err := uu.otp.GenerateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	recoveryCodes := make([]domain.RecoveryCode, 0, len(rawCodes))
+	for _, rawCode := range rawCodes {
+		recoveryCodes = append(recoveryCodes, domain.RecoveryCode{
+			ID:        uuid.New(),
+			UserID:    user.ID,
+			CodeHash:  uu.us.HashToken(uu.otp.NormalizeRecoveryCode(rawCode)),
+			CreatedAt: time.Now(),
+		})
+	}
+	if err := uu.rcr.ReplaceAll(ctx, user.ID, recoveryCodes); err != nil {
+		return nil, err
+	}
+
+	user.TwoFactorEnabled = true
+	if err := uu.ur.Update(ctx, user); err != nil {
+		return nil, err
+	}
+
+	return rawCodes, nil
+}
+
+// verifyTOTP checks a TOTP code and records its time step so the same code cannot be replayed
+func (uu *userUsecase) verifyTOTP(ctx context.Context, user *domain.User, code string) error {
+	if user.TwoFactorSecret == nil {
+		return ErrInvalidTwoFactorCode
+	}
+
+	step, ok := uu.otp.Verify(*user.TwoFactorSecret, code, time.Now())
+	if !ok || step <= user.TwoFactorLastUsedStep {
+		return ErrInvalidTwoFactorCode
+	}
+
+	user.TwoFactorLastUsedStep = step
+	return uu.ur.Update(ctx, user)
+}
+
+// consumeRecoveryCode marks a matching unused recovery code as used
+func (uu *userUsecase) consumeRecoveryCode(ctx context.Context, user *domain.User, code string) error {
+	codeHash := uu.us.HashToken(uu.otp.NormalizeRecoveryCode(code))
+
+	recoveryCode, err := uu.rcr.FindUnusedByCodeHash(ctx, user.ID, codeHash)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrInvalidTwoFactorCode
+		}
+		return err
+	}
+
+	used, err := uu.rcr.MarkUsed(ctx, recoveryCode.ID, time.Now())
+	if err != nil {
+		return err
+	}
+	if !used {
+		// consumed concurrently by another login
+		return ErrInvalidTwoFactorCode
+	}
+
 	return nil
 }
@@ -1,19 +1,42 @@
 package usecase
 
 import (
+	"errors"
+	"fmt"
+	"unicode"
+
 	"github.com/go-playground/validator/v10"
 )
 
+// PasswordPolicy defines the rules a user-chosen password must satisfy
+type PasswordPolicy struct {
+	MinLength     int
+	RequireUpper  bool
+	RequireLower  bool
+	RequireDigit  bool
+	RequireSymbol bool
+}
+
+// DefaultPasswordPolicy returns the policy used when nothing is configured
+func DefaultPasswordPolicy() PasswordPolicy {
+	return PasswordPolicy{MinLength: 8}
+}
+
+// maxPasswordBytes is the longest password bcrypt can hash; longer ones make it fail
+const maxPasswordBytes = 72
+
 type UserUsecaseValidator interface {
 	ValidateChangePassword(currentPassword, newPassword string) error
+	ValidatePassword(password string) error
 }
 
 type userUsecaseValidator struct {
 	validate *validator.Validate
+	policy   PasswordPolicy
 }
 
-func NewUserUsecaseValidator() UserUsecaseValidator {
-	return &userUsecaseValidator{validate: validator.New()}
+func NewUserUsecaseValidator(policy PasswordPolicy) UserUsecaseValidator {
+	return &userUsecaseValidator{validate: validator.New(), policy: policy}
 }
 
 func (uv *userUsecaseValidator) ValidateChangePassword(currentPassword, newPassword string) error {
@@ -22,5 +45,46 @@ func (uv *userUsecaseValidator) ValidateChangePassword(currentPassword, newPassw
 		NewPassword     string `validate:"nefield=CurrentPassword"`
 	}
 	req := changePasswordRequest{CurrentPassword: currentPassword, NewPassword: newPassword}
-	return uv.validate.Struct(req)
+	if err := uv.validate.Struct(req); err != nil {
+		return err
+	}
+	return uv.ValidatePassword(newPassword)
+}
+
+func (uv *userUsecaseValidator) ValidatePassword(password string) error {
+	if len([]rune(password)) < uv.policy.MinLength {
+		return fmt.Errorf("password must be at least %d characters", uv.policy.MinLength)
+	}
+	if len(password) > maxPasswordBytes {
+		return fmt.Errorf("password must be at most %d bytes", maxPasswordBytes)
+	}
+
+	var hasUpper, hasLower, hasDigit, hasSymbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r):
+			hasSymbol = true
+		}
+	}
+
+	if uv.policy.RequireUpper && !hasUpper {
+		return errors.New("password must contain an uppercase letter")
+	}
+	if uv.policy.RequireLower && !hasLower {
+		return errors.New("password must contain a lowercase letter")
+	}
+	if uv.policy.RequireDigit && !hasDigit {
+		return errors.New("password must contain a digit")
+	}
+	if uv.policy.RequireSymbol && !hasSymbol {
+		return errors.New("password must contain a symbol")
+	}
+
+	return nil
 }
@@ -0,0 +1,126 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"trip_app/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// VerificationPolicy controls the lifetime of verification tokens and how often they can be resent.
+// A zero ResendCooldown or MaxResendsPerDay disables that limit.
+type VerificationPolicy struct {
+	// TokenTTL is the lifetime of a verification token
+	TokenTTL time.Duration
+	// ResendCooldown is the minimum time between two verification emails to the same address
+	ResendCooldown time.Duration
+	// MaxResendsPerDay caps the resends per address within a rolling 24 hour window
+	MaxResendsPerDay int
+}
+
+// DefaultVerificationPolicy returns the policy used when nothing is configured
+func DefaultVerificationPolicy() VerificationPolicy {
+	return VerificationPolicy{
+		TokenTTL:         30 * time.Minute,
+		ResendCooldown:   time.Minute,
+		MaxResendsPerDay: 5,
+	}
+}
+
+// verificationResendWindow is the window MaxResendsPerDay is counted in
+const verificationResendWindow = 24 * time.Hour
+
+var ErrTooManyVerificationEmails = errors.New("too many verification emails requested. please try again later")
+
+// VerificationResendThrottledError is returned while the address is in its cooldown or over
+// its daily cap. RetryAfter tells the client how long to wait.
+type VerificationResendThrottledError struct {
+	RetryAfter time.Duration
+}
+
+func (e *VerificationResendThrottledError) Error() string {
+	return ErrTooManyVerificationEmails.Error()
+}
+
+func (e *VerificationResendThrottledError) Unwrap() error {
+	return ErrTooManyVerificationEmails
+}
+
+func (uu *userUsecase) ResendVerificationEmail(ctx context.Context, email string) error {
+	user, err := uu.ur.FindByEmail(ctx, email)
+	if err != nil {
+		// do not reveal whether the email is registered
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	// nothing to verify any more
+	if user.IsActive {
+		return nil
+	}
+
+	policy := uu.cfg.Verification
+	now := time.Now()
+	if err := uu.reserveVerificationEmail(user, now); err != nil {
+		return err
+	}
+
+	// the legacy flow emails a fresh initial password with every token;
+	// otherwise the placeholder password stays as it is
+	rawPassword := ""
+	if uu.cfg.LegacyInitialPassword {
+		var hashPassword string
+		rawPassword, hashPassword, err = uu.up.GeneratePassword()
+		if err != nil {
+			return err
+		}
+		user.PasswordHash = hashPassword
+	}
+
+	// a new token replaces the old one, so only the latest email can be used
+	rawToken, hashToken, err := uu.us.GenerateToken()
+	if err != nil {
+		return err
+	}
+	expiresAt := now.Add(policy.TokenTTL)
+
+	user.VerificationTokenHash = &hashToken
+	user.VerificationTokenExpiresAt = &expiresAt
+
+	if err := uu.ur.Update(ctx, user); err != nil {
+		return err
+	}
+
+	return uu.ue.SendVerificationEmail(ctx, user.Email, rawToken, rawPassword)
+}
+
+// reserveVerificationEmail applies the resend cooldown and daily cap to a verification email for the user,
+// which is inactive. If another email may be sent now, it counts it on the user; the caller saves the user.
+func (uu *userUsecase) reserveVerificationEmail(user *domain.User, now time.Time) error {
+	policy := uu.cfg.Verification
+
+	if policy.ResendCooldown > 0 && user.VerificationSentAt != nil {
+		if next := user.VerificationSentAt.Add(policy.ResendCooldown); now.Before(next) {
+			return &VerificationResendThrottledError{RetryAfter: next.Sub(now)}
+		}
+	}
+
+	// start a new window once the previous one is over
+	if user.VerificationResendWindowStartedAt == nil || !now.Before(user.VerificationResendWindowStartedAt.Add(verificationResendWindow)) {
+		user.VerificationResendWindowStartedAt = &now
+		user.VerificationResendCount = 0
+	}
+	if policy.MaxResendsPerDay > 0 && user.VerificationResendCount >= policy.MaxResendsPerDay {
+		windowEnd := user.VerificationResendWindowStartedAt.Add(verificationResendWindow)
+		return &VerificationResendThrottledError{RetryAfter: windowEnd.Sub(now)}
+	}
+
+	user.VerificationSentAt = &now
+	user.VerificationResendCount++
+	return nil
+}
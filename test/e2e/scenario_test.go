@@ -2,23 +2,38 @@ package test
 
 import (
 	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"io"
+	"math/big"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"os"
+	"regexp"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"trip_app/api"
 	"trip_app/internal/domain"
 	"trip_app/internal/handler"
+	"trip_app/internal/infrastructure/oidc"
 	"trip_app/internal/middleware"
 	"trip_app/internal/repository"
 	"trip_app/internal/security"
 	"trip_app/internal/usecase"
 	"trip_app/test/mock"
 
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -29,7 +44,6 @@ import (
 var (
 	testDB          *gorm.DB
 	testServer      *echo.Echo
-	jwtSecret       = "test-secret-key-for-testing-only"
 	mockEmailSender *mock.MockEmailSender
 )
 
@@ -41,7 +55,7 @@ func setupTestDB(t *testing.T) {
 	}
 
 	var err error
-	testDB, err = gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	testDB, err = gorm.Open(postgres.Open(dsn), &gorm.Config{TranslateError: true})
 	require.NoError(t, err, "Failed to connect to test database")
 
 	err = testDB.AutoMigrate(
@@ -52,91 +66,233 @@ func setupTestDB(t *testing.T) {
 		&domain.Member{},
 		&domain.Schedule{},
 		&domain.ShareToken{},
+		&domain.TripCollaborator{},
+		&domain.TripInvitation{},
+		&domain.ShareLinkAccess{},
+		&domain.CalendarFeed{},
+		&domain.ScheduleImport{},
 	)
 	require.NoError(t, err, "Failed to migrate database")
 }
 
 // cleanupTestDB はテスト後に全テーブルをクリーンアップ
 func cleanupTestDB(t *testing.T) {
-	testDB.Exec("TRUNCATE TABLE schedules, share_tokens, trips, users RESTART IDENTITY CASCADE")
+	testDB.Exec("TRUNCATE TABLE schedule_imports, calendar_feeds, share_link_accesses, trip_invitations, trip_collaborators, schedules, share_tokens, trips, users RESTART IDENTITY CASCADE")
+}
+
+// testServerConfig はテスト用HTTPサーバーのユースケース設定
+type testServerConfig struct {
+	User    usecase.UserUsecaseConfig
+	Account usecase.AccountUsecaseConfig
+	// Keys はJWTの署名鍵セット（nilの場合はテスト用のEd25519鍵を生成）
+	Keys security.KeySet
+	// OIDCProviders はログインに使用できるOpenID Connectプロバイダー（キーはURLの{provider}）
+	OIDCProviders map[string]oidc.Provider
+}
+
+// defaultTestServerConfig は本番と同じデフォルト設定を返す
+func defaultTestServerConfig() testServerConfig {
+	return testServerConfig{
+		User: usecase.UserUsecaseConfig{
+			LoginThrottle: usecase.DefaultLoginThrottlePolicy(),
+			Verification:  usecase.DefaultVerificationPolicy(),
+		},
+		Account: usecase.AccountUsecaseConfig{DeletionGracePeriod: usecase.DefaultAccountDeletionGracePeriod},
+	}
 }
 
 // setupTestServer はテスト用HTTPサーバーを構築（全層を初期化）
 func setupTestServer(t *testing.T) {
+	setupTestServerWithConfig(t, defaultTestServerConfig())
+}
+
+// setupTestServerWithConfig は設定を指定してテスト用HTTPサーバーを構築
+func setupTestServerWithConfig(t *testing.T, cfg testServerConfig) {
+	keys := cfg.Keys
+	if keys == nil {
+		keys = newTestKeySet(t, generateEd25519TestKey(t, "test-key"))
+	}
+
 	userRepo := repository.NewUserRepository(testDB)
+	sessionRepo := mock.NewInMemorySessionRepository()
+	refreshTokenRepo := mock.NewInMemoryRefreshTokenRepository()
+	personalAccessTokenRepo := mock.NewInMemoryPersonalAccessTokenRepository()
+	recoveryCodeRepo := mock.NewInMemoryRecoveryCodeRepository()
+	loginAttemptRepo := mock.NewInMemoryLoginAttemptRepository()
+	userIdentityRepo := mock.NewInMemoryUserIdentityRepository()
+	oidcAuthRequestRepo := mock.NewInMemoryOIDCAuthRequestRepository()
 	tripRepo := repository.NewTripRepository(testDB)
+	tripCollaboratorRepo := repository.NewTripCollaboratorRepository(testDB)
+	tripInvitationRepo := repository.NewTripInvitationRepository(testDB)
+	memberRepo := repository.NewMemberRepository(testDB)
 	scheduleRepo := repository.NewScheduleRepository(testDB)
 	shareTokenRepo := repository.NewShareTokenRepository(testDB)
 	publicTripRepo := repository.NewPublicTripRepository(testDB)
+	shareLinkAccessRepo := repository.NewShareLinkAccessRepository(testDB)
+	calendarFeedRepo := repository.NewCalendarFeedRepository(testDB)
+	scheduleImportRepo := repository.NewScheduleImportRepository(testDB)
 
 	passwordGenerator := security.NewPasswordGenerator()
 	tokenGenerator := security.NewTokenGenerator()
-	authTokenGenerator := security.NewAuthTokenGenerator(jwtSecret)
+	authTokenGenerator := security.NewAuthTokenGenerator(keys)
+	totpGenerator := security.NewTOTPGenerator("Trip App")
 	mockEmailSender = mock.NewMockEmailSender()
 
-	userValidator := usecase.NewUserUsecaseValidator()
+	userValidator := usecase.NewUserUsecaseValidator(usecase.DefaultPasswordPolicy())
 	scheduleUsecaseValidator := usecase.NewScheduleUsecaseValidator()
 	userHandlerValidator := handler.NewUserHandlerValidator()
 	scheduleHandlerValidator := handler.NewScheduleHandlerValidator()
 
-	userUsecase := usecase.NewUserUsecase(userRepo, userValidator, passwordGenerator, tokenGenerator, authTokenGenerator, mockEmailSender)
-	tripUsecase := usecase.NewTripUsecase(tripRepo, tokenGenerator)
+	userUsecase := usecase.NewUserUsecase(userRepo, sessionRepo, refreshTokenRepo, recoveryCodeRepo, loginAttemptRepo, userIdentityRepo, oidcAuthRequestRepo, userValidator, passwordGenerator, tokenGenerator, authTokenGenerator, totpGenerator, mockEmailSender, cfg.OIDCProviders, cfg.User)
+	accountUsecase := usecase.NewAccountUsecase(userRepo, tripRepo, shareTokenRepo, sessionRepo, personalAccessTokenRepo, calendarFeedRepo, passwordGenerator, cfg.Account)
+	personalAccessTokenUsecase := usecase.NewPersonalAccessTokenUsecase(personalAccessTokenRepo, tokenGenerator)
+	tripUsecase := usecase.NewTripUsecase(tripRepo, tripCollaboratorRepo, tokenGenerator)
+	tripCollaboratorUsecase := usecase.NewTripCollaboratorUsecase(tripCollaboratorRepo, tripRepo, userRepo)
+	tripInvitationUsecase := usecase.NewTripInvitationUsecase(tripInvitationRepo, tripRepo, tripCollaboratorRepo, userRepo, tokenGenerator, mockEmailSender)
+	tripMemberUsecase := usecase.NewTripMemberUsecase(memberRepo, tripRepo)
+	tripBackupUsecase := usecase.NewTripBackupUsecase(tripRepo, scheduleUsecaseValidator)
 	scheduleUsecase := usecase.NewScheduleUsecase(scheduleRepo, scheduleUsecaseValidator)
-	shareTokenUsecase := usecase.NewShareTokenUsecase(shareTokenRepo, tokenGenerator)
-	publicTripUsecase := usecase.NewPublicTripUsecase(publicTripRepo, tokenGenerator)
+	scheduleImportUsecase := usecase.NewScheduleImportUsecase(scheduleUsecase, scheduleImportRepo, scheduleUsecaseValidator)
+	scheduleCSVUsecase := usecase.NewScheduleCSVUsecase(scheduleRepo, scheduleUsecaseValidator)
+	shareTokenUsecase := usecase.NewShareTokenUsecase(shareTokenRepo, tokenGenerator, passwordGenerator)
+	publicTripUsecase := usecase.NewPublicTripUsecase(publicTripRepo, shareTokenRepo, loginAttemptRepo, tokenGenerator, passwordGenerator, authTokenGenerator, cfg.User.LoginThrottle)
+	shareActivityUsecase := usecase.NewShareActivityUsecase(shareLinkAccessRepo, usecase.ShareActivityConfig{IPHashKey: []byte("test-ip-hash-key")})
+	calendarFeedUsecase := usecase.NewCalendarFeedUsecase(calendarFeedRepo, tripRepo, tokenGenerator)
+
+	// アクセスログの書き込みはテスト終了時に停止する
+	activityCtx, stopActivity := context.WithCancel(context.Background())
+	t.Cleanup(stopActivity)
+	go shareActivityUsecase.Run(activityCtx)
 
 	h := handler.NewHandler(
 		userUsecase,
+		accountUsecase,
+		personalAccessTokenUsecase,
 		tripUsecase,
+		tripCollaboratorUsecase,
+		tripInvitationUsecase,
+		tripMemberUsecase,
+		tripBackupUsecase,
 		scheduleUsecase,
+		scheduleImportUsecase,
+		scheduleCSVUsecase,
 		shareTokenUsecase,
+		shareActivityUsecase,
 		publicTripUsecase,
+		calendarFeedUsecase,
+		keys,
 		userHandlerValidator,
 		scheduleHandlerValidator,
 	)
-
-	tripOwnershipMiddleware := middleware.TripOwnershipMiddleware(tripUsecase)
-	authMiddleware := middleware.AuthMiddleware(jwtSecret)
-	shareTokenOwnershipMiddleware := middleware.ShareTokenOwnershipMiddleware(publicTripUsecase)
+	publicPageHandler := handler.NewPublicPageHandler(publicTripUsecase, shareActivityUsecase)
+
+	tripPermissionMiddleware := middleware.TripPermissionMiddleware(tripUsecase)
+	tripOwnerOnlyMiddleware := middleware.TripRoleMiddleware(domain.TripRoleOwner)
+	authMiddleware := middleware.AuthMiddleware(keys, userUsecase, personalAccessTokenUsecase)
+	sessionOnlyMiddleware := middleware.SessionOnlyMiddleware()
+	tripsScopeMiddleware := middleware.ScopeMiddleware(domain.ScopeTripsRead, domain.ScopeTripsWrite)
+	shareViewMiddleware := middleware.ShareTokenOwnershipMiddleware(publicTripUsecase, shareActivityUsecase, domain.ShareScopeView)
+	shareVisitMiddleware := middleware.ShareTokenVisitMiddleware(publicTripUsecase, shareActivityUsecase)
+	shareEditSchedulesMiddleware := middleware.ShareTokenOwnershipMiddleware(publicTripUsecase, shareActivityUsecase, domain.ShareScopeEditSchedules)
+	shareEditTripMiddleware := middleware.ShareTokenOwnershipMiddleware(publicTripUsecase, shareActivityUsecase, domain.ShareScopeEditTrip)
 
 	e := echo.New()
+	e.IPExtractor = echo.ExtractIPDirect()
 	wrapper := &api.ServerInterfaceWrapper{Handler: h}
 
 	e.POST("/login", wrapper.LoginUser)
+	e.POST("/login/2fa", wrapper.LoginWithTwoFactor)
+	e.POST("/auth/refresh", wrapper.RefreshAuthToken)
+	e.GET("/.well-known/jwks.json", wrapper.GetJWKS)
+	e.GET("/auth/oidc/:provider/start", wrapper.StartOIDCLogin)
+	e.GET("/auth/oidc/:provider/callback", wrapper.HandleOIDCCallback)
 	e.POST("/signup", wrapper.CreateUser)
+	e.POST("/users/verify/resend", wrapper.ResendVerificationEmail)
 	e.POST("/users/verify/:verificationToken", wrapper.VerifyUser)
+	e.POST("/users/email/confirm/:emailChangeToken", wrapper.ConfirmEmailChange)
+	e.POST("/password/forgot", wrapper.RequestPasswordReset)
+	e.POST("/password/reset", wrapper.ResetPassword)
 
 	publicTripGroup := e.Group("/public/trips/:shareToken")
-	publicTripGroup.Use(shareTokenOwnershipMiddleware)
-	publicTripGroup.GET("", wrapper.GetPublicTripByShareToken)
-	publicTripGroup.PUT("", wrapper.UpdatePublicTripByShareToken)
-	publicTripGroup.GET("/details", wrapper.GetTripDetailsForPublicTrip)
-	publicTripGroup.GET("/schedules", wrapper.GetSchedulesForPublicTrip)
-	publicTripGroup.POST("/schedules", wrapper.AddScheduleToPublicTrip)
-	publicTripGroup.GET("/schedules/:scheduleId", wrapper.GetScheduleForPublicTrip)
-	publicTripGroup.PATCH("/schedules/:scheduleId", wrapper.UpdateScheduleForPublicTrip)
-	publicTripGroup.DELETE("/schedules/:scheduleId", wrapper.DeleteScheduleForPublicTrip)
+	publicTripGroup.POST("/unlock", wrapper.UnlockPublicTrip)
+	publicTripGroup.GET("", wrapper.GetPublicTripByShareToken, shareVisitMiddleware)
+	publicTripGroup.PUT("", wrapper.UpdatePublicTripByShareToken, shareEditTripMiddleware)
+	publicTripGroup.GET("/details", wrapper.GetTripDetailsForPublicTrip, shareViewMiddleware)
+	publicTripGroup.GET("/schedules", wrapper.GetSchedulesForPublicTrip, shareViewMiddleware)
+	publicTripGroup.POST("/schedules", wrapper.AddScheduleToPublicTrip, shareEditSchedulesMiddleware)
+	publicTripGroup.GET("/schedules/:scheduleId", wrapper.GetScheduleForPublicTrip, shareViewMiddleware)
+	publicTripGroup.PATCH("/schedules/:scheduleId", wrapper.UpdateScheduleForPublicTrip, shareEditSchedulesMiddleware)
+	publicTripGroup.DELETE("/schedules/:scheduleId", wrapper.DeleteScheduleForPublicTrip, shareEditSchedulesMiddleware)
+	publicTripGroup.GET("/calendar.ics", wrapper.GetPublicTripCalendar, shareViewMiddleware)
+
+	e.GET("/p/:shareToken", publicPageHandler.GetPublicTripPage)
+	e.POST("/p/:shareToken/unlock", publicPageHandler.UnlockPublicTripPage)
+	e.GET("/static/*", echo.WrapHandler(handler.PublicPageStatic()))
+	e.GET("/calendar-feeds/:feedToken/calendar.ics", wrapper.GetCalendarFeedCalendar)
 
 	authRequired := e.Group("")
 	authRequired.Use(authMiddleware)
-	authRequired.POST("/logout", wrapper.LogoutUser)
-	authRequired.GET("/me", wrapper.GetMe)
-	authRequired.PUT("/me/password", wrapper.ChangePassword)
-	authRequired.GET("/trips", wrapper.GetUserTrips)
-	authRequired.POST("/trips", wrapper.CreateUserTrip)
-
-	tripOwnerGroup := authRequired.Group("/trips/:tripId")
-	tripOwnerGroup.Use(tripOwnershipMiddleware)
-	tripOwnerGroup.GET("", wrapper.GetUserTrip)
-	tripOwnerGroup.PUT("", wrapper.UpdateUserTrip)
-	tripOwnerGroup.DELETE("", wrapper.DeleteUserTrip)
-	tripOwnerGroup.GET("/details", wrapper.GetTripDetails)
-	tripOwnerGroup.GET("/schedules", wrapper.GetSchedulesForTrip)
-	tripOwnerGroup.POST("/schedules", wrapper.AddScheduleToTrip)
-	tripOwnerGroup.GET("/schedules/:scheduleId", wrapper.GetScheduleForTrip)
-	tripOwnerGroup.PATCH("/schedules/:scheduleId", wrapper.UpdateScheduleForTrip)
-	tripOwnerGroup.DELETE("/schedules/:scheduleId", wrapper.DeleteScheduleForTrip)
-	tripOwnerGroup.POST("/share", wrapper.CreateShareLinkForTrip)
+
+	sessionOnlyGroup := authRequired.Group("")
+	sessionOnlyGroup.Use(sessionOnlyMiddleware)
+	sessionOnlyGroup.POST("/logout", wrapper.LogoutUser)
+	sessionOnlyGroup.POST("/logout/all", wrapper.LogoutAllSessions)
+	sessionOnlyGroup.GET("/me", wrapper.GetMe)
+	sessionOnlyGroup.DELETE("/me", wrapper.DeleteMe)
+	sessionOnlyGroup.GET("/me/export", wrapper.ExportPersonalData)
+	sessionOnlyGroup.GET("/me/memberships", wrapper.ListMyMemberships)
+	sessionOnlyGroup.POST("/me/2fa/setup", wrapper.SetupTwoFactor)
+	sessionOnlyGroup.POST("/me/2fa/confirm", wrapper.ConfirmTwoFactor)
+	sessionOnlyGroup.PUT("/me/password", wrapper.ChangePassword)
+	sessionOnlyGroup.PUT("/me/email", wrapper.ChangeEmail)
+	sessionOnlyGroup.GET("/me/tokens", wrapper.ListPersonalAccessTokens)
+	sessionOnlyGroup.POST("/me/tokens", wrapper.CreatePersonalAccessToken)
+	sessionOnlyGroup.DELETE("/me/tokens/:tokenId", wrapper.RevokePersonalAccessToken)
+	sessionOnlyGroup.GET("/me/calendar-feed", wrapper.GetCalendarFeed)
+	sessionOnlyGroup.POST("/me/calendar-feed", wrapper.CreateCalendarFeed)
+	sessionOnlyGroup.DELETE("/me/calendar-feed", wrapper.RevokeCalendarFeed)
+	sessionOnlyGroup.POST("/invitations/:invitationToken/accept", wrapper.AcceptTripInvitation)
+	sessionOnlyGroup.POST("/public/trips/:shareToken/members/:memberId/claim", wrapper.ClaimMemberForPublicTrip, shareEditTripMiddleware)
+
+	tripsGroup := authRequired.Group("/trips")
+	tripsGroup.Use(tripsScopeMiddleware)
+	tripsGroup.GET("", wrapper.GetUserTrips)
+	tripsGroup.POST("", wrapper.CreateUserTrip)
+	tripsGroup.POST("/import", wrapper.ImportTrip)
+
+	tripGroup := tripsGroup.Group("/:tripId")
+	tripGroup.Use(tripPermissionMiddleware)
+	tripGroup.GET("", wrapper.GetUserTrip)
+	tripGroup.PUT("", wrapper.UpdateUserTrip)
+	tripGroup.DELETE("", wrapper.DeleteUserTrip, tripOwnerOnlyMiddleware)
+	tripGroup.GET("/details", wrapper.GetTripDetails)
+	tripGroup.GET("/calendar.ics", wrapper.GetTripCalendar)
+	tripGroup.GET("/export", wrapper.ExportTrip)
+	tripGroup.POST("/members", wrapper.AddTripMember)
+	tripGroup.PATCH("/members/:memberId", wrapper.UpdateTripMember)
+	tripGroup.DELETE("/members/:memberId", wrapper.DeleteTripMember)
+	tripGroup.GET("/schedules", wrapper.GetSchedulesForTrip)
+	tripGroup.POST("/schedules", wrapper.AddScheduleToTrip)
+	tripGroup.GET("/schedules.csv", wrapper.ExportSchedulesCsv)
+	tripGroup.POST("/schedules.csv", wrapper.ImportSchedulesCsv)
+	tripGroup.POST("/schedules/import", wrapper.ImportSchedulesToTrip)
+	tripGroup.GET("/schedules/:scheduleId", wrapper.GetScheduleForTrip)
+	tripGroup.PATCH("/schedules/:scheduleId", wrapper.UpdateScheduleForTrip)
+	tripGroup.DELETE("/schedules/:scheduleId", wrapper.DeleteScheduleForTrip)
+	tripGroup.POST("/share", wrapper.CreateShareLinkForTrip, tripOwnerOnlyMiddleware)
+	tripGroup.GET("/share-links", wrapper.ListTripShareLinks, tripOwnerOnlyMiddleware)
+	tripGroup.POST("/share-links", wrapper.CreateTripShareLink, tripOwnerOnlyMiddleware)
+	tripGroup.DELETE("/share-links/:shareLinkId", wrapper.RevokeTripShareLink, tripOwnerOnlyMiddleware)
+	tripGroup.GET("/share/activity", wrapper.GetTripShareActivity, tripOwnerOnlyMiddleware)
+	tripGroup.GET("/collaborators", wrapper.ListTripCollaborators)
+	tripGroup.POST("/collaborators", wrapper.AddTripCollaborator, tripOwnerOnlyMiddleware)
+	tripGroup.PUT("/collaborators/:userId", wrapper.UpdateTripCollaborator, tripOwnerOnlyMiddleware)
+	tripGroup.DELETE("/collaborators/:userId", wrapper.RemoveTripCollaborator, tripOwnerOnlyMiddleware)
+	tripGroup.PUT("/owner", wrapper.TransferTripOwnership, tripOwnerOnlyMiddleware)
+	tripGroup.GET("/invitations", wrapper.ListTripInvitations, tripOwnerOnlyMiddleware)
+	tripGroup.POST("/invitations", wrapper.CreateTripInvitation, tripOwnerOnlyMiddleware)
+	tripGroup.DELETE("/invitations/:invitationId", wrapper.RevokeTripInvitation, tripOwnerOnlyMiddleware)
+	tripGroup.POST("/invitations/:invitationId/resend", wrapper.ResendTripInvitation, tripOwnerOnlyMiddleware)
 
 	testServer = e
 }
@@ -163,6 +319,106 @@ func makeRequest(t *testing.T, method, path string, body interface{}, token stri
 	return rec
 }
 
+// makeRequestFromIP は送信元IPを指定してHTTPリクエストを送信
+func makeRequestFromIP(t *testing.T, method, path string, body interface{}, ip string) *httptest.ResponseRecorder {
+	jsonBody, err := json.Marshal(body)
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(method, path, bytes.NewBuffer(jsonBody))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.RemoteAddr = ip + ":12345"
+
+	rec := httptest.NewRecorder()
+	testServer.ServeHTTP(rec, req)
+	return rec
+}
+
+// makeShareRequest はパスフレーズ付き共有リンクのアクセス許可トークンを付けてHTTPリクエストを送信
+func makeShareRequest(t *testing.T, method, path string, body interface{}, grant string) *httptest.ResponseRecorder {
+	var reqBody io.Reader
+	if body != nil {
+		jsonBody, err := json.Marshal(body)
+		require.NoError(t, err)
+		reqBody = bytes.NewBuffer(jsonBody)
+	}
+
+	req := httptest.NewRequest(method, path, reqBody)
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	if grant != "" {
+		req.Header.Set(middleware.ShareGrantHeader, grant)
+	}
+
+	rec := httptest.NewRecorder()
+	testServer.ServeHTTP(rec, req)
+	return rec
+}
+
+// makeShareVisitRequest は訪問の入口で発行された訪問トークンを付けてHTTPリクエストを送信
+func makeShareVisitRequest(t *testing.T, method, path string, body interface{}, visit string) *httptest.ResponseRecorder {
+	var reqBody io.Reader
+	if body != nil {
+		jsonBody, err := json.Marshal(body)
+		require.NoError(t, err)
+		reqBody = bytes.NewBuffer(jsonBody)
+	}
+
+	req := httptest.NewRequest(method, path, reqBody)
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	if visit != "" {
+		req.Header.Set(middleware.ShareVisitHeader, visit)
+	}
+
+	rec := httptest.NewRecorder()
+	testServer.ServeHTTP(rec, req)
+	return rec
+}
+
+// makePageRequest はブラウザと同様にHTMLページへリクエストを送信（フォームはURLエンコードで送信）
+func makePageRequest(t *testing.T, method, path string, form url.Values, cookies []*http.Cookie) *httptest.ResponseRecorder {
+	var reqBody io.Reader
+	if form != nil {
+		reqBody = strings.NewReader(form.Encode())
+	}
+
+	req := httptest.NewRequest(method, path, reqBody)
+	if form != nil {
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+	}
+	for _, cookie := range cookies {
+		req.AddCookie(cookie)
+	}
+
+	rec := httptest.NewRecorder()
+	testServer.ServeHTTP(rec, req)
+	return rec
+}
+
+// makeRawRequest はJSON以外のリクエストボディをそのまま送信
+func makeRawRequest(t *testing.T, method, path, contentType string, body io.Reader, token string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, body)
+	req.Header.Set(echo.HeaderContentType, contentType)
+	if token != "" {
+		req.Header.Set(echo.HeaderAuthorization, "Bearer "+token)
+	}
+
+	rec := httptest.NewRecorder()
+	testServer.ServeHTTP(rec, req)
+	return rec
+}
+
+// makeUploadRequest はファイルをmultipart/form-dataのfileフィールドでアップロード
+func makeUploadRequest(t *testing.T, path, filename string, content []byte, token string) *httptest.ResponseRecorder {
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+	part, err := writer.CreateFormFile("file", filename)
+	require.NoError(t, err)
+	_, err = part.Write(content)
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	return makeRawRequest(t, http.MethodPost, path, writer.FormDataContentType(), &buf, token)
+}
+
 // TestMain はテスト全体のエントリーポイント
 func TestMain(m *testing.M) {
 	code := m.Run()
@@ -188,19 +444,27 @@ func TestScenario_BasicUserFlow(t *testing.T) {
 	require.NoError(t, err)
 	assert.Contains(t, signupResp, "id")
 
-	// メール認証（モックから初回パスワードとトークンを取得）
+	// 初回パスワードはメールで送付されないべき
 	verificationToken := mockEmailSender.GetLastToken()
-	initialPassword := mockEmailSender.GetLastPassword()
 	require.NotEmpty(t, verificationToken, "Verification token should be captured by mock")
-	require.NotEmpty(t, initialPassword, "Initial password should be captured by mock")
+	assert.Empty(t, mockEmailSender.GetLastPassword())
 
+	// パスワード未指定での認証（失敗するべき）
 	rec = makeRequest(t, http.MethodPost, "/users/verify/"+verificationToken, nil, "")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	// ポリシーを満たさないパスワードでの認証（失敗するべき）
+	rec = makeRequest(t, http.MethodPost, "/users/verify/"+verificationToken, map[string]interface{}{"password": "short"}, "")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	// パスワードを設定してメール認証
+	rec = makeRequest(t, http.MethodPost, "/users/verify/"+verificationToken, map[string]interface{}{"password": "mypassword123"}, "")
 	assert.Equal(t, http.StatusOK, rec.Code)
 
-	// ログイン（初回パスワードを使用）
+	// ログイン（設定したパスワードを使用）
 	loginReq := map[string]interface{}{
 		"email":    "test@example.com",
-		"password": initialPassword,
+		"password": "mypassword123",
 	}
 	rec = makeRequest(t, http.MethodPost, "/login", loginReq, "")
 	assert.Equal(t, http.StatusOK, rec.Code)
@@ -439,21 +703,18 @@ func TestScenario_PasswordChangeFlow(t *testing.T) {
 
 	token := createAndLoginUser(t, "pwuser", "pw@example.com", "oldpassword")
 
-	// 初回パスワードを取得（mockから）
-	initialPassword := mockEmailSender.GetLastPassword()
-
 	// パスワードを変更
 	changeReq := map[string]interface{}{
-		"currentPassword": initialPassword,
+		"currentPassword": "oldpassword",
 		"newPassword":     "newpassword123",
 	}
 	rec := makeRequest(t, http.MethodPut, "/me/password", changeReq, token)
 	assert.Equal(t, http.StatusNoContent, rec.Code)
 
-	// 初回パスワードでログイン試行（失敗するべき）
+	// 変更前のパスワードでログイン試行（失敗するべき）
 	loginReq := map[string]interface{}{
 		"email":    "pw@example.com",
-		"password": initialPassword,
+		"password": "oldpassword",
 	}
 	rec = makeRequest(t, http.MethodPost, "/login", loginReq, "")
 	assert.Equal(t, http.StatusUnauthorized, rec.Code)
@@ -462,73 +723,2569 @@ func TestScenario_PasswordChangeFlow(t *testing.T) {
 	loginReq["password"] = "newpassword123"
 	rec = makeRequest(t, http.MethodPost, "/login", loginReq, "")
 	assert.Equal(t, http.StatusOK, rec.Code)
+
+	// パスワード変更前のトークンは失効しているべき
+	rec = makeRequest(t, http.MethodGet, "/me", nil, token)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
 }
 
-// ========================================
-// ヘルパー関数
-// ========================================
+// TestScenario_LogoutFlow はログアウトによるセッション失効をテスト
+func TestScenario_LogoutFlow(t *testing.T) {
+	setupTestDB(t)
+	defer cleanupTestDB(t)
+	setupTestServer(t)
 
-// createAndLoginUser はユーザー登録・認証・ログインを行いJWTトークンを返す
-func createAndLoginUser(t *testing.T, username, email, password string) string {
+	token := createAndLoginUser(t, "logoutuser", "logout@example.com", "password123")
+
+	// ログアウト前はアクセス可能
+	rec := makeRequest(t, http.MethodGet, "/me", nil, token)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	// ログアウト
+	rec = makeRequest(t, http.MethodPost, "/logout", nil, token)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+
+	// ログアウト後のトークンは拒否されるべき
+	rec = makeRequest(t, http.MethodGet, "/me", nil, token)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	// 2端末でログイン
+	loginReq := map[string]interface{}{
+		"email":    "logout@example.com",
+		"password": "password123",
+	}
+	token1 := loginAndGetToken(t, loginReq)
+	token2 := loginAndGetToken(t, loginReq)
+
+	// 全端末からログアウト
+	rec = makeRequest(t, http.MethodPost, "/logout/all", nil, token1)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+
+	// どちらのトークンも拒否されるべき
+	rec = makeRequest(t, http.MethodGet, "/me", nil, token1)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	rec = makeRequest(t, http.MethodGet, "/me", nil, token2)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+// TestScenario_RefreshTokenFlow はリフレッシュトークンのローテーションと再利用検知をテスト
+func TestScenario_RefreshTokenFlow(t *testing.T) {
+	setupTestDB(t)
+	defer cleanupTestDB(t)
+	setupTestServer(t)
+
+	createAndLoginUser(t, "refreshuser", "refresh@example.com", "password123")
+
+	// ログインしてリフレッシュトークンを取得
+	loginReq := map[string]interface{}{
+		"email":    "refresh@example.com",
+		"password": "password123",
+	}
+	rec := makeRequest(t, http.MethodPost, "/login", loginReq, "")
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var loginResp map[string]interface{}
+	err := json.Unmarshal(rec.Body.Bytes(), &loginResp)
+	require.NoError(t, err)
+	refreshToken1 := loginResp["refreshToken"].(string)
+	assert.NotEmpty(t, refreshToken1)
+	assert.LessOrEqual(t, loginResp["expiresIn"].(float64), float64(15*60))
+
+	// リフレッシュトークンで新しいトークンを取得（ローテーション）
+	rec = makeRequest(t, http.MethodPost, "/auth/refresh", map[string]interface{}{"refreshToken": refreshToken1}, "")
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var refreshResp map[string]interface{}
+	err = json.Unmarshal(rec.Body.Bytes(), &refreshResp)
+	require.NoError(t, err)
+	accessToken2 := refreshResp["token"].(string)
+	refreshToken2 := refreshResp["refreshToken"].(string)
+	assert.NotEqual(t, refreshToken1, refreshToken2)
+
+	// 新しいアクセストークンでアクセス可能
+	rec = makeRequest(t, http.MethodGet, "/me", nil, accessToken2)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	// 使用済みのリフレッシュトークンを再利用（拒否されるべき）
+	rec = makeRequest(t, http.MethodPost, "/auth/refresh", map[string]interface{}{"refreshToken": refreshToken1}, "")
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	// 再利用検知によりファミリー全体が失効するため、最新のトークンも拒否されるべき
+	rec = makeRequest(t, http.MethodPost, "/auth/refresh", map[string]interface{}{"refreshToken": refreshToken2}, "")
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	rec = makeRequest(t, http.MethodGet, "/me", nil, accessToken2)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	// 不正なリフレッシュトークン（拒否されるべき）
+	rec = makeRequest(t, http.MethodPost, "/auth/refresh", map[string]interface{}{"refreshToken": "invalid"}, "")
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+// TestScenario_PasswordResetFlow はメールによるパスワード再設定をテスト
+func TestScenario_PasswordResetFlow(t *testing.T) {
+	setupTestDB(t)
+	defer cleanupTestDB(t)
+	setupTestServer(t)
+
+	token := createAndLoginUser(t, "resetuser", "reset@example.com", "password123")
+
+	// 未登録のメールアドレスでも同じレスポンスを返すべき
+	rec := makeRequest(t, http.MethodPost, "/password/forgot", map[string]interface{}{"email": "unknown@example.com"}, "")
+	assert.Equal(t, http.StatusAccepted, rec.Code)
+	unknownBody := rec.Body.String()
+	assert.Empty(t, mockEmailSender.GetLastPasswordResetToken())
+
+	// 登録済みのメールアドレスで再設定をリクエスト
+	rec = makeRequest(t, http.MethodPost, "/password/forgot", map[string]interface{}{"email": "reset@example.com"}, "")
+	assert.Equal(t, http.StatusAccepted, rec.Code)
+	assert.Equal(t, unknownBody, rec.Body.String())
+
+	// 再設定メールはバックグラウンドで送信される
+	require.Eventually(t, func() bool {
+		return mockEmailSender.GetLastPasswordResetToken() != ""
+	}, 5*time.Second, 10*time.Millisecond)
+	resetToken := mockEmailSender.GetLastPasswordResetToken()
+
+	// 不正なトークンでの再設定（失敗するべき）
+	rec = makeRequest(t, http.MethodPost, "/password/reset", map[string]interface{}{"token": "invalid", "newPassword": "resetpassword123"}, "")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	// パスワードを再設定
+	rec = makeRequest(t, http.MethodPost, "/password/reset", map[string]interface{}{"token": resetToken, "newPassword": "resetpassword123"}, "")
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+
+	// 既存のセッションは失効しているべき
+	rec = makeRequest(t, http.MethodGet, "/me", nil, token)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	// 同じトークンは再利用できないべき
+	rec = makeRequest(t, http.MethodPost, "/password/reset", map[string]interface{}{"token": resetToken, "newPassword": "anotherpassword123"}, "")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	// 新しいパスワードでログイン可能
+	loginReq := map[string]interface{}{
+		"email":    "reset@example.com",
+		"password": "resetpassword123",
+	}
+	rec = makeRequest(t, http.MethodPost, "/login", loginReq, "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+// TestScenario_LegacyInitialPasswordFlow は旧フロー（初回パスワードのメール送付）をテスト
+func TestScenario_LegacyInitialPasswordFlow(t *testing.T) {
+	setupTestDB(t)
+	defer cleanupTestDB(t)
+	cfg := defaultTestServerConfig()
+	cfg.User.LegacyInitialPassword = true
+	setupTestServerWithConfig(t, cfg)
+
+	// ユーザー登録
 	signupReq := map[string]interface{}{
-		"name":  username,
-		"email": email,
+		"name":  "legacyuser",
+		"email": "legacy@example.com",
 	}
 	rec := makeRequest(t, http.MethodPost, "/signup", signupReq, "")
-	require.Equal(t, http.StatusCreated, rec.Code)
+	assert.Equal(t, http.StatusCreated, rec.Code)
 
 	// メール認証（モックから初回パスワードとトークンを取得）
 	verificationToken := mockEmailSender.GetLastToken()
 	initialPassword := mockEmailSender.GetLastPassword()
 	require.NotEmpty(t, verificationToken)
 	require.NotEmpty(t, initialPassword)
+
+	// 旧フローではパスワード未指定でも認証できるべき
 	rec = makeRequest(t, http.MethodPost, "/users/verify/"+verificationToken, nil, "")
-	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, http.StatusOK, rec.Code)
 
 	// ログイン（初回パスワードを使用）
 	loginReq := map[string]interface{}{
-		"email":    email,
+		"email":    "legacy@example.com",
 		"password": initialPassword,
 	}
 	rec = makeRequest(t, http.MethodPost, "/login", loginReq, "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+// TestScenario_TwoFactorFlow はTOTPによる2段階認証の登録とログインをテスト
+func TestScenario_TwoFactorFlow(t *testing.T) {
+	setupTestDB(t)
+	defer cleanupTestDB(t)
+	setupTestServer(t)
+
+	token := createAndLoginUser(t, "totpuser", "totp@example.com", "password123")
+	totp := security.NewTOTPGenerator("Trip App")
+
+	// 登録開始前の確認（失敗するべき）
+	rec := makeRequest(t, http.MethodPost, "/me/2fa/confirm", map[string]interface{}{"code": "123456"}, token)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	// 2段階認証の登録開始
+	rec = makeRequest(t, http.MethodPost, "/me/2fa/setup", nil, token)
 	require.Equal(t, http.StatusOK, rec.Code)
 
-	var loginResp map[string]interface{}
-	err := json.Unmarshal(rec.Body.Bytes(), &loginResp)
+	var setupResp map[string]interface{}
+	err := json.Unmarshal(rec.Body.Bytes(), &setupResp)
 	require.NoError(t, err)
-	return loginResp["token"].(string)
-}
+	secret := setupResp["secret"].(string)
+	otpauthURI := setupResp["otpauthUri"].(string)
+	assert.True(t, strings.HasPrefix(otpauthURI, "otpauth://totp/"))
+	assert.Contains(t, otpauthURI, "secret="+secret)
 
-// createTrip は旅行を作成してIDを返す
-func createTrip(t *testing.T, token, title, startDate, endDate string) string {
-	tripReq := map[string]interface{}{
-		"title":     title,
-		"startDate": startDate,
-		"endDate":   endDate,
-		"members":   []interface{}{},
+	// 誤ったコードでの確認（失敗するべき）
+	wrongCode, err := totp.GenerateCode(secret, time.Now().Add(-10*time.Minute))
+	require.NoError(t, err)
+	rec = makeRequest(t, http.MethodPost, "/me/2fa/confirm", map[string]interface{}{"code": wrongCode}, token)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	// 正しいコードで有効化し、リカバリーコードを取得
+	confirmCode, err := totp.GenerateCode(secret, time.Now())
+	require.NoError(t, err)
+	rec = makeRequest(t, http.MethodPost, "/me/2fa/confirm", map[string]interface{}{"code": confirmCode}, token)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var confirmResp struct {
+		RecoveryCodes []string `json:"recoveryCodes"`
 	}
-	rec := makeRequest(t, http.MethodPost, "/trips", tripReq, token)
-	require.Equal(t, http.StatusCreated, rec.Code)
+	err = json.Unmarshal(rec.Body.Bytes(), &confirmResp)
+	require.NoError(t, err)
+	require.Len(t, confirmResp.RecoveryCodes, 10)
 
-	var tripResp map[string]interface{}
-	err := json.Unmarshal(rec.Body.Bytes(), &tripResp)
+	// 有効化後の再登録（競合するべき）
+	rec = makeRequest(t, http.MethodPost, "/me/2fa/setup", nil, token)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+
+	// パスワードのみではトークンは発行されず、チャレンジトークンが返るべき
+	loginReq := map[string]interface{}{
+		"email":    "totp@example.com",
+		"password": "password123",
+	}
+	challengeToken := loginAndGetChallengeToken(t, loginReq)
+
+	// チャレンジトークンはアクセストークンとして使用できないべき
+	rec = makeRequest(t, http.MethodGet, "/me", nil, challengeToken)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	// 使用済みのコードの再利用（拒否されるべき）
+	rec = makeRequest(t, http.MethodPost, "/login/2fa", map[string]interface{}{"challengeToken": challengeToken, "code": confirmCode}, "")
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	// 次の時間ステップのコードでログイン完了
+	nextCode, err := totp.GenerateCode(secret, time.Now().Add(30*time.Second))
 	require.NoError(t, err)
-	return tripResp["id"].(string)
+	rec = makeRequest(t, http.MethodPost, "/login/2fa", map[string]interface{}{"challengeToken": challengeToken, "code": nextCode}, "")
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var loginResp map[string]interface{}
+	err = json.Unmarshal(rec.Body.Bytes(), &loginResp)
+	require.NoError(t, err)
+	rec = makeRequest(t, http.MethodGet, "/me", nil, loginResp["token"].(string))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	// リカバリーコードでログイン（ハイフンなし・小文字でも受け付けるべき）
+	recoveryCode := confirmResp.RecoveryCodes[0]
+	challengeToken = loginAndGetChallengeToken(t, loginReq)
+	rec = makeRequest(t, http.MethodPost, "/login/2fa", map[string]interface{}{"challengeToken": challengeToken, "code": strings.ToLower(strings.ReplaceAll(recoveryCode, "-", ""))}, "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	// 使用済みのリカバリーコード（拒否されるべき）
+	challengeToken = loginAndGetChallengeToken(t, loginReq)
+	rec = makeRequest(t, http.MethodPost, "/login/2fa", map[string]interface{}{"challengeToken": challengeToken, "code": recoveryCode}, "")
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	// 不正なチャレンジトークン（拒否されるべき）
+	rec = makeRequest(t, http.MethodPost, "/login/2fa", map[string]interface{}{"challengeToken": token, "code": confirmResp.RecoveryCodes[1]}, "")
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
 }
 
-// createSchedule はスケジュールを作成してIDを返す
-func createSchedule(t *testing.T, token string, tripID string, title, date string) string {
-	scheduleReq := map[string]interface{}{
-		"title":         title,
-		"startDateTime": date + "T10:00:00Z",
-		"endDateTime":   date + "T12:00:00Z",
-		"memo":          "テストスケジュール",
+// TestScenario_LoginThrottleFlow はログイン失敗時のアカウント単位・IP単位のロックアウトをテスト
+func TestScenario_LoginThrottleFlow(t *testing.T) {
+	setupTestDB(t)
+	defer cleanupTestDB(t)
+	cfg := defaultTestServerConfig()
+	cfg.User.LoginThrottle = usecase.LoginThrottlePolicy{
+		MaxAccountFailures: 3,
+		MaxIPFailures:      5,
+		BaseLockout:        time.Minute,
+		MaxLockout:         time.Hour,
+		FailureWindow:      time.Hour,
 	}
-	rec := makeRequest(t, http.MethodPost, fmt.Sprintf("/trips/%s/schedules", tripID), scheduleReq, token)
-	require.Equal(t, http.StatusCreated, rec.Code)
+	setupTestServerWithConfig(t, cfg)
 
-	var scheduleResp map[string]interface{}
-	err := json.Unmarshal(rec.Body.Bytes(), &scheduleResp)
+	createAndLoginUser(t, "lockuser", "lock@example.com", "password123")
+	createAndLoginUser(t, "resetcountuser", "resetcount@example.com", "password123")
+	createAndLoginUser(t, "ipuser", "ip@example.com", "password123")
+
+	wrongReq := map[string]interface{}{"email": "lock@example.com", "password": "wrongpassword"}
+	correctReq := map[string]interface{}{"email": "lock@example.com", "password": "password123"}
+
+	// 上限回数まではパスワード誤りとして扱われるべき
+	for i := 0; i < 3; i++ {
+		rec := makeRequestFromIP(t, http.MethodPost, "/login", wrongReq, fmt.Sprintf("198.51.100.%d", i+1))
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	}
+
+	// ロック中は正しいパスワード・別のIPでも拒否され、Retry-Afterが返るべき
+	rec := makeRequestFromIP(t, http.MethodPost, "/login", correctReq, "198.51.100.10")
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	retryAfter, err := strconv.Atoi(rec.Header().Get("Retry-After"))
 	require.NoError(t, err)
-	return scheduleResp["id"].(string)
+	assert.Greater(t, retryAfter, 0)
+	assert.LessOrEqual(t, retryAfter, 60)
+
+	// ログイン成功で失敗回数がリセットされるべき
+	resetReq := map[string]interface{}{"email": "resetcount@example.com", "password": "wrongpassword"}
+	for i := 0; i < 2; i++ {
+		rec = makeRequestFromIP(t, http.MethodPost, "/login", resetReq, "203.0.113.1")
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	}
+	resetReq["password"] = "password123"
+	rec = makeRequestFromIP(t, http.MethodPost, "/login", resetReq, "203.0.113.2")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	resetReq["password"] = "wrongpassword"
+	for i := 0; i < 2; i++ {
+		rec = makeRequestFromIP(t, http.MethodPost, "/login", resetReq, "203.0.113.3")
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	}
+
+	// 同一IPから複数アカウントへの失敗（未登録のメールアドレスも含む）でIPがロックされるべき
+	for i := 0; i < 5; i++ {
+		probeReq := map[string]interface{}{"email": fmt.Sprintf("probe%d@example.com", i), "password": "wrongpassword"}
+		rec = makeRequestFromIP(t, http.MethodPost, "/login", probeReq, "192.0.2.100")
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	}
+	ipReq := map[string]interface{}{"email": "ip@example.com", "password": "password123"}
+	rec = makeRequestFromIP(t, http.MethodPost, "/login", ipReq, "192.0.2.100")
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.NotEmpty(t, rec.Header().Get("Retry-After"))
+
+	// 別のIPからは同じアカウントでログインできるべき
+	rec = makeRequestFromIP(t, http.MethodPost, "/login", ipReq, "192.0.2.101")
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+// TestScenario_EmailChangeFlow はメールアドレス変更と再認証をテスト
+func TestScenario_EmailChangeFlow(t *testing.T) {
+	setupTestDB(t)
+	defer cleanupTestDB(t)
+	setupTestServer(t)
+
+	token := createAndLoginUser(t, "changeuser", "change@example.com", "password123")
+	createAndLoginUser(t, "takenuser", "taken@example.com", "password123")
+
+	// 誤ったパスワード（失敗するべき）
+	rec := makeRequest(t, http.MethodPut, "/me/email", map[string]interface{}{"currentPassword": "wrongpassword", "newEmail": "new@example.com"}, token)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	// 使用中のメールアドレス（競合するべき）
+	rec = makeRequest(t, http.MethodPut, "/me/email", map[string]interface{}{"currentPassword": "password123", "newEmail": "taken@example.com"}, token)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+
+	// 現在と同じメールアドレス（失敗するべき）
+	rec = makeRequest(t, http.MethodPut, "/me/email", map[string]interface{}{"currentPassword": "password123", "newEmail": "change@example.com"}, token)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	// 変更をリクエスト（旧メールアドレスに通知が送られるべき）
+	rec = makeRequest(t, http.MethodPut, "/me/email", map[string]interface{}{"currentPassword": "password123", "newEmail": "race@example.com"}, token)
+	assert.Equal(t, http.StatusAccepted, rec.Code)
+	assert.Equal(t, "change@example.com", mockEmailSender.GetLastEmailChangeNotice())
+	raceToken := mockEmailSender.GetLastEmailChangeToken()
+	require.NotEmpty(t, raceToken)
+
+	// 確定前は旧メールアドレスのままログインできるべき
+	rec = makeRequest(t, http.MethodPost, "/login", map[string]interface{}{"email": "race@example.com", "password": "password123"}, "")
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	rec = makeRequest(t, http.MethodPost, "/login", map[string]interface{}{"email": "change@example.com", "password": "password123"}, "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	// 確定前に別ユーザーが同じメールアドレスでサインアップした場合、確定は競合するべき
+	rec = makeRequest(t, http.MethodPost, "/signup", map[string]interface{}{"name": "raceuser", "email": "race@example.com"}, "")
+	require.Equal(t, http.StatusCreated, rec.Code)
+	rec = makeRequest(t, http.MethodPost, "/users/email/confirm/"+raceToken, nil, "")
+	assert.Equal(t, http.StatusConflict, rec.Code)
+
+	// 別のメールアドレスへの変更をリクエストして確定
+	rec = makeRequest(t, http.MethodPut, "/me/email", map[string]interface{}{"currentPassword": "password123", "newEmail": "changed@example.com"}, token)
+	assert.Equal(t, http.StatusAccepted, rec.Code)
+	changeToken := mockEmailSender.GetLastEmailChangeToken()
+	require.NotEmpty(t, changeToken)
+
+	rec = makeRequest(t, http.MethodPost, "/users/email/confirm/"+changeToken, nil, "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	// 新しいメールアドレスでログインでき、旧メールアドレスではログインできないべき
+	rec = makeRequest(t, http.MethodPost, "/login", map[string]interface{}{"email": "changed@example.com", "password": "password123"}, "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	rec = makeRequest(t, http.MethodPost, "/login", map[string]interface{}{"email": "change@example.com", "password": "password123"}, "")
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	rec = makeRequest(t, http.MethodGet, "/me", nil, token)
+	require.Equal(t, http.StatusOK, rec.Code)
+	var meResp map[string]interface{}
+	err := json.Unmarshal(rec.Body.Bytes(), &meResp)
+	require.NoError(t, err)
+	assert.Equal(t, "changed@example.com", meResp["email"])
+
+	// 確認トークンは再利用できないべき
+	rec = makeRequest(t, http.MethodPost, "/users/email/confirm/"+changeToken, nil, "")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+// TestScenario_AccountExportFlow は個人データのエクスポートをテスト
+func TestScenario_AccountExportFlow(t *testing.T) {
+	setupTestDB(t)
+	defer cleanupTestDB(t)
+	setupTestServer(t)
+
+	token := createAndLoginUser(t, "exportuser", "export@example.com", "password123")
+	otherToken := createAndLoginUser(t, "otheruser", "other@example.com", "password123")
+	tripID := createTrip(t, token, "エクスポート旅行", "2025-05-01", "2025-05-03")
+	createSchedule(t, token, tripID, "観光", "2025-05-01")
+	createTrip(t, otherToken, "他人の旅行", "2025-06-01", "2025-06-02")
+
+	rec := makeRequest(t, http.MethodPost, fmt.Sprintf("/trips/%s/share", tripID), nil, token)
+	require.Equal(t, http.StatusCreated, rec.Code)
+	var shareResp map[string]interface{}
+	err := json.Unmarshal(rec.Body.Bytes(), &shareResp)
+	require.NoError(t, err)
+	shareToken := shareResp["shareToken"].(string)
+
+	// エクスポートはダウンロード用のファイルとして返るべき
+	rec = makeRequest(t, http.MethodGet, "/me/export", nil, token)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Header().Get(echo.HeaderContentDisposition), "attachment")
+
+	var export api.PersonalDataExport
+	err = json.Unmarshal(rec.Body.Bytes(), &export)
+	require.NoError(t, err)
+	assert.Equal(t, "export@example.com", string(*export.User.Email))
+
+	// 自分の旅行だけがスケジュールと共有リンクのメタデータ付きで含まれるべき
+	require.Len(t, export.Trips, 1)
+	assert.Equal(t, "エクスポート旅行", *export.Trips[0].Trip.Title)
+	require.Len(t, export.Trips[0].Schedules, 1)
+	assert.Equal(t, "観光", *export.Trips[0].Schedules[0].Title)
+	require.NotNil(t, export.Trips[0].ShareLinks)
+	require.Len(t, *export.Trips[0].ShareLinks, 1)
+	assert.Equal(t, "edit_trip", (*export.Trips[0].ShareLinks)[0].Scope)
+
+	// 共有トークンやパスワードハッシュは含まれないべき
+	assert.NotContains(t, rec.Body.String(), shareToken)
+	assert.NotContains(t, rec.Body.String(), "password")
+
+	// 認証なし（失敗するべき）
+	rec = makeRequest(t, http.MethodGet, "/me/export", nil, "")
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+// TestScenario_AccountDeletionFlow は猶予期間付きのアカウント削除と取り消しをテスト
+func TestScenario_AccountDeletionFlow(t *testing.T) {
+	setupTestDB(t)
+	defer cleanupTestDB(t)
+	setupTestServer(t)
+
+	token := createAndLoginUser(t, "deleteuser", "delete@example.com", "password123")
+	tripID := createTrip(t, token, "削除される旅行", "2025-05-01", "2025-05-03")
+	createSchedule(t, token, tripID, "観光", "2025-05-01")
+
+	rec := makeRequest(t, http.MethodPost, fmt.Sprintf("/trips/%s/share", tripID), nil, token)
+	require.Equal(t, http.StatusCreated, rec.Code)
+	var shareResp map[string]interface{}
+	err := json.Unmarshal(rec.Body.Bytes(), &shareResp)
+	require.NoError(t, err)
+	shareToken := shareResp["shareToken"].(string)
+
+	// パスワードなし・誤ったパスワード（失敗するべき）
+	rec = makeRequest(t, http.MethodDelete, "/me", map[string]interface{}{}, token)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	rec = makeRequest(t, http.MethodDelete, "/me", map[string]interface{}{"password": "wrongpassword"}, token)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	// 削除を予約（猶予期間後の日時が返るべき）
+	rec = makeRequest(t, http.MethodDelete, "/me", map[string]interface{}{"password": "password123"}, token)
+	require.Equal(t, http.StatusAccepted, rec.Code)
+	var deletionResp api.AccountDeletionResponse
+	err = json.Unmarshal(rec.Body.Bytes(), &deletionResp)
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(usecase.DefaultAccountDeletionGracePeriod), deletionResp.DeletionScheduledAt, time.Minute)
+
+	// 共有リンクとセッションは即時に失効するべき
+	rec = makeRequest(t, http.MethodGet, "/public/trips/"+shareToken, nil, "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	rec = makeRequest(t, http.MethodGet, "/me", nil, token)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	// 猶予期間内にログインすると削除が取り消されるべき
+	token = loginAndGetToken(t, map[string]interface{}{"email": "delete@example.com", "password": "password123"})
+	var user domain.User
+	require.NoError(t, testDB.Where("email = ?", "delete@example.com").First(&user).Error)
+	assert.Nil(t, user.DeletionScheduledAt)
+
+	// 再度削除を予約し、猶予期間が過ぎた後の削除処理で旅行ごと削除されるべき
+	rec = makeRequest(t, http.MethodDelete, "/me", map[string]interface{}{"password": "password123"}, token)
+	require.Equal(t, http.StatusAccepted, rec.Code)
+
+	accountUsecase := usecase.NewAccountUsecase(
+		repository.NewUserRepository(testDB),
+		repository.NewTripRepository(testDB),
+		repository.NewShareTokenRepository(testDB),
+		mock.NewInMemorySessionRepository(),
+		mock.NewInMemoryPersonalAccessTokenRepository(),
+		repository.NewCalendarFeedRepository(testDB),
+		security.NewPasswordGenerator(),
+		usecase.AccountUsecaseConfig{DeletionGracePeriod: usecase.DefaultAccountDeletionGracePeriod},
+	)
+	deleted, err := accountUsecase.PurgeScheduledDeletions(context.Background(), time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, 0, deleted)
+	deleted, err = accountUsecase.PurgeScheduledDeletions(context.Background(), time.Now().Add(usecase.DefaultAccountDeletionGracePeriod+time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, 1, deleted)
+
+	var count int64
+	testDB.Model(&domain.Trip{}).Where("id = ?", tripID).Count(&count)
+	assert.Equal(t, int64(0), count)
+	testDB.Model(&domain.Schedule{}).Where("trip_id = ?", tripID).Count(&count)
+	assert.Equal(t, int64(0), count)
+
+	rec = makeRequest(t, http.MethodPost, "/login", map[string]interface{}{"email": "delete@example.com", "password": "password123"}, "")
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+// TestScenario_AccountImmediateDeletionFlow は猶予期間なしのアカウント即時削除をテスト
+func TestScenario_AccountImmediateDeletionFlow(t *testing.T) {
+	setupTestDB(t)
+	defer cleanupTestDB(t)
+	cfg := defaultTestServerConfig()
+	cfg.Account.DeletionGracePeriod = 0
+	setupTestServerWithConfig(t, cfg)
+
+	token := createAndLoginUser(t, "instantuser", "instant@example.com", "password123")
+	tripID := createTrip(t, token, "即時削除される旅行", "2025-05-01", "2025-05-03")
+
+	rec := makeRequest(t, http.MethodDelete, "/me", map[string]interface{}{"password": "password123"}, token)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+
+	// ユーザーと旅行は削除済みであるべき
+	var count int64
+	testDB.Model(&domain.User{}).Where("email = ?", "instant@example.com").Count(&count)
+	assert.Equal(t, int64(0), count)
+	testDB.Model(&domain.Trip{}).Where("id = ?", tripID).Count(&count)
+	assert.Equal(t, int64(0), count)
+}
+
+// TestScenario_ResendVerificationFlow は認証メールの再送（再送間隔・1日の上限）をテスト
+func TestScenario_ResendVerificationFlow(t *testing.T) {
+	setupTestDB(t)
+	defer cleanupTestDB(t)
+	cfg := defaultTestServerConfig()
+	cfg.User.Verification.ResendCooldown = time.Minute
+	cfg.User.Verification.MaxResendsPerDay = 2
+	setupTestServerWithConfig(t, cfg)
+
+	// 前回の送信時刻を巻き戻して再送間隔を経過させる
+	rewindVerificationSentAt := func(email string) {
+		err := testDB.Model(&domain.User{}).Where("email = ?", email).
+			Update("verification_sent_at", time.Now().Add(-2*time.Minute)).Error
+		require.NoError(t, err)
+	}
+	resendReq := map[string]interface{}{"email": "resend@example.com"}
+
+	rec := makeRequest(t, http.MethodPost, "/signup", map[string]interface{}{"name": "resenduser", "email": "resend@example.com"}, "")
+	require.Equal(t, http.StatusCreated, rec.Code)
+	firstToken := mockEmailSender.GetLastToken()
+
+	// 登録直後の再送は再送間隔内のためメールを送信しないが、未認証のアカウントがあるとわからないよう同じレスポンスを返すべき
+	rec = makeRequest(t, http.MethodPost, "/users/verify/resend", resendReq, "")
+	assert.Equal(t, http.StatusAccepted, rec.Code)
+	assert.Empty(t, rec.Header().Get("Retry-After"))
+	assert.Equal(t, firstToken, mockEmailSender.GetLastToken())
+
+	// 再度の仮登録でも再送間隔を回避できないが、新規の仮登録と同じレスポンスを返すべき
+	signupReq := map[string]interface{}{"name": "resenduser", "email": "resend@example.com"}
+	rec = makeRequest(t, http.MethodPost, "/signup", signupReq, "")
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Empty(t, rec.Header().Get("Retry-After"))
+	assert.Equal(t, firstToken, mockEmailSender.GetLastToken())
+
+	// 未登録のメールアドレスでも同じレスポンスを返し、メールは送信しないべき
+	rec = makeRequest(t, http.MethodPost, "/users/verify/resend", map[string]interface{}{"email": "unknown@example.com"}, "")
+	assert.Equal(t, http.StatusAccepted, rec.Code)
+	assert.Equal(t, firstToken, mockEmailSender.GetLastToken())
+
+	// トークンの期限切れ後も再送で認証をやり直せるべき
+	err := testDB.Model(&domain.User{}).Where("email = ?", "resend@example.com").
+		Update("verification_token_expires_at", time.Now().Add(-time.Minute)).Error
+	require.NoError(t, err)
+	rec = makeRequest(t, http.MethodPost, "/users/verify/"+firstToken, map[string]interface{}{"password": "password123"}, "")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rewindVerificationSentAt("resend@example.com")
+	rec = makeRequest(t, http.MethodPost, "/users/verify/resend", resendReq, "")
+	assert.Equal(t, http.StatusAccepted, rec.Code)
+	secondToken := mockEmailSender.GetLastToken()
+	assert.NotEqual(t, firstToken, secondToken)
+
+	// 再度の仮登録も再送の回数に数えるべき
+	rewindVerificationSentAt("resend@example.com")
+	rec = makeRequest(t, http.MethodPost, "/signup", signupReq, "")
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	latestToken := mockEmailSender.GetLastToken()
+	assert.NotEqual(t, secondToken, latestToken)
+
+	// 1日の上限（2回）を超えた再送・仮登録はメールを送信せず、同じレスポンスを返すべき
+	rewindVerificationSentAt("resend@example.com")
+	rec = makeRequest(t, http.MethodPost, "/users/verify/resend", resendReq, "")
+	assert.Equal(t, http.StatusAccepted, rec.Code)
+	assert.Equal(t, latestToken, mockEmailSender.GetLastToken())
+	rec = makeRequest(t, http.MethodPost, "/signup", signupReq, "")
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Equal(t, latestToken, mockEmailSender.GetLastToken())
+
+	// 古いトークンは無効で、最新のトークンで認証できるべき
+	rec = makeRequest(t, http.MethodPost, "/users/verify/"+secondToken, map[string]interface{}{"password": "password123"}, "")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	rec = makeRequest(t, http.MethodPost, "/users/verify/"+latestToken, map[string]interface{}{"password": "password123"}, "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	loginAndGetToken(t, map[string]interface{}{"email": "resend@example.com", "password": "password123"})
+}
+
+// TestScenario_PersonalAccessTokenFlow はスコープ付きパーソナルアクセストークンの発行・利用・失効をテスト
+func TestScenario_PersonalAccessTokenFlow(t *testing.T) {
+	setupTestDB(t)
+	defer cleanupTestDB(t)
+	setupTestServer(t)
+
+	token := createAndLoginUser(t, "patuser", "pat@example.com", "password123")
+	tripID := createTrip(t, token, "PAT旅行", "2024-08-01", "2024-08-03")
+
+	// 読み取り専用トークンを発行できるべき（生のトークンは発行時のみ返る）
+	createToken := func(req map[string]interface{}) (string, string) {
+		rec := makeRequest(t, http.MethodPost, "/me/tokens", req, token)
+		require.Equal(t, http.StatusCreated, rec.Code)
+		var resp struct {
+			Token               string                 `json:"token"`
+			PersonalAccessToken map[string]interface{} `json:"personalAccessToken"`
+		}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		assert.True(t, strings.HasPrefix(resp.Token, usecase.PersonalAccessTokenPrefix))
+		return resp.Token, resp.PersonalAccessToken["id"].(string)
+	}
+	readToken, readTokenID := createToken(map[string]interface{}{"name": "読み取り", "scopes": []string{"trips:read"}})
+
+	// trips:read で旅行を参照できるべき
+	rec := makeRequest(t, http.MethodGet, "/trips", nil, readToken)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	rec = makeRequest(t, http.MethodGet, "/trips/"+tripID+"/schedules", nil, readToken)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	// trips:write がないため更新系は拒否されるべき
+	rec = makeRequest(t, http.MethodPost, "/trips", map[string]interface{}{
+		"title": "作成不可", "startDate": "2024-09-01", "endDate": "2024-09-02", "members": []interface{}{},
+	}, readToken)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	rec = makeRequest(t, http.MethodDelete, "/trips/"+tripID, nil, readToken)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	// アカウント系のエンドポイントはトークンでは利用できないべき
+	rec = makeRequest(t, http.MethodGet, "/me", nil, readToken)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	rec = makeRequest(t, http.MethodPost, "/me/tokens", map[string]interface{}{"name": "自己発行", "scopes": []string{"trips:write"}}, readToken)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	// 一覧には使用日時が記録され、トークンのハッシュは含まれないべき
+	rec = makeRequest(t, http.MethodGet, "/me/tokens", nil, token)
+	require.Equal(t, http.StatusOK, rec.Code)
+	var tokens []map[string]interface{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &tokens))
+	require.Len(t, tokens, 1)
+	assert.Equal(t, readTokenID, tokens[0]["id"])
+	assert.Equal(t, []interface{}{"trips:read"}, tokens[0]["scopes"])
+	assert.NotNil(t, tokens[0]["lastUsedAt"])
+	assert.NotContains(t, rec.Body.String(), readToken)
+
+	// trips:write で旅行を作成できるべき
+	writeToken, _ := createToken(map[string]interface{}{"name": "書き込み", "scopes": []string{"trips:read", "trips:write"}})
+	createTrip(t, writeToken, "トークンで作成", "2024-09-01", "2024-09-02")
+
+	// 未知のスコープや過去の有効期限は拒否されるべき
+	rec = makeRequest(t, http.MethodPost, "/me/tokens", map[string]interface{}{"name": "不正", "scopes": []string{"admin"}}, token)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	rec = makeRequest(t, http.MethodPost, "/me/tokens", map[string]interface{}{
+		"name": "期限切れ", "scopes": []string{"trips:read"}, "expiresAt": time.Now().Add(-time.Hour).Format(time.RFC3339),
+	}, token)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	// 有効期限を過ぎたトークンは認証に失敗するべき
+	tokenGenerator := security.NewTokenGenerator()
+	patRepo := mock.NewInMemoryPersonalAccessTokenRepository()
+	patUsecase := usecase.NewPersonalAccessTokenUsecase(patRepo, tokenGenerator)
+	expiredAt := time.Now().Add(-time.Minute)
+	rawExpiredToken := usecase.PersonalAccessTokenPrefix + "expired"
+	require.NoError(t, patRepo.Create(context.Background(), &domain.PersonalAccessToken{
+		ID:        uuid.New(),
+		UserID:    uuid.New(),
+		Name:      "期限切れ",
+		TokenHash: tokenGenerator.HashToken(rawExpiredToken),
+		Scopes:    domain.ScopeTripsRead,
+		ExpiresAt: &expiredAt,
+	}))
+	_, err := patUsecase.Authenticate(context.Background(), rawExpiredToken)
+	assert.ErrorIs(t, err, usecase.ErrInvalidPersonalAccessToken)
+
+	// 他のユーザーのトークンは失効できないべき
+	otherToken := createAndLoginUser(t, "otherpat", "otherpat@example.com", "password123")
+	rec = makeRequest(t, http.MethodDelete, "/me/tokens/"+readTokenID, nil, otherToken)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	// 失効したトークンは使えなくなるべき
+	rec = makeRequest(t, http.MethodDelete, "/me/tokens/"+readTokenID, nil, token)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	rec = makeRequest(t, http.MethodGet, "/trips", nil, readToken)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	rec = makeRequest(t, http.MethodDelete, "/me/tokens/"+readTokenID, nil, token)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+// TestScenario_SigningKeyRotationFlow はJWTの署名鍵ローテーションとJWKSの公開をテスト
+func TestScenario_SigningKeyRotationFlow(t *testing.T) {
+	setupTestDB(t)
+	defer cleanupTestDB(t)
+
+	// 旧鍵（Ed25519）は公開鍵のみを残して退役し、新鍵（RSA）で署名する
+	oldKey := generateEd25519TestKey(t, "2024-01-01")
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	newKey := security.Key{ID: "2024-07-01", PrivateKey: rsaKey}
+	cfg := defaultTestServerConfig()
+	cfg.Keys = newTestKeySet(t, security.Key{ID: oldKey.ID, PublicKey: oldKey.PrivateKey.Public()}, newKey)
+	setupTestServerWithConfig(t, cfg)
+
+	// JWKSには検証に使う全ての公開鍵が含まれ、秘密鍵の情報は含まれないべき
+	rec := makeRequest(t, http.MethodGet, "/.well-known/jwks.json", nil, "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.NotEmpty(t, rec.Header().Get("Cache-Control"))
+	var jwks struct {
+		Keys []map[string]interface{} `json:"keys"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &jwks))
+	require.Len(t, jwks.Keys, 2)
+	assert.Equal(t, "2024-01-01", jwks.Keys[0]["kid"])
+	assert.Equal(t, "OKP", jwks.Keys[0]["kty"])
+	assert.Equal(t, "EdDSA", jwks.Keys[0]["alg"])
+	assert.Equal(t, "2024-07-01", jwks.Keys[1]["kid"])
+	assert.Equal(t, "RSA", jwks.Keys[1]["kty"])
+	assert.Equal(t, "RS256", jwks.Keys[1]["alg"])
+	for _, key := range jwks.Keys {
+		assert.NotContains(t, key, "d")
+	}
+
+	// 新しく発行されるトークンは新鍵で署名され、kidヘッダーを持つべき
+	token := createAndLoginUser(t, "rotationuser", "rotation@example.com", "password123")
+	claims := &security.JwtCustomClaims{}
+	parsed, _, err := jwt.NewParser().ParseUnverified(token, claims)
+	require.NoError(t, err)
+	assert.Equal(t, "RS256", parsed.Method.Alg())
+	assert.Equal(t, "2024-07-01", parsed.Header["kid"])
+
+	// JWKSの公開鍵だけで外部サービスがトークンを検証できるべき
+	nBytes, err := base64.RawURLEncoding.DecodeString(jwks.Keys[1]["n"].(string))
+	require.NoError(t, err)
+	eBytes, err := base64.RawURLEncoding.DecodeString(jwks.Keys[1]["e"].(string))
+	require.NoError(t, err)
+	publishedKey := &rsa.PublicKey{N: new(big.Int).SetBytes(nBytes), E: int(new(big.Int).SetBytes(eBytes).Int64())}
+	_, err = jwt.Parse(token, func(*jwt.Token) (interface{}, error) { return publishedKey, nil }, jwt.WithValidMethods([]string{"RS256"}))
+	assert.NoError(t, err)
+
+	rec = makeRequest(t, http.MethodGet, "/me", nil, token)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	// ローテーション前に旧鍵で発行されたトークンは、期限まで引き続き検証できるべき
+	user := &domain.User{ID: uuid.MustParse(claims.UserID)}
+	sessionID := uuid.MustParse(claims.ID)
+	oldToken, _, err := security.NewAuthTokenGenerator(newTestKeySet(t, oldKey)).GenerateAccessToken(user, sessionID)
+	require.NoError(t, err)
+	rec = makeRequest(t, http.MethodGet, "/me", nil, oldToken)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	// 鍵セットに存在しない（削除済みの）鍵で署名されたトークンは拒否されるべき
+	retiredToken, _, err := security.NewAuthTokenGenerator(newTestKeySet(t, generateEd25519TestKey(t, "2023-07-01"))).GenerateAccessToken(user, sessionID)
+	require.NoError(t, err)
+	rec = makeRequest(t, http.MethodGet, "/me", nil, retiredToken)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	// 既知のkidでも鍵と異なるアルゴリズム（HS256）のトークンは拒否されるべき
+	hsToken := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	hsToken.Header["kid"] = "2024-07-01"
+	hsTokenString, err := hsToken.SignedString([]byte("test-secret-key-for-testing-only"))
+	require.NoError(t, err)
+	rec = makeRequest(t, http.MethodGet, "/me", nil, hsTokenString)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	// 検証専用の鍵は署名鍵として指定できないべき
+	_, err = security.NewKeySet([]security.Key{{ID: oldKey.ID, PublicKey: oldKey.PrivateKey.Public()}, newKey}, oldKey.ID)
+	assert.Error(t, err)
+}
+
+// TestScenario_OIDCLoginFlow はOpenID Connect（認可コード + PKCE）によるログインと外部アカウントの紐付けをテスト
+func TestScenario_OIDCLoginFlow(t *testing.T) {
+	setupTestDB(t)
+	defer cleanupTestDB(t)
+
+	// httptestで起動したモックプロバイダーをログイン先として登録
+	provider := mock.NewOIDCProvider("trip-app", "client-secret")
+	defer provider.Close()
+	cfg := defaultTestServerConfig()
+	cfg.OIDCProviders = map[string]oidc.Provider{
+		"mock": oidc.NewProvider(oidc.Config{
+			Name:         "mock",
+			Issuer:       provider.Issuer(),
+			ClientID:     "trip-app",
+			ClientSecret: "client-secret",
+			RedirectURL:  "http://localhost:8080/auth/oidc/mock/callback",
+		}, nil),
+	}
+	setupTestServerWithConfig(t, cfg)
+
+	// ログイン開始はPKCE（S256）付きで認可エンドポイントへリダイレクトするべき
+	rec := makeRequest(t, http.MethodGet, "/auth/oidc/mock/start", nil, "")
+	require.Equal(t, http.StatusFound, rec.Code)
+	authURL, err := url.Parse(rec.Header().Get("Location"))
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(authURL.String(), provider.Issuer()+"/authorize"))
+	assert.Equal(t, "S256", authURL.Query().Get("code_challenge_method"))
+	assert.NotEmpty(t, authURL.Query().Get("code_challenge"))
+	assert.NotEmpty(t, authURL.Query().Get("state"))
+	assert.NotEmpty(t, authURL.Query().Get("nonce"))
+	assert.NotContains(t, authURL.RawQuery, "code_verifier")
+
+	// 未登録のプロバイダー（見つからないべき）
+	rec = makeRequest(t, http.MethodGet, "/auth/oidc/unknown/start", nil, "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	// 確認済みメールアドレスの新規アカウントは、有効なユーザーとして作成されログインできるべき
+	provider.SignIn(mock.OIDCAccount{Subject: "sub-new", Email: "oidc-new@example.com", EmailVerified: true, Name: "OIDC User"})
+	callbackQuery := authorizeWithOIDC(t, "mock")
+	rec = makeRequest(t, http.MethodGet, "/auth/oidc/mock/callback?"+callbackQuery.Encode(), nil, "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	token := authTokenFromResponse(t, rec)
+	meResp := getMe(t, token)
+	assert.Equal(t, "oidc-new@example.com", meResp["email"])
+	assert.Equal(t, "OIDC User", meResp["name"])
+	assert.Equal(t, true, meResp["is_active"])
+
+	// 同じstateの再利用（拒否されるべき）
+	rec = makeRequest(t, http.MethodGet, "/auth/oidc/mock/callback?"+callbackQuery.Encode(), nil, "")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	// 同じ外部アカウントでの再ログインは同じユーザーになるべき
+	rec = loginWithOIDC(t, "mock")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, meResp["id"], getMe(t, authTokenFromResponse(t, rec))["id"])
+
+	// 別のログインのstateと組み合わせた認可コード（PKCEの検証で拒否されるべき）
+	first := authorizeWithOIDC(t, "mock")
+	second := authorizeWithOIDC(t, "mock")
+	swapped := url.Values{"code": {first.Get("code")}, "state": {second.Get("state")}}
+	rec = makeRequest(t, http.MethodGet, "/auth/oidc/mock/callback?"+swapped.Encode(), nil, "")
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	// 不正なstate・パラメータ不足（拒否されるべき）
+	rec = makeRequest(t, http.MethodGet, "/auth/oidc/mock/callback?code=abc&state=invalid", nil, "")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	rec = makeRequest(t, http.MethodGet, "/auth/oidc/mock/callback?state=invalid", nil, "")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	// プロバイダーで認可が拒否された場合（認証失敗になるべき）
+	provider.SignOut()
+	rec = loginWithOIDC(t, "mock")
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	// 確認済みメールアドレスは既存のパスワードユーザーに紐付き、パスワードログインも引き続き使えるべき
+	passwordToken := createAndLoginUser(t, "linkeduser", "linked@example.com", "password123")
+	provider.SignIn(mock.OIDCAccount{Subject: "sub-linked", Email: "linked@example.com", EmailVerified: true, Name: "Linked"})
+	rec = loginWithOIDC(t, "mock")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, getMe(t, passwordToken)["id"], getMe(t, authTokenFromResponse(t, rec))["id"])
+	loginAndGetToken(t, map[string]interface{}{"email": "linked@example.com", "password": "password123"})
+
+	// 認証待ちのアカウントは、確認済みメールアドレスでのログインで有効化されるべき
+	rec = makeRequest(t, http.MethodPost, "/signup", map[string]interface{}{"name": "pending", "email": "pending@example.com"}, "")
+	require.Equal(t, http.StatusCreated, rec.Code)
+	provider.SignIn(mock.OIDCAccount{Subject: "sub-pending", Email: "pending@example.com", EmailVerified: true})
+	rec = loginWithOIDC(t, "mock")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var pendingUser domain.User
+	require.NoError(t, testDB.Where("email = ?", "pending@example.com").First(&pendingUser).Error)
+	assert.True(t, pendingUser.IsActive)
+	assert.Nil(t, pendingUser.VerificationTokenHash)
+
+	// 未確認のメールアドレスでは既存アカウントに紐付けず、アカウントも作成しないべき
+	provider.SignIn(mock.OIDCAccount{Subject: "sub-attacker", Email: "linked@example.com", EmailVerified: false})
+	rec = loginWithOIDC(t, "mock")
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	provider.SignIn(mock.OIDCAccount{Subject: "sub-unverified", Email: "unverified@example.com", EmailVerified: false})
+	rec = loginWithOIDC(t, "mock")
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	var count int64
+	testDB.Model(&domain.User{}).Where("email = ?", "unverified@example.com").Count(&count)
+	assert.Equal(t, int64(0), count)
+
+	// 2段階認証が有効なユーザーは、OIDCでログインしてもチャレンジトークンが返るべき
+	totpToken := createAndLoginUser(t, "oidctotp", "oidc-totp@example.com", "password123")
+	rec = makeRequest(t, http.MethodPost, "/me/2fa/setup", nil, totpToken)
+	require.Equal(t, http.StatusOK, rec.Code)
+	var setupResp map[string]interface{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &setupResp))
+	code, err := security.NewTOTPGenerator("Trip App").GenerateCode(setupResp["secret"].(string), time.Now())
+	require.NoError(t, err)
+	rec = makeRequest(t, http.MethodPost, "/me/2fa/confirm", map[string]interface{}{"code": code}, totpToken)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	provider.SignIn(mock.OIDCAccount{Subject: "sub-totp", Email: "oidc-totp@example.com", EmailVerified: true})
+	rec = loginWithOIDC(t, "mock")
+	require.Equal(t, http.StatusAccepted, rec.Code)
+	var challengeResp map[string]interface{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &challengeResp))
+	assert.NotContains(t, challengeResp, "token")
+	assert.NotEmpty(t, challengeResp["challengeToken"])
+}
+
+// TestScenario_TripCollaboratorFlow は共同編集者の権限（owner・editor・viewer）と所有権の譲渡をテスト
+func TestScenario_TripCollaboratorFlow(t *testing.T) {
+	setupTestDB(t)
+	defer cleanupTestDB(t)
+	setupTestServer(t)
+
+	ownerToken := createAndLoginUser(t, "owner", "owner@example.com", "password123")
+	editorToken := createAndLoginUser(t, "editor", "editor@example.com", "password123")
+	viewerToken := createAndLoginUser(t, "viewer", "viewer@example.com", "password123")
+	outsiderToken := createAndLoginUser(t, "outsider", "outsider@example.com", "password123")
+	ownerID := getMe(t, ownerToken)["id"].(string)
+	editorID := getMe(t, editorToken)["id"].(string)
+	viewerID := getMe(t, viewerToken)["id"].(string)
+	outsiderID := getMe(t, outsiderToken)["id"].(string)
+
+	tripID := createTrip(t, ownerToken, "みんなの旅行", "2025-08-01", "2025-08-03")
+	tripPath := fmt.Sprintf("/trips/%s", tripID)
+
+	// 作成者はownerになるべき
+	rec := makeRequest(t, http.MethodGet, tripPath, nil, ownerToken)
+	require.Equal(t, http.StatusOK, rec.Code)
+	var tripResp map[string]interface{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &tripResp))
+	assert.Equal(t, "owner", tripResp["role"])
+
+	// 共同編集者の追加
+	rec = makeRequest(t, http.MethodPost, tripPath+"/collaborators", map[string]interface{}{"email": "editor@example.com", "role": "editor"}, ownerToken)
+	require.Equal(t, http.StatusCreated, rec.Code)
+	rec = makeRequest(t, http.MethodPost, tripPath+"/collaborators", map[string]interface{}{"email": "viewer@example.com", "role": "viewer"}, ownerToken)
+	require.Equal(t, http.StatusCreated, rec.Code)
+
+	// 重複・未登録ユーザー・ownerの付与・owner以外による追加（失敗するべき）
+	rec = makeRequest(t, http.MethodPost, tripPath+"/collaborators", map[string]interface{}{"email": "editor@example.com", "role": "viewer"}, ownerToken)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	rec = makeRequest(t, http.MethodPost, tripPath+"/collaborators", map[string]interface{}{"email": "nobody@example.com", "role": "viewer"}, ownerToken)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	rec = makeRequest(t, http.MethodPost, tripPath+"/collaborators", map[string]interface{}{"email": "outsider@example.com", "role": "owner"}, ownerToken)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	rec = makeRequest(t, http.MethodPost, tripPath+"/collaborators", map[string]interface{}{"email": "outsider@example.com", "role": "viewer"}, editorToken)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	// 共同編集している旅行も一覧に含まれ、権限が返るべき
+	rec = makeRequest(t, http.MethodGet, "/trips", nil, editorToken)
+	require.Equal(t, http.StatusOK, rec.Code)
+	var tripsResp []map[string]interface{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &tripsResp))
+	require.Len(t, tripsResp, 1)
+	assert.Equal(t, tripID, tripsResp[0]["id"])
+	assert.Equal(t, "editor", tripsResp[0]["role"])
+
+	// viewerは参照のみ可能
+	rec = makeRequest(t, http.MethodGet, tripPath+"/details", nil, viewerToken)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	rec = makeRequest(t, http.MethodGet, tripPath+"/schedules", nil, viewerToken)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	updateReq := map[string]interface{}{"title": "更新後の旅行", "startDate": "2025-08-01", "endDate": "2025-08-04", "members": []interface{}{}}
+	rec = makeRequest(t, http.MethodPut, tripPath, updateReq, viewerToken)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	scheduleReq := map[string]interface{}{"title": "観光", "startDateTime": "2025-08-01T10:00:00Z", "endDateTime": "2025-08-01T12:00:00Z"}
+	rec = makeRequest(t, http.MethodPost, tripPath+"/schedules", scheduleReq, viewerToken)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	// editorは旅行とスケジュールを編集できるが、削除・共有・共同編集者の管理はできない
+	rec = makeRequest(t, http.MethodPut, tripPath, updateReq, editorToken)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	rec = makeRequest(t, http.MethodPost, tripPath+"/schedules", scheduleReq, editorToken)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	rec = makeRequest(t, http.MethodDelete, tripPath, nil, editorToken)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	rec = makeRequest(t, http.MethodPost, tripPath+"/share", nil, editorToken)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	// 共同編集者でないユーザー（拒否されるべき）
+	rec = makeRequest(t, http.MethodGet, tripPath, nil, outsiderToken)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	// 共同編集者の一覧はownerが先頭
+	rec = makeRequest(t, http.MethodGet, tripPath+"/collaborators", nil, viewerToken)
+	require.Equal(t, http.StatusOK, rec.Code)
+	var collaboratorsResp []map[string]interface{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &collaboratorsResp))
+	require.Len(t, collaboratorsResp, 3)
+	assert.Equal(t, ownerID, collaboratorsResp[0]["userId"])
+	assert.Equal(t, "owner", collaboratorsResp[0]["role"])
+
+	// 権限の変更（ownerの権限は変更できないべき）
+	rec = makeRequest(t, http.MethodPut, tripPath+"/collaborators/"+viewerID, map[string]interface{}{"role": "editor"}, ownerToken)
+	require.Equal(t, http.StatusOK, rec.Code)
+	rec = makeRequest(t, http.MethodPost, tripPath+"/schedules", scheduleReq, viewerToken)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	rec = makeRequest(t, http.MethodPut, tripPath+"/collaborators/"+ownerID, map[string]interface{}{"role": "viewer"}, ownerToken)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+
+	// 共同編集者でないユーザーへの譲渡（失敗するべき）
+	rec = makeRequest(t, http.MethodPut, tripPath+"/owner", map[string]interface{}{"userId": outsiderID}, ownerToken)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	// 所有権の譲渡：元のownerはeditorになる
+	rec = makeRequest(t, http.MethodPut, tripPath+"/owner", map[string]interface{}{"userId": editorID}, ownerToken)
+	require.Equal(t, http.StatusNoContent, rec.Code)
+	rec = makeRequest(t, http.MethodGet, tripPath, nil, ownerToken)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &tripResp))
+	assert.Equal(t, "editor", tripResp["role"])
+	rec = makeRequest(t, http.MethodDelete, tripPath, nil, ownerToken)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	rec = makeRequest(t, http.MethodPut, tripPath+"/owner", map[string]interface{}{"userId": ownerID}, ownerToken)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	// 新しいownerによる共同編集者の削除（ownerは削除できないべき）
+	rec = makeRequest(t, http.MethodDelete, tripPath+"/collaborators/"+viewerID, nil, editorToken)
+	require.Equal(t, http.StatusNoContent, rec.Code)
+	rec = makeRequest(t, http.MethodGet, tripPath, nil, viewerToken)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	rec = makeRequest(t, http.MethodDelete, tripPath+"/collaborators/"+editorID, nil, editorToken)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+
+	// 新しいownerは旅行を削除できる
+	rec = makeRequest(t, http.MethodDelete, tripPath, nil, editorToken)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+}
+
+// TestScenario_TripInvitationFlow はメールでの招待（送信・再送信・取り消し・承諾）とメンバーの紐付けをテスト
+func TestScenario_TripInvitationFlow(t *testing.T) {
+	setupTestDB(t)
+	defer cleanupTestDB(t)
+	setupTestServer(t)
+
+	ownerToken := createAndLoginUser(t, "owner", "owner@example.com", "password123")
+	outsiderToken := createAndLoginUser(t, "outsider", "outsider@example.com", "password123")
+
+	tripReq := map[string]interface{}{
+		"title":     "招待する旅行",
+		"startDate": "2025-09-01",
+		"endDate":   "2025-09-03",
+		"members":   []interface{}{map[string]interface{}{"name": "花子"}, map[string]interface{}{"name": "太郎"}},
+	}
+	rec := makeRequest(t, http.MethodPost, "/trips", tripReq, ownerToken)
+	require.Equal(t, http.StatusCreated, rec.Code)
+	var tripResp map[string]interface{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &tripResp))
+	tripPath := fmt.Sprintf("/trips/%s", tripResp["id"])
+
+	// 未登録のメールアドレスへの招待（メンバー「花子」に紐付ける）
+	inviteReq := map[string]interface{}{"email": "hanako@example.com", "role": "viewer", "memberName": "花子"}
+	rec = makeRequest(t, http.MethodPost, tripPath+"/invitations", inviteReq, ownerToken)
+	require.Equal(t, http.StatusCreated, rec.Code)
+	var invitationResp map[string]interface{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &invitationResp))
+	assert.Equal(t, "viewer", invitationResp["role"])
+	assert.Equal(t, "花子", invitationResp["memberName"])
+	assert.Equal(t, "hanako@example.com", mockEmailSender.GetLastInvitationEmail())
+	invitationToken := mockEmailSender.GetLastInvitationToken()
+	require.NotEmpty(t, invitationToken)
+
+	// 重複・存在しないメンバー・ownerの付与・owner以外による招待（失敗するべき）
+	rec = makeRequest(t, http.MethodPost, tripPath+"/invitations", inviteReq, ownerToken)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	rec = makeRequest(t, http.MethodPost, tripPath+"/invitations", map[string]interface{}{"email": "jiro@example.com", "role": "viewer", "memberName": "次郎"}, ownerToken)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	rec = makeRequest(t, http.MethodPost, tripPath+"/invitations", map[string]interface{}{"email": "jiro@example.com", "role": "owner"}, ownerToken)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	rec = makeRequest(t, http.MethodPost, tripPath+"/invitations", map[string]interface{}{"email": "jiro@example.com", "role": "viewer"}, outsiderToken)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	// 直後の再送信は制限されるべき
+	rec = makeRequest(t, http.MethodPost, tripPath+"/invitations/"+invitationResp["id"].(string)+"/resend", nil, ownerToken)
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.NotEmpty(t, rec.Header().Get("Retry-After"))
+
+	// 取り消した招待は承諾できないべき
+	rec = makeRequest(t, http.MethodPost, tripPath+"/invitations", map[string]interface{}{"email": "outsider@example.com", "role": "editor"}, ownerToken)
+	require.Equal(t, http.StatusCreated, rec.Code)
+	var revokedResp map[string]interface{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &revokedResp))
+	revokedToken := mockEmailSender.GetLastInvitationToken()
+	rec = makeRequest(t, http.MethodGet, tripPath+"/invitations", nil, ownerToken)
+	require.Equal(t, http.StatusOK, rec.Code)
+	var invitationsResp []map[string]interface{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &invitationsResp))
+	assert.Len(t, invitationsResp, 2)
+	rec = makeRequest(t, http.MethodDelete, tripPath+"/invitations/"+revokedResp["id"].(string), nil, ownerToken)
+	require.Equal(t, http.StatusNoContent, rec.Code)
+	rec = makeRequest(t, http.MethodPost, "/invitations/"+revokedToken+"/accept", nil, outsiderToken)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	rec = makeRequest(t, http.MethodGet, tripPath, nil, outsiderToken)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	// 招待先と異なるアカウントでは承諾できないべき
+	rec = makeRequest(t, http.MethodPost, "/invitations/"+invitationToken+"/accept", nil, outsiderToken)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	// 招待されたメールアドレスでサインアップして承諾
+	hanakoToken := createAndLoginUser(t, "hanako", "hanako@example.com", "password123")
+	hanakoID := getMe(t, hanakoToken)["id"].(string)
+	rec = makeRequest(t, http.MethodPost, "/invitations/"+invitationToken+"/accept", nil, hanakoToken)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &tripResp))
+	assert.Equal(t, "viewer", tripResp["role"])
+
+	// 承諾後は旅行を参照でき、メンバー「花子」に紐付いているべき
+	rec = makeRequest(t, http.MethodGet, tripPath, nil, hanakoToken)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &tripResp))
+	for _, m := range tripResp["members"].([]interface{}) {
+		member := m.(map[string]interface{})
+		if member["name"] == "花子" {
+			assert.Equal(t, hanakoID, member["userId"])
+		} else {
+			assert.NotContains(t, member, "userId")
+		}
+	}
+	rec = makeRequest(t, http.MethodPut, tripPath, map[string]interface{}{"title": "変更", "startDate": "2025-09-01", "endDate": "2025-09-03", "members": []interface{}{}}, hanakoToken)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	// 招待トークンは一度しか使用できず、承諾済みの招待は一覧に含まれないべき
+	rec = makeRequest(t, http.MethodPost, "/invitations/"+invitationToken+"/accept", nil, hanakoToken)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	rec = makeRequest(t, http.MethodGet, tripPath+"/invitations", nil, ownerToken)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &invitationsResp))
+	assert.Empty(t, invitationsResp)
+
+	// 既に共同編集者のユーザーへの招待（失敗するべき）
+	rec = makeRequest(t, http.MethodPost, tripPath+"/invitations", map[string]interface{}{"email": "hanako@example.com", "role": "editor"}, ownerToken)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+}
+
+// TestScenario_MemberClaimFlow は共有リンクからのメンバーの紐付けと、メンバーになっている旅行の一覧をテスト
+func TestScenario_MemberClaimFlow(t *testing.T) {
+	setupTestDB(t)
+	defer cleanupTestDB(t)
+	setupTestServer(t)
+
+	ownerToken := createAndLoginUser(t, "owner", "owner@example.com", "password123")
+	hanakoToken := createAndLoginUser(t, "hanako", "hanako@example.com", "password123")
+	taroToken := createAndLoginUser(t, "taro", "taro@example.com", "password123")
+	hanakoID := getMe(t, hanakoToken)["id"].(string)
+
+	tripReq := map[string]interface{}{
+		"title":     "メンバーのいる旅行",
+		"startDate": "2025-10-01",
+		"endDate":   "2025-10-02",
+		"members":   []interface{}{map[string]interface{}{"name": "花子"}, map[string]interface{}{"name": "太郎"}},
+	}
+	rec := makeRequest(t, http.MethodPost, "/trips", tripReq, ownerToken)
+	require.Equal(t, http.StatusCreated, rec.Code)
+	var tripResp map[string]interface{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &tripResp))
+	tripID := tripResp["id"].(string)
+	memberIDs := map[string]string{}
+	for _, m := range tripResp["members"].([]interface{}) {
+		member := m.(map[string]interface{})
+		memberIDs[member["name"].(string)] = member["id"].(string)
+	}
+
+	rec = makeRequest(t, http.MethodPost, fmt.Sprintf("/trips/%s/share", tripID), nil, ownerToken)
+	require.Equal(t, http.StatusCreated, rec.Code)
+	var shareResp map[string]interface{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &shareResp))
+	claimPath := func(memberID string) string {
+		return fmt.Sprintf("/public/trips/%s/members/%s/claim", shareResp["shareToken"], memberID)
+	}
+
+	// 紐付け前はメンバーになっている旅行はない
+	rec = makeRequest(t, http.MethodGet, "/me/memberships", nil, hanakoToken)
+	require.Equal(t, http.StatusOK, rec.Code)
+	var membershipsResp []map[string]interface{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &membershipsResp))
+	assert.Empty(t, membershipsResp)
+
+	// 未ログイン・存在しないメンバー（失敗するべき）
+	rec = makeRequest(t, http.MethodPost, claimPath(memberIDs["花子"]), nil, "")
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	// 旅行情報を編集できない共有リンクでは紐付けられないべき
+	rec = makeRequest(t, http.MethodPost, fmt.Sprintf("/trips/%s/share-links", tripID), map[string]interface{}{"name": "閲覧用", "scope": "view"}, ownerToken)
+	require.Equal(t, http.StatusCreated, rec.Code)
+	var viewLinkResp map[string]interface{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &viewLinkResp))
+	rec = makeRequest(t, http.MethodPost, fmt.Sprintf("/public/trips/%s/members/%s/claim", viewLinkResp["shareToken"], memberIDs["花子"]), nil, hanakoToken)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	rec = makeRequest(t, http.MethodPost, claimPath(uuid.New().String()), nil, hanakoToken)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	// 共有リンクから「花子」を紐付けるとviewerとして旅行を参照できるべき
+	rec = makeRequest(t, http.MethodPost, claimPath(memberIDs["花子"]), nil, hanakoToken)
+	require.Equal(t, http.StatusOK, rec.Code)
+	var memberResp map[string]interface{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &memberResp))
+	assert.Equal(t, hanakoID, memberResp["userId"])
+	rec = makeRequest(t, http.MethodGet, "/trips/"+tripID, nil, hanakoToken)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &tripResp))
+	assert.Equal(t, "viewer", tripResp["role"])
+
+	// 同じメンバーの再紐付けは成功し、別のユーザー・同じ旅行の別メンバーへの紐付けは失敗するべき
+	rec = makeRequest(t, http.MethodPost, claimPath(memberIDs["花子"]), nil, hanakoToken)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	rec = makeRequest(t, http.MethodPost, claimPath(memberIDs["花子"]), nil, taroToken)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	rec = makeRequest(t, http.MethodPost, claimPath(memberIDs["太郎"]), nil, hanakoToken)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+
+	// ownerが紐付けた場合は権限が変わらないべき
+	rec = makeRequest(t, http.MethodPost, claimPath(memberIDs["太郎"]), nil, ownerToken)
+	require.Equal(t, http.StatusOK, rec.Code)
+	rec = makeRequest(t, http.MethodGet, "/trips/"+tripID, nil, ownerToken)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &tripResp))
+	assert.Equal(t, "owner", tripResp["role"])
+
+	// メンバーになっている旅行の一覧
+	rec = makeRequest(t, http.MethodGet, "/me/memberships", nil, hanakoToken)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &membershipsResp))
+	require.Len(t, membershipsResp, 1)
+	assert.Equal(t, memberIDs["花子"], membershipsResp[0]["memberId"])
+	assert.Equal(t, "花子", membershipsResp[0]["memberName"])
+	membershipTrip := membershipsResp[0]["trip"].(map[string]interface{})
+	assert.Equal(t, tripID, membershipTrip["id"])
+	assert.Equal(t, "viewer", membershipTrip["role"])
+
+	// 紐付けていないユーザーの一覧は空
+	rec = makeRequest(t, http.MethodGet, "/me/memberships", nil, taroToken)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &membershipsResp))
+	assert.Empty(t, membershipsResp)
+}
+
+func TestScenario_TripMemberFlow(t *testing.T) {
+	setupTestDB(t)
+	defer cleanupTestDB(t)
+	setupTestServer(t)
+
+	ownerToken := createAndLoginUser(t, "owner", "owner@example.com", "password123")
+	hanakoToken := createAndLoginUser(t, "hanako", "hanako@example.com", "password123")
+	viewerToken := createAndLoginUser(t, "viewer", "viewer@example.com", "password123")
+	hanakoID := getMe(t, hanakoToken)["id"].(string)
+
+	tripReq := map[string]interface{}{
+		"title":     "メンバー編集の旅行",
+		"startDate": "2025-11-01",
+		"endDate":   "2025-11-03",
+		"members": []interface{}{
+			map[string]interface{}{"name": "花子"},
+			map[string]interface{}{"name": "太郎"},
+			map[string]interface{}{"name": "次郎"},
+		},
+	}
+	rec := makeRequest(t, http.MethodPost, "/trips", tripReq, ownerToken)
+	require.Equal(t, http.StatusCreated, rec.Code)
+	var tripResp map[string]interface{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &tripResp))
+	tripID := tripResp["id"].(string)
+	memberIDs := map[string]string{}
+	for _, m := range tripResp["members"].([]interface{}) {
+		member := m.(map[string]interface{})
+		memberIDs[member["name"].(string)] = member["id"].(string)
+	}
+
+	// 「花子」をユーザーに紐付けておく
+	rec = makeRequest(t, http.MethodPost, fmt.Sprintf("/trips/%s/share", tripID), nil, ownerToken)
+	require.Equal(t, http.StatusCreated, rec.Code)
+	var shareResp map[string]interface{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &shareResp))
+	rec = makeRequest(t, http.MethodPost, fmt.Sprintf("/public/trips/%s/members/%s/claim", shareResp["shareToken"], memberIDs["花子"]), nil, hanakoToken)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	rec = makeRequest(t, http.MethodPost, fmt.Sprintf("/trips/%s/collaborators", tripID), map[string]interface{}{
+		"email": "viewer@example.com",
+		"role":  "viewer",
+	}, ownerToken)
+	require.Equal(t, http.StatusCreated, rec.Code)
+
+	// 更新時にIDを指定したメンバーは維持・名前変更され、IDのないメンバーは追加、含まれないメンバーは削除されるべき
+	updateReq := map[string]interface{}{
+		"title":     "メンバー編集の旅行",
+		"startDate": "2025-11-01",
+		"endDate":   "2025-11-03",
+		"members": []interface{}{
+			map[string]interface{}{"id": memberIDs["花子"], "name": "はなこ"},
+			map[string]interface{}{"id": memberIDs["太郎"], "name": "太郎"},
+			map[string]interface{}{"name": "三郎"},
+		},
+	}
+	rec = makeRequest(t, http.MethodPut, "/trips/"+tripID, updateReq, ownerToken)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &tripResp))
+	members := tripResp["members"].([]interface{})
+	require.Len(t, members, 3)
+	updatedIDs := map[string]map[string]interface{}{}
+	for _, m := range members {
+		member := m.(map[string]interface{})
+		updatedIDs[member["name"].(string)] = member
+	}
+	require.Contains(t, updatedIDs, "はなこ")
+	assert.Equal(t, memberIDs["花子"], updatedIDs["はなこ"]["id"])
+	assert.Equal(t, hanakoID, updatedIDs["はなこ"]["userId"])
+	assert.Equal(t, memberIDs["太郎"], updatedIDs["太郎"]["id"])
+	require.Contains(t, updatedIDs, "三郎")
+	assert.NotContains(t, updatedIDs, "次郎")
+
+	// 紐付けたユーザーのメンバーシップは名前変更後も維持されるべき
+	rec = makeRequest(t, http.MethodGet, "/me/memberships", nil, hanakoToken)
+	require.Equal(t, http.StatusOK, rec.Code)
+	var membershipsResp []map[string]interface{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &membershipsResp))
+	require.Len(t, membershipsResp, 1)
+	assert.Equal(t, memberIDs["花子"], membershipsResp[0]["memberId"])
+	assert.Equal(t, "はなこ", membershipsResp[0]["memberName"])
+
+	// 他の旅行・削除済みのメンバーIDの指定やIDの重複は400で、何も変更されないべき
+	otherTripResp := map[string]interface{}{}
+	rec = makeRequest(t, http.MethodPost, "/trips", map[string]interface{}{
+		"title":     "別の旅行",
+		"startDate": "2025-12-01",
+		"endDate":   "2025-12-02",
+		"members":   []interface{}{map[string]interface{}{"name": "他人"}},
+	}, ownerToken)
+	require.Equal(t, http.StatusCreated, rec.Code)
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &otherTripResp))
+	otherMemberID := otherTripResp["members"].([]interface{})[0].(map[string]interface{})["id"].(string)
+	for _, invalidMembers := range [][]interface{}{
+		{map[string]interface{}{"id": otherMemberID, "name": "他人"}},
+		{map[string]interface{}{"id": memberIDs["次郎"], "name": "次郎"}},
+		{map[string]interface{}{"id": memberIDs["太郎"], "name": "太郎"}, map[string]interface{}{"id": memberIDs["太郎"], "name": "太郎2"}},
+	} {
+		updateReq["members"] = invalidMembers
+		rec = makeRequest(t, http.MethodPut, "/trips/"+tripID, updateReq, ownerToken)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	}
+	rec = makeRequest(t, http.MethodGet, "/trips/"+tripID, nil, ownerToken)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &tripResp))
+	assert.Len(t, tripResp["members"], 3)
+
+	// membersを省略した更新ではメンバーが変わらないべき
+	delete(updateReq, "members")
+	rec = makeRequest(t, http.MethodPut, "/trips/"+tripID, updateReq, ownerToken)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &tripResp))
+	assert.Len(t, tripResp["members"], 3)
+
+	// メンバー単位の追加・名前変更・削除
+	rec = makeRequest(t, http.MethodPost, fmt.Sprintf("/trips/%s/members", tripID), map[string]interface{}{"name": "四郎"}, ownerToken)
+	require.Equal(t, http.StatusCreated, rec.Code)
+	var memberResp map[string]interface{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &memberResp))
+	assert.Equal(t, "四郎", memberResp["name"])
+	shiroID := memberResp["id"].(string)
+
+	rec = makeRequest(t, http.MethodPatch, fmt.Sprintf("/trips/%s/members/%s", tripID, shiroID), map[string]interface{}{"name": "しろう"}, ownerToken)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &memberResp))
+	assert.Equal(t, shiroID, memberResp["id"])
+	assert.Equal(t, "しろう", memberResp["name"])
+
+	// 空の名前は400、他の旅行のメンバーは404
+	rec = makeRequest(t, http.MethodPost, fmt.Sprintf("/trips/%s/members", tripID), map[string]interface{}{"name": " "}, ownerToken)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	rec = makeRequest(t, http.MethodPatch, fmt.Sprintf("/trips/%s/members/%s", tripID, otherMemberID), map[string]interface{}{"name": "他人"}, ownerToken)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	rec = makeRequest(t, http.MethodDelete, fmt.Sprintf("/trips/%s/members/%s", tripID, otherMemberID), nil, ownerToken)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	// viewerはメンバーを編集できないべき
+	rec = makeRequest(t, http.MethodPost, fmt.Sprintf("/trips/%s/members", tripID), map[string]interface{}{"name": "五郎"}, viewerToken)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	rec = makeRequest(t, http.MethodDelete, fmt.Sprintf("/trips/%s/members/%s", tripID, shiroID), nil, viewerToken)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	rec = makeRequest(t, http.MethodDelete, fmt.Sprintf("/trips/%s/members/%s", tripID, shiroID), nil, ownerToken)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	rec = makeRequest(t, http.MethodDelete, fmt.Sprintf("/trips/%s/members/%s", tripID, shiroID), nil, ownerToken)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = makeRequest(t, http.MethodGet, "/trips/"+tripID, nil, ownerToken)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &tripResp))
+	assert.Len(t, tripResp["members"], 3)
+}
+
+func TestScenario_ScopedShareLinkFlow(t *testing.T) {
+	setupTestDB(t)
+	defer cleanupTestDB(t)
+	setupTestServer(t)
+
+	ownerToken := createAndLoginUser(t, "owner", "owner@example.com", "password123")
+	otherToken := createAndLoginUser(t, "other", "other@example.com", "password123")
+	tripID := createTrip(t, ownerToken, "共有範囲の旅行", "2025-08-01", "2025-08-03")
+	scheduleID := createSchedule(t, ownerToken, tripID, "海水浴", "2025-08-01")
+	shareLinksPath := fmt.Sprintf("/trips/%s/share-links", tripID)
+
+	createShareLink := func(req map[string]interface{}) map[string]interface{} {
+		rec := makeRequest(t, http.MethodPost, shareLinksPath, req, ownerToken)
+		require.Equal(t, http.StatusCreated, rec.Code)
+		var resp map[string]interface{}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		return resp
+	}
+	viewLink := createShareLink(map[string]interface{}{"name": "家族用", "scope": "view"})
+	scheduleLink := createShareLink(map[string]interface{}{"name": "幹事用", "scope": "edit_schedules", "maxUses": 3})
+	tripLink := createShareLink(map[string]interface{}{"name": "共同幹事用", "scope": "edit_trip"})
+	assert.Equal(t, "view", viewLink["scope"])
+	assert.Equal(t, float64(3), scheduleLink["maxUses"])
+	publicPath := func(link map[string]interface{}) string {
+		return "/public/trips/" + link["shareToken"].(string)
+	}
+
+	// 不正な指定（失敗するべき）
+	for _, invalidReq := range []map[string]interface{}{
+		{"name": "不明", "scope": "admin"},
+		{"name": " ", "scope": "view"},
+		{"name": "期限切れ", "scope": "view", "expiresAt": time.Now().Add(-time.Hour).Format(time.RFC3339)},
+		{"name": "回数0", "scope": "view", "maxUses": 0},
+	} {
+		rec := makeRequest(t, http.MethodPost, shareLinksPath, invalidReq, ownerToken)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	}
+	// owner以外は共有リンクを管理できないべき
+	rec := makeRequest(t, http.MethodGet, shareLinksPath, nil, otherToken)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	scheduleReq := map[string]interface{}{
+		"title":         "花火大会",
+		"startDateTime": "2025-08-02T19:00:00Z",
+		"endDateTime":   "2025-08-02T21:00:00Z",
+	}
+	tripReq := map[string]interface{}{
+		"title":     "共有範囲の旅行（更新）",
+		"startDate": "2025-08-01",
+		"endDate":   "2025-08-04",
+	}
+
+	// viewは参照のみできるべき
+	rec = makeRequest(t, http.MethodGet, publicPath(viewLink), nil, "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	rec = makeRequest(t, http.MethodGet, publicPath(viewLink)+"/schedules/"+scheduleID, nil, "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	rec = makeRequest(t, http.MethodPost, publicPath(viewLink)+"/schedules", scheduleReq, "")
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	rec = makeRequest(t, http.MethodPut, publicPath(viewLink), tripReq, "")
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	// edit_schedulesはスケジュールを編集できるが旅行情報は編集できないべき
+	rec = makeRequest(t, http.MethodPost, publicPath(scheduleLink)+"/schedules", scheduleReq, "")
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	rec = makeRequest(t, http.MethodPut, publicPath(scheduleLink), tripReq, "")
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	// 利用回数は入口のGETだけを数え、上限に達したら訪問トークンのない他の操作も410になるべき
+	var lastVisit string
+	for i := 0; i < 3; i++ {
+		rec = makeRequest(t, http.MethodGet, publicPath(scheduleLink), nil, "")
+		assert.Equal(t, http.StatusOK, rec.Code)
+		lastVisit = rec.Header().Get(middleware.ShareVisitHeader)
+		require.NotEmpty(t, lastVisit)
+	}
+	rec = makeRequest(t, http.MethodGet, publicPath(scheduleLink), nil, "")
+	assert.Equal(t, http.StatusGone, rec.Code)
+	assert.Empty(t, rec.Header().Get(middleware.ShareVisitHeader))
+	rec = makeRequest(t, http.MethodGet, publicPath(scheduleLink)+"/details", nil, "")
+	assert.Equal(t, http.StatusGone, rec.Code)
+	rec = makeRequest(t, http.MethodPost, publicPath(scheduleLink)+"/schedules", scheduleReq, "")
+	assert.Equal(t, http.StatusGone, rec.Code)
+	// 上限に達した訪問でも、その訪問トークンがあれば編集できるべき
+	rec = makeShareVisitRequest(t, http.MethodPost, publicPath(scheduleLink)+"/schedules", scheduleReq, lastVisit)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	// 訪問トークンで入口のGETを繰り返しても上限は超えられないべき
+	rec = makeShareVisitRequest(t, http.MethodGet, publicPath(scheduleLink), nil, lastVisit)
+	assert.Equal(t, http.StatusGone, rec.Code)
+
+	// 1回の訪問で詳細やスケジュールを取得しても利用回数は1回だけ数え、最後の訪問でも訪問トークンで取得できるべき
+	twoVisitLink := createShareLink(map[string]interface{}{"name": "2回まで", "scope": "view", "maxUses": 2})
+	for visit := 0; visit < 2; visit++ {
+		rec = makeRequest(t, http.MethodGet, publicPath(twoVisitLink), nil, "")
+		require.Equal(t, http.StatusOK, rec.Code, "visit %d", visit)
+		visitToken := rec.Header().Get(middleware.ShareVisitHeader)
+		require.NotEmpty(t, visitToken)
+		for _, path := range []string{"/details", "/schedules", "/schedules/" + scheduleID, "/calendar.ics"} {
+			rec = makeShareVisitRequest(t, http.MethodGet, publicPath(twoVisitLink)+path, nil, visitToken)
+			assert.Equal(t, http.StatusOK, rec.Code, "visit %d: %s", visit, path)
+		}
+	}
+	rec = makeRequest(t, http.MethodGet, publicPath(twoVisitLink), nil, "")
+	assert.Equal(t, http.StatusGone, rec.Code)
+	rec = makeRequest(t, http.MethodGet, publicPath(twoVisitLink)+"/details", nil, "")
+	assert.Equal(t, http.StatusGone, rec.Code)
+	// 別の共有リンクの訪問トークンは使用できないべき
+	rec = makeShareVisitRequest(t, http.MethodGet, publicPath(twoVisitLink)+"/details", nil, lastVisit)
+	assert.Equal(t, http.StatusGone, rec.Code)
+
+	// edit_tripは旅行情報も編集できるべき
+	rec = makeRequest(t, http.MethodPut, publicPath(tripLink), tripReq, "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	// 有効期限を過ぎたリンクは410になるべき
+	expiringLink := createShareLink(map[string]interface{}{
+		"name":      "期限付き",
+		"scope":     "view",
+		"expiresAt": time.Now().Add(time.Hour).Format(time.RFC3339),
+	})
+	rec = makeRequest(t, http.MethodGet, publicPath(expiringLink), nil, "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	require.NoError(t, testDB.Model(&domain.ShareToken{}).Where("id = ?", expiringLink["id"]).
+		Update("expires_at", time.Now().Add(-time.Minute)).Error)
+	rec = makeRequest(t, http.MethodGet, publicPath(expiringLink), nil, "")
+	assert.Equal(t, http.StatusGone, rec.Code)
+
+	// 一覧には利用回数が含まれ、トークンは含まれないべき
+	rec = makeRequest(t, http.MethodGet, shareLinksPath, nil, ownerToken)
+	require.Equal(t, http.StatusOK, rec.Code)
+	var linksResp []map[string]interface{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &linksResp))
+	require.Len(t, linksResp, 5)
+	assert.Equal(t, "家族用", linksResp[0]["name"])
+	assert.Equal(t, float64(3), linksResp[1]["useCount"])
+	assert.Equal(t, float64(2), linksResp[3]["useCount"])
+	assert.NotContains(t, rec.Body.String(), viewLink["shareToken"])
+
+	// 失効させたリンクは使用できないべき
+	rec = makeRequest(t, http.MethodDelete, fmt.Sprintf("%s/%s", shareLinksPath, viewLink["id"]), nil, ownerToken)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	rec = makeRequest(t, http.MethodGet, publicPath(viewLink), nil, "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	rec = makeRequest(t, http.MethodDelete, fmt.Sprintf("%s/%s", shareLinksPath, viewLink["id"]), nil, ownerToken)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	// 既定の共有リンクは名前ではなく区別されるため、同じ名前のリンクがあっても発行できるべき
+	sameNameLink := createShareLink(map[string]interface{}{"name": domain.DefaultShareLinkName, "scope": "view"})
+
+	// 既定の共有リンクは1つだけで、再生成すると以前のトークンは使用できないべき
+	rec = makeRequest(t, http.MethodPost, fmt.Sprintf("/trips/%s/share", tripID), nil, ownerToken)
+	require.Equal(t, http.StatusCreated, rec.Code)
+	var defaultLink map[string]interface{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &defaultLink))
+	assert.Equal(t, "edit_trip", defaultLink["scope"])
+	assert.NotEqual(t, sameNameLink["id"], defaultLink["id"])
+	rec = makeRequest(t, http.MethodPost, fmt.Sprintf("/trips/%s/share", tripID), nil, ownerToken)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	rec = makeRequest(t, http.MethodPost, fmt.Sprintf("/trips/%s/share?regenerate=true", tripID), nil, ownerToken)
+	require.Equal(t, http.StatusCreated, rec.Code)
+	var regeneratedLink map[string]interface{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &regeneratedLink))
+	assert.Equal(t, defaultLink["id"], regeneratedLink["id"])
+	rec = makeRequest(t, http.MethodGet, publicPath(defaultLink), nil, "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	rec = makeRequest(t, http.MethodGet, publicPath(regeneratedLink), nil, "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	// 同じ名前のリンクは再生成されないべき
+	rec = makeRequest(t, http.MethodGet, publicPath(sameNameLink), nil, "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestScenario_PasswordProtectedShareLinkFlow(t *testing.T) {
+	setupTestDB(t)
+	defer cleanupTestDB(t)
+	setupTestServer(t)
+
+	ownerToken := createAndLoginUser(t, "owner", "owner@example.com", "password123")
+	tripID := createTrip(t, ownerToken, "秘密の旅行", "2025-09-01", "2025-09-03")
+	shareLinksPath := fmt.Sprintf("/trips/%s/share-links", tripID)
+
+	createShareLink := func(req map[string]interface{}) map[string]interface{} {
+		rec := makeRequest(t, http.MethodPost, shareLinksPath, req, ownerToken)
+		require.Equal(t, http.StatusCreated, rec.Code)
+		var resp map[string]interface{}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		return resp
+	}
+	unlock := func(link map[string]interface{}, passphrase string) *httptest.ResponseRecorder {
+		return makeRequest(t, http.MethodPost, fmt.Sprintf("/public/trips/%s/unlock", link["shareToken"]), map[string]interface{}{"passphrase": passphrase}, "")
+	}
+
+	// 短すぎるパスフレーズ（失敗するべき）
+	rec := makeRequest(t, http.MethodPost, shareLinksPath, map[string]interface{}{"name": "短い", "scope": "view", "passphrase": "short"}, ownerToken)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	protectedLink := createShareLink(map[string]interface{}{"name": "家族用", "scope": "view", "passphrase": "correct horse"})
+	otherLink := createShareLink(map[string]interface{}{"name": "友人用", "scope": "view", "passphrase": "battery staple"})
+	openLink := createShareLink(map[string]interface{}{"name": "公開", "scope": "view"})
+	assert.Equal(t, true, protectedLink["passwordProtected"])
+	assert.Equal(t, false, openLink["passwordProtected"])
+	protectedPath := "/public/trips/" + protectedLink["shareToken"].(string)
+
+	// アクセス許可トークンなしは401、パスフレーズなしのリンクのunlockは400
+	rec = makeShareRequest(t, http.MethodGet, protectedPath, nil, "")
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	rec = unlock(openLink, "anything")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	rec = makeRequest(t, http.MethodPost, "/public/trips/unknown-token/unlock", map[string]interface{}{"passphrase": "correct horse"}, "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	// 誤ったパスフレーズは401、正しいパスフレーズでアクセス許可トークンを取得
+	rec = unlock(protectedLink, "wrong passphrase")
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	rec = unlock(protectedLink, "correct horse")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var grantResp map[string]interface{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &grantResp))
+	grant := grantResp["grantToken"].(string)
+	assert.NotEmpty(t, grantResp["expiresAt"])
+
+	rec = makeShareRequest(t, http.MethodGet, protectedPath, nil, grant)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	rec = makeShareRequest(t, http.MethodGet, protectedPath+"/details", nil, grant)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	// アクセス許可トークンは別の共有リンクやアクセストークンの代わりには使用できないべき
+	rec = makeShareRequest(t, http.MethodGet, "/public/trips/"+otherLink["shareToken"].(string), nil, grant)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	rec = makeShareRequest(t, http.MethodGet, protectedPath, nil, ownerToken)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	rec = makeRequest(t, http.MethodGet, "/trips", nil, grant)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	// 誤ったパスフレーズが続くと共有リンクごとにロックされ、正しいパスフレーズでも429になるべき
+	for i := 0; i < 5; i++ {
+		rec = unlock(otherLink, "wrong passphrase")
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	}
+	rec = unlock(otherLink, "battery staple")
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.NotEmpty(t, rec.Header().Get("Retry-After"))
+
+	// 他の共有リンクはロックの影響を受けないべき
+	rec = unlock(protectedLink, "correct horse")
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	// 一覧にはパスフレーズの有無が含まれ、ハッシュは含まれないべき
+	rec = makeRequest(t, http.MethodGet, shareLinksPath, nil, ownerToken)
+	require.Equal(t, http.StatusOK, rec.Code)
+	var linksResp []map[string]interface{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &linksResp))
+	require.Len(t, linksResp, 3)
+	assert.Equal(t, true, linksResp[0]["passwordProtected"])
+	assert.NotContains(t, rec.Body.String(), "$2a$")
+}
+
+func TestScenario_ShareLinkActivityFlow(t *testing.T) {
+	setupTestDB(t)
+	defer cleanupTestDB(t)
+	setupTestServer(t)
+
+	ownerToken := createAndLoginUser(t, "owner", "owner@example.com", "password123")
+	otherToken := createAndLoginUser(t, "other", "other@example.com", "password123")
+	tripID := createTrip(t, ownerToken, "アクセス記録の旅行", "2025-10-01", "2025-10-03")
+	scheduleID := createSchedule(t, ownerToken, tripID, "紅葉狩り", "2025-10-01")
+	activityPath := fmt.Sprintf("/trips/%s/share/activity", tripID)
+
+	rec := makeRequest(t, http.MethodPost, fmt.Sprintf("/trips/%s/share-links", tripID), map[string]interface{}{"name": "幹事用", "scope": "edit_schedules"}, ownerToken)
+	require.Equal(t, http.StatusCreated, rec.Code)
+	var link map[string]interface{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &link))
+	publicPath := "/public/trips/" + link["shareToken"].(string)
+
+	// 参照2回・編集1回・許可されていない編集1回
+	rec = makeRequest(t, http.MethodGet, publicPath, nil, "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	rec = makeRequest(t, http.MethodGet, publicPath+"/schedules", nil, "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	rec = makeRequest(t, http.MethodPatch, publicPath+"/schedules/"+scheduleID, map[string]interface{}{"title": "紅葉狩り（午後）"}, "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	rec = makeRequest(t, http.MethodPut, publicPath, map[string]interface{}{"title": "変更", "startDate": "2025-10-01", "endDate": "2025-10-03"}, "")
+	require.Equal(t, http.StatusForbidden, rec.Code)
+
+	// owner以外はアクセス状況を参照できないべき
+	rec = makeRequest(t, http.MethodGet, activityPath, nil, otherToken)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	rec = makeRequest(t, http.MethodGet, activityPath+"?days=0", nil, ownerToken)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	rec = makeRequest(t, http.MethodGet, activityPath+"?days=91", nil, ownerToken)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	// ログは非同期に書き込まれるため、記録されるまで待つ
+	var activity struct {
+		Daily []struct {
+			Date     string `json:"date"`
+			Requests int    `json:"requests"`
+			Visitors int    `json:"visitors"`
+		} `json:"daily"`
+		RecentEdits []map[string]interface{} `json:"recentEdits"`
+	}
+	require.Eventually(t, func() bool {
+		rec := makeRequest(t, http.MethodGet, activityPath+"?days=7", nil, ownerToken)
+		if rec.Code != http.StatusOK || json.Unmarshal(rec.Body.Bytes(), &activity) != nil {
+			return false
+		}
+		return len(activity.Daily) == 7 && activity.Daily[6].Requests == 3
+	}, 5*time.Second, 50*time.Millisecond)
+
+	// 日別の集計は今日までの7日分で、許可されなかったリクエストは含まないべき
+	assert.Equal(t, time.Now().UTC().Format(time.DateOnly), activity.Daily[6].Date)
+	assert.Equal(t, 1, activity.Daily[6].Visitors)
+	assert.Equal(t, 0, activity.Daily[0].Requests)
+
+	// 編集はルートのパターンで記録され、共有トークンやIPアドレスは含まないべき
+	require.Len(t, activity.RecentEdits, 1)
+	edit := activity.RecentEdits[0]
+	assert.Equal(t, "幹事用", edit["shareLinkName"])
+	assert.Equal(t, link["id"], edit["shareLinkId"])
+	assert.Equal(t, http.MethodPatch, edit["method"])
+	assert.Equal(t, "/public/trips/:shareToken/schedules/:scheduleId", edit["route"])
+	assert.Equal(t, float64(http.StatusOK), edit["status"])
+	assert.NotEmpty(t, edit["userAgentFamily"])
+	rec = makeRequest(t, http.MethodGet, activityPath, nil, ownerToken)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.NotContains(t, rec.Body.String(), link["shareToken"])
+	assert.NotContains(t, rec.Body.String(), "192.0.2.1")
+
+	// 失効させた共有リンクの編集も名前付きで残るべき
+	rec = makeRequest(t, http.MethodDelete, fmt.Sprintf("/trips/%s/share-links/%s", tripID, link["id"]), nil, ownerToken)
+	require.Equal(t, http.StatusNoContent, rec.Code)
+	rec = makeRequest(t, http.MethodGet, activityPath+"?limit=1", nil, ownerToken)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &activity))
+	require.Len(t, activity.RecentEdits, 1)
+	assert.Equal(t, "幹事用", activity.RecentEdits[0]["shareLinkName"])
+	assert.Nil(t, activity.RecentEdits[0]["shareLinkId"])
+}
+
+func TestScenario_PublicTripPageFlow(t *testing.T) {
+	setupTestDB(t)
+	defer cleanupTestDB(t)
+	setupTestServer(t)
+
+	ownerToken := createAndLoginUser(t, "owner", "owner@example.com", "password123")
+	tripID := createTrip(t, ownerToken, "夏の<沖縄>旅行", "2025-08-01", "2025-08-03")
+	rec := makeRequest(t, http.MethodPost, fmt.Sprintf("/trips/%s/members", tripID), map[string]interface{}{"name": "太郎"}, ownerToken)
+	require.Equal(t, http.StatusCreated, rec.Code)
+	rec = makeRequest(t, http.MethodPost, fmt.Sprintf("/trips/%s/schedules", tripID), map[string]interface{}{
+		"title":         "花火大会",
+		"startDateTime": "2025-08-01T20:00:00Z",
+		"endDateTime":   "2025-08-01T22:00:00Z",
+	}, ownerToken)
+	require.Equal(t, http.StatusCreated, rec.Code)
+
+	createShareLink := func(req map[string]interface{}) string {
+		rec := makeRequest(t, http.MethodPost, fmt.Sprintf("/trips/%s/share-links", tripID), req, ownerToken)
+		require.Equal(t, http.StatusCreated, rec.Code)
+		var resp map[string]interface{}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		return resp["shareToken"].(string)
+	}
+	viewToken := createShareLink(map[string]interface{}{"name": "家族用", "scope": "view"})
+
+	// HTMLで旅行・メンバー・日ごとのスケジュールとOpen Graphタグを表示するべき
+	rec = makePageRequest(t, http.MethodGet, "/p/"+viewToken, nil, nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Header().Get(echo.HeaderContentType), "text/html")
+	assert.Equal(t, "no-store", rec.Header().Get("Cache-Control"))
+	body := rec.Body.String()
+	assert.Contains(t, body, `<meta property="og:title" content="夏の&lt;沖縄&gt;旅行">`)
+	assert.Contains(t, body, `<meta property="og:description" content="2025/8/1（金） 〜 2025/8/3（日）・メンバー1人・予定1件">`)
+	assert.Contains(t, body, "<li>太郎</li>")
+	assert.NotContains(t, body, "<沖縄>")
+	assert.NotContains(t, body, "<script")
+	assert.Contains(t, body, "20:00〜22:00")
+	assert.Less(t, strings.Index(body, "2025/8/1（金）</h3>"), strings.Index(body, "花火大会"))
+	assert.Less(t, strings.Index(body, "花火大会"), strings.Index(body, "2025/8/2（土）</h3>"))
+
+	// tzを指定するとその時刻で日付ごとにまとめるべき
+	rec = makePageRequest(t, http.MethodGet, "/p/"+viewToken+"?tz=Asia/Tokyo", nil, nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+	body = rec.Body.String()
+	assert.Contains(t, body, "05:00〜07:00")
+	assert.Less(t, strings.Index(body, "2025/8/2（土）</h3>"), strings.Index(body, "花火大会"))
+	for _, tz := range []string{"Mars/Olympus", "Local"} {
+		rec = makePageRequest(t, http.MethodGet, "/p/"+viewToken+"?tz="+tz, nil, nil)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	}
+
+	// スタイルシートは埋め込みのファイルから配信するべき
+	rec = makePageRequest(t, http.MethodGet, "/static/public_trip.css", nil, nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Header().Get(echo.HeaderContentType), "text/css")
+
+	// 存在しない・失効した共有リンクは旅行の情報を含まないエラーページになるべき
+	rec = makePageRequest(t, http.MethodGet, "/p/unknown-token", nil, nil)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Contains(t, rec.Header().Get(echo.HeaderContentType), "text/html")
+	expiredToken := createShareLink(map[string]interface{}{"name": "1回限り", "scope": "view", "maxUses": 1})
+	rec = makePageRequest(t, http.MethodGet, "/p/"+expiredToken, nil, nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	rec = makePageRequest(t, http.MethodGet, "/p/"+expiredToken, nil, nil)
+	assert.Equal(t, http.StatusGone, rec.Code)
+	assert.NotContains(t, rec.Body.String(), "沖縄")
+
+	// パスフレーズ付きの共有リンクはフォームで解除し、Cookieのアクセス許可トークンで表示するべき
+	protectedToken := createShareLink(map[string]interface{}{"name": "友人用", "scope": "view", "passphrase": "correct horse"})
+	pagePath := "/p/" + protectedToken
+	rec = makePageRequest(t, http.MethodGet, pagePath+"?tz=Asia/Tokyo", nil, nil)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Contains(t, rec.Body.String(), `action="`+pagePath+`/unlock"`)
+	assert.NotContains(t, rec.Body.String(), "沖縄")
+
+	rec = makePageRequest(t, http.MethodPost, pagePath+"/unlock", url.Values{"passphrase": {"wrong passphrase"}, "tz": {"Asia/Tokyo"}}, nil)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Contains(t, rec.Body.String(), "パスフレーズが正しくありません")
+
+	rec = makePageRequest(t, http.MethodPost, pagePath+"/unlock", url.Values{"passphrase": {"correct horse"}, "tz": {"Asia/Tokyo"}}, nil)
+	require.Equal(t, http.StatusSeeOther, rec.Code)
+	assert.Equal(t, pagePath+"?tz=Asia%2FTokyo", rec.Header().Get("Location"))
+	cookies := rec.Result().Cookies()
+	require.Len(t, cookies, 1)
+	assert.Equal(t, handler.ShareGrantCookie, cookies[0].Name)
+	assert.Equal(t, pagePath, cookies[0].Path)
+	assert.True(t, cookies[0].HttpOnly)
+
+	rec = makePageRequest(t, http.MethodGet, rec.Header().Get("Location"), nil, cookies)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "花火大会")
+
+	// Cookieは別の共有リンクやJSONのAPIでは使用できないべき
+	otherToken := createShareLink(map[string]interface{}{"name": "同僚用", "scope": "view", "passphrase": "battery staple"})
+	rec = makePageRequest(t, http.MethodGet, "/p/"+otherToken, nil, cookies)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	rec = makePageRequest(t, http.MethodGet, "/public/trips/"+protectedToken, nil, cookies)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func TestScenario_CrossTripScheduleFlow(t *testing.T) {
+	setupTestDB(t)
+	defer cleanupTestDB(t)
+	setupTestServer(t)
+
+	ownerToken := createAndLoginUser(t, "owner", "owner@example.com", "password123")
+	otherToken := createAndLoginUser(t, "other", "other@example.com", "password123")
+	tripA := createTrip(t, ownerToken, "旅行A", "2025-11-01", "2025-11-03")
+	ownTripB := createTrip(t, ownerToken, "旅行B", "2025-12-01", "2025-12-03")
+	otherTripC := createTrip(t, otherToken, "旅行C", "2025-12-10", "2025-12-12")
+	scheduleA := createSchedule(t, ownerToken, tripA, "旅行Aの予定", "2025-11-01")
+	scheduleB := createSchedule(t, ownerToken, ownTripB, "旅行Bの予定", "2025-12-01")
+	scheduleC := createSchedule(t, otherToken, otherTripC, "旅行Cの予定", "2025-12-10")
+
+	rec := makeRequest(t, http.MethodPost, fmt.Sprintf("/trips/%s/share-links", tripA), map[string]interface{}{"name": "幹事用", "scope": "edit_schedules"}, ownerToken)
+	require.Equal(t, http.StatusCreated, rec.Code)
+	var link map[string]interface{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &link))
+	publicPathA := "/public/trips/" + link["shareToken"].(string)
+
+	updateReq := map[string]interface{}{"title": "書き換え"}
+
+	// 他の旅行のスケジュールは、自分が所有する旅行のものでも旅行Aのパスからは404になるべき
+	for _, scheduleID := range []string{scheduleB, scheduleC} {
+		path := fmt.Sprintf("/trips/%s/schedules/%s", tripA, scheduleID)
+		rec = makeRequest(t, http.MethodGet, path, nil, ownerToken)
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		rec = makeRequest(t, http.MethodPatch, path, updateReq, ownerToken)
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		rec = makeRequest(t, http.MethodDelete, path, nil, ownerToken)
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+
+		// 旅行Aの共有リンクからも404になるべき
+		path = publicPathA + "/schedules/" + scheduleID
+		rec = makeRequest(t, http.MethodGet, path, nil, "")
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		rec = makeRequest(t, http.MethodPatch, path, updateReq, "")
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		rec = makeRequest(t, http.MethodDelete, path, nil, "")
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	}
+
+	// 他の旅行のスケジュールは変更されていないべき
+	for _, c := range []struct {
+		token, tripID, scheduleID, title string
+	}{
+		{ownerToken, ownTripB, scheduleB, "旅行Bの予定"},
+		{otherToken, otherTripC, scheduleC, "旅行Cの予定"},
+	} {
+		rec = makeRequest(t, http.MethodGet, fmt.Sprintf("/trips/%s/schedules/%s", c.tripID, c.scheduleID), nil, c.token)
+		require.Equal(t, http.StatusOK, rec.Code)
+		var scheduleResp map[string]interface{}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &scheduleResp))
+		assert.Equal(t, c.title, scheduleResp["title"])
+	}
+
+	// 旅行C（アクセス権なし）のパスからは旅行Aのスケジュールにもアクセスできないべき
+	rec = makeRequest(t, http.MethodGet, fmt.Sprintf("/trips/%s/schedules/%s", otherTripC, scheduleA), nil, ownerToken)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	rec = makeRequest(t, http.MethodGet, fmt.Sprintf("/trips/%s/schedules/%s", otherTripC, scheduleA), nil, otherToken)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	// 同じ旅行のスケジュールは従来どおり操作できるべき
+	rec = makeRequest(t, http.MethodPatch, publicPathA+"/schedules/"+scheduleA, updateReq, "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	rec = makeRequest(t, http.MethodGet, fmt.Sprintf("/trips/%s/schedules/%s", tripA, scheduleA), nil, ownerToken)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "書き換え")
+	rec = makeRequest(t, http.MethodDelete, publicPathA+"/schedules/"+scheduleA, nil, "")
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	rec = makeRequest(t, http.MethodDelete, fmt.Sprintf("/trips/%s/schedules/%s", tripA, scheduleA), nil, ownerToken)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestScenario_CalendarExportFlow(t *testing.T) {
+	setupTestDB(t)
+	defer cleanupTestDB(t)
+	setupTestServer(t)
+
+	ownerToken := createAndLoginUser(t, "owner", "owner@example.com", "password123")
+	friendToken := createAndLoginUser(t, "friend", "friend@example.com", "password123")
+	tripID := createTrip(t, ownerToken, "沖縄旅行", "2025-08-01", "2025-08-03")
+	scheduleID := createSchedule(t, ownerToken, tripID, "美ら海水族館", "2025-08-01")
+	friendTripID := createTrip(t, friendToken, "京都旅行", "2025-09-01", "2025-09-02")
+	createSchedule(t, friendToken, friendTripID, "清水寺", "2025-09-01")
+
+	// 旅行のカレンダーはスケジュールごとにVEVENTを返すべき
+	calendarPath := fmt.Sprintf("/trips/%s/calendar.ics", tripID)
+	rec := makeRequest(t, http.MethodGet, calendarPath, nil, ownerToken)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "text/calendar; charset=utf-8", rec.Header().Get("Content-Type"))
+	body := rec.Body.String()
+	assert.True(t, strings.HasPrefix(body, "BEGIN:VCALENDAR\r\n"))
+	assert.Contains(t, body, "X-WR-CALNAME:沖縄旅行\r\n")
+	assert.Contains(t, body, "UID:"+scheduleID+"@trip-app\r\n")
+	assert.Contains(t, body, "DTSTART:20250801T100000Z\r\n")
+	assert.Contains(t, body, "DTEND:20250801T120000Z\r\n")
+	assert.Contains(t, body, "SUMMARY:美ら海水族館\r\n")
+	assert.Contains(t, body, "DESCRIPTION:テストスケジュール\r\n")
+	assert.Equal(t, 1, strings.Count(body, "BEGIN:VEVENT"))
+	assert.NotContains(t, body, "DTSTAMP:00010101")
+
+	// スケジュールを編集してもUIDは変わらず、DTSTAMPが更新されるべき
+	stamp := regexp.MustCompile(`DTSTAMP:(\S+)`).FindStringSubmatch(body)[1]
+	time.Sleep(time.Second)
+	rec = makeRequest(t, http.MethodPatch, fmt.Sprintf("/trips/%s/schedules/%s", tripID, scheduleID), map[string]interface{}{"memo": "チケット; 予約済み, 2枚"}, ownerToken)
+	require.Equal(t, http.StatusOK, rec.Code)
+	rec = makeRequest(t, http.MethodGet, calendarPath, nil, ownerToken)
+	require.Equal(t, http.StatusOK, rec.Code)
+	body = rec.Body.String()
+	assert.Contains(t, body, "UID:"+scheduleID+"@trip-app\r\n")
+	assert.NotContains(t, body, "DTSTAMP:"+stamp)
+	assert.Contains(t, body, `DESCRIPTION:チケット\; 予約済み\, 2枚`)
+
+	// 権限のないユーザーは取得できないべき
+	rec = makeRequest(t, http.MethodGet, calendarPath, nil, friendToken)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	rec = makeRequest(t, http.MethodGet, calendarPath, nil, "")
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	// 共有リンクからも取得できるべき
+	rec = makeRequest(t, http.MethodPost, fmt.Sprintf("/trips/%s/share-links", tripID), map[string]interface{}{"name": "家族用", "scope": "view"}, ownerToken)
+	require.Equal(t, http.StatusCreated, rec.Code)
+	var link map[string]interface{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &link))
+	rec = makeRequest(t, http.MethodGet, "/public/trips/"+link["shareToken"].(string)+"/calendar.ics", nil, "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "UID:"+scheduleID+"@trip-app\r\n")
+	rec = makeRequest(t, http.MethodGet, "/public/trips/invalid-token/calendar.ics", nil, "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	// 購読URLは発行前は存在しないべき
+	rec = makeRequest(t, http.MethodGet, "/me/calendar-feed", nil, ownerToken)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = makeRequest(t, http.MethodPost, "/me/calendar-feed", nil, ownerToken)
+	require.Equal(t, http.StatusCreated, rec.Code)
+	var feed map[string]interface{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &feed))
+	feedToken := feed["token"].(string)
+	feedPath := "/calendar-feeds/" + feedToken + "/calendar.ics"
+	assert.True(t, strings.HasSuffix(feed["url"].(string), feedPath))
+
+	// 共同編集している旅行も、旅行名付きで購読URLに含まれるべき
+	rec = makeRequest(t, http.MethodPost, fmt.Sprintf("/trips/%s/collaborators", friendTripID), map[string]interface{}{"email": "owner@example.com", "role": "viewer"}, friendToken)
+	require.Equal(t, http.StatusCreated, rec.Code)
+	rec = makeRequest(t, http.MethodGet, feedPath, nil, "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	body = rec.Body.String()
+	assert.Equal(t, 2, strings.Count(body, "BEGIN:VEVENT"))
+	assert.Contains(t, body, "SUMMARY:[沖縄旅行] 美ら海水族館\r\n")
+	assert.Contains(t, body, "SUMMARY:[京都旅行] 清水寺\r\n")
+	assert.Less(t, strings.Index(body, "美ら海水族館"), strings.Index(body, "清水寺"))
+
+	rec = makeRequest(t, http.MethodGet, "/me/calendar-feed", nil, ownerToken)
+	require.Equal(t, http.StatusOK, rec.Code)
+	var feedStatus map[string]interface{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &feedStatus))
+	assert.NotContains(t, feedStatus, "token")
+	assert.NotNil(t, feedStatus["lastUsedAt"])
+
+	// 再発行すると以前の購読URLは使えなくなるべき
+	rec = makeRequest(t, http.MethodPost, "/me/calendar-feed", nil, ownerToken)
+	require.Equal(t, http.StatusCreated, rec.Code)
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &feed))
+	newFeedPath := "/calendar-feeds/" + feed["token"].(string) + "/calendar.ics"
+	rec = makeRequest(t, http.MethodGet, feedPath, nil, "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	rec = makeRequest(t, http.MethodGet, newFeedPath, nil, "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	// 失効後は購読URLが使えなくなるべき
+	rec = makeRequest(t, http.MethodDelete, "/me/calendar-feed", nil, ownerToken)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	rec = makeRequest(t, http.MethodGet, newFeedPath, nil, "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	rec = makeRequest(t, http.MethodDelete, "/me/calendar-feed", nil, ownerToken)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestScenario_ScheduleImportFlow(t *testing.T) {
+	setupTestDB(t)
+	defer cleanupTestDB(t)
+	setupTestServer(t)
+
+	ownerToken := createAndLoginUser(t, "owner", "owner@example.com", "password123")
+	friendToken := createAndLoginUser(t, "friend", "friend@example.com", "password123")
+	tripID := createTrip(t, ownerToken, "沖縄旅行", "2025-08-01", "2025-08-03")
+	scheduleID := createSchedule(t, ownerToken, tripID, "美ら海水族館", "2025-08-01")
+
+	ics := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//Example//Test//EN",
+		// TZID付きの予定
+		"BEGIN:VEVENT",
+		"UID:flight@example.com",
+		"DTSTART;TZID=Asia/Tokyo:20250801T090000",
+		"DTEND;TZID=Asia/Tokyo:20250801T113000",
+		"SUMMARY:那覇行きの便",
+		"DESCRIPTION:座席 12A\\, 預け荷物あり",
+		"END:VEVENT",
+		// 終日の予定
+		"BEGIN:VEVENT",
+		"UID:hotel@example.com",
+		"DTSTART;VALUE=DATE:20250802",
+		"SUMMARY:ホテル",
+		"END:VEVENT",
+		// このアプリから書き出した予定
+		"BEGIN:VEVENT",
+		"UID:" + scheduleID + "@trip-app",
+		"DTSTART:20250801T100000Z",
+		"DTEND:20250801T120000Z",
+		"SUMMARY:美ら海水族館",
+		"END:VEVENT",
+		// ファイル内で重複したUID
+		"BEGIN:VEVENT",
+		"UID:flight@example.com",
+		"DTSTART;TZID=Asia/Tokyo:20250801T090000",
+		"DTEND;TZID=Asia/Tokyo:20250801T113000",
+		"SUMMARY:那覇行きの便",
+		"END:VEVENT",
+		// SUMMARYがない予定
+		"BEGIN:VEVENT",
+		"UID:untitled@example.com",
+		"DTSTART:20250803T010000Z",
+		"DTEND:20250803T020000Z",
+		"END:VEVENT",
+		// 終了が開始より前の予定
+		"BEGIN:VEVENT",
+		"UID:reversed@example.com",
+		"DTSTART:20250803T050000Z",
+		"DTEND:20250803T040000Z",
+		"SUMMARY:逆転した予定",
+		"END:VEVENT",
+		"END:VCALENDAR",
+		"",
+	}, "\r\n")
+	importPath := fmt.Sprintf("/trips/%s/schedules/import?tz=Asia/Tokyo", tripID)
+
+	countSchedules := func() int {
+		rec := makeRequest(t, http.MethodGet, fmt.Sprintf("/trips/%s/schedules", tripID), nil, ownerToken)
+		require.Equal(t, http.StatusOK, rec.Code)
+		var schedules []map[string]interface{}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &schedules))
+		return len(schedules)
+	}
+	decodeReport := func(rec *httptest.ResponseRecorder) api.ScheduleImportReport {
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		var report api.ScheduleImportReport
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &report))
+		return report
+	}
+	statuses := func(report api.ScheduleImportReport) []string {
+		res := make([]string, len(report.Results))
+		for i, result := range report.Results {
+			res[i] = result.Status
+		}
+		return res
+	}
+
+	// ドライランは作成される内容を返し、何も保存しないべき
+	report := decodeReport(makeUploadRequest(t, importPath+"&dryRun=true", "trip.ics", []byte(ics), ownerToken))
+	assert.True(t, report.DryRun)
+	assert.Equal(t, []string{"would_create", "would_create", "duplicate", "duplicate", "error", "error"}, statuses(report))
+	assert.Equal(t, 2, report.Created)
+	assert.Equal(t, 2, report.Duplicates)
+	assert.Equal(t, 2, report.Failed)
+	require.NotNil(t, report.Results[0].Schedule)
+	assert.Nil(t, report.Results[0].Schedule.Id)
+	assert.NotNil(t, report.Results[4].Error)
+	assert.Equal(t, 1, countSchedules())
+
+	// 取り込むと、取り込める予定だけが作成されるべき
+	report = decodeReport(makeUploadRequest(t, importPath, "trip.ics", []byte(ics), ownerToken))
+	assert.False(t, report.DryRun)
+	assert.Equal(t, []string{"created", "created", "duplicate", "duplicate", "error", "error"}, statuses(report))
+	assert.Equal(t, 3, countSchedules())
+
+	flight := report.Results[0].Schedule
+	require.NotNil(t, flight)
+	require.NotNil(t, flight.Id)
+	assert.Equal(t, "那覇行きの便", *flight.Title)
+	assert.True(t, time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC).Equal(*flight.StartDateTime))
+	assert.True(t, time.Date(2025, 8, 1, 2, 30, 0, 0, time.UTC).Equal(*flight.EndDateTime))
+	assert.Equal(t, "座席 12A, 預け荷物あり", *flight.Memo)
+
+	// 終日の予定はtzの日付の0時から翌日の0時までになるべき
+	hotel := report.Results[1].Schedule
+	require.NotNil(t, hotel)
+	assert.True(t, time.Date(2025, 8, 1, 15, 0, 0, 0, time.UTC).Equal(*hotel.StartDateTime))
+	assert.True(t, time.Date(2025, 8, 2, 15, 0, 0, 0, time.UTC).Equal(*hotel.EndDateTime))
+
+	// 同じファイルを再度取り込んでも重複して作成されないべき
+	report = decodeReport(makeUploadRequest(t, importPath, "trip.ics", []byte(ics), ownerToken))
+	assert.Equal(t, []string{"duplicate", "duplicate", "duplicate", "duplicate", "error", "error"}, statuses(report))
+	assert.Equal(t, 3, countSchedules())
+
+	// 取り込んだスケジュールを削除すると、再度取り込めるべき
+	rec := makeRequest(t, http.MethodDelete, fmt.Sprintf("/trips/%s/schedules/%s", tripID, *flight.Id), nil, ownerToken)
+	require.Equal(t, http.StatusNoContent, rec.Code)
+	report = decodeReport(makeUploadRequest(t, importPath, "trip.ics", []byte(ics), ownerToken))
+	assert.Equal(t, "created", report.Results[0].Status)
+	assert.Equal(t, 3, countSchedules())
+
+	// text/calendarのリクエストボディでも取り込めるべき
+	single := "BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nUID:dinner@example.com\r\nDTSTART:20250802T190000\r\nDTEND:20250802T210000\r\nSUMMARY:夕食\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n"
+	report = decodeReport(makeRawRequest(t, http.MethodPost, importPath, "text/calendar", strings.NewReader(single), ownerToken))
+	require.Equal(t, []string{"created"}, statuses(report))
+	assert.True(t, time.Date(2025, 8, 2, 10, 0, 0, 0, time.UTC).Equal(*report.Results[0].Schedule.StartDateTime))
+
+	// 不正なファイルやタイムゾーンは400を返すべき
+	rec = makeUploadRequest(t, importPath, "trip.ics", []byte("not a calendar"), ownerToken)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	rec = makeUploadRequest(t, fmt.Sprintf("/trips/%s/schedules/import?tz=Mars/Olympus", tripID), "trip.ics", []byte(ics), ownerToken)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	rec = makeRequest(t, http.MethodPost, importPath, map[string]interface{}{"file": ics}, ownerToken)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	// 大きすぎるファイルは413を返すべき
+	large := strings.Replace(ics, "SUMMARY:ホテル", "SUMMARY:ホテル\r\nX-PADDING:"+strings.Repeat("a", 2<<20), 1)
+	rec = makeUploadRequest(t, importPath, "trip.ics", []byte(large), ownerToken)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+
+	// 権限のないユーザーは取り込めないべき
+	rec = makeUploadRequest(t, importPath, "trip.ics", []byte(ics), friendToken)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	assert.Equal(t, 4, countSchedules())
+}
+
+func TestScenario_ScheduleCSVFlow(t *testing.T) {
+	setupTestDB(t)
+	defer cleanupTestDB(t)
+	setupTestServer(t)
+
+	ownerToken := createAndLoginUser(t, "owner", "owner@example.com", "password123")
+	viewerToken := createAndLoginUser(t, "viewer", "viewer@example.com", "password123")
+	tripID := createTrip(t, ownerToken, "沖縄旅行", "2025-08-01", "2025-08-03")
+	createSchedule(t, ownerToken, tripID, "美ら海水族館", "2025-08-01")
+	rec := makeRequest(t, http.MethodPost, fmt.Sprintf("/trips/%s/collaborators", tripID), map[string]interface{}{"email": "viewer@example.com", "role": "viewer"}, ownerToken)
+	require.Equal(t, http.StatusCreated, rec.Code)
+
+	csvPath := fmt.Sprintf("/trips/%s/schedules.csv", tripID)
+	countSchedules := func(tripID string) int {
+		rec := makeRequest(t, http.MethodGet, fmt.Sprintf("/trips/%s/schedules", tripID), nil, ownerToken)
+		require.Equal(t, http.StatusOK, rec.Code)
+		var schedules []map[string]interface{}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &schedules))
+		return len(schedules)
+	}
+	decodeReport := func(rec *httptest.ResponseRecorder) api.ScheduleCsvImportReport {
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		var report api.ScheduleCsvImportReport
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &report))
+		return report
+	}
+
+	// エクスポートはBOM付きのCSVで、日時はtzの時刻になるべき
+	rec = makeRequest(t, http.MethodGet, csvPath+"?tz=Asia/Tokyo", nil, viewerToken)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "text/csv; charset=utf-8", rec.Header().Get("Content-Type"))
+	assert.Contains(t, rec.Header().Get("Content-Disposition"), "attachment")
+	exported := rec.Body.String()
+	assert.True(t, strings.HasPrefix(exported, "\uFEFFtitle,start,end,memo,time_zone\r\n"))
+	assert.Contains(t, exported, "美ら海水族館,2025-08-01 19:00,2025-08-01 21:00,テストスケジュール,Asia/Tokyo\r\n")
+
+	csv := strings.Join([]string{
+		"title,start,end,memo,time_zone",
+		"国際通り,2025-08-02 10:00,2025-08-02 12:00,お土産,",
+		"首里城,2025/8/2 14:00,2025/8/2 13:00,,Asia/Tokyo",
+		"",
+	}, "\r\n")
+
+	// atomicでは1行でもエラーがあれば何も作成しないべき
+	report := decodeReport(makeUploadRequest(t, csvPath+"?atomic=true&tz=Asia/Tokyo", "schedules.csv", []byte(csv), ownerToken))
+	assert.True(t, report.Atomic)
+	assert.Equal(t, 0, report.Created)
+	assert.Equal(t, 1, report.Skipped)
+	assert.Equal(t, 1, report.Failed)
+	require.Len(t, report.Rows, 2)
+	assert.Equal(t, "skipped", report.Rows[0].Status)
+	assert.Equal(t, 3, report.Rows[1].Line)
+	assert.Equal(t, "error", report.Rows[1].Status)
+	require.NotNil(t, report.Rows[1].Error)
+	assert.Contains(t, *report.Rows[1].Error, "end must be after start")
+	assert.Equal(t, 1, countSchedules(tripID))
+
+	// atomicでなければ正しい行だけを作成するべき
+	report = decodeReport(makeUploadRequest(t, csvPath+"?tz=Asia/Tokyo", "schedules.csv", []byte(csv), ownerToken))
+	assert.False(t, report.Atomic)
+	assert.Equal(t, 1, report.Created)
+	assert.Equal(t, 1, report.Failed)
+	require.NotNil(t, report.Rows[0].Schedule)
+	require.NotNil(t, report.Rows[0].Schedule.Id)
+	assert.True(t, time.Date(2025, 8, 2, 1, 0, 0, 0, time.UTC).Equal(*report.Rows[0].Schedule.StartDateTime))
+	assert.Equal(t, "お土産", *report.Rows[0].Schedule.Memo)
+	assert.Equal(t, 2, countSchedules(tripID))
+
+	// エクスポートしたCSVをそのまま別の旅行に取り込めるべき
+	rec = makeRequest(t, http.MethodGet, csvPath, nil, ownerToken)
+	require.Equal(t, http.StatusOK, rec.Code)
+	copyTripID := createTrip(t, ownerToken, "沖縄旅行（コピー）", "2025-08-01", "2025-08-03")
+	report = decodeReport(makeRawRequest(t, http.MethodPost, fmt.Sprintf("/trips/%s/schedules.csv?atomic=true", copyTripID), "text/csv", rec.Body, ownerToken))
+	assert.Equal(t, 2, report.Created)
+	rec = makeRequest(t, http.MethodGet, fmt.Sprintf("/trips/%s/schedules.csv", copyTripID), nil, ownerToken)
+	require.Equal(t, http.StatusOK, rec.Code)
+	rec2 := makeRequest(t, http.MethodGet, csvPath, nil, ownerToken)
+	assert.Equal(t, rec2.Body.String(), rec.Body.String())
+
+	// ヘッダーやタイムゾーンが正しくなければ400を返すべき
+	rec = makeRawRequest(t, http.MethodPost, csvPath, "text/csv", strings.NewReader("name,date\r\nx,2025-08-01\r\n"), ownerToken)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	rec = makeUploadRequest(t, csvPath+"?tz=Mars/Olympus", "schedules.csv", []byte(csv), ownerToken)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	rec = makeRequest(t, http.MethodGet, csvPath+"?tz=Local", nil, ownerToken)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	// 閲覧者はエクスポートできるが取り込めないべき
+	rec = makeUploadRequest(t, csvPath, "schedules.csv", []byte(csv), viewerToken)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	assert.Equal(t, 2, countSchedules(tripID))
+}
+
+func TestScenario_TripBackupFlow(t *testing.T) {
+	setupTestDB(t)
+	defer cleanupTestDB(t)
+	setupTestServer(t)
+
+	ownerToken := createAndLoginUser(t, "owner", "owner@example.com", "password123")
+	otherToken := createAndLoginUser(t, "other", "other@example.com", "password123")
+	rec := makeRequest(t, http.MethodPost, "/trips", map[string]interface{}{
+		"title":     "北海道旅行",
+		"startDate": "2025-09-10",
+		"endDate":   "2025-09-12",
+		"members":   []interface{}{map[string]interface{}{"name": "太郎"}, map[string]interface{}{"name": "花子"}},
+	}, ownerToken)
+	require.Equal(t, http.StatusCreated, rec.Code)
+	var source api.Trip
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &source))
+	tripID := source.Id.String()
+	createSchedule(t, ownerToken, tripID, "小樽運河", "2025-09-11")
+	createSchedule(t, ownerToken, tripID, "札幌時計台", "2025-09-10")
+
+	countTrips := func(token string) int {
+		rec := makeRequest(t, http.MethodGet, "/trips", nil, token)
+		require.Equal(t, http.StatusOK, rec.Code)
+		var trips []api.Trip
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &trips))
+		return len(trips)
+	}
+
+	// エクスポートはIDを含まず、メンバーはrefで、スケジュールは開始日時順に並ぶべき
+	rec = makeRequest(t, http.MethodGet, fmt.Sprintf("/trips/%s/export", tripID), nil, ownerToken)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Header().Get("Content-Disposition"), "attachment")
+	exported := rec.Body.Bytes()
+	var backup api.TripBackup
+	require.NoError(t, json.Unmarshal(exported, &backup))
+	assert.Equal(t, "trip-app/trip", backup.Format)
+	assert.Equal(t, 1, backup.Version)
+	assert.Equal(t, "北海道旅行", backup.Trip.Title)
+	assert.Equal(t, "2025-09-10", backup.Trip.StartDate.String())
+	assert.Nil(t, backup.Trip.OwnerMemberRef)
+	require.Len(t, backup.Members, 2)
+	assert.NotEqual(t, backup.Members[0].Ref, backup.Members[1].Ref)
+	for _, member := range *source.Members {
+		assert.NotContains(t, string(exported), member.Id.String())
+	}
+	require.Len(t, backup.Schedules, 2)
+	assert.Equal(t, "札幌時計台", backup.Schedules[0].Title)
+	assert.Equal(t, "小樽運河", backup.Schedules[1].Title)
+	assert.NotContains(t, string(exported), tripID)
+
+	// 別のユーザーが取り込むと、新しいIDの旅行の所有者になるべき
+	rec = makeRawRequest(t, http.MethodPost, "/trips/import", "application/json", bytes.NewReader(exported), otherToken)
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	var imported api.Trip
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &imported))
+	assert.NotEqual(t, tripID, imported.Id.String())
+	assert.Equal(t, "owner", *imported.Role)
+	assert.Equal(t, "北海道旅行", *imported.Title)
+	require.Len(t, *imported.Members, 2)
+	names := []string{}
+	for _, member := range *imported.Members {
+		names = append(names, *member.Name)
+		for _, sourceMember := range *source.Members {
+			assert.NotEqual(t, *sourceMember.Id, *member.Id)
+		}
+	}
+	assert.ElementsMatch(t, []string{"太郎", "花子"}, names)
+
+	rec = makeRequest(t, http.MethodGet, fmt.Sprintf("/trips/%s/schedules", imported.Id), nil, otherToken)
+	require.Equal(t, http.StatusOK, rec.Code)
+	var schedules []api.Schedule
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &schedules))
+	require.Len(t, schedules, 2)
+
+	// 取り込んだ旅行をエクスポートすると、元と同じ内容になるべき
+	rec = makeRequest(t, http.MethodGet, fmt.Sprintf("/trips/%s/export", imported.Id), nil, otherToken)
+	require.Equal(t, http.StatusOK, rec.Code)
+	var reexported api.TripBackup
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &reexported))
+	assert.Equal(t, backup.Trip, reexported.Trip)
+	assert.Equal(t, backup.Schedules, reexported.Schedules)
+
+	// ownerMemberRefのメンバーは取り込んだユーザーに紐付くべき
+	doc := map[string]interface{}{
+		"format":     "trip-app/trip",
+		"version":    1,
+		"exportedAt": "2025-07-01T00:00:00Z",
+		"trip": map[string]interface{}{
+			"title":          "京都旅行",
+			"startDate":      "2025-10-01",
+			"endDate":        "2025-10-02",
+			"ownerMemberRef": "me",
+		},
+		"members": []interface{}{
+			map[string]interface{}{"ref": "me", "name": "自分"},
+			map[string]interface{}{"ref": "friend", "name": "友人"},
+		},
+		"schedules": []interface{}{
+			map[string]interface{}{"title": "清水寺", "startDateTime": "2025-10-01T01:00:00Z", "endDateTime": "2025-10-01T03:00:00Z"},
+		},
+	}
+	docJSON, err := json.Marshal(doc)
+	require.NoError(t, err)
+	rec = makeUploadRequest(t, "/trips/import", "trip.json", docJSON, otherToken)
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &imported))
+	me := getMe(t, otherToken)
+	for _, member := range *imported.Members {
+		if *member.Name == "自分" {
+			require.NotNil(t, member.UserId)
+			assert.Equal(t, me["id"], member.UserId.String())
+		} else {
+			assert.Nil(t, member.UserId)
+		}
+	}
+	assert.Equal(t, 2, countTrips(otherToken))
+
+	// 形式やバージョンが正しくない文書は何も作成せずに400を返すべき
+	invalid := []func(doc map[string]interface{}){
+		func(doc map[string]interface{}) { doc["version"] = 99 },
+		func(doc map[string]interface{}) { delete(doc, "version") },
+		func(doc map[string]interface{}) { doc["format"] = "other-app/trip" },
+		func(doc map[string]interface{}) {
+			doc["trip"].(map[string]interface{})["ownerMemberRef"] = "nobody"
+		},
+		func(doc map[string]interface{}) {
+			doc["schedules"] = []interface{}{
+				map[string]interface{}{"title": "清水寺", "startDateTime": "2025-10-01T03:00:00Z", "endDateTime": "2025-10-01T01:00:00Z"},
+			}
+		},
+	}
+	for i, modify := range invalid {
+		var copied map[string]interface{}
+		require.NoError(t, json.Unmarshal(docJSON, &copied))
+		modify(copied)
+		rec = makeRequest(t, http.MethodPost, "/trips/import", copied, otherToken)
+		assert.Equal(t, http.StatusBadRequest, rec.Code, "case %d: %s", i, rec.Body.String())
+	}
+	assert.Equal(t, 2, countTrips(otherToken))
+
+	// 旅行にアクセスできないユーザーはエクスポートできないべき
+	rec = makeRequest(t, http.MethodGet, fmt.Sprintf("/trips/%s/export", tripID), nil, otherToken)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+}
+
+// ========================================
+// ヘルパー関数
+// ========================================
+
+// createAndLoginUser はユーザー登録・認証・ログインを行いJWTトークンを返す
+func createAndLoginUser(t *testing.T, username, email, password string) string {
+	signupReq := map[string]interface{}{
+		"name":  username,
+		"email": email,
+	}
+	rec := makeRequest(t, http.MethodPost, "/signup", signupReq, "")
+	require.Equal(t, http.StatusCreated, rec.Code)
+
+	// メール認証（モックからトークンを取得し、パスワードを設定）
+	verificationToken := mockEmailSender.GetLastToken()
+	require.NotEmpty(t, verificationToken)
+	verifyReq := map[string]interface{}{
+		"password": password,
+	}
+	rec = makeRequest(t, http.MethodPost, "/users/verify/"+verificationToken, verifyReq, "")
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	// ログイン（設定したパスワードを使用）
+	loginReq := map[string]interface{}{
+		"email":    email,
+		"password": password,
+	}
+	rec = makeRequest(t, http.MethodPost, "/login", loginReq, "")
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var loginResp map[string]interface{}
+	err := json.Unmarshal(rec.Body.Bytes(), &loginResp)
+	require.NoError(t, err)
+	return loginResp["token"].(string)
+}
+
+// loginAndGetToken はログインしてJWTトークンを返す
+func loginAndGetToken(t *testing.T, loginReq map[string]interface{}) string {
+	rec := makeRequest(t, http.MethodPost, "/login", loginReq, "")
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var loginResp map[string]interface{}
+	err := json.Unmarshal(rec.Body.Bytes(), &loginResp)
+	require.NoError(t, err)
+	return loginResp["token"].(string)
+}
+
+// loginAndGetChallengeToken は2段階認証が有効なユーザーでログインしてチャレンジトークンを返す
+func loginAndGetChallengeToken(t *testing.T, loginReq map[string]interface{}) string {
+	rec := makeRequest(t, http.MethodPost, "/login", loginReq, "")
+	require.Equal(t, http.StatusAccepted, rec.Code)
+
+	var challengeResp map[string]interface{}
+	err := json.Unmarshal(rec.Body.Bytes(), &challengeResp)
+	require.NoError(t, err)
+	assert.NotContains(t, challengeResp, "token")
+	return challengeResp["challengeToken"].(string)
+}
+
+// createTrip は旅行を作成してIDを返す
+func createTrip(t *testing.T, token, title, startDate, endDate string) string {
+	tripReq := map[string]interface{}{
+		"title":     title,
+		"startDate": startDate,
+		"endDate":   endDate,
+		"members":   []interface{}{},
+	}
+	rec := makeRequest(t, http.MethodPost, "/trips", tripReq, token)
+	require.Equal(t, http.StatusCreated, rec.Code)
+
+	var tripResp map[string]interface{}
+	err := json.Unmarshal(rec.Body.Bytes(), &tripResp)
+	require.NoError(t, err)
+	return tripResp["id"].(string)
+}
+
+// createSchedule はスケジュールを作成してIDを返す
+func createSchedule(t *testing.T, token string, tripID string, title, date string) string {
+	scheduleReq := map[string]interface{}{
+		"title":         title,
+		"startDateTime": date + "T10:00:00Z",
+		"endDateTime":   date + "T12:00:00Z",
+		"memo":          "テストスケジュール",
+	}
+	rec := makeRequest(t, http.MethodPost, fmt.Sprintf("/trips/%s/schedules", tripID), scheduleReq, token)
+	require.Equal(t, http.StatusCreated, rec.Code)
+
+	var scheduleResp map[string]interface{}
+	err := json.Unmarshal(rec.Body.Bytes(), &scheduleResp)
+	require.NoError(t, err)
+	return scheduleResp["id"].(string)
+}
+
+// authorizeWithOIDC はOIDCログインを開始してプロバイダーで認可し、コールバックに渡されるクエリを返す
+func authorizeWithOIDC(t *testing.T, providerName string) url.Values {
+	rec := makeRequest(t, http.MethodGet, "/auth/oidc/"+providerName+"/start", nil, "")
+	require.Equal(t, http.StatusFound, rec.Code)
+
+	// プロバイダーからのリダイレクトは追わず、コールバックURLを取り出す
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }}
+	res, err := client.Get(rec.Header().Get("Location"))
+	require.NoError(t, err)
+	defer res.Body.Close()
+	require.Equal(t, http.StatusFound, res.StatusCode)
+
+	callbackURL, err := url.Parse(res.Header.Get("Location"))
+	require.NoError(t, err)
+	require.Equal(t, "/auth/oidc/"+providerName+"/callback", callbackURL.Path)
+	return callbackURL.Query()
+}
+
+// loginWithOIDC はOIDCログインを行い、コールバックのレスポンスを返す
+func loginWithOIDC(t *testing.T, providerName string) *httptest.ResponseRecorder {
+	query := authorizeWithOIDC(t, providerName)
+	return makeRequest(t, http.MethodGet, "/auth/oidc/"+providerName+"/callback?"+query.Encode(), nil, "")
+}
+
+// authTokenFromResponse はログインのレスポンスからJWTトークンを取り出す
+func authTokenFromResponse(t *testing.T, rec *httptest.ResponseRecorder) string {
+	var authResp map[string]interface{}
+	err := json.Unmarshal(rec.Body.Bytes(), &authResp)
+	require.NoError(t, err)
+	return authResp["token"].(string)
+}
+
+// getMe はログイン中のユーザー情報を取得
+func getMe(t *testing.T, token string) map[string]interface{} {
+	rec := makeRequest(t, http.MethodGet, "/me", nil, token)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var meResp map[string]interface{}
+	err := json.Unmarshal(rec.Body.Bytes(), &meResp)
+	require.NoError(t, err)
+	return meResp
+}
+
+// generateEd25519TestKey はテスト用のEd25519署名鍵を生成
+func generateEd25519TestKey(t *testing.T, id string) security.Key {
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	return security.Key{ID: id, PrivateKey: privateKey}
+}
+
+// newTestKeySet は鍵の一覧から鍵セットを作成（署名鍵はkidが最大の秘密鍵）
+func newTestKeySet(t *testing.T, keys ...security.Key) security.KeySet {
+	keySet, err := security.NewKeySet(keys, "")
+	require.NoError(t, err)
+	return keySet
 }
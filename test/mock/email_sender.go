@@ -2,14 +2,22 @@ package mock
 
 import (
 	"context"
+	"sync"
 	"trip_app/internal/infrastructure/email"
 )
 
 // MockEmailSender はテスト用のメール送信モック
 // 実際にはメールを送信せず、トークンとパスワードを保存するだけ
 type MockEmailSender struct {
-	lastToken    string
-	lastPassword string
+	// パスワード再設定メールはバックグラウンドで送信されるため、muで保護する
+	mu                     sync.Mutex
+	lastToken              string
+	lastPassword           string
+	lastPasswordResetToken string
+	lastEmailChangeToken   string
+	lastEmailChangeNotice  string
+	lastInvitationToken    string
+	lastInvitationEmail    string
 }
 
 // NewMockEmailSender はMockEmailSenderの新しいインスタンスを作成
@@ -24,6 +32,33 @@ func (m *MockEmailSender) SendVerificationEmail(ctx context.Context, recipientEm
 	return nil
 }
 
+// SendPasswordResetEmail はパスワード再設定メールの送信をシミュレートし、再設定トークンを保存
+func (m *MockEmailSender) SendPasswordResetEmail(ctx context.Context, recipientEmail, rawToken string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.lastPasswordResetToken = rawToken
+	return nil
+}
+
+// SendEmailChangeConfirmationEmail はメールアドレス変更の確認メールの送信をシミュレートし、確認トークンを保存
+func (m *MockEmailSender) SendEmailChangeConfirmationEmail(ctx context.Context, recipientEmail, rawToken string) error {
+	m.lastEmailChangeToken = rawToken
+	return nil
+}
+
+// SendEmailChangeNoticeEmail は旧メールアドレスへの通知の送信をシミュレートし、送信先を保存
+func (m *MockEmailSender) SendEmailChangeNoticeEmail(ctx context.Context, recipientEmail, newEmail string) error {
+	m.lastEmailChangeNotice = recipientEmail
+	return nil
+}
+
+// SendTripInvitationEmail は旅行への招待メールの送信をシミュレートし、招待トークンと送信先を保存
+func (m *MockEmailSender) SendTripInvitationEmail(ctx context.Context, recipientEmail, inviterName, tripTitle, rawToken string) error {
+	m.lastInvitationToken = rawToken
+	m.lastInvitationEmail = recipientEmail
+	return nil
+}
+
 // GetLastToken は最後に送信されたトークンを返す
 func (m *MockEmailSender) GetLastToken() string {
 	return m.lastToken
@@ -34,5 +69,32 @@ func (m *MockEmailSender) GetLastPassword() string {
 	return m.lastPassword
 }
 
+// GetLastPasswordResetToken は最後に送信されたパスワード再設定トークンを返す
+func (m *MockEmailSender) GetLastPasswordResetToken() string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.lastPasswordResetToken
+}
+
+// GetLastEmailChangeToken は最後に送信されたメールアドレス変更の確認トークンを返す
+func (m *MockEmailSender) GetLastEmailChangeToken() string {
+	return m.lastEmailChangeToken
+}
+
+// GetLastEmailChangeNotice は最後にメールアドレス変更の通知を送信した旧メールアドレスを返す
+func (m *MockEmailSender) GetLastEmailChangeNotice() string {
+	return m.lastEmailChangeNotice
+}
+
+// GetLastInvitationToken は最後に送信された招待トークンを返す
+func (m *MockEmailSender) GetLastInvitationToken() string {
+	return m.lastInvitationToken
+}
+
+// GetLastInvitationEmail は最後に招待メールを送信したメールアドレスを返す
+func (m *MockEmailSender) GetLastInvitationEmail() string {
+	return m.lastInvitationEmail
+}
+
 // コンパイル時にinterfaceを実装していることを確認
 var _ email.Sender = (*MockEmailSender)(nil)
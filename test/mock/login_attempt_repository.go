@@ -0,0 +1,73 @@
+package mock
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"trip_app/internal/domain"
+	"trip_app/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+// InMemoryLoginAttemptRepository はテスト用のログイン失敗カウンターストア
+type InMemoryLoginAttemptRepository struct {
+	mu       sync.Mutex
+	attempts map[string]domain.LoginAttempt
+}
+
+// NewInMemoryLoginAttemptRepository はInMemoryLoginAttemptRepositoryの新しいインスタンスを作成
+func NewInMemoryLoginAttemptRepository() *InMemoryLoginAttemptRepository {
+	return &InMemoryLoginAttemptRepository{attempts: make(map[string]domain.LoginAttempt)}
+}
+
+// FindByKey はキーに対応するカウンターを取得
+func (r *InMemoryLoginAttemptRepository) FindByKey(ctx context.Context, key string) (*domain.LoginAttempt, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	attempt, ok := r.attempts[key]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &attempt, nil
+}
+
+// RecordFailure は失敗回数を加算（前回の失敗がwindowStartより前なら1からやり直し）
+func (r *InMemoryLoginAttemptRepository) RecordFailure(ctx context.Context, key string, failedAt, windowStart time.Time) (*domain.LoginAttempt, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	attempt, ok := r.attempts[key]
+	if !ok || attempt.LastFailedAt.Before(windowStart) {
+		attempt.Key = key
+		attempt.FailureCount = 0
+	}
+	attempt.FailureCount++
+	attempt.LastFailedAt = failedAt
+	r.attempts[key] = attempt
+	return &attempt, nil
+}
+
+// Lock はキーをlockedUntilまでロック
+func (r *InMemoryLoginAttemptRepository) Lock(ctx context.Context, key string, lockedUntil time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	attempt, ok := r.attempts[key]
+	if !ok {
+		return nil
+	}
+	attempt.LockedUntil = &lockedUntil
+	r.attempts[key] = attempt
+	return nil
+}
+
+// Reset はカウンターを削除
+func (r *InMemoryLoginAttemptRepository) Reset(ctx context.Context, key string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.attempts, key)
+	return nil
+}
+
+// コンパイル時にinterfaceを実装していることを確認
+var _ repository.LoginAttemptRepository = (*InMemoryLoginAttemptRepository)(nil)
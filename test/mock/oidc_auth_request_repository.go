@@ -0,0 +1,58 @@
+package mock
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"trip_app/internal/domain"
+	"trip_app/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+// InMemoryOIDCAuthRequestRepository はテスト用のOpenID Connectログイン状態ストア
+type InMemoryOIDCAuthRequestRepository struct {
+	mu       sync.Mutex
+	requests map[string]domain.OIDCAuthRequest
+}
+
+// NewInMemoryOIDCAuthRequestRepository はInMemoryOIDCAuthRequestRepositoryの新しいインスタンスを作成
+func NewInMemoryOIDCAuthRequestRepository() *InMemoryOIDCAuthRequestRepository {
+	return &InMemoryOIDCAuthRequestRepository{requests: make(map[string]domain.OIDCAuthRequest)}
+}
+
+// Create はログイン状態を保存
+func (r *InMemoryOIDCAuthRequestRepository) Create(ctx context.Context, request *domain.OIDCAuthRequest) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.requests[request.StateHash] = *request
+	return nil
+}
+
+// Consume はログイン状態を削除して返す（存在しない場合はgorm.ErrRecordNotFoundを返す）
+func (r *InMemoryOIDCAuthRequestRepository) Consume(ctx context.Context, stateHash string) (*domain.OIDCAuthRequest, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	request, ok := r.requests[stateHash]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	delete(r.requests, stateHash)
+	return &request, nil
+}
+
+// DeleteExpired は期限切れのログイン状態を削除
+func (r *InMemoryOIDCAuthRequestRepository) DeleteExpired(ctx context.Context, now time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for stateHash, request := range r.requests {
+		if request.ExpiresAt.Before(now) {
+			delete(r.requests, stateHash)
+		}
+	}
+	return nil
+}
+
+// コンパイル時にinterfaceを実装していることを確認
+var _ repository.OIDCAuthRequestRepository = (*InMemoryOIDCAuthRequestRepository)(nil)
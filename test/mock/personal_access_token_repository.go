@@ -0,0 +1,97 @@
+package mock
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"trip_app/internal/domain"
+	"trip_app/internal/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// InMemoryPersonalAccessTokenRepository はテスト用のパーソナルアクセストークンストア
+type InMemoryPersonalAccessTokenRepository struct {
+	mu     sync.Mutex
+	tokens map[uuid.UUID]domain.PersonalAccessToken
+}
+
+// NewInMemoryPersonalAccessTokenRepository はInMemoryPersonalAccessTokenRepositoryの新しいインスタンスを作成
+func NewInMemoryPersonalAccessTokenRepository() *InMemoryPersonalAccessTokenRepository {
+	return &InMemoryPersonalAccessTokenRepository{tokens: make(map[uuid.UUID]domain.PersonalAccessToken)}
+}
+
+// Create はトークンを保存
+func (r *InMemoryPersonalAccessTokenRepository) Create(ctx context.Context, token *domain.PersonalAccessToken) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.tokens[token.ID] = *token
+	return nil
+}
+
+// FindByTokenHash はハッシュ値からトークンを取得
+func (r *InMemoryPersonalAccessTokenRepository) FindByTokenHash(ctx context.Context, tokenHash string) (*domain.PersonalAccessToken, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, token := range r.tokens {
+		if token.TokenHash == tokenHash {
+			return &token, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+// FindByUserID はユーザーのトークンを作成日時順に取得
+func (r *InMemoryPersonalAccessTokenRepository) FindByUserID(ctx context.Context, userID uuid.UUID) ([]domain.PersonalAccessToken, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var tokens []domain.PersonalAccessToken
+	for _, token := range r.tokens {
+		if token.UserID == userID {
+			tokens = append(tokens, token)
+		}
+	}
+	sort.Slice(tokens, func(i, j int) bool { return tokens[i].CreatedAt.Before(tokens[j].CreatedAt) })
+	return tokens, nil
+}
+
+// Delete はユーザーのトークンを削除（存在しない場合はfalse）
+func (r *InMemoryPersonalAccessTokenRepository) Delete(ctx context.Context, userID, tokenID uuid.UUID) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	token, ok := r.tokens[tokenID]
+	if !ok || token.UserID != userID {
+		return false, nil
+	}
+	delete(r.tokens, tokenID)
+	return true, nil
+}
+
+// DeleteAllByUserID はユーザーの全トークンを削除
+func (r *InMemoryPersonalAccessTokenRepository) DeleteAllByUserID(ctx context.Context, userID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for id, token := range r.tokens {
+		if token.UserID == userID {
+			delete(r.tokens, id)
+		}
+	}
+	return nil
+}
+
+// UpdateLastUsedAt は最終使用日時を更新
+func (r *InMemoryPersonalAccessTokenRepository) UpdateLastUsedAt(ctx context.Context, tokenID uuid.UUID, usedAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if token, ok := r.tokens[tokenID]; ok {
+		token.LastUsedAt = &usedAt
+		r.tokens[tokenID] = token
+	}
+	return nil
+}
+
+// コンパイル時にinterfaceを実装していることを確認
+var _ repository.PersonalAccessTokenRepository = (*InMemoryPersonalAccessTokenRepository)(nil)
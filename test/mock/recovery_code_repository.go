@@ -0,0 +1,67 @@
+package mock
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"trip_app/internal/domain"
+	"trip_app/internal/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// InMemoryRecoveryCodeRepository はテスト用のリカバリーコードストア
+type InMemoryRecoveryCodeRepository struct {
+	mu    sync.Mutex
+	codes map[uuid.UUID]domain.RecoveryCode
+}
+
+// NewInMemoryRecoveryCodeRepository はInMemoryRecoveryCodeRepositoryの新しいインスタンスを作成
+func NewInMemoryRecoveryCodeRepository() *InMemoryRecoveryCodeRepository {
+	return &InMemoryRecoveryCodeRepository{codes: make(map[uuid.UUID]domain.RecoveryCode)}
+}
+
+// ReplaceAll はユーザーの既存コードを削除し、新しいコードを保存
+func (r *InMemoryRecoveryCodeRepository) ReplaceAll(ctx context.Context, userID uuid.UUID, recoveryCodes []domain.RecoveryCode) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for id, code := range r.codes {
+		if code.UserID == userID {
+			delete(r.codes, id)
+		}
+	}
+	for _, code := range recoveryCodes {
+		r.codes[code.ID] = code
+	}
+	return nil
+}
+
+// FindUnusedByCodeHash はハッシュ値から未使用のリカバリーコードを取得
+func (r *InMemoryRecoveryCodeRepository) FindUnusedByCodeHash(ctx context.Context, userID uuid.UUID, codeHash string) (*domain.RecoveryCode, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, code := range r.codes {
+		if code.UserID == userID && code.CodeHash == codeHash && code.UsedAt == nil {
+			return &code, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+// MarkUsed はコードを使用済みにする（既に使用済みの場合はfalse）
+func (r *InMemoryRecoveryCodeRepository) MarkUsed(ctx context.Context, recoveryCodeID uuid.UUID, usedAt time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	code, ok := r.codes[recoveryCodeID]
+	if !ok || code.UsedAt != nil {
+		return false, nil
+	}
+	code.UsedAt = &usedAt
+	r.codes[recoveryCodeID] = code
+	return true, nil
+}
+
+// コンパイル時にinterfaceを実装していることを確認
+var _ repository.RecoveryCodeRepository = (*InMemoryRecoveryCodeRepository)(nil)
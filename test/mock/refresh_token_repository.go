@@ -0,0 +1,60 @@
+package mock
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"trip_app/internal/domain"
+	"trip_app/internal/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// InMemoryRefreshTokenRepository はテスト用のリフレッシュトークンストア
+type InMemoryRefreshTokenRepository struct {
+	mu     sync.Mutex
+	tokens map[uuid.UUID]domain.RefreshToken
+}
+
+// NewInMemoryRefreshTokenRepository はInMemoryRefreshTokenRepositoryの新しいインスタンスを作成
+func NewInMemoryRefreshTokenRepository() *InMemoryRefreshTokenRepository {
+	return &InMemoryRefreshTokenRepository{tokens: make(map[uuid.UUID]domain.RefreshToken)}
+}
+
+// Create はリフレッシュトークンを保存
+func (r *InMemoryRefreshTokenRepository) Create(ctx context.Context, refreshToken *domain.RefreshToken) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.tokens[refreshToken.ID] = *refreshToken
+	return nil
+}
+
+// FindByTokenHash はハッシュ値からリフレッシュトークンを取得
+func (r *InMemoryRefreshTokenRepository) FindByTokenHash(ctx context.Context, tokenHash string) (*domain.RefreshToken, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, token := range r.tokens {
+		if token.TokenHash == tokenHash {
+			return &token, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+// MarkRotated はトークンを使用済みにする（既に使用済みの場合はfalse）
+func (r *InMemoryRefreshTokenRepository) MarkRotated(ctx context.Context, refreshTokenID uuid.UUID, rotatedAt time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	token, ok := r.tokens[refreshTokenID]
+	if !ok || token.RotatedAt != nil {
+		return false, nil
+	}
+	token.RotatedAt = &rotatedAt
+	r.tokens[refreshTokenID] = token
+	return true, nil
+}
+
+// コンパイル時にinterfaceを実装していることを確認
+var _ repository.RefreshTokenRepository = (*InMemoryRefreshTokenRepository)(nil)
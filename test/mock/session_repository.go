@@ -0,0 +1,71 @@
+package mock
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"trip_app/internal/domain"
+	"trip_app/internal/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// InMemorySessionRepository はテスト用のセッションストア
+// DBの代わりにメモリ上のmapでセッションを保持する
+type InMemorySessionRepository struct {
+	mu       sync.Mutex
+	sessions map[uuid.UUID]domain.Session
+}
+
+// NewInMemorySessionRepository はInMemorySessionRepositoryの新しいインスタンスを作成
+func NewInMemorySessionRepository() *InMemorySessionRepository {
+	return &InMemorySessionRepository{sessions: make(map[uuid.UUID]domain.Session)}
+}
+
+// Create はセッションを保存
+func (r *InMemorySessionRepository) Create(ctx context.Context, session *domain.Session) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.sessions[session.ID] = *session
+	return nil
+}
+
+// FindByID はセッションを取得（存在しない場合はgorm.ErrRecordNotFoundを返す）
+func (r *InMemorySessionRepository) FindByID(ctx context.Context, sessionID uuid.UUID) (*domain.Session, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	session, ok := r.sessions[sessionID]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &session, nil
+}
+
+// Revoke は指定したセッションを失効
+func (r *InMemorySessionRepository) Revoke(ctx context.Context, sessionID uuid.UUID, revokedAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if session, ok := r.sessions[sessionID]; ok && session.RevokedAt == nil {
+		session.RevokedAt = &revokedAt
+		r.sessions[sessionID] = session
+	}
+	return nil
+}
+
+// RevokeAllByUserID はユーザーの全セッションを失効
+func (r *InMemorySessionRepository) RevokeAllByUserID(ctx context.Context, userID uuid.UUID, revokedAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for id, session := range r.sessions {
+		if session.UserID == userID && session.RevokedAt == nil {
+			session.RevokedAt = &revokedAt
+			r.sessions[id] = session
+		}
+	}
+	return nil
+}
+
+// コンパイル時にinterfaceを実装していることを確認
+var _ repository.SessionRepository = (*InMemorySessionRepository)(nil)
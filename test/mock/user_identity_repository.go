@@ -0,0 +1,79 @@
+package mock
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"trip_app/internal/domain"
+	"trip_app/internal/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// InMemoryUserIdentityRepository はテスト用の外部IDプロバイダー紐付けストア
+type InMemoryUserIdentityRepository struct {
+	mu         sync.Mutex
+	identities map[uuid.UUID]domain.UserIdentity
+}
+
+// NewInMemoryUserIdentityRepository はInMemoryUserIdentityRepositoryの新しいインスタンスを作成
+func NewInMemoryUserIdentityRepository() *InMemoryUserIdentityRepository {
+	return &InMemoryUserIdentityRepository{identities: make(map[uuid.UUID]domain.UserIdentity)}
+}
+
+// Create は紐付けを保存（同じプロバイダー・subjectが存在する場合はgorm.ErrDuplicatedKeyを返す）
+func (r *InMemoryUserIdentityRepository) Create(ctx context.Context, identity *domain.UserIdentity) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, existing := range r.identities {
+		if existing.Provider == identity.Provider && existing.Subject == identity.Subject {
+			return gorm.ErrDuplicatedKey
+		}
+	}
+	r.identities[identity.ID] = *identity
+	return nil
+}
+
+// FindByProviderSubject はプロバイダーとsubjectから紐付けを取得
+func (r *InMemoryUserIdentityRepository) FindByProviderSubject(ctx context.Context, provider, subject string) (*domain.UserIdentity, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, identity := range r.identities {
+		if identity.Provider == provider && identity.Subject == subject {
+			return &identity, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+// FindByUserID はユーザーの紐付けを作成日時順に取得
+func (r *InMemoryUserIdentityRepository) FindByUserID(ctx context.Context, userID uuid.UUID) ([]domain.UserIdentity, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var identities []domain.UserIdentity
+	for _, identity := range r.identities {
+		if identity.UserID == userID {
+			identities = append(identities, identity)
+		}
+	}
+	sort.Slice(identities, func(i, j int) bool { return identities[i].CreatedAt.Before(identities[j].CreatedAt) })
+	return identities, nil
+}
+
+// UpdateLogin はログイン日時とメールアドレスを更新
+func (r *InMemoryUserIdentityRepository) UpdateLogin(ctx context.Context, identityID uuid.UUID, email string, loggedInAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if identity, ok := r.identities[identityID]; ok {
+		identity.Email = email
+		identity.LastLoginAt = loggedInAt
+		r.identities[identityID] = identity
+	}
+	return nil
+}
+
+// コンパイル時にinterfaceを実装していることを確認
+var _ repository.UserIdentityRepository = (*InMemoryUserIdentityRepository)(nil)